@@ -39,6 +39,7 @@ const (
 )
 
 // DomainSpec defines the desired state of Domain
+// +kubebuilder:validation:XValidation:rule="!has(self.certificateRef) || !['ngrok.app', 'ngrok.dev', 'ngrok.io', 'ngrok.pizza', 'ngrok-free.app', 'ngrok-free.dev', 'ngrok-free.pizza'].exists(s, self.domain.lowerAscii().endsWith('.' + s))",message="certificateRef is not supported for ngrok-managed domains"
 type DomainSpec struct {
 	// Description is a human-readable description of the object in the ngrok API/Dashboard
 	// +kubebuilder:default:=`Created by ngrok-operator`
@@ -73,6 +74,22 @@ type DomainSpec struct {
 	// +kubebuilder:validation:Enum=Delete;Retain
 	// +kubebuilder:default=Delete
 	ReclaimPolicy DomainReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// CertificateRef references a kubernetes.io/tls Secret in the same namespace
+	// as the Domain. When set, the certificate and private key are uploaded to
+	// ngrok and served for the domain instead of an ngrok-managed certificate.
+	// The Secret is watched, and a renewed certificate is uploaded and attached
+	// automatically. Not supported for ngrok-managed domains.
+	// +kubebuilder:validation:Optional
+	CertificateRef *DomainCertificateRef `json:"certificateRef,omitempty"`
+}
+
+// DomainCertificateRef references a Secret holding a TLS certificate for a Domain
+type DomainCertificateRef struct {
+	// Name of the Secret. It must contain the tls.crt and tls.key keys.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 }
 
 // GetResolvesTo returns ResolvesTo if set, falling back to the deprecated
@@ -133,6 +150,22 @@ type DomainStatus struct {
 type DomainStatusCertificateInfo struct {
 	// ID is the certificate ID
 	ID string `json:"id"`
+	// SecretName is the name of the Secret the certificate was uploaded from.
+	// Empty when the certificate is managed by ngrok.
+	SecretName string `json:"secretName,omitempty"`
+	// Fingerprint is the SHA-256 fingerprint of the uploaded certificate, used
+	// to detect when the Secret has been renewed
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// NotBefore is when the uploaded certificate becomes valid
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// NotAfter is when the uploaded certificate expires
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// IsUploaded returns true if the certificate was uploaded from a Secret rather
+// than provisioned by ngrok
+func (c *DomainStatusCertificateInfo) IsUploaded() bool {
+	return c != nil && c.SecretName != ""
 }
 
 // DomainStatusCertificateManagementPolicy contains the certificate management configuration
@@ -176,6 +209,7 @@ type DomainStatusProvisioningJob struct {
 // +kubebuilder:printcolumn:name="Reclaim Policy",type=string,JSONPath=`.spec.reclaimPolicy`,description="Reclaim Policy"
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Domain Ready"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+// +kubebuilder:printcolumn:name="Certificate Expires",type=date,JSONPath=`.status.certificate.notAfter`,description="Uploaded Certificate Expiry",priority=2
 // +kubebuilder:printcolumn:name="CNAME Target",type=string,JSONPath=`.status.cnameTarget`,description="CNAME Target",priority=2
 // +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].reason`,description="Ready Reason",priority=1
 // +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].message`,description="Ready Message",priority=1
@@ -189,6 +223,11 @@ type Domain struct {
 	Status DomainStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns a pointer to the conditions slice for Domain
+func (d *Domain) GetConditions() *[]metav1.Condition {
+	return &d.Status.Conditions
+}
+
 // SetObservedGeneration records the generation the controller reconciled.
 func (d *Domain) SetObservedGeneration(generation int64) {
 	d.Status.ObservedGeneration = generation
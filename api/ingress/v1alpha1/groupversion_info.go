@@ -50,6 +50,8 @@ func addKnownTypes(s *runtime.Scheme) error {
 		&DomainList{},
 		&IPPolicy{},
 		&IPPolicyList{},
+		&TCPAddress{},
+		&TCPAddressList{},
 	)
 	metav1.AddToGroupVersion(s, GroupVersion)
 	return nil
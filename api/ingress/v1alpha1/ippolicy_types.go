@@ -71,6 +71,66 @@ type IPPolicySpec struct {
 	Metadata json.RawMessage `json:"metadata,omitempty"`
 	// Rules is a list of rules that belong to the policy
 	Rules []IPPolicyRule `json:"rules,omitempty"`
+	// Sources compute additional rules for the policy from live Kubernetes
+	// resources. The rules are kept in sync as the sources change. A CIDR that
+	// is already in Rules or an earlier source is not added again.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Sources []IPPolicyRuleSource `json:"sources,omitempty"`
+}
+
+// IPPolicyRuleSource computes rules for the policy from a live Kubernetes
+// source. Exactly one of nodes, configMap or services must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.nodes), has(self.configMap), has(self.services)].exists_one(x, x)",message="exactly one of nodes, configMap or services must be set on a source"
+type IPPolicyRuleSource struct {
+	// Name identifies the source in the status of the policy and in the
+	// metadata of the rules computed from it
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Action is the action of every rule computed from the source
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=allow;deny
+	Action string `json:"action"`
+	// Nodes adds a rule for the external IPs of the matching Nodes
+	// +optional
+	Nodes *IPPolicyNodesSource `json:"nodes,omitempty"`
+	// ConfigMap adds a rule for each CIDR listed under a key of a ConfigMap in
+	// the namespace of the policy
+	// +optional
+	ConfigMap *IPPolicyConfigMapSource `json:"configMap,omitempty"`
+	// Services adds a rule for the load balancer and external IPs of the
+	// matching Services in the namespace of the policy
+	// +optional
+	Services *IPPolicyServicesSource `json:"services,omitempty"`
+}
+
+type IPPolicyNodesSource struct {
+	// Selector selects the Nodes whose ExternalIP addresses are added. All
+	// Nodes are selected when it is not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+type IPPolicyConfigMapSource struct {
+	// Name is the name of the ConfigMap
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Key is the key of the ConfigMap listing the CIDRs, separated by
+	// whitespace or commas. Text following a # is ignored, and a bare IP
+	// address is treated as a range of a single address.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+type IPPolicyServicesSource struct {
+	// Selector selects the Services whose load balancer ingress IPs and
+	// external IPs are added, such as the Services fronting egress gateways
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
 }
 
 // IPPolicyStatus defines the observed state of IPPolicy
@@ -88,6 +148,25 @@ type IPPolicyStatus struct {
 	// +listMapKey=type
 	// +kubebuilder:validation:MaxItems=8
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Sources reports the rules computed from each of spec.sources
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Sources []IPPolicySourceStatus `json:"sources,omitempty"`
+}
+
+// IPPolicySourceStatus is the observed state of a source of an IP policy
+type IPPolicySourceStatus struct {
+	// Name is the name of the source
+	Name string `json:"name"`
+	// RuleCount is the number of rules from the source that are applied to
+	// the policy
+	RuleCount int32 `json:"ruleCount"`
+	// Message explains why some or all of the source's CIDRs are not applied,
+	// such as a missing ConfigMap or invalid entries
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -107,6 +186,11 @@ type IPPolicy struct {
 	Status IPPolicyStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns a pointer to the conditions slice for IPPolicy
+func (p *IPPolicy) GetConditions() *[]metav1.Condition {
+	return &p.Status.Conditions
+}
+
 // SetObservedGeneration records the generation the controller reconciled.
 func (p *IPPolicy) SetObservedGeneration(generation int64) {
 	p.Status.ObservedGeneration = generation
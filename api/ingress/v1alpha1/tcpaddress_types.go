@@ -0,0 +1,145 @@
+/*
+MIT License
+
+Copyright (c) 2022 ngrok, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TCPAddressReclaimPolicy string
+
+const (
+	TCPAddressReclaimPolicyDelete TCPAddressReclaimPolicy = "Delete"
+	TCPAddressReclaimPolicyRetain TCPAddressReclaimPolicy = "Retain"
+)
+
+// TCPAddressSpec defines the desired state of TCPAddress
+// +kubebuilder:validation:XValidation:rule="has(self.address) == has(oldSelf.address)",message="address cannot be added or removed"
+type TCPAddressSpec struct {
+	// Description is a human-readable description of the object in the ngrok API/Dashboard
+	// +kubebuilder:default:=`Created by ngrok-operator`
+	// +kubebuilder:validation:MaxLength=255
+	Description string `json:"description,omitempty"`
+	// Metadata is arbitrary key/value data associated with the object in the
+	// ngrok API/Dashboard. A raw JSON string is also accepted for backward
+	// compatibility and is deprecated; use a map of string values instead.
+	// The ngrokMetadata Helm value is not merged into this field.
+	//
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:default:=`{"owned-by":"ngrok-operator"}`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+
+	// Address is an existing reserved TCP address in host:port form, such as
+	// 1.tcp.ngrok.io:12345, to adopt instead of reserving a new one. The
+	// reservation must already exist in the ngrok account.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9.-]+:[0-9]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="address is immutable"
+	Address string `json:"address,omitempty"`
+
+	// Region is the ngrok region to reserve a new address in, such as us or eu.
+	// Defaults to the account's default region. Ignored when adopting an existing address.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
+	Region string `json:"region,omitempty"`
+
+	// ReclaimPolicy is the policy to use when the TCPAddress is deleted. Retain
+	// keeps the reservation in the ngrok account so it can be adopted again.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	ReclaimPolicy TCPAddressReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// TCPAddressStatus defines the observed state of TCPAddress
+type TCPAddressStatus struct {
+	// ObservedGeneration is the most recent metadata.generation observed by the
+	// controller. When it matches metadata.generation, the status reflects the
+	// latest spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ID is the unique identifier of the reserved address
+	ID string `json:"id,omitempty"`
+
+	// Address is the reserved address in host:port form
+	Address string `json:"address,omitempty"`
+
+	// Region is the ngrok region the address is reserved in
+	Region string `json:"region,omitempty"`
+
+	// Conditions represent the latest available observations of the TCP address's state
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tcpaddr
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`,description="Reserved Address ID"
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.status.address`,description="Address"
+// +kubebuilder:printcolumn:name="Reclaim Policy",type=string,JSONPath=`.spec.reclaimPolicy`,description="Reclaim Policy"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="TCPAddress Ready"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+// +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.status.region`,description="Region",priority=2
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].reason`,description="Ready Reason",priority=1
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].message`,description="Ready Message",priority=1
+
+// TCPAddress is the Schema for the tcpaddresses API. It reserves a TCP
+// address, such as 1.tcp.ngrok.io:12345, that tcp:// endpoints can listen on.
+type TCPAddress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TCPAddressSpec   `json:"spec,omitempty"`
+	Status TCPAddressStatus `json:"status,omitempty"`
+}
+
+// SetObservedGeneration records the generation the controller reconciled.
+func (a *TCPAddress) SetObservedGeneration(generation int64) {
+	a.Status.ObservedGeneration = generation
+}
+
+// URL returns the tcp:// URL of the reserved address, or an empty string if
+// the address has not been reserved yet
+func (a *TCPAddress) URL() string {
+	if a.Status.Address == "" {
+		return ""
+	}
+	return "tcp://" + a.Status.Address
+}
+
+// +kubebuilder:object:root=true
+
+// TCPAddressList contains a list of TCPAddress
+type TCPAddressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TCPAddress `json:"items"`
+}
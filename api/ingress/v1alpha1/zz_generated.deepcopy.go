@@ -61,6 +61,21 @@ func (in *Domain) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainCertificateRef) DeepCopyInto(out *DomainCertificateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainCertificateRef.
+func (in *DomainCertificateRef) DeepCopy() *DomainCertificateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainCertificateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainList) DeepCopyInto(out *DomainList) {
 	*out = *in
@@ -130,6 +145,11 @@ func (in *DomainSpec) DeepCopyInto(out *DomainSpec) {
 			copy(*out, *in)
 		}
 	}
+	if in.CertificateRef != nil {
+		in, out := &in.CertificateRef, &out.CertificateRef
+		*out = new(DomainCertificateRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainSpec.
@@ -163,7 +183,7 @@ func (in *DomainStatus) DeepCopyInto(out *DomainStatus) {
 	if in.Certificate != nil {
 		in, out := &in.Certificate, &out.Certificate
 		*out = new(DomainStatusCertificateInfo)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.CertificateManagementPolicy != nil {
 		in, out := &in.CertificateManagementPolicy, &out.CertificateManagementPolicy
@@ -197,6 +217,14 @@ func (in *DomainStatus) DeepCopy() *DomainStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainStatusCertificateInfo) DeepCopyInto(out *DomainStatusCertificateInfo) {
 	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainStatusCertificateInfo.
@@ -379,6 +407,21 @@ func (in *IPPolicy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPolicyConfigMapSource) DeepCopyInto(out *IPPolicyConfigMapSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicyConfigMapSource.
+func (in *IPPolicyConfigMapSource) DeepCopy() *IPPolicyConfigMapSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPolicyConfigMapSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPolicyList) DeepCopyInto(out *IPPolicyList) {
 	*out = *in
@@ -411,6 +454,26 @@ func (in *IPPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPolicyNodesSource) DeepCopyInto(out *IPPolicyNodesSource) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicyNodesSource.
+func (in *IPPolicyNodesSource) DeepCopy() *IPPolicyNodesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPolicyNodesSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPolicyRule) DeepCopyInto(out *IPPolicyRule) {
 	*out = *in
@@ -431,6 +494,67 @@ func (in *IPPolicyRule) DeepCopy() *IPPolicyRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPolicyRuleSource) DeepCopyInto(out *IPPolicyRuleSource) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(IPPolicyNodesSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(IPPolicyConfigMapSource)
+		**out = **in
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(IPPolicyServicesSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicyRuleSource.
+func (in *IPPolicyRuleSource) DeepCopy() *IPPolicyRuleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPolicyRuleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPolicyServicesSource) DeepCopyInto(out *IPPolicyServicesSource) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicyServicesSource.
+func (in *IPPolicyServicesSource) DeepCopy() *IPPolicyServicesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPolicyServicesSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPolicySourceStatus) DeepCopyInto(out *IPPolicySourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicySourceStatus.
+func (in *IPPolicySourceStatus) DeepCopy() *IPPolicySourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPolicySourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPolicySpec) DeepCopyInto(out *IPPolicySpec) {
 	*out = *in
@@ -446,6 +570,13 @@ func (in *IPPolicySpec) DeepCopyInto(out *IPPolicySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]IPPolicyRuleSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicySpec.
@@ -468,6 +599,11 @@ func (in *IPPolicyStatus) DeepCopyInto(out *IPPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]IPPolicySourceStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPolicyStatus.
@@ -479,3 +615,104 @@ func (in *IPPolicyStatus) DeepCopy() *IPPolicyStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPAddress) DeepCopyInto(out *TCPAddress) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPAddress.
+func (in *TCPAddress) DeepCopy() *TCPAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TCPAddress) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPAddressList) DeepCopyInto(out *TCPAddressList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TCPAddress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPAddressList.
+func (in *TCPAddressList) DeepCopy() *TCPAddressList {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPAddressList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TCPAddressList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPAddressSpec) DeepCopyInto(out *TCPAddressSpec) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(json.RawMessage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPAddressSpec.
+func (in *TCPAddressSpec) DeepCopy() *TCPAddressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPAddressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPAddressStatus) DeepCopyInto(out *TCPAddressStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPAddressStatus.
+func (in *TCPAddressStatus) DeepCopy() *TCPAddressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPAddressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
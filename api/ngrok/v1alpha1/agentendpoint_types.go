@@ -41,10 +41,14 @@ import (
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
 // +kubebuilder:printcolumn:name="Upstream URL",type="string",JSONPath=".spec.upstream.url"
 // +kubebuilder:printcolumn:name="Bindings",type="string",JSONPath=".spec.bindings"
+// +kubebuilder:printcolumn:name="Agents",type="integer",JSONPath=".status.api.poolSize"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",priority=1
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Regions",type="string",JSONPath=".status.api.regions",priority=1
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.api.updatedAt",priority=1
+// +kubebuilder:printcolumn:name="Active Replica",type="string",JSONPath=".status.activeReplica",priority=1
 type AgentEndpoint struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -151,6 +155,16 @@ type AgentEndpointSpec struct {
 	// +kubebuilder:validation:items:Pattern=`^(public|internal|kubernetes)$`
 	Bindings []string `json:"bindings,omitempty"`
 
+	// Controls whether or not the Agent Endpoint should allow pooling with other
+	// Agent Endpoints sharing the same URL, such as the ones started by the other
+	// agent-manager replicas. When Agent Endpoints are pooled, any requests going
+	// to the URL will be distributed among all endpoints in the pool. When false,
+	// the endpoint must be the only one with its URL, so only one agent can start it.
+	// Defaults to true.
+	//
+	// +kubebuilder:validation:Optional
+	PoolingEnabled *bool `json:"poolingEnabled,omitempty"`
+
 	// List of references to kubernetes.io/tls Secrets containing the client
 	// certificates to present to the upstream when performing a TLS handshake.
 	// Each Secret must contain the certificate under the `tls.crt` key and its
@@ -171,6 +185,11 @@ type AgentEndpointSpec struct {
 	//
 	// +kubebuilder:validation:Optional
 	TLSTermination *EndpointTLSTermination `json:"tlsTermination,omitempty"`
+
+	// Schedule limits when the endpoint is online. The endpoint is always online when unset.
+	//
+	// +kubebuilder:validation:Optional
+	Schedule *EndpointSchedule `json:"schedule,omitempty"`
 }
 
 // EndpointTLSTermination configures agent-side ("zero-knowledge") TLS termination.
@@ -272,6 +291,17 @@ type AgentEndpointStatus struct {
 	// +nullable
 	DomainRef *K8sObjectRefOptionalNamespace `json:"domainRef,omitempty"`
 
+	// ActiveReplica is the agent-manager replica serving the endpoint when it doesn't allow pooling and the
+	// agent-manager runs in primary/standby mode. It is empty when every replica serves the endpoint.
+	// +kubebuilder:validation:Optional
+	ActiveReplica string `json:"activeReplica,omitempty"`
+
+	// API is what the ngrok API reports about the endpoints serving the assigned URL. It is refreshed
+	// periodically and is nil when the endpoint has no assigned URL.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	API *EndpointAPIStatus `json:"api,omitempty"`
+
 	// Conditions describe the current conditions of the AgentEndpoint.
 	//
 	// +listType=map
@@ -90,6 +90,11 @@ type CloudEndpointSpec struct {
 	// +kubebuilder:validation:MaxItems=1
 	// +kubebuilder:validation:items:Pattern=`^(public|internal|kubernetes)$`
 	Bindings []string `json:"bindings,omitempty"`
+
+	// Schedule limits when the endpoint is online. The endpoint is always online when unset.
+	//
+	// +kubebuilder:validation:Optional
+	Schedule *EndpointSchedule `json:"schedule,omitempty"`
 }
 
 // CloudEndpointStatus defines the observed state of CloudEndpoint
@@ -113,6 +118,12 @@ type CloudEndpointStatus struct {
 	// +nullable
 	DomainRef *K8sObjectRefOptionalNamespace `json:"domainRef,omitempty"`
 
+	// API is what the ngrok API reports about the endpoints serving the assigned URL. It is refreshed
+	// periodically and is nil when the endpoint has no assigned URL.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	API *EndpointAPIStatus `json:"api,omitempty"`
+
 	// Conditions describe the current conditions of the CloudEndpoint.
 	//
 	// +listType=map
@@ -130,10 +141,13 @@ type CloudEndpointStatus struct {
 // +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
 // +kubebuilder:printcolumn:name="Bindings",type="string",JSONPath=".spec.bindings"
+// +kubebuilder:printcolumn:name="Pool",type="integer",JSONPath=".status.api.poolSize"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",priority=1
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1
+// +kubebuilder:printcolumn:name="Regions",type="string",JSONPath=".status.api.regions",priority=1
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.api.updatedAt",priority=1
 type CloudEndpoint struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
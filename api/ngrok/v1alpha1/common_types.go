@@ -46,6 +46,67 @@ type K8sObjectRefOptionalNamespace struct {
 	Namespace *string `json:"namespace,omitempty"`
 }
 
+// EndpointSchedule limits when an endpoint is online. Outside of its active windows the endpoint is removed from
+// ngrok and the resource has an Expired condition.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.cron) || has(self.ttl)",message="ttl is required when cron is set"
+type EndpointSchedule struct {
+	// NotBefore is the time the endpoint is first brought online. Defaults to when the resource is created.
+	//
+	// +kubebuilder:validation:Optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// NotAfter is the time the endpoint is taken offline for good
+	//
+	// +kubebuilder:validation:Optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// TTL is how long the endpoint stays online each time it is brought online. Without a cron schedule, the
+	// endpoint is brought online once, at notBefore.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Cron brings the endpoint online again on a schedule, for ttl each time. It is a standard five field cron
+	// expression, such as "0 9 * * 1-5" for 9:00 on weekdays.
+	//
+	// +kubebuilder:validation:Optional
+	Cron string `json:"cron,omitempty"`
+
+	// TimeZone is the IANA time zone the cron schedule is evaluated in. Defaults to UTC.
+	//
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// EndpointAPIStatus is what the ngrok API reports about the endpoints serving the URL of a resource
+type EndpointAPIStatus struct {
+	// PoolSize is the number of endpoints serving the URL. For an AgentEndpoint, it is the number of agents that
+	// started the endpoint; for a pooled CloudEndpoint, the number of CloudEndpoints in the pool.
+	PoolSize int32 `json:"poolSize"`
+
+	// Regions are the ngrok regions the endpoints are served from
+	//
+	// +kubebuilder:validation:Optional
+	Regions []string `json:"regions,omitempty"`
+
+	// Principal is the ID of the user or bot user that owns the endpoints
+	//
+	// +kubebuilder:validation:Optional
+	Principal string `json:"principal,omitempty"`
+
+	// CreatedAt is when the oldest of the endpoints was created
+	//
+	// +kubebuilder:validation:Optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// UpdatedAt is when any of the endpoints was last updated
+	//
+	// +kubebuilder:validation:Optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
 // +kubebuilder:object:generate=false
 // EndpointWithDomain represents an endpoint resource that has domain conditions and references
 type EndpointWithDomain interface {
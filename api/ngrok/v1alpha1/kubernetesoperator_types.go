@@ -27,6 +27,7 @@ package v1alpha1
 import (
 	"encoding/json"
 
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -98,12 +99,38 @@ type KubernetesOperatorStatus struct {
 	// Drain reports the progress of the drain triggered by deleting this resource
 	// +optional
 	Drain *KubernetesOperatorDrainStatus `json:"drain,omitempty"`
+
+	// EffectiveConfig is the configuration the operator's managers are using, after
+	// applying the defaults from their flags to spec.config
+	// +optional
+	EffectiveConfig *KubernetesOperatorEffectiveConfig `json:"effectiveConfig,omitempty"`
+}
+
+// KubernetesOperatorEffectiveConfig reports the configuration in effect for the operator's managers
+type KubernetesOperatorEffectiveConfig struct {
+	// DefaultDomainReclaimPolicy is the reclaim policy of the Domains the operator creates
+	// +optional
+	DefaultDomainReclaimPolicy ingressv1alpha1.DomainReclaimPolicy `json:"defaultDomainReclaimPolicy,omitempty"`
+
+	// NgrokMetadata is the custom metadata added to the ngrok API resources the operator creates
+	// +optional
+	NgrokMetadata map[string]string `json:"ngrokMetadata,omitempty"`
+
+	// EndpointSelectors are the endpoint selectors of the bindings feature, from spec.config
+	// or spec.binding
+	// +optional
+	EndpointSelectors []string `json:"endpointSelectors,omitempty"`
+
+	// DrainPolicy is the policy the drain uses, from spec.config or spec.drain
+	// +optional
+	DrainPolicy DrainPolicy `json:"drainPolicy,omitempty"`
 }
 
 // KubernetesOperatorDrainStatus reports drain progress while the operator is
 // cleaning up the resources it manages during deletion
 type KubernetesOperatorDrainStatus struct {
-	// DrainedResources is the number of resources successfully drained in the latest attempt
+	// DrainedResources is the number of resources successfully drained so far,
+	// including those drained by earlier attempts
 	DrainedResources int `json:"drainedResources"`
 
 	// FailedResources is the number of resources that could not be drained in the latest attempt
@@ -117,8 +144,70 @@ type KubernetesOperatorDrainStatus struct {
 	// +kubebuilder:validation:MaxItems=20
 	// +kubebuilder:validation:items:MaxLength=1024
 	Errors []string `json:"errors,omitempty"`
+
+	// DryRun is true when this status is a plan published because
+	// spec.drain.dryRun is set, rather than the progress of an actual drain
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Resources records the action and outcome for each resource the drain
+	// processes, in drain order. Resources drained by earlier attempts are
+	// carried forward so a retried drain only processes what is left.
+	// +optional
+	// +kubebuilder:validation:MaxItems=500
+	Resources []KubernetesOperatorDrainResource `json:"resources,omitempty"`
+}
+
+// KubernetesOperatorDrainResource reports how the drain handles a single resource
+type KubernetesOperatorDrainResource struct {
+	// Kind is the kind of the resource, e.g. CloudEndpoint
+	Kind string `json:"kind"`
+
+	// Namespace of the resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Action is what the drain does to the resource
+	Action DrainAction `json:"action"`
+
+	// State is the outcome of the action
+	State DrainResourceState `json:"state"`
+
+	// Message describes why the action failed
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message,omitempty"`
 }
 
+// DrainAction is the action the drain takes for a resource
+// +kubebuilder:validation:Enum=Delete;Retain;RemoveFinalizer
+type DrainAction string
+
+const (
+	// DrainActionDelete deletes an operator resource so its controller removes it from the ngrok API
+	DrainActionDelete DrainAction = "Delete"
+	// DrainActionRetain removes the finalizer from an operator resource, preserving it in the ngrok API
+	DrainActionRetain DrainAction = "Retain"
+	// DrainActionRemoveFinalizer removes the finalizer from a user resource
+	DrainActionRemoveFinalizer DrainAction = "RemoveFinalizer"
+)
+
+// DrainResourceState is the outcome of a drain action for a resource
+// +kubebuilder:validation:Enum=Planned;Drained;Failed
+type DrainResourceState string
+
+const (
+	// DrainResourceStatePlanned means the action would be taken by a drain, reported by a dry-run
+	DrainResourceStatePlanned DrainResourceState = "Planned"
+	// DrainResourceStateDrained means the action completed
+	DrainResourceStateDrained DrainResourceState = "Drained"
+	// DrainResourceStateFailed means the action failed and is retried by the next drain attempt
+	DrainResourceStateFailed DrainResourceState = "Failed"
+)
+
 // Condition types for KubernetesOperator. The condition type and reason string
 // values are part of the public API contract — tooling like kubectl wait,
 // Argo CD and Flux health checks depend on them.
@@ -201,6 +290,39 @@ type KubernetesOperatorSpec struct {
 
 	// Drain configures the drain behavior for uninstall
 	Drain *DrainConfig `json:"drain,omitempty"`
+
+	// Config holds settings that the operator's managers reload when they change,
+	// without restarting. Unset fields default to the managers' flags.
+	// +optional
+	Config *KubernetesOperatorConfig `json:"config,omitempty"`
+}
+
+// KubernetesOperatorConfig holds the settings of the operator's managers that can be
+// changed on the KubernetesOperator at runtime
+type KubernetesOperatorConfig struct {
+	// DefaultDomainReclaimPolicy is the reclaim policy of the Domains the operator
+	// creates. Domains that already exist keep their policy. Defaults to the
+	// --default-domain-reclaim-policy flag.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DefaultDomainReclaimPolicy ingressv1alpha1.DomainReclaimPolicy `json:"defaultDomainReclaimPolicy,omitempty"`
+
+	// NgrokMetadata is the custom metadata added to the ngrok API resources created
+	// for Ingresses and Gateways. Defaults to the --ngrokMetadata flag.
+	// +optional
+	NgrokMetadata map[string]string `json:"ngrokMetadata,omitempty"`
+
+	// DrainPolicy is the policy of the next drain. Defaults to spec.drain.policy,
+	// which follows the --drain-policy flag.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DrainPolicy DrainPolicy `json:"drainPolicy,omitempty"`
+
+	// EndpointSelectors are the endpoint selectors of the bindings feature. Defaults
+	// to spec.binding.endpointSelectors, which follows the --bindings-endpoint-selectors
+	// flag.
+	// +optional
+	EndpointSelectors []string `json:"endpointSelectors,omitempty"`
 }
 
 // DrainConfig configures the drain behavior during operator uninstall
@@ -208,6 +330,12 @@ type DrainConfig struct {
 	// Policy determines whether to delete ngrok API resources or just remove finalizers
 	// +kubebuilder:default=Retain
 	Policy DrainPolicy `json:"policy,omitempty"`
+
+	// DryRun publishes the drain plan for the current policy in status.drain
+	// without changing any resources. Deleting the KubernetesOperator always
+	// runs the real drain, regardless of this setting.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -240,14 +368,35 @@ type KubernetesOperatorList struct {
 	Items           []KubernetesOperator `json:"items"`
 }
 
-// GetDrainPolicy returns the configured drain policy, defaulting to Retain if not set.
+// IsDrainDryRun reports whether a drain plan should be published without draining.
+func (ko *KubernetesOperator) IsDrainDryRun() bool {
+	return ko.Spec.Drain != nil && ko.Spec.Drain.DryRun
+}
+
+// GetDrainPolicy returns the configured drain policy, preferring spec.config over spec.drain and defaulting to
+// Retain if neither sets it.
 func (ko *KubernetesOperator) GetDrainPolicy() DrainPolicy {
+	if ko.Spec.Config != nil && ko.Spec.Config.DrainPolicy != "" {
+		return ko.Spec.Config.DrainPolicy
+	}
 	if ko.Spec.Drain != nil && ko.Spec.Drain.Policy != "" {
 		return ko.Spec.Drain.Policy
 	}
 	return DrainPolicyRetain
 }
 
+// GetEndpointSelectors returns the endpoint selectors of the bindings feature, preferring spec.config over
+// spec.binding.
+func (ko *KubernetesOperator) GetEndpointSelectors() []string {
+	if ko.Spec.Config != nil && len(ko.Spec.Config.EndpointSelectors) > 0 {
+		return ko.Spec.Config.EndpointSelectors
+	}
+	if ko.Spec.Binding != nil {
+		return ko.Spec.Binding.EndpointSelectors
+	}
+	return nil
+}
+
 // IsDrainComplete reports whether the drain triggered by deleting this resource
 // has finished (the Draining condition is False with reason DrainCompleted).
 // This only checks condition status, not reason: it relies on the invariant
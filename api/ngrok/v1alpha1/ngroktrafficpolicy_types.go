@@ -58,11 +58,79 @@ type NgrokTrafficPolicyStatus struct {
 	// +listMapKey=type
 	// +kubebuilder:validation:MaxItems=8
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ConsumerCounts summarizes how many resources reference this policy and
+	// how many of them report it as applied.
+	// +optional
+	ConsumerCounts NgrokTrafficPolicyConsumerCounts `json:"consumerCounts,omitempty"`
+
+	// Consumers lists the resources that reference this policy along with the
+	// result of applying it. Consumers whose policy failed to apply are listed
+	// first; when there are more consumers than fit, ConsumerCounts still
+	// reflects the full set.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	Consumers []NgrokTrafficPolicyConsumer `json:"consumers,omitempty"`
+}
+
+// NgrokTrafficPolicyConsumerCounts aggregates the apply results of every
+// resource that references a NgrokTrafficPolicy
+type NgrokTrafficPolicyConsumerCounts struct {
+	// Total is the number of resources that reference this policy, including
+	// reference-only consumers
+	Total int32 `json:"total"`
+
+	// Applied is the number of consumers reporting the policy as applied
+	Applied int32 `json:"applied"`
+
+	// Failed is the number of consumers reporting the policy failed to apply
+	Failed int32 `json:"failed"`
+
+	// Pending is the number of consumers that have not reported a result yet.
+	// Reference-only consumers are not counted.
+	Pending int32 `json:"pending"`
+}
+
+// NgrokTrafficPolicyConsumer is a resource that references a NgrokTrafficPolicy
+type NgrokTrafficPolicyConsumer struct {
+	// Kind of the referencing resource, e.g. CloudEndpoint, AgentEndpoint,
+	// Ingress, Gateway, HTTPRoute or Service
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+
+	// Name of the referencing resource
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the referencing resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Applied mirrors the consumer's TrafficPolicyApplied condition. Unknown
+	// means the consumer has not reported a result yet, or, with the
+	// ReferenceOnly reason, that the consumer (an Ingress, Gateway or
+	// HTTPRoute) merges the policy into generated endpoints and never reports
+	// a result; see the TrafficPolicyApplied conditions of those endpoints.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Applied metav1.ConditionStatus `json:"applied"`
+
+	// Reason is the reason reported by the consumer's TrafficPolicyApplied condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message reported by the consumer's TrafficPolicyApplied condition
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Consumers",type="integer",JSONPath=".status.consumerCounts.total"
+// +kubebuilder:printcolumn:name="Applied",type="integer",JSONPath=".status.consumerCounts.applied"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.consumerCounts.failed",priority=1
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
@@ -113,6 +113,11 @@ func (in *AgentEndpointSpec) DeepCopyInto(out *AgentEndpointSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PoolingEnabled != nil {
+		in, out := &in.PoolingEnabled, &out.PoolingEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ClientCertificateRefs != nil {
 		in, out := &in.ClientCertificateRefs, &out.ClientCertificateRefs
 		*out = make([]K8sObjectRef, len(*in))
@@ -123,6 +128,11 @@ func (in *AgentEndpointSpec) DeepCopyInto(out *AgentEndpointSpec) {
 		*out = new(EndpointTLSTermination)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(EndpointSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentEndpointSpec.
@@ -143,6 +153,11 @@ func (in *AgentEndpointStatus) DeepCopyInto(out *AgentEndpointStatus) {
 		*out = new(K8sObjectRefOptionalNamespace)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(EndpointAPIStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -244,6 +259,11 @@ func (in *CloudEndpointSpec) DeepCopyInto(out *CloudEndpointSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(EndpointSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEndpointSpec.
@@ -264,6 +284,11 @@ func (in *CloudEndpointStatus) DeepCopyInto(out *CloudEndpointStatus) {
 		*out = new(K8sObjectRefOptionalNamespace)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(EndpointAPIStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -344,6 +369,62 @@ func (in *EndpointMutualTLS) DeepCopy() *EndpointMutualTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointAPIStatus) DeepCopyInto(out *EndpointAPIStatus) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAPIStatus.
+func (in *EndpointAPIStatus) DeepCopy() *EndpointAPIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointAPIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointSchedule) DeepCopyInto(out *EndpointSchedule) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointSchedule.
+func (in *EndpointSchedule) DeepCopy() *EndpointSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EndpointTLSTermination) DeepCopyInto(out *EndpointTLSTermination) {
 	*out = *in
@@ -477,6 +558,33 @@ func (in *KubernetesOperatorBinding) DeepCopy() *KubernetesOperatorBinding {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesOperatorConfig) DeepCopyInto(out *KubernetesOperatorConfig) {
+	*out = *in
+	if in.NgrokMetadata != nil {
+		in, out := &in.NgrokMetadata, &out.NgrokMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EndpointSelectors != nil {
+		in, out := &in.EndpointSelectors, &out.EndpointSelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorConfig.
+func (in *KubernetesOperatorConfig) DeepCopy() *KubernetesOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubernetesOperatorDeployment) DeepCopyInto(out *KubernetesOperatorDeployment) {
 	*out = *in
@@ -492,6 +600,21 @@ func (in *KubernetesOperatorDeployment) DeepCopy() *KubernetesOperatorDeployment
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesOperatorDrainResource) DeepCopyInto(out *KubernetesOperatorDrainResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorDrainResource.
+func (in *KubernetesOperatorDrainResource) DeepCopy() *KubernetesOperatorDrainResource {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesOperatorDrainResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubernetesOperatorDrainStatus) DeepCopyInto(out *KubernetesOperatorDrainStatus) {
 	*out = *in
@@ -500,6 +623,11 @@ func (in *KubernetesOperatorDrainStatus) DeepCopyInto(out *KubernetesOperatorDra
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]KubernetesOperatorDrainResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorDrainStatus.
@@ -512,6 +640,33 @@ func (in *KubernetesOperatorDrainStatus) DeepCopy() *KubernetesOperatorDrainStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesOperatorEffectiveConfig) DeepCopyInto(out *KubernetesOperatorEffectiveConfig) {
+	*out = *in
+	if in.NgrokMetadata != nil {
+		in, out := &in.NgrokMetadata, &out.NgrokMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EndpointSelectors != nil {
+		in, out := &in.EndpointSelectors, &out.EndpointSelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorEffectiveConfig.
+func (in *KubernetesOperatorEffectiveConfig) DeepCopy() *KubernetesOperatorEffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesOperatorEffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in KubernetesOperatorEnabledFeatures) DeepCopyInto(out *KubernetesOperatorEnabledFeatures) {
 	{
@@ -591,6 +746,11 @@ func (in *KubernetesOperatorSpec) DeepCopyInto(out *KubernetesOperatorSpec) {
 		*out = new(DrainConfig)
 		**out = **in
 	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(KubernetesOperatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorSpec.
@@ -623,6 +783,11 @@ func (in *KubernetesOperatorStatus) DeepCopyInto(out *KubernetesOperatorStatus)
 		*out = new(KubernetesOperatorDrainStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(KubernetesOperatorEffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesOperatorStatus.
@@ -662,6 +827,36 @@ func (in *NgrokTrafficPolicy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NgrokTrafficPolicyConsumer) DeepCopyInto(out *NgrokTrafficPolicyConsumer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NgrokTrafficPolicyConsumer.
+func (in *NgrokTrafficPolicyConsumer) DeepCopy() *NgrokTrafficPolicyConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(NgrokTrafficPolicyConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NgrokTrafficPolicyConsumerCounts) DeepCopyInto(out *NgrokTrafficPolicyConsumerCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NgrokTrafficPolicyConsumerCounts.
+func (in *NgrokTrafficPolicyConsumerCounts) DeepCopy() *NgrokTrafficPolicyConsumerCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(NgrokTrafficPolicyConsumerCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NgrokTrafficPolicyList) DeepCopyInto(out *NgrokTrafficPolicyList) {
 	*out = *in
@@ -724,6 +919,12 @@ func (in *NgrokTrafficPolicyStatus) DeepCopyInto(out *NgrokTrafficPolicyStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.ConsumerCounts = in.ConsumerCounts
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]NgrokTrafficPolicyConsumer, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NgrokTrafficPolicyStatus.
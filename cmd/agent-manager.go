@@ -50,6 +50,7 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	"github.com/ngrok/ngrok-operator/internal/drain"
 	"github.com/ngrok/ngrok-operator/internal/healthcheck"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 	"github.com/ngrok/ngrok-operator/internal/version"
 	"github.com/ngrok/ngrok-operator/pkg/agent"
 	// +kubebuilder:scaffold:imports
@@ -89,6 +90,10 @@ type agentManagerOpts struct {
 
 	defaultDomainReclaimPolicy string
 
+	// primary/standby flags
+	primaryStandby bool
+	electionID     string
+
 	// env vars
 	namespace string
 }
@@ -123,6 +128,10 @@ func agentCmd() *cobra.Command {
 
 	c.Flags().StringVar(&opts.defaultDomainReclaimPolicy, "default-domain-reclaim-policy", string(ingressv1alpha1.DomainReclaimPolicyDelete), "The default domain reclaim policy to apply to created domains")
 
+	// primary/standby flags
+	c.Flags().BoolVar(&opts.primaryStandby, "primary-standby", false, "Start AgentEndpoints that don't allow pooling on the replica holding the leader lock only. The other replicas stand by to take over")
+	c.Flags().StringVar(&opts.electionID, "election-id", "ngrok-operator-agent-manager-leader", "The name of the lease that is used for holding the leader lock in primary/standby mode")
+
 	opts.zapOpts = &zap.Options{}
 	goFlagSet := flag.NewFlagSet("manager", flag.ContinueOnError)
 	opts.zapOpts.BindFlags(goFlagSet)
@@ -155,7 +164,13 @@ func runAgentController(_ context.Context, opts agentManagerOpts) error {
 		},
 		WebhookServer:          webhook.NewServer(webhook.Options{Port: 9443}),
 		HealthProbeBindAddress: opts.probeAddr,
-		LeaderElection:         false,
+		// Leader election only picks the primary in primary/standby mode. The AgentEndpoint controller runs on
+		// every replica regardless.
+		LeaderElection:          opts.primaryStandby,
+		LeaderElectionID:        opts.electionID,
+		LeaderElectionNamespace: opts.namespace,
+		// Let a standby take over right away when the primary shuts down
+		LeaderElectionReleaseOnCancel: true,
 
 		// The KubernetesOperator CR is a singleton owned by the operator and always
 		// lives in the release namespace, regardless of `watchNamespace`. Pin its
@@ -212,15 +227,43 @@ func runAgentController(_ context.Context, opts agentManagerOpts) error {
 	// Create drain state checker - controller will use this to check if draining
 	drainState := drain.NewStateChecker(mgr.GetClient(), opts.namespace, opts.releaseName)
 
+	// The flag is the default of the reclaim policy when it isn't set in the KubernetesOperator's spec.config
+	operatorConfig := operatorconfig.NewStore(operatorconfig.Defaults{
+		DefaultDomainReclaimPolicy: *defaultDomainReclaimPolicy,
+	})
+	if err := (&operatorconfig.Watcher{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("operator-config"),
+		Store:     operatorConfig,
+		Namespace: opts.namespace,
+		Name:      opts.releaseName,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to watch the operator configuration: %w", err)
+	}
+
+	var primaryStandby *agentcontroller.PrimaryStandby
+	if opts.primaryStandby {
+		identity, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("unable to get the replica identity for primary/standby mode: %w", err)
+		}
+		setupLog.Info("running in primary/standby mode", "identity", identity, "electionID", opts.electionID)
+		primaryStandby = &agentcontroller.PrimaryStandby{
+			Identity: identity,
+			Elected:  mgr.Elected(),
+		}
+	}
+
 	if err = (&agentcontroller.AgentEndpointReconciler{
-		Client:                     mgr.GetClient(),
-		Log:                        ctrl.Log.WithName("controllers").WithName("agentendpoint"),
-		Scheme:                     mgr.GetScheme(),
-		Recorder:                   mgr.GetEventRecorder("agentendpoint-controller"),
-		AgentDriver:                ad,
-		DefaultDomainReclaimPolicy: defaultDomainReclaimPolicy,
-		ControllerLabels:           labels.NewControllerLabelValues(opts.namespace, opts.managerName),
-		DrainState:                 drainState,
+		Client:           mgr.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("agentendpoint"),
+		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorder("agentendpoint-controller"),
+		AgentDriver:      ad,
+		OperatorConfig:   operatorConfig,
+		ControllerLabels: labels.NewControllerLabelValues(opts.namespace, opts.managerName),
+		DrainState:       drainState,
+		PrimaryStandby:   primaryStandby,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AgentEndpoint")
 		os.Exit(1)
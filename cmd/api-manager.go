@@ -67,7 +67,13 @@ import (
 	ngrokcontroller "github.com/ngrok/ngrok-operator/internal/controller/ngrok"
 	servicecontroller "github.com/ngrok/ngrok-operator/internal/controller/service"
 	"github.com/ngrok/ngrok-operator/internal/drain"
+	"github.com/ngrok/ngrok-operator/internal/drift"
+	"github.com/ngrok/ngrok-operator/internal/endpointstatus"
+	"github.com/ngrok/ngrok-operator/internal/gc"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
+	"github.com/ngrok/ngrok-operator/internal/periodic"
+	"github.com/ngrok/ngrok-operator/internal/resolvers"
 	"github.com/ngrok/ngrok-operator/internal/util"
 	"github.com/ngrok/ngrok-operator/internal/version"
 	"github.com/ngrok/ngrok-operator/pkg/managerdriver"
@@ -118,6 +124,15 @@ type apiManagerOpts struct {
 	enableFeatureGateway          bool
 	enableFeatureBindings         bool
 	disableGatewayReferenceGrants bool
+	ingressNginxCompatibility     bool
+	ingressSecretAnnotations      bool
+	externalDNS                   bool
+	verifyDomainDNS               bool
+
+	multiCluster struct {
+		name    string
+		members []string
+	}
 
 	bindings struct {
 		endpointSelectors  []string
@@ -134,6 +149,14 @@ type apiManagerOpts struct {
 
 	defaultDomainReclaimPolicy string
 	drainPolicy                ngrokv1alpha1.DrainPolicy
+	drainDryRun                bool
+	driftPolicy                string
+	driftScanInterval          time.Duration
+	gcInterval                 time.Duration
+	gcGracePeriod              time.Duration
+	gcDryRun                   bool
+	gcKinds                    []string
+	endpointStatusInterval     time.Duration
 }
 
 func apiCmd() *cobra.Command {
@@ -156,6 +179,12 @@ func apiCmd() *cobra.Command {
 	c.Flags().StringVar(&opts.apiURL, "api-url", "", "The base URL to use for the ngrok api")
 	c.Flags().StringVar(&opts.ingressControllerName, "ingress-controller-name", "ngrok.com/ingress-controller", "The name of the controller to use for matching ingresses classes")
 	c.Flags().StringVar(&opts.ingressWatchNamespace, "ingress-watch-namespace", "", "Namespace to watch for Kubernetes Ingress resources. Defaults to all namespaces.")
+	c.Flags().BoolVar(&opts.ingressNginxCompatibility, "ingress-nginx-compatibility", false, "When true, supported nginx.ingress.kubernetes.io annotations on Ingresses are translated into ngrok configuration")
+	c.Flags().BoolVar(&opts.ingressSecretAnnotations, "ingress-secret-annotations", false, "When true, the ngrok.com/basic-auth-secret and ngrok.com/oidc-secret annotations on Ingresses are translated. The credentials in the referenced Secrets are copied into the traffic policy of the generated endpoints")
+	c.Flags().StringVar(&opts.multiCluster.name, "multi-cluster-name", "", "Name of this cluster. When set, the endpoints of Ingress and Gateway hostnames are pooled with the other clusters in --multi-cluster-members and fail over between them")
+	c.Flags().StringSliceVar(&opts.multiCluster.members, "multi-cluster-members", nil, "Clusters serving the same hostnames, including this one, as name[:priority[:weight]]. Lower priorities are tried first, and clusters with the same priority share traffic by weight")
+	c.Flags().BoolVar(&opts.externalDNS, "external-dns", false, "When true, the CNAME records of custom domains are published as external-dns DNSEndpoint resources. Requires the DNSEndpoint CRD")
+	c.Flags().BoolVar(&opts.verifyDomainDNS, "verify-domain-dns", false, "When true, the CNAME records of custom domains are resolved from inside the cluster and reported in their DNSConfigured condition")
 	// TODO(operator-rename): Same as above, but for the manager name.
 	c.Flags().StringVar(&opts.managerName, "manager-name", "ngrok-ingress-controller-manager", "Manager name to identify unique ngrok ingress controller instances")
 	c.Flags().StringVar(&opts.clusterDomain, "cluster-domain", common.DefaultClusterDomain, "Cluster domain used in the cluster")
@@ -172,6 +201,14 @@ func apiCmd() *cobra.Command {
 	c.Flags().StringVar(&opts.bindings.ingressEndpoint, "bindings-ingress-endpoint", "", "The endpoint the bindings forwarder connects to")
 	c.Flags().StringVar(&opts.defaultDomainReclaimPolicy, "default-domain-reclaim-policy", string(ingressv1alpha1.DomainReclaimPolicyDelete), "The default domain reclaim policy to apply to created domains")
 	c.Flags().StringVar((*string)(&opts.drainPolicy), "drain-policy", string(ngrokv1alpha1.DrainPolicyRetain), "Policy for draining resources during uninstall: Delete or Retain")
+	c.Flags().BoolVar(&opts.drainDryRun, "drain-dry-run", false, "Publish the drain plan in the KubernetesOperator status without draining anything")
+	c.Flags().StringVar(&opts.driftPolicy, "drift-policy", string(drift.PolicyReport), "What to do when a Domain, IPPolicy or CloudEndpoint was changed outside of the operator: Report or Correct")
+	c.Flags().DurationVar(&opts.driftScanInterval, "drift-scan-interval", 0, "How often to check Domains, IPPolicies and CloudEndpoints for changes made outside of the operator. Drift detection is disabled when 0")
+	c.Flags().DurationVar(&opts.endpointStatusInterval, "endpoint-status-interval", 5*time.Minute, "How often to refresh the status of CloudEndpoints and AgentEndpoints from the ngrok API, such as the number of agents serving them. Disabled when 0")
+	c.Flags().DurationVar(&opts.gcInterval, "gc-interval", 0, "How often to look for ngrok API resources created by this installation that no longer have a CR. Garbage collection is disabled when 0")
+	c.Flags().DurationVar(&opts.gcGracePeriod, "gc-grace-period", time.Hour, "How long an ngrok API resource must be without a CR before it is deleted")
+	c.Flags().BoolVar(&opts.gcDryRun, "gc-dry-run", true, "Only report ngrok API resources that no longer have a CR, without deleting them")
+	c.Flags().StringSliceVar(&opts.gcKinds, "gc-kinds", gc.DefaultKinds, "Kinds of CRs whose orphaned ngrok API resources are garbage collected")
 
 	opts.zapOpts = &zap.Options{}
 	goFlagSet := flag.NewFlagSet("manager", flag.ContinueOnError)
@@ -257,6 +294,29 @@ func startOperator(ctx context.Context, opts apiManagerOpts) error {
 		}
 	}
 
+	// Publishing DNS records through external-dns requires its DNSEndpoint CRD
+	if opts.externalDNS {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(k8sConfig)
+		if err != nil {
+			return fmt.Errorf("unable to create discovery client: %w", err)
+		}
+
+		dnsEndpointCRDInstalled := false
+		resourceList, err := discoveryClient.ServerResourcesForGroupVersion(ingresscontroller.DNSEndpointGVK.GroupVersion().String())
+		if err == nil {
+			for _, r := range resourceList.APIResources {
+				if r.Kind == ingresscontroller.DNSEndpointGVK.Kind {
+					dnsEndpointCRDInstalled = true
+					break
+				}
+			}
+		}
+		if !dnsEndpointCRDInstalled {
+			setupLog.Info("external-dns DNSEndpoint CRD not detected, DNS records for custom domains will not be published. Install the DNSEndpoint CRD and enable the crd source in external-dns to use this feature")
+			opts.externalDNS = false
+		}
+	}
+
 	var ok bool
 	opts.namespace, ok = os.LookupEnv("POD_NAMESPACE")
 	if !ok {
@@ -330,6 +390,29 @@ func runNormalMode(ctx context.Context, opts apiManagerOpts, k8sClient client.Cl
 		return err
 	}
 
+	customNgrokMetadata := map[string]string{}
+	if opts.ngrokMetadata != "" {
+		customNgrokMetadata, err = util.ParseHelmDictionary(opts.ngrokMetadata)
+		if err != nil {
+			return fmt.Errorf("unable to parse ngrokMetadata: %w", err)
+		}
+	}
+
+	// The flags are the defaults of the settings that aren't set in the KubernetesOperator's spec.config
+	operatorConfig := operatorconfig.NewStore(operatorconfig.Defaults{
+		DefaultDomainReclaimPolicy: *defaultDomainReclaimPolicy,
+		NgrokMetadata:              customNgrokMetadata,
+	})
+	if err := (&operatorconfig.Watcher{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("operator-config"),
+		Store:     operatorConfig,
+		Namespace: opts.namespace,
+		Name:      opts.releaseName,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to watch the operator configuration: %w", err)
+	}
+
 	ngrokClientset, err := loadNgrokClientset(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("Unable to load ngrokClientSet: %w", err)
@@ -358,15 +441,27 @@ func runNormalMode(ctx context.Context, opts apiManagerOpts, k8sClient client.Cl
 	var k8sResourceDriver *managerdriver.Driver
 	if opts.enableFeatureIngress || opts.enableFeatureGateway {
 		// we only need a driver if these features are enabled
-		k8sResourceDriver, err = getK8sResourceDriver(ctx, mgr, opts, tcpRouteCRDInstalled, tlsRouteCRDInstalled, *defaultDomainReclaimPolicy, drainState)
+		k8sResourceDriver, err = getK8sResourceDriver(ctx, mgr, opts, tcpRouteCRDInstalled, tlsRouteCRDInstalled, operatorConfig, drainState)
 		if err != nil {
 			return fmt.Errorf("unable to create Driver: %w", err)
 		}
+
+		// Recreate the resources with the new custom metadata right away, rather than on the next change to them
+		operatorConfig.OnChange(func(ctx context.Context, _ operatorconfig.Config) {
+			select {
+			case <-mgr.Elected():
+			default:
+				return
+			}
+			if err := k8sResourceDriver.Sync(ctx, mgr.GetClient()); err != nil && !errors.Is(err, managerdriver.ErrSyncRequeue) {
+				setupLog.Error(err, "unable to sync after the operator configuration changed")
+			}
+		})
 	}
 
 	if opts.enableFeatureIngress {
 		setupLog.Info("Ingress feature set enabled")
-		if err := enableIngressFeatureSet(ctx, opts, mgr, k8sResourceDriver, ngrokClientset, *defaultDomainReclaimPolicy, drainState); err != nil {
+		if err := enableIngressFeatureSet(ctx, opts, mgr, k8sResourceDriver, ngrokClientset, operatorConfig, drainState); err != nil {
 			return fmt.Errorf("unable to enable Ingress feature set: %w", err)
 		}
 	} else {
@@ -411,6 +506,7 @@ func runNormalMode(ctx context.Context, opts apiManagerOpts, k8sClient client.Cl
 		K8sOpName:         opts.releaseName,
 		NgrokClientset:    ngrokClientset,
 		DrainOrchestrator: drainOrchestrator,
+		OperatorConfig:    operatorConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubernetesOperator")
 		os.Exit(1)
@@ -508,7 +604,7 @@ func loadNgrokClientset(ctx context.Context, opts apiManagerOpts) (ngrokapi.Clie
 }
 
 // getK8sResourceDriver returns a new Driver instance that is seeded with the current state of the cluster.
-func getK8sResourceDriver(ctx context.Context, mgr manager.Manager, options apiManagerOpts, tcpRouteCRDInstalled, tlsRouteCRDInstalled bool, defaultDomainReclaimPolicy ingressv1alpha1.DomainReclaimPolicy, drainState managerdriver.DrainState) (*managerdriver.Driver, error) {
+func getK8sResourceDriver(ctx context.Context, mgr manager.Manager, options apiManagerOpts, tcpRouteCRDInstalled, tlsRouteCRDInstalled bool, operatorConfig *operatorconfig.Store, drainState managerdriver.DrainState) (*managerdriver.Driver, error) {
 	logger := mgr.GetLogger().WithName("cache-store-driver")
 
 	driverOpts := []managerdriver.DriverOpt{
@@ -516,11 +612,25 @@ func getK8sResourceDriver(ctx context.Context, mgr manager.Manager, options apiM
 		managerdriver.WithGatewayControllerName(string(gatewaycontroller.ControllerName)),
 		managerdriver.WithClusterDomain(options.clusterDomain),
 		managerdriver.WithDisableGatewayReferenceGrants(options.disableGatewayReferenceGrants),
-		managerdriver.WithDefaultDomainReclaimPolicy(defaultDomainReclaimPolicy),
+		managerdriver.WithIngressNginxCompatibility(options.ingressNginxCompatibility),
+		managerdriver.WithIngressSecretAnnotations(options.ingressSecretAnnotations),
+		managerdriver.WithOperatorConfig(operatorConfig),
 		managerdriver.WithEventRecorder(mgr.GetEventRecorder("k8s-resource-driver")),
 		managerdriver.WithDrainState(drainState),
 	}
 
+	if options.multiCluster.name != "" {
+		members, err := managerdriver.ParseClusterMembers(options.multiCluster.members)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --multi-cluster-members: %w", err)
+		}
+		multiCluster := &managerdriver.MultiCluster{ClusterName: options.multiCluster.name, Members: members}
+		if err := multiCluster.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid multi-cluster configuration: %w", err)
+		}
+		driverOpts = append(driverOpts, managerdriver.WithMultiCluster(multiCluster))
+	}
+
 	if tcpRouteCRDInstalled {
 		driverOpts = append(driverOpts, managerdriver.WithGatewayTCPRouteEnabled(true))
 	}
@@ -539,14 +649,6 @@ func getK8sResourceDriver(ctx context.Context, mgr manager.Manager, options apiM
 		},
 		driverOpts...,
 	)
-	if options.ngrokMetadata != "" {
-		customMetadata, err := util.ParseHelmDictionary(options.ngrokMetadata)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse ngrokMetadata: %w", err)
-		}
-		d.WithNgrokMetadata(customMetadata)
-	}
-
 	var seedOpts []client.ListOption
 	if options.ingressWatchNamespace != "" {
 		seedOpts = append(seedOpts, client.InNamespace(options.ingressWatchNamespace))
@@ -560,10 +662,85 @@ func getK8sResourceDriver(ctx context.Context, mgr manager.Manager, options apiM
 	return d, nil
 }
 
+// addGarbageCollector adds the collector of the orphaned ngrok API resources of this installation. The resources are
+// identified by the KubernetesOperator ID that the driver adds to the metadata of the resources it creates, or by the
+// owners and custom metadata for those created before it did.
+func addGarbageCollector(opts apiManagerOpts, mgr ctrl.Manager, ngrokClientset ngrokapi.Clientset, operatorConfig *operatorconfig.Store, drainState controller.DrainState) error {
+	if err := gc.ValidateKinds(opts.gcKinds); err != nil {
+		return err
+	}
+
+	customMetadata := map[string]string{}
+	if opts.ngrokMetadata != "" {
+		var err error
+		customMetadata, err = util.ParseHelmDictionary(opts.ngrokMetadata)
+		if err != nil {
+			return fmt.Errorf("unable to parse ngrokMetadata: %w", err)
+		}
+	}
+
+	collector := &gc.Collector{
+		Client:      mgr.GetClient(),
+		Clientset:   ngrokClientset,
+		Log:         ctrl.Log.WithName("gc"),
+		DrainState:  drainState,
+		GracePeriod: opts.gcGracePeriod,
+		DryRun:      opts.gcDryRun,
+		Kinds:       opts.gcKinds,
+		Owners:      []string{"ngrok-operator", "kubernetes-gateway-api"},
+		Metadata:    customMetadata,
+		OperatorID:  operatorConfig.OperatorID,
+		ConfigMetadata: func() map[string]string {
+			return operatorConfig.Get().NgrokMetadata
+		},
+	}
+	if err := mgr.Add(&periodic.LeaderTask{Interval: opts.gcInterval, Run: collector.Collect}); err != nil {
+		return fmt.Errorf("unable to add garbage collector: %w", err)
+	}
+	setupLog.Info("garbage collection enabled", "interval", opts.gcInterval, "gracePeriod", opts.gcGracePeriod, "dryRun", opts.gcDryRun, "kinds", opts.gcKinds)
+	return nil
+}
+
 // enableIngressFeatureSet enables the Ingress feature set for the operator
-func enableIngressFeatureSet(_ context.Context, opts apiManagerOpts, mgr ctrl.Manager, driver *managerdriver.Driver, ngrokClientset ngrokapi.Clientset, defaultDomainReclaimPolicy ingressv1alpha1.DomainReclaimPolicy, drainState controller.DrainState) error {
+func enableIngressFeatureSet(_ context.Context, opts apiManagerOpts, mgr ctrl.Manager, driver *managerdriver.Driver, ngrokClientset ngrokapi.Clientset, operatorConfig *operatorconfig.Store, drainState controller.DrainState) error {
 	controllerLabels := labels.NewControllerLabelValues(opts.namespace, opts.managerName)
 
+	var driftScanner *drift.Scanner
+	if opts.driftScanInterval > 0 {
+		driftPolicy, err := drift.ValidatePolicy(opts.driftPolicy)
+		if err != nil {
+			return err
+		}
+		driftScanner = &drift.Scanner{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("drift"),
+			Recorder: mgr.GetEventRecorder("drift-scanner"),
+			Policy:   driftPolicy,
+		}
+		if err := mgr.Add(&periodic.LeaderTask{Interval: opts.driftScanInterval, Run: driftScanner.Scan}); err != nil {
+			return fmt.Errorf("unable to add drift scanner: %w", err)
+		}
+		setupLog.Info("drift detection enabled", "interval", opts.driftScanInterval, "policy", driftPolicy)
+	}
+
+	if opts.endpointStatusInterval > 0 {
+		poller := &endpointstatus.Poller{
+			Client:    mgr.GetClient(),
+			Endpoints: ngrokClientset.Endpoints(),
+			Log:       ctrl.Log.WithName("endpoint-status"),
+		}
+		if err := mgr.Add(&periodic.LeaderTask{Interval: opts.endpointStatusInterval, Run: poller.Poll}); err != nil {
+			return fmt.Errorf("unable to add endpoint status poller: %w", err)
+		}
+		setupLog.Info("endpoint status enabled", "interval", opts.endpointStatusInterval)
+	}
+
+	if opts.gcInterval > 0 {
+		if err := addGarbageCollector(opts, mgr, ngrokClientset, operatorConfig, drainState); err != nil {
+			return err
+		}
+	}
+
 	if err := (&ingresscontroller.IngressReconciler{
 		Client:     mgr.GetClient(),
 		Log:        ctrl.Log.WithName("controllers").WithName("ingress"),
@@ -583,22 +760,29 @@ func enableIngressFeatureSet(_ context.Context, opts apiManagerOpts, mgr ctrl.Ma
 		Recorder:         mgr.GetEventRecorder("service-controller"),
 		ControllerLabels: controllerLabels,
 		ClusterDomain:    opts.clusterDomain,
-		// TODO(stacks): Once we have a way to support unqualified tcp addresses(i.e. 'tcp://') in the Cloud & Agent Endpoint CRs,
-		// we can remove this. It feels weird to have this here since the ServiceReconciler should only be performing translations
-		// and not dependent on the ngrok API.
-		TCPAddresses: ngrokClientset.TCPAddresses(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Service")
 		os.Exit(1)
 	}
 
+	// Resolving the records of custom domains from inside the cluster is opt-in, since split-horizon DNS or a CDN in
+	// front of the domain can make records that work for clients look wrong from the cluster
+	var dnsResolver resolvers.DNSResolver
+	if opts.verifyDomainDNS {
+		dnsResolver = resolvers.NewDefaultDNSResolver()
+	}
+
 	if err := (&ingresscontroller.DomainReconciler{
-		Client:        mgr.GetClient(),
-		Log:           ctrl.Log.WithName("controllers").WithName("domain"),
-		Scheme:        mgr.GetScheme(),
-		Recorder:      mgr.GetEventRecorder("domain-controller"),
-		DomainsClient: ngrokClientset.Domains(),
-		DrainState:    drainState,
+		Client:                mgr.GetClient(),
+		Log:                   ctrl.Log.WithName("controllers").WithName("domain"),
+		Scheme:                mgr.GetScheme(),
+		Recorder:              mgr.GetEventRecorder("domain-controller"),
+		DomainsClient:         ngrokClientset.Domains(),
+		TLSCertificatesClient: ngrokClientset.TLSCertificates(),
+		DrainState:            drainState,
+		ExternalDNS:           opts.externalDNS,
+		DNSResolver:           dnsResolver,
+		DriftScanner:          driftScanner,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Domain")
 		os.Exit(1)
@@ -612,31 +796,46 @@ func enableIngressFeatureSet(_ context.Context, opts apiManagerOpts, mgr ctrl.Ma
 		IPPoliciesClient:    ngrokClientset.IPPolicies(),
 		IPPolicyRulesClient: ngrokClientset.IPPolicyRules(),
 		DrainState:          drainState,
+		DriftScanner:        driftScanner,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IPPolicy")
 		os.Exit(1)
 	}
 
+	if err := (&ingresscontroller.TCPAddressReconciler{
+		Client:             mgr.GetClient(),
+		Log:                ctrl.Log.WithName("controllers").WithName("tcp-address"),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorder("tcp-address-controller"),
+		TCPAddressesClient: ngrokClientset.TCPAddresses(),
+		DrainState:         drainState,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TCPAddress")
+		os.Exit(1)
+	}
+
 	if err := (&ngrokcontroller.NgrokTrafficPolicyReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("traffic-policy"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorder("policy-controller"),
-		Driver:   driver,
+		Client:         mgr.GetClient(),
+		Log:            ctrl.Log.WithName("controllers").WithName("traffic-policy"),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorder("policy-controller"),
+		Driver:         driver,
+		GatewayEnabled: opts.enableFeatureGateway,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TrafficPolicy")
 		os.Exit(1)
 	}
 
 	if err := (&ngrokcontroller.CloudEndpointReconciler{
-		Client:                     mgr.GetClient(),
-		Log:                        ctrl.Log.WithName("controllers").WithName("cloud-endpoint"),
-		Scheme:                     mgr.GetScheme(),
-		Recorder:                   mgr.GetEventRecorder("cloud-endpoint-controller"),
-		NgrokClientset:             ngrokClientset,
-		DefaultDomainReclaimPolicy: new(defaultDomainReclaimPolicy),
-		ControllerLabels:           controllerLabels,
-		DrainState:                 drainState,
+		Client:           mgr.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("cloud-endpoint"),
+		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorder("cloud-endpoint-controller"),
+		NgrokClientset:   ngrokClientset,
+		OperatorConfig:   operatorConfig,
+		ControllerLabels: controllerLabels,
+		DrainState:       drainState,
+		DriftScanner:     driftScanner,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CloudEndpoint")
 		os.Exit(1)
@@ -789,23 +988,26 @@ func enableBindingsFeatureSet(_ context.Context, opts apiManagerOpts, mgr ctrl.M
 	return nil
 }
 
+// createKubernetesOperator creates or updates the KubernetesOperator of this installation from the flags. It never
+// touches spec.config, which holds the settings changed on the KubernetesOperator while the operator runs.
 func createKubernetesOperator(ctx context.Context, client client.Client, opts apiManagerOpts) error {
 	k8sOperator := &ngrokv1alpha1.KubernetesOperator{
 		Name:      opts.releaseName,
 		Namespace: opts.namespace,
 	}
 	_, err := controllerutil.CreateOrUpdate(ctx, client, k8sOperator, func() error {
-		k8sOperator.Spec = ngrokv1alpha1.KubernetesOperatorSpec{
-			Description: opts.description,
-			Deployment: &ngrokv1alpha1.KubernetesOperatorDeployment{
-				Name:      opts.releaseName,
-				Namespace: opts.namespace,
-				Version:   version.GetVersion(),
-			},
-			Region: opts.region,
-			Drain: &ngrokv1alpha1.DrainConfig{
-				Policy: opts.drainPolicy,
-			},
+		spec := &k8sOperator.Spec
+		spec.Description = opts.description
+		spec.Deployment = &ngrokv1alpha1.KubernetesOperatorDeployment{
+			Name:      opts.releaseName,
+			Namespace: opts.namespace,
+			Version:   version.GetVersion(),
+		}
+		spec.Region = opts.region
+
+		spec.Drain = &ngrokv1alpha1.DrainConfig{
+			Policy: opts.drainPolicy,
+			DryRun: opts.drainDryRun,
 		}
 
 		features := []string{}
@@ -819,17 +1021,21 @@ func createKubernetesOperator(ctx context.Context, client client.Client, opts ap
 
 		if opts.enableFeatureBindings {
 			features = append(features, ngrokv1alpha1.KubernetesOperatorFeatureBindings)
-			k8sOperator.Spec.Binding = &ngrokv1alpha1.KubernetesOperatorBinding{
-				TlsSecretName:     "ngrok-operator-default-tls",
-				EndpointSelectors: opts.bindings.endpointSelectors,
+			if spec.Binding == nil {
+				spec.Binding = &ngrokv1alpha1.KubernetesOperatorBinding{}
 			}
+			spec.Binding.TlsSecretName = "ngrok-operator-default-tls"
+			spec.Binding.EndpointSelectors = opts.bindings.endpointSelectors
+			spec.Binding.IngressEndpoint = nil
 			if opts.bindings.ingressEndpoint != "" {
-				k8sOperator.Spec.Binding.IngressEndpoint = &opts.bindings.ingressEndpoint
+				spec.Binding.IngressEndpoint = &opts.bindings.ingressEndpoint
 			}
+		} else {
+			spec.Binding = nil
 		}
-		k8sOperator.Spec.EnabledFeatures = features
+		spec.EnabledFeatures = features
 
-		setupLog.Info("created KubernetesOperator", "name", k8sOperator.Name, "namespace", k8sOperator.Namespace, "op", fmt.Sprintf("%+v", k8sOperator.Spec.Binding))
+		setupLog.Info("created KubernetesOperator", "name", k8sOperator.Name, "namespace", k8sOperator.Namespace, "op", fmt.Sprintf("%+v", spec.Binding))
 		return nil
 	})
 	return err
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/importer"
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd())
+}
+
+type importOpts struct {
+	apiURL         string
+	namespace      string
+	kinds          []string
+	includeManaged bool
+	apply          bool
+}
+
+func importCmd() *cobra.Command {
+	var opts importOpts
+	c := &cobra.Command{
+		Use:   "import",
+		Short: "Generate CRs that adopt existing resources in the ngrok API",
+		Long: `Lists the domains, IP policies, cloud endpoints and TCP addresses in the ngrok account and generates the
+CRs that adopt them. Each CR has the ngrok.com/adopt annotation set to the ID of the resource, so the operator takes
+ownership of it instead of creating a new one. The CRs are printed as YAML unless --apply is set.
+
+The NGROK_API_KEY environment variable must be set.`,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return runImport(c.Context(), c.OutOrStdout(), opts)
+		},
+	}
+
+	c.Flags().StringVar(&opts.apiURL, "api-url", "", "The base URL to use for the ngrok api")
+	c.Flags().StringVarP(&opts.namespace, "namespace", "n", "default", "Namespace to generate the CRs in")
+	c.Flags().StringSliceVar(&opts.kinds, "kinds", importer.AllKinds, "Kinds of CRs to generate")
+	c.Flags().BoolVar(&opts.includeManaged, "include-managed", false, "Also import resources whose metadata marks them as owned by an ngrok-operator")
+	c.Flags().BoolVar(&opts.apply, "apply", false, "Create the CRs in the current cluster instead of printing them")
+
+	return c
+}
+
+func runImport(ctx context.Context, out io.Writer, opts importOpts) error {
+	ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr)))
+
+	if err := importer.ValidateKinds(opts.kinds); err != nil {
+		return err
+	}
+
+	ngrokClientset, err := loadNgrokClientset(ctx, apiManagerOpts{apiURL: opts.apiURL})
+	if err != nil {
+		return err
+	}
+
+	objs, err := importer.New(ngrokClientset, importer.Options{
+		Namespace:      opts.namespace,
+		Kinds:          opts.kinds,
+		IncludeManaged: opts.includeManaged,
+	}).Import(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.apply {
+		return applyImportedObjects(ctx, out, objs)
+	}
+
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("marshaling %s: %w", obj.GetName(), err)
+		}
+		fmt.Fprintf(out, "---\n%s", b)
+	}
+	return nil
+}
+
+// applyImportedObjects creates the imported CRs and records the IDs of the adopted resources in their status
+func applyImportedObjects(ctx context.Context, out io.Writer, objs []client.Object) error {
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("unable to create kubernetes client: %w", err)
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		status, err := importedStatusPatch(obj)
+		if err != nil {
+			return err
+		}
+		if err := k8sClient.Create(ctx, obj); err != nil {
+			return fmt.Errorf("creating %s %s/%s: %w", gvk.Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+
+		// Status is ignored on create, so it is written separately. The ngrok.com/adopt annotation covers the
+		// case where the controller reconciles the CR before the status is written.
+		if err := k8sClient.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, status)); err != nil {
+			return fmt.Errorf("setting status of %s %s/%s: %w", gvk.Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		fmt.Fprintf(out, "%s %s/%s adopted %s\n", gvk.Kind, obj.GetNamespace(), obj.GetName(), obj.GetAnnotations()[annotations.AdoptAnnotation])
+	}
+	return nil
+}
+
+// importedStatusPatch returns a merge patch that sets the status of an imported CR
+func importedStatusPatch(obj client.Object) ([]byte, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %s: %w", obj.GetName(), err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshaling %s: %w", obj.GetName(), err)
+	}
+	return json.Marshal(map[string]json.RawMessage{"status": fields["status"]})
+}
@@ -160,6 +160,14 @@ These resources have no namespace and always require a ClusterRole, regardless o
   - get
   - list
   - watch
+- apiGroups:
+  - ""
+  resources:
+  - nodes
+  verbs:
+  - get
+  - list
+  - watch
 - apiGroups:
   - networking.k8s.io
   resources:
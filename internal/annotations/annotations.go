@@ -17,19 +17,22 @@ limitations under the License.
 package annotations
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/annotations/parser"
 	"github.com/ngrok/ngrok-operator/internal/errors"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	// ComputedURLAnnotation is the annotation key for the computed URL of an endpoint.
-	// This is temporarily used by the Service controller to store reserved TCP addresses,
-	// while we work to add support for assigning TCP addresses to Cloud/Agent Endpoints
-	// when their URL is specified as 'tcp://', for example.
+	// The Service controller records the URL of the endpoint it creates here, such as
+	// the address of the TCPAddress a TCP Service listens on, and reports it in the
+	// Service's load balancer status.
 	ComputedURLAnnotation = "ngrok.com/computed-url"
 
 	// DeniedKeyName name of the key that contains the reason to deny a location
@@ -58,6 +61,20 @@ const (
 	URLAnnotation = "ngrok.com/url"
 	URLKey        = "url"
 
+	// This annotation can be used on a TCP service to listen on the address reserved by the named
+	// TCPAddress in the same namespace. Without it, a TCPAddress named after the service is created.
+	TCPAddressAnnotation = "ngrok.com/tcp-address"
+	TCPAddressKey        = "tcp-address"
+
+	// These annotations can be used on a multi-port service to set the URL, or the TCPAddress, of the endpoint of
+	// each port. The value is a JSON object keyed by port name or number, e.g.
+	// '{"metrics":"tcp://","amqps":"tls://amqp.example.com"}'. Ports without an entry listen on the URL and TCPAddress
+	// of the url and tcp-address annotations if they are the first port, and on a TCPAddress of their own otherwise.
+	PortURLsAnnotation         = "ngrok.com/port-urls"
+	PortURLsKey                = "port-urls"
+	PortTCPAddressesAnnotation = "ngrok.com/port-tcp-addresses"
+	PortTCPAddressesKey        = "port-tcp-addresses"
+
 	// MetadataAnnotation allows setting ngrok metadata on the endpoint created from this resource.
 	// The value must be a JSON object string, e.g. '{"env":"prod","team":"platform"}'.
 	// This metadata is merged with the operator-level default metadata; keys in this annotation take precedence.
@@ -71,6 +88,18 @@ const (
 	// resource (by namespace/name) is used; if none is set, the operator default is used.
 	DescriptionAnnotation = "ngrok.com/description"
 	DescriptionKey        = "description"
+
+	// AdoptAnnotation names the ID of an existing ngrok API resource that a Domain, IPPolicy, CloudEndpoint or
+	// TCPAddress takes ownership of instead of creating a new one. It is only read until the resource has an ID in
+	// its status.
+	AdoptAnnotation = "ngrok.com/adopt"
+	AdoptKey        = "adopt"
+
+	// EndpointScheduleAnnotation gives the public endpoints created from an Ingress or Service an activation window,
+	// a TTL or a cron schedule. The value is a JSON object with the fields of the endpoints' spec.schedule, e.g.
+	// '{"cron":"0 9 * * 1-5","ttl":"8h","timeZone":"Europe/Paris"}'.
+	EndpointScheduleAnnotation = "ngrok.com/endpoint-schedule"
+	EndpointScheduleKey        = "endpoint-schedule"
 )
 
 // LEGACY-PREFIX-MIGRATION: BEGIN
@@ -160,6 +189,34 @@ func ExtractURL(obj client.Object) (string, error) {
 	return parser.GetStringAnnotation(URLKey, obj)
 }
 
+// ExtractTCPAddress extracts the name of the TCPAddress from the annotation "ngrok.com/tcp-address".
+// Returns ("", nil) if the annotation is not set.
+func ExtractTCPAddress(obj client.Object) (string, error) {
+	return optionalString(TCPAddressKey, obj)
+}
+
+// ExtractPortURLs extracts the URL of each service port from the annotation "ngrok.com/port-urls".
+// Returns (nil, nil) if the annotation is not set.
+func ExtractPortURLs(obj client.Object) (map[string]string, error) {
+	return optionalStringMap(PortURLsKey, obj)
+}
+
+// ExtractPortTCPAddresses extracts the name of the TCPAddress of each service port from the annotation
+// "ngrok.com/port-tcp-addresses". Returns (nil, nil) if the annotation is not set.
+func ExtractPortTCPAddresses(obj client.Object) (map[string]string, error) {
+	return optionalStringMap(PortTCPAddressesKey, obj)
+}
+
+// ExtractAdoptID extracts the ID of the ngrok API resource to adopt from the annotation "ngrok.com/adopt".
+// Returns ("", nil) if the annotation is not set.
+func ExtractAdoptID(obj client.Object) (string, error) {
+	val, err := optionalString(AdoptKey, obj)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(val), nil
+}
+
 // ExtractComputedURL reads the operator-written computed-url annotation.
 // During the legacy-prefix migration window it dual-reads: it prefers the new
 // `ngrok.com/computed-url` key and falls back to `k8s.ngrok.com/computed-url`
@@ -221,3 +278,23 @@ func ExtractDescription(obj client.Object) (string, error) {
 	}
 	return val, nil
 }
+
+// ExtractEndpointSchedule extracts the endpoint schedule from the annotation "ngrok.com/endpoint-schedule".
+// Returns (nil, nil) if the annotation is not set.
+func ExtractEndpointSchedule(obj client.Object) (*ngrokv1alpha1.EndpointSchedule, error) {
+	val, err := optionalString(EndpointScheduleKey, obj)
+	if err != nil || val == "" {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(val))
+	dec.DisallowUnknownFields()
+	s := &ngrokv1alpha1.EndpointSchedule{}
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", EndpointScheduleAnnotation, err)
+	}
+	if err := schedule.Validate(s); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", EndpointScheduleAnnotation, err)
+	}
+	return s, nil
+}
@@ -18,12 +18,16 @@ package annotations_test
 
 import (
 	"testing"
+	"time"
 
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/annotations"
 	"github.com/ngrok/ngrok-operator/internal/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestExtractNgrokTrafficPolicyFromAnnotations(t *testing.T) {
@@ -507,3 +511,139 @@ func TestExtractDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractTCPAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "annotation not present returns empty string",
+			annotations: nil,
+			expected:    "",
+		},
+		{
+			name:        "name is returned unchanged",
+			annotations: map[string]string{"ngrok.com/tcp-address": "my-addr"},
+			expected:    "my-addr",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.Service{
+				Name:        "test-service",
+				Namespace:   "default",
+				Annotations: tc.annotations,
+			}
+			got, err := annotations.ExtractTCPAddress(obj)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestExtractPortURLs(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			name:        "annotation not present returns nil",
+			annotations: nil,
+			expected:    nil,
+		},
+		{
+			name:        "urls keyed by port name or number",
+			annotations: map[string]string{"ngrok.com/port-urls": `{"metrics":"tcp://","5671":"tls://amqp.example.com"}`},
+			expected:    map[string]string{"metrics": "tcp://", "5671": "tls://amqp.example.com"},
+		},
+		{
+			name:        "invalid JSON returns an error",
+			annotations: map[string]string{"ngrok.com/port-urls": "metrics=tcp://"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.Service{
+				Name:        "test-service",
+				Namespace:   "default",
+				Annotations: tc.annotations,
+			}
+			got, err := annotations.ExtractPortURLs(obj)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestExtractPortTCPAddresses(t *testing.T) {
+	obj := &corev1.Service{
+		Name:        "test-service",
+		Namespace:   "default",
+		Annotations: map[string]string{"ngrok.com/port-tcp-addresses": `{"metrics":"broker-metrics"}`},
+	}
+	got, err := annotations.ExtractPortTCPAddresses(obj)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"metrics": "broker-metrics"}, got)
+}
+
+func TestExtractEndpointSchedule(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    *ngrokv1alpha1.EndpointSchedule
+		expectedErr bool
+	}{
+		{
+			name:        "annotation not present returns nil",
+			annotations: nil,
+			expected:    nil,
+		},
+		{
+			name:        "cron schedule",
+			annotations: map[string]string{"ngrok.com/endpoint-schedule": `{"cron":"0 9 * * 1-5","ttl":"8h","timeZone":"Europe/Paris"}`},
+			expected: &ngrokv1alpha1.EndpointSchedule{
+				Cron:     "0 9 * * 1-5",
+				TTL:      &metav1.Duration{Duration: 8 * time.Hour},
+				TimeZone: "Europe/Paris",
+			},
+		},
+		{
+			name:        "unknown field returns an error",
+			annotations: map[string]string{"ngrok.com/endpoint-schedule": `{"expires":"8h"}`},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid schedule returns an error",
+			annotations: map[string]string{"ngrok.com/endpoint-schedule": `{"cron":"0 9 * * 1-5"}`},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &networking.Ingress{
+				Name:        "test-ingress",
+				Namespace:   "default",
+				Annotations: tc.annotations,
+			}
+			got, err := annotations.ExtractEndpointSchedule(obj)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
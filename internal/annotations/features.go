@@ -0,0 +1,304 @@
+package annotations
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ngrok/ngrok-operator/internal/annotations/parser"
+	"github.com/ngrok/ngrok-operator/internal/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The following annotations configure common ngrok features directly on an Ingress, without writing an
+// NgrokTrafficPolicy. They are compiled into traffic policy rules that run before any rules from the
+// ngrok.com/traffic-policy annotation. When both configure the same action, the traffic policy wins.
+const (
+	// RateLimitAnnotation limits requests per client as "<capacity>/<window>", e.g. "100/1m".
+	RateLimitAnnotation = "ngrok.com/rate-limit"
+	RateLimitKey        = "rate-limit"
+	// RateLimitBucketKeyAnnotation is a comma separated list of CEL expressions used to bucket
+	// requests for rate limiting. Defaults to the client IP.
+	RateLimitBucketKeyAnnotation = "ngrok.com/rate-limit-key"
+	RateLimitBucketKeyKey        = "rate-limit-key"
+
+	// BasicAuthSecretAnnotation names a Secret in the same namespace holding basic auth credentials,
+	// either as a kubernetes.io/basic-auth Secret or as "username:password" lines under the "auth" key.
+	BasicAuthSecretAnnotation = "ngrok.com/basic-auth-secret"
+	BasicAuthSecretKey        = "basic-auth-secret"
+
+	// IPPoliciesAnnotation is a comma separated list of IPPolicy names in the same namespace that
+	// restrict which client IPs may connect.
+	IPPoliciesAnnotation = "ngrok.com/ip-policies"
+	IPPoliciesKey        = "ip-policies"
+
+	// OIDCIssuerURLAnnotation enables OpenID Connect authentication with the given issuer.
+	OIDCIssuerURLAnnotation = "ngrok.com/oidc-issuer-url"
+	OIDCIssuerURLKey        = "oidc-issuer-url"
+	// OIDCSecretAnnotation names a Secret in the same namespace with "client-id" and "client-secret" keys.
+	OIDCSecretAnnotation = "ngrok.com/oidc-secret"
+	OIDCSecretKey        = "oidc-secret"
+	// OIDCScopesAnnotation is a comma separated list of additional scopes to request.
+	OIDCScopesAnnotation = "ngrok.com/oidc-scopes"
+	OIDCScopesKey        = "oidc-scopes"
+
+	// CORSAllowOriginsAnnotation enables CORS for the comma separated list of origins, or "*".
+	CORSAllowOriginsAnnotation = "ngrok.com/cors-allow-origins"
+	CORSAllowOriginsKey        = "cors-allow-origins"
+	// CORSAllowMethodsAnnotation overrides the methods allowed in preflight responses.
+	CORSAllowMethodsAnnotation = "ngrok.com/cors-allow-methods"
+	CORSAllowMethodsKey        = "cors-allow-methods"
+	// CORSAllowHeadersAnnotation overrides the request headers allowed in preflight responses.
+	CORSAllowHeadersAnnotation = "ngrok.com/cors-allow-headers"
+	CORSAllowHeadersKey        = "cors-allow-headers"
+	// CORSAllowCredentialsAnnotation sets Access-Control-Allow-Credentials when "true".
+	CORSAllowCredentialsAnnotation = "ngrok.com/cors-allow-credentials"
+	CORSAllowCredentialsKey        = "cors-allow-credentials"
+	// CORSMaxAgeAnnotation sets how long, in seconds, browsers may cache preflight responses.
+	CORSMaxAgeAnnotation = "ngrok.com/cors-max-age"
+	CORSMaxAgeKey        = "cors-max-age"
+
+	// RequestHeadersAnnotation is a JSON object of headers to add to requests sent upstream.
+	RequestHeadersAnnotation = "ngrok.com/request-headers"
+	RequestHeadersKey        = "request-headers"
+	// ResponseHeadersAnnotation is a JSON object of headers to add to responses sent to clients.
+	ResponseHeadersAnnotation = "ngrok.com/response-headers"
+	ResponseHeadersKey        = "response-headers"
+)
+
+// Defaults used when the optional CORS annotations are not set.
+var (
+	DefaultCORSAllowMethods = []string{"GET", "PUT", "POST", "DELETE", "PATCH", "OPTIONS"}
+	DefaultCORSAllowHeaders = []string{"DNT", "Keep-Alive", "User-Agent", "X-Requested-With", "If-Modified-Since", "Cache-Control", "Content-Type", "Range", "Authorization"}
+	DefaultRateLimitKey     = []string{"conn.client_ip"}
+)
+
+// FeatureAnnotations holds the parsed values of the feature annotations on a resource. Nil fields
+// mean the feature is not configured.
+type FeatureAnnotations struct {
+	RateLimit       *RateLimit
+	BasicAuthSecret string
+	IPPolicies      []string
+	OIDC            *OIDC
+	CORS            *CORS
+	RequestHeaders  map[string]string
+	ResponseHeaders map[string]string
+}
+
+// RateLimit is parsed from the ngrok.com/rate-limit and ngrok.com/rate-limit-key annotations.
+type RateLimit struct {
+	Capacity   int
+	Window     time.Duration
+	BucketKeys []string
+}
+
+// OIDC is parsed from the ngrok.com/oidc-* annotations.
+type OIDC struct {
+	IssuerURL  string
+	SecretName string
+	Scopes     []string
+}
+
+// CORS is parsed from the ngrok.com/cors-* annotations.
+type CORS struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	AllowCredentials bool
+	MaxAge           *int
+}
+
+// IsEmpty returns true if no feature annotations are set.
+func (f *FeatureAnnotations) IsEmpty() bool {
+	return f == nil || (f.RateLimit == nil &&
+		f.BasicAuthSecret == "" &&
+		len(f.IPPolicies) == 0 &&
+		f.OIDC == nil &&
+		f.CORS == nil &&
+		len(f.RequestHeaders) == 0 &&
+		len(f.ResponseHeaders) == 0)
+}
+
+// SecretNames returns the names of the Secrets referenced by the feature annotations.
+func (f *FeatureAnnotations) SecretNames() []string {
+	if f == nil {
+		return nil
+	}
+	names := []string{}
+	if f.BasicAuthSecret != "" {
+		names = append(names, f.BasicAuthSecret)
+	}
+	if f.OIDC != nil && f.OIDC.SecretName != "" {
+		names = append(names, f.OIDC.SecretName)
+	}
+	return names
+}
+
+// ExtractFeatureAnnotations parses the feature annotations (rate limiting, basic auth, IP policies,
+// OIDC, CORS, and request/response headers) on obj. It returns nil if none are set.
+func ExtractFeatureAnnotations(obj client.Object) (*FeatureAnnotations, error) {
+	f := &FeatureAnnotations{}
+	var err error
+
+	if f.RateLimit, err = extractRateLimit(obj); err != nil {
+		return nil, err
+	}
+	if f.BasicAuthSecret, err = optionalString(BasicAuthSecretKey, obj); err != nil {
+		return nil, err
+	}
+	if f.IPPolicies, err = optionalStringSlice(IPPoliciesKey, obj); err != nil {
+		return nil, err
+	}
+	if f.OIDC, err = extractOIDC(obj); err != nil {
+		return nil, err
+	}
+	if f.CORS, err = extractCORS(obj); err != nil {
+		return nil, err
+	}
+	if f.RequestHeaders, err = optionalStringMap(RequestHeadersKey, obj); err != nil {
+		return nil, err
+	}
+	if f.ResponseHeaders, err = optionalStringMap(ResponseHeadersKey, obj); err != nil {
+		return nil, err
+	}
+
+	if f.BasicAuthSecret != "" && f.OIDC != nil {
+		return nil, errors.NewInvalidAnnotationConfiguration(BasicAuthSecretAnnotation,
+			"cannot be combined with "+OIDCIssuerURLAnnotation)
+	}
+
+	if f.IsEmpty() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func extractRateLimit(obj client.Object) (*RateLimit, error) {
+	val, err := optionalString(RateLimitKey, obj)
+	if err != nil || val == "" {
+		return nil, err
+	}
+
+	capacity, window, ok := strings.Cut(val, "/")
+	if !ok {
+		return nil, errors.NewInvalidAnnotationContent(RateLimitAnnotation, val)
+	}
+	rl := &RateLimit{BucketKeys: DefaultRateLimitKey}
+	if rl.Capacity, err = strconv.Atoi(strings.TrimSpace(capacity)); err != nil || rl.Capacity <= 0 {
+		return nil, errors.NewInvalidAnnotationContent(RateLimitAnnotation, val)
+	}
+	if rl.Window, err = time.ParseDuration(strings.TrimSpace(window)); err != nil || rl.Window <= 0 {
+		return nil, errors.NewInvalidAnnotationContent(RateLimitAnnotation, val)
+	}
+
+	keys, err := optionalStringSlice(RateLimitBucketKeyKey, obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		rl.BucketKeys = keys
+	}
+	return rl, nil
+}
+
+func extractOIDC(obj client.Object) (*OIDC, error) {
+	issuer, err := optionalString(OIDCIssuerURLKey, obj)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := optionalString(OIDCSecretKey, obj)
+	if err != nil {
+		return nil, err
+	}
+	if issuer == "" && secret == "" {
+		return nil, nil
+	}
+	if issuer == "" {
+		return nil, errors.NewInvalidAnnotationConfiguration(OIDCSecretAnnotation, OIDCIssuerURLAnnotation+" must also be set")
+	}
+	if secret == "" {
+		return nil, errors.NewInvalidAnnotationConfiguration(OIDCIssuerURLAnnotation, OIDCSecretAnnotation+" must also be set")
+	}
+	if _, err := parser.StringToURL(issuer); err != nil {
+		return nil, errors.NewInvalidAnnotationContent(OIDCIssuerURLAnnotation, issuer)
+	}
+
+	scopes, err := optionalStringSlice(OIDCScopesKey, obj)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDC{IssuerURL: issuer, SecretName: secret, Scopes: scopes}, nil
+}
+
+func extractCORS(obj client.Object) (*CORS, error) {
+	origins, err := optionalStringSlice(CORSAllowOriginsKey, obj)
+	if err != nil || len(origins) == 0 {
+		return nil, err
+	}
+	if len(origins) > 1 && slices.Contains(origins, "*") {
+		return nil, errors.NewInvalidAnnotationConfiguration(CORSAllowOriginsAnnotation, `"*" cannot be combined with other origins`)
+	}
+
+	cors := &CORS{
+		AllowOrigins: origins,
+		AllowMethods: DefaultCORSAllowMethods,
+		AllowHeaders: DefaultCORSAllowHeaders,
+	}
+	if methods, err := optionalStringSlice(CORSAllowMethodsKey, obj); err != nil {
+		return nil, err
+	} else if len(methods) > 0 {
+		cors.AllowMethods = methods
+	}
+	if headers, err := optionalStringSlice(CORSAllowHeadersKey, obj); err != nil {
+		return nil, err
+	} else if len(headers) > 0 {
+		cors.AllowHeaders = headers
+	}
+
+	cors.AllowCredentials, err = parser.GetBoolAnnotation(CORSAllowCredentialsKey, obj)
+	if err != nil && !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+	if cors.AllowCredentials && slices.Contains(origins, "*") {
+		return nil, errors.NewInvalidAnnotationConfiguration(CORSAllowCredentialsAnnotation, `credentials cannot be allowed for origin "*"`)
+	}
+
+	maxAge, err := parser.GetIntAnnotation(CORSMaxAgeKey, obj)
+	switch {
+	case err == nil && maxAge < 0:
+		return nil, errors.NewInvalidAnnotationContent(CORSMaxAgeAnnotation, maxAge)
+	case err == nil:
+		cors.MaxAge = &maxAge
+	case !errors.IsMissingAnnotations(err):
+		return nil, err
+	}
+	return cors, nil
+}
+
+func optionalString(key string, obj client.Object) (string, error) {
+	val, err := parser.GetStringAnnotation(key, obj)
+	if errors.IsMissingAnnotations(err) {
+		return "", nil
+	}
+	return val, err
+}
+
+func optionalStringSlice(key string, obj client.Object) ([]string, error) {
+	vals, err := parser.GetStringSliceAnnotation(key, obj)
+	if errors.IsMissingAnnotations(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(vals, func(v string) bool { return v == "" }), nil
+}
+
+func optionalStringMap(key string, obj client.Object) (map[string]string, error) {
+	m, err := parser.GetStringMapAnnotation(key, obj)
+	if errors.IsMissingAnnotations(err) {
+		return nil, nil
+	}
+	return m, err
+}
@@ -0,0 +1,202 @@
+package annotations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networking "k8s.io/api/networking/v1"
+)
+
+func TestExtractFeatureAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    *annotations.FeatureAnnotations
+		expectedErr string
+	}{
+		{
+			name:        "No annotations",
+			annotations: nil,
+			expected:    nil,
+		},
+		{
+			name: "Unrelated annotations",
+			annotations: map[string]string{
+				annotations.TrafficPolicyAnnotation: "policy1",
+			},
+			expected: nil,
+		},
+		{
+			name: "Rate limit with default key",
+			annotations: map[string]string{
+				annotations.RateLimitAnnotation: "100/1m",
+			},
+			expected: &annotations.FeatureAnnotations{
+				RateLimit: &annotations.RateLimit{Capacity: 100, Window: time.Minute, BucketKeys: []string{"conn.client_ip"}},
+			},
+		},
+		{
+			name: "Rate limit with custom key",
+			annotations: map[string]string{
+				annotations.RateLimitAnnotation:          "5/30s",
+				annotations.RateLimitBucketKeyAnnotation: "req.headers['x-api-key'], conn.client_ip",
+			},
+			expected: &annotations.FeatureAnnotations{
+				RateLimit: &annotations.RateLimit{Capacity: 5, Window: 30 * time.Second, BucketKeys: []string{"req.headers['x-api-key']", "conn.client_ip"}},
+			},
+		},
+		{
+			name: "Rate limit missing window",
+			annotations: map[string]string{
+				annotations.RateLimitAnnotation: "100",
+			},
+			expectedErr: "ngrok.com/rate-limit",
+		},
+		{
+			name: "Rate limit zero capacity",
+			annotations: map[string]string{
+				annotations.RateLimitAnnotation: "0/1m",
+			},
+			expectedErr: "ngrok.com/rate-limit",
+		},
+		{
+			name: "Basic auth and IP policies",
+			annotations: map[string]string{
+				annotations.BasicAuthSecretAnnotation: "creds",
+				annotations.IPPoliciesAnnotation:      "office,vpn",
+			},
+			expected: &annotations.FeatureAnnotations{
+				BasicAuthSecret: "creds",
+				IPPolicies:      []string{"office", "vpn"},
+			},
+		},
+		{
+			name: "OIDC",
+			annotations: map[string]string{
+				annotations.OIDCIssuerURLAnnotation: "https://accounts.example.com",
+				annotations.OIDCSecretAnnotation:    "oidc",
+				annotations.OIDCScopesAnnotation:    "openid,email",
+			},
+			expected: &annotations.FeatureAnnotations{
+				OIDC: &annotations.OIDC{IssuerURL: "https://accounts.example.com", SecretName: "oidc", Scopes: []string{"openid", "email"}},
+			},
+		},
+		{
+			name: "OIDC without secret",
+			annotations: map[string]string{
+				annotations.OIDCIssuerURLAnnotation: "https://accounts.example.com",
+			},
+			expectedErr: "ngrok.com/oidc-secret must also be set",
+		},
+		{
+			name: "Basic auth and OIDC",
+			annotations: map[string]string{
+				annotations.BasicAuthSecretAnnotation: "creds",
+				annotations.OIDCIssuerURLAnnotation:   "https://accounts.example.com",
+				annotations.OIDCSecretAnnotation:      "oidc",
+			},
+			expectedErr: "cannot be combined with ngrok.com/oidc-issuer-url",
+		},
+		{
+			name: "CORS defaults",
+			annotations: map[string]string{
+				annotations.CORSAllowOriginsAnnotation: "*",
+			},
+			expected: &annotations.FeatureAnnotations{
+				CORS: &annotations.CORS{
+					AllowOrigins: []string{"*"},
+					AllowMethods: annotations.DefaultCORSAllowMethods,
+					AllowHeaders: annotations.DefaultCORSAllowHeaders,
+				},
+			},
+		},
+		{
+			name: "CORS overrides",
+			annotations: map[string]string{
+				annotations.CORSAllowOriginsAnnotation:     "https://a.example.com,https://b.example.com",
+				annotations.CORSAllowMethodsAnnotation:     "GET,POST",
+				annotations.CORSAllowHeadersAnnotation:     "Content-Type",
+				annotations.CORSAllowCredentialsAnnotation: "true",
+				annotations.CORSMaxAgeAnnotation:           "600",
+			},
+			expected: &annotations.FeatureAnnotations{
+				CORS: &annotations.CORS{
+					AllowOrigins:     []string{"https://a.example.com", "https://b.example.com"},
+					AllowMethods:     []string{"GET", "POST"},
+					AllowHeaders:     []string{"Content-Type"},
+					AllowCredentials: true,
+					MaxAge:           new(600),
+				},
+			},
+		},
+		{
+			name: "CORS wildcard with other origins",
+			annotations: map[string]string{
+				annotations.CORSAllowOriginsAnnotation: "*,https://a.example.com",
+			},
+			expectedErr: `"*" cannot be combined with other origins`,
+		},
+		{
+			name: "CORS credentials with wildcard",
+			annotations: map[string]string{
+				annotations.CORSAllowOriginsAnnotation:     "*",
+				annotations.CORSAllowCredentialsAnnotation: "true",
+			},
+			expectedErr: `credentials cannot be allowed for origin "*"`,
+		},
+		{
+			name: "Headers",
+			annotations: map[string]string{
+				annotations.RequestHeadersAnnotation:  `{"X-Forwarded-By": "ngrok"}`,
+				annotations.ResponseHeadersAnnotation: `{"Strict-Transport-Security": "max-age=31536000"}`,
+			},
+			expected: &annotations.FeatureAnnotations{
+				RequestHeaders:  map[string]string{"X-Forwarded-By": "ngrok"},
+				ResponseHeaders: map[string]string{"Strict-Transport-Security": "max-age=31536000"},
+			},
+		},
+		{
+			name: "Headers not a JSON object",
+			annotations: map[string]string{
+				annotations.RequestHeadersAnnotation: "X-Forwarded-By: ngrok",
+			},
+			expectedErr: "ngrok.com/request-headers",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &networking.Ingress{
+				Name:        "test-ingress",
+				Namespace:   "default",
+				Annotations: tc.annotations,
+			}
+
+			features, err := annotations.ExtractFeatureAnnotations(obj)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.Nil(t, features)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, features)
+		})
+	}
+}
+
+func TestFeatureAnnotationsSecretNames(t *testing.T) {
+	var empty *annotations.FeatureAnnotations
+	assert.True(t, empty.IsEmpty())
+	assert.Empty(t, empty.SecretNames())
+
+	features := &annotations.FeatureAnnotations{
+		BasicAuthSecret: "creds",
+		OIDC:            &annotations.OIDC{SecretName: "oidc"},
+	}
+	assert.False(t, features.IsEmpty())
+	assert.Equal(t, []string{"creds", "oidc"}, features.SecretNames())
+}
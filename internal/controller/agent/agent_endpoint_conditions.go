@@ -4,6 +4,7 @@ import (
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/controller/conditions"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +28,8 @@ const (
 	ReasonEndpointCreated    = "EndpointCreated"
 	ReasonConfigError        = "ConfigurationError"
 	ReasonDomainNotReady     = "DomainNotReady"
+	ReasonForwarderClosed    = "ForwarderClosed"
+	ReasonAgentDisconnected  = "AgentDisconnected"
 	ReasonPending            = "Pending"
 	ReasonUnknown            = "Unknown"
 )
@@ -63,9 +66,16 @@ func calculateAgentEndpointReadyCondition(aep *ngrokv1alpha1.AgentEndpoint, doma
 	// Overall ready status - all conditions must be true
 	ready := endpointCreated && trafficPolicyReady && domainReady
 
+	// An endpoint whose schedule is offline is expected to not be created
+	expiredCondition := meta.FindStatusCondition(aep.Status.Conditions, schedule.ConditionExpired)
+	expired := expiredCondition != nil && expiredCondition.Status == metav1.ConditionTrue
+
 	// Determine reason and message based on state
 	var reason, message string
 	switch {
+	case expired:
+		reason = expiredCondition.Reason
+		message = expiredCondition.Message
 	case ready:
 		reason = ReasonEndpointActive
 		message = "AgentEndpoint is active and ready"
@@ -9,6 +9,7 @@ import (
 
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 )
 
 // Helper function to create a test AgentEndpoint
@@ -239,3 +240,29 @@ func TestCalculateAgentEndpointReadyCondition_MultipleIssues(t *testing.T) {
 	assert.Equal(t, "ProvisioningError", readyCondition.Reason)
 	assert.Equal(t, "Certificate provisioning in progress", readyCondition.Message)
 }
+
+func TestCalculateAgentEndpointReadyCondition_Expired(t *testing.T) {
+	// An expired endpoint reports its schedule, even while its domain isn't ready
+	endpoint := createTestAgentEndpointWithConditions("test-endpoint", "default", []metav1.Condition{
+		{
+			Type:    ConditionEndpointCreated,
+			Status:  metav1.ConditionFalse,
+			Reason:  schedule.ReasonScheduleWaiting,
+			Message: "Offline until 2026-03-04T12:00:00Z",
+		},
+		{
+			Type:    schedule.ConditionExpired,
+			Status:  metav1.ConditionTrue,
+			Reason:  schedule.ReasonScheduleWaiting,
+			Message: "Offline until 2026-03-04T12:00:00Z",
+		},
+	})
+
+	calculateAgentEndpointReadyCondition(endpoint, nil)
+
+	readyCondition := meta.FindStatusCondition(endpoint.Status.Conditions, ConditionReady)
+	assert.NotNil(t, readyCondition)
+	assert.Equal(t, metav1.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, schedule.ReasonScheduleWaiting, readyCondition.Reason)
+	assert.Equal(t, "Offline until 2026-03-04T12:00:00Z", readyCondition.Message)
+}
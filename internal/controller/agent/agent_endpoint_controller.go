@@ -39,16 +39,21 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	"github.com/ngrok/ngrok-operator/internal/util"
 	"github.com/ngrok/ngrok-operator/pkg/agent"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerruntime "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -105,9 +110,19 @@ type AgentEndpointReconciler struct {
 	DomainManager              *domainpkg.Manager
 	TrafficPolicyManager       *trafficpolicypkg.Manager
 
+	// OperatorConfig, when set, provides the default domain reclaim policy from the KubernetesOperator instead of
+	// DefaultDomainReclaimPolicy
+	OperatorConfig *operatorconfig.Store
+
 	// DrainState is used to check if the operator is draining.
 	// If draining, non-delete reconciles are skipped to prevent new finalizers.
 	DrainState controller.DrainState
+
+	// PrimaryStandby, when set, limits the endpoints that don't allow pooling to the replica holding the lease.
+	// When nil, every replica starts every endpoint.
+	PrimaryStandby *PrimaryStandby
+
+	forwarderEvents forwarderEvents
 }
 
 // SetupWithManager sets up the controller with the Manager
@@ -136,6 +151,9 @@ func (r *AgentEndpointReconciler) SetupWithManagerNamed(mgr ctrl.Manager, contro
 		if r.DefaultDomainReclaimPolicy != nil {
 			opts = append(opts, domainpkg.WithDefaultDomainReclaimPolicy(*r.DefaultDomainReclaimPolicy))
 		}
+		if r.OperatorConfig != nil {
+			opts = append(opts, domainpkg.WithOperatorConfig(r.OperatorConfig))
+		}
 
 		dm, err := domainpkg.NewManager(r.Client, r.Recorder, opts...)
 		if err != nil {
@@ -149,13 +167,14 @@ func (r *AgentEndpointReconciler) SetupWithManagerNamed(mgr ctrl.Manager, contro
 	}
 
 	r.controller = &controller.BaseController[*ngrokv1alpha1.AgentEndpoint]{
-		Kube:       r.Client,
-		Log:        r.Log,
-		Recorder:   r.Recorder,
-		DrainState: r.DrainState,
-		Update:     r.update,
-		Delete:     r.delete,
-		StatusID:   r.statusID,
+		Kube:         r.Client,
+		Log:          r.Log,
+		Recorder:     r.Recorder,
+		DrainState:   r.DrainState,
+		Update:       r.update,
+		Delete:       r.delete,
+		StatusID:     r.statusID,
+		RequeueAfter: r.requeueAfter,
 		ErrResult: func(_ controller.BaseControllerOp, cr *ngrokv1alpha1.AgentEndpoint, err error) (ctrl.Result, error) {
 			if errors.Is(err, domainpkg.ErrDomainNotReady) {
 				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
@@ -208,8 +227,10 @@ func (r *AgentEndpointReconciler) SetupWithManagerNamed(mgr ctrl.Manager, contro
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(controllerName).
+		// Every replica runs its own agent, so the controller runs on standby replicas too
+		WithOptions(controllerruntime.Options{NeedLeaderElection: new(false)}).
 		For(&ngrokv1alpha1.AgentEndpoint{}, builder.WithPredicates(
 			predicate.Or(
 				predicate.AnnotationChangedPredicate{},
@@ -228,7 +249,11 @@ func (r *AgentEndpointReconciler) SetupWithManagerNamed(mgr ctrl.Manager, contro
 			&ingressv1alpha1.Domain{},
 			r.controller.NewEnqueueRequestForMapFunc(r.findAgentEndpointsForDomain),
 		).
-		Complete(r)
+		WatchesRawSource(r.forwarderEventSource())
+	if r.PrimaryStandby != nil {
+		b = b.WatchesRawSource(r.electedSource())
+	}
+	return b.Complete(r)
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -241,6 +266,11 @@ func (r *AgentEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 }
 
 func (r *AgentEndpointReconciler) update(ctx context.Context, endpoint *ngrokv1alpha1.AgentEndpoint) error {
+	if !r.PrimaryStandby.Serves(endpoint) {
+		// The primary starts the endpoint and reports its status. Stop it in case it was started before it
+		// stopped allowing pooling.
+		return r.AgentDriver.DeleteAgentEndpoint(ctx, r.statusID(endpoint))
+	}
 
 	// EnsureDomainExists checks if the domain exists, creates it if needed, and sets conditions/domainRef
 	domainResult, err := r.DomainManager.EnsureDomainExists(ctx, endpoint)
@@ -271,6 +301,14 @@ func (r *AgentEndpointReconciler) update(ctx context.Context, endpoint *ngrokv1a
 		return r.updateStatus(ctx, endpoint, nil, domainResult, err)
 	}
 
+	if online, err := r.applySchedule(ctx, endpoint, domainResult); !online {
+		return err
+	}
+
+	if recreate, err := r.applyForwarderEvent(ctx, endpoint, domainResult); !recreate {
+		return err
+	}
+
 	// Create the endpoint
 	tunnelName := r.statusID(endpoint)
 	result, err := r.AgentDriver.CreateAgentEndpoint(ctx, tunnelName, endpoint.Spec, tpResult.Policy, clientCerts, agentTLS)
@@ -292,12 +330,50 @@ func (r *AgentEndpointReconciler) update(ctx context.Context, endpoint *ngrokv1a
 	return r.updateStatus(ctx, endpoint, result, domainResult, nil)
 }
 
+// applySchedule records the state of the endpoint's schedule in the Expired condition. While the schedule is
+// offline, it stops the agent endpoint, writes the status and returns false. The endpoint is started again by the
+// reconcile requested for when the schedule comes back online.
+func (r *AgentEndpointReconciler) applySchedule(ctx context.Context, endpoint *ngrokv1alpha1.AgentEndpoint, domainResult *domainpkg.DomainResult) (bool, error) {
+	state, err := schedule.Evaluate(endpoint.Spec.Schedule, endpoint.CreationTimestamp.Time, time.Now())
+	if err != nil {
+		setEndpointCreatedCondition(endpoint, false, ReasonConfigError, fmt.Sprintf("Invalid schedule: %v", err))
+		r.Recorder.Eventf(endpoint, nil, v1.EventTypeWarning, "ConfigError", "Reconcile", fmt.Sprintf("Invalid schedule: %v", err))
+		return false, r.updateStatus(ctx, endpoint, nil, domainResult, reconcile.TerminalError(err))
+	}
+	schedule.SetCondition(&endpoint.Status.Conditions, endpoint.Generation, endpoint.Spec.Schedule, state)
+	if state.Active {
+		return true, nil
+	}
+
+	if err := r.AgentDriver.DeleteAgentEndpoint(ctx, r.statusID(endpoint)); err != nil {
+		return false, r.updateStatus(ctx, endpoint, nil, domainResult, err)
+	}
+	if endpoint.Status.AssignedURL != "" {
+		r.Recorder.Eventf(endpoint, nil, v1.EventTypeNormal, "Expired", "Reconcile", fmt.Sprintf("Stopped endpoint %s until its schedule is online", endpoint.Status.AssignedURL))
+		endpoint.Status.AssignedURL = ""
+	}
+	expired := meta.FindStatusCondition(endpoint.Status.Conditions, schedule.ConditionExpired)
+	setEndpointCreatedCondition(endpoint, false, expired.Reason, expired.Message)
+	return false, r.updateStatus(ctx, endpoint, nil, domainResult, nil)
+}
+
 func (r *AgentEndpointReconciler) delete(ctx context.Context, endpoint *ngrokv1alpha1.AgentEndpoint) error {
 	tunnelName := r.statusID(endpoint)
+	r.forwarderEvents.forget(tunnelName)
 	return r.AgentDriver.DeleteAgentEndpoint(ctx, tunnelName)
 	// TODO: Delete any associated domain
 }
 
+// requeueAfter returns how long to wait before reconciling the endpoint again: until the next change of its schedule,
+// or until its forwarder can be started again after it closed, whichever comes first
+func (r *AgentEndpointReconciler) requeueAfter(endpoint *ngrokv1alpha1.AgentEndpoint) time.Duration {
+	d := schedule.RequeueAfter(endpoint.Spec.Schedule, endpoint.CreationTimestamp.Time)
+	if wait := r.forwarderEvents.restartDelay(r.statusID(endpoint)); wait > 0 && (d == 0 || wait < d) {
+		d = wait
+	}
+	return d
+}
+
 func (r *AgentEndpointReconciler) statusID(endpoint *ngrokv1alpha1.AgentEndpoint) string {
 	return fmt.Sprintf("%s/%s", endpoint.Namespace, endpoint.Name)
 }
@@ -543,6 +619,7 @@ func (r *AgentEndpointReconciler) updateStatus(ctx context.Context, endpoint *ng
 	if result != nil {
 		endpoint.Status.AssignedURL = result.URL
 	}
+	endpoint.Status.ActiveReplica = r.PrimaryStandby.activeReplica(endpoint)
 
 	// Calculate overall Ready condition based on other conditions and domain status
 	calculateAgentEndpointReadyCondition(endpoint, domainResult)
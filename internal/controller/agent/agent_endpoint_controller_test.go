@@ -1180,6 +1180,44 @@ var _ = Describe("AgentEndpoint Controller", func() {
 			}, timeout, interval).Should(Succeed())
 		})
 
+		It("should flip to not ready while the agent is disconnected", func(ctx SpecContext) {
+			agentEndpoint = &ngrokv1alpha1.AgentEndpoint{
+				Name:      "disconnect-runtime",
+				Namespace: namespace,
+				Spec: ngrokv1alpha1.AgentEndpointSpec{
+					URL: "tcp://97.tcp.ngrok.io:97979",
+					Upstream: ngrokv1alpha1.EndpointUpstream{
+						URL: "http://test-service:80",
+					},
+				},
+			}
+			name := namespace + "/disconnect-runtime"
+
+			envMockDriver.SetEndpointResult(name, &agent.EndpointResult{
+				URL: "tcp://97.tcp.ngrok.io:97979",
+			})
+
+			readyReason := func(g Gomega) string {
+				obj := &ngrokv1alpha1.AgentEndpoint{}
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(agentEndpoint), obj)).To(Succeed())
+				cond := testutils.FindCondition(obj.Status.Conditions, ConditionReady)
+				g.Expect(cond).NotTo(BeNil())
+				return cond.Reason
+			}
+
+			By("Creating the AgentEndpoint")
+			Expect(k8sClient.Create(ctx, agentEndpoint)).To(Succeed())
+			Eventually(readyReason, timeout, interval).Should(Equal(ReasonEndpointActive))
+
+			By("Losing the agent session")
+			envMockDriver.SendEvent(agent.ForwarderEvent{Name: name, Type: agent.ForwarderErrored, Err: errors.New("session closed")})
+			Eventually(readyReason, timeout, interval).Should(Equal(ReasonAgentDisconnected))
+
+			By("Reconnecting the agent")
+			envMockDriver.SendEvent(agent.ForwarderEvent{Name: name, Type: agent.ForwarderReconnected})
+			Eventually(readyReason, timeout, interval).Should(Equal(ReasonEndpointActive))
+		})
+
 		It("should not infinitely requeue when the ngrok API rejects the traffic policy", func(ctx SpecContext) {
 			agentEndpoint = &ngrokv1alpha1.AgentEndpoint{
 				Name:      "policy-rejected-runtime",
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/pkg/agent"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// forwarderRestartBackoff is how long to wait before starting a forwarder again after it first closed. The delay
+	// doubles each time it closes again, up to forwarderRestartMaxBackoff.
+	forwarderRestartBackoff    = time.Second
+	forwarderRestartMaxBackoff = 5 * time.Minute
+	// forwarderStablePeriod is how long a forwarder must stay up for its earlier closes to be forgotten
+	forwarderStablePeriod = time.Minute
+)
+
+// forwarderEvents holds the latest forwarder lifecycle event of each AgentEndpoint that hasn't been reconciled since,
+// and the recent closes of their forwarders, so that a forwarder that keeps closing is started again with backoff
+type forwarderEvents struct {
+	mu     sync.Mutex
+	events map[string]agent.ForwarderEvent
+	closes map[string]forwarderCloses
+
+	// now returns the current time, time.Now when nil
+	now func() time.Time
+}
+
+// forwarderCloses tracks the closes of an AgentEndpoint's forwarder
+type forwarderCloses struct {
+	// count is the number of times the forwarder closed without staying up for forwarderStablePeriod in between
+	count int
+	// restartAt is when the forwarder is started again after it last closed
+	restartAt time.Time
+}
+
+func (f *forwarderEvents) clock() time.Time {
+	if f.now != nil {
+		return f.now()
+	}
+	return time.Now()
+}
+
+func (f *forwarderEvents) put(event agent.ForwarderEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.events == nil {
+		f.events = make(map[string]agent.ForwarderEvent)
+	}
+	f.events[event.Name] = event
+}
+
+// take removes and returns the event for the AgentEndpoint name, if any. When the forwarder closed, it schedules when
+// to start it again.
+func (f *forwarderEvents) take(name string) (agent.ForwarderEvent, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	event, ok := f.events[name]
+	delete(f.events, name)
+	if !ok || event.Type != agent.ForwarderClosed {
+		return event, ok
+	}
+
+	now := f.clock()
+	c := f.closes[name]
+	if now.Sub(c.restartAt) >= forwarderStablePeriod {
+		c.count = 0
+	}
+	backoff := forwarderRestartBackoff
+	for range c.count {
+		if backoff >= forwarderRestartMaxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	c.count++
+	c.restartAt = now.Add(min(backoff, forwarderRestartMaxBackoff))
+	if f.closes == nil {
+		f.closes = make(map[string]forwarderCloses)
+	}
+	f.closes[name] = c
+	return event, true
+}
+
+// restartDelay returns how long to wait before starting the forwarder of the AgentEndpoint name again after it
+// closed, or 0 if it can be started now
+func (f *forwarderEvents) restartDelay(name string) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.closes[name]
+	if !ok {
+		return 0
+	}
+	return max(c.restartAt.Sub(f.clock()), 0)
+}
+
+// forget removes the event and closes of the AgentEndpoint name, once it is deleted
+func (f *forwarderEvents) forget(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.events, name)
+	delete(f.closes, name)
+}
+
+// forwarderEventSource returns a source that records the forwarder lifecycle events of the agent driver and enqueues
+// the AgentEndpoint each of them is for
+func (r *AgentEndpointReconciler) forwarderEventSource() source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-r.AgentDriver.Events():
+					namespace, name, err := cache.SplitMetaNamespaceKey(event.Name)
+					if err != nil {
+						r.Log.Error(err, "ignoring forwarder event for unknown agent endpoint", "name", event.Name)
+						continue
+					}
+					r.Log.V(1).Info("agent endpoint forwarder event", "name", event.Name, "type", event.Type, "error", event.Err)
+					r.forwarderEvents.put(event)
+					// The reconcile requeues the endpoint until its forwarder can be started again, see restartDelay
+					queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+				}
+			}
+		}()
+		return nil
+	})
+}
+
+// applyForwarderEvent records the forwarder lifecycle event received for the endpoint since the last reconcile, if
+// any. When the forwarder closed or the agent lost its session, the endpoint is marked as not created and the status
+// is written before going on. It returns false when the forwarder must not be recreated, including while a forwarder
+// that closed waits for its backoff.
+func (r *AgentEndpointReconciler) applyForwarderEvent(ctx context.Context, endpoint *ngrokv1alpha1.AgentEndpoint, domainResult *domainpkg.DomainResult) (bool, error) {
+	name := r.statusID(endpoint)
+	event, ok := r.forwarderEvents.take(name)
+	if !ok {
+		return r.forwarderEvents.restartDelay(name) == 0, nil
+	}
+
+	var reason, message string
+	switch event.Type {
+	case agent.ForwarderReconnected:
+		r.Recorder.Eventf(endpoint, nil, v1.EventTypeNormal, "AgentReconnected", "Reconcile", "Recreating the agent endpoint after the agent reconnected")
+		return true, nil
+	case agent.ForwarderErrored:
+		reason, message = ReasonAgentDisconnected, "The agent lost its session to ngrok"
+	default:
+		reason, message = ReasonForwarderClosed, "The agent endpoint closed"
+	}
+	if event.Err != nil {
+		message += ": " + event.Err.Error()
+	}
+
+	r.Recorder.Eventf(endpoint, nil, v1.EventTypeWarning, reason, "Reconcile", message)
+	setEndpointCreatedCondition(endpoint, false, reason, message)
+	calculateAgentEndpointReadyCondition(endpoint, domainResult)
+	if err := r.controller.ReconcileStatus(ctx, endpoint, nil); err != nil {
+		return false, err
+	}
+
+	// The agent starts its endpoints again when it reconnects, which is reported by a ForwarderReconnected event
+	return event.Type == agent.ForwarderClosed && r.forwarderEvents.restartDelay(name) == 0, nil
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ngrok/ngrok-operator/pkg/agent"
+)
+
+func TestForwarderEventsRestartBackoff(t *testing.T) {
+	now := time.Now()
+	f := &forwarderEvents{now: func() time.Time { return now }}
+	const name = "default/test"
+
+	closeForwarder := func() time.Duration {
+		f.put(agent.ForwarderEvent{Name: name, Type: agent.ForwarderClosed})
+		_, ok := f.take(name)
+		assert.True(t, ok)
+		return f.restartDelay(name)
+	}
+
+	// The delay doubles each time the forwarder closes soon after being started again
+	assert.Equal(t, time.Second, closeForwarder())
+	now = now.Add(time.Second)
+	assert.Zero(t, f.restartDelay(name))
+	assert.Equal(t, 2*time.Second, closeForwarder())
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 4*time.Second, closeForwarder())
+
+	// and is capped
+	for range 20 {
+		closeForwarder()
+	}
+	assert.Equal(t, forwarderRestartMaxBackoff, f.restartDelay(name))
+
+	// A forwarder that stayed up for a while starts over
+	now = now.Add(forwarderRestartMaxBackoff + forwarderStablePeriod)
+	assert.Equal(t, time.Second, closeForwarder())
+
+	// Other events don't delay the forwarder
+	now = now.Add(time.Second)
+	f.put(agent.ForwarderEvent{Name: name, Type: agent.ForwarderReconnected})
+	_, ok := f.take(name)
+	assert.True(t, ok)
+	assert.Zero(t, f.restartDelay(name))
+
+	// Deleting the endpoint forgets its closes
+	f.forget(name)
+	assert.Equal(t, time.Second, closeForwarder())
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// PrimaryStandby serves the AgentEndpoints that don't allow pooling from a single agent-manager replica, the
+// primary, which holds the manager's leader election lease. The other replicas keep their agent sessions connected
+// and serve pooled endpoints, so that one of them can take over as soon as it acquires the lease.
+type PrimaryStandby struct {
+	// Identity is the name of this replica, reported in the status of the endpoints it serves as the primary
+	Identity string
+
+	// Elected is closed when this replica becomes the primary
+	Elected <-chan struct{}
+}
+
+// IsPrimary returns whether this replica holds the lease
+func (p *PrimaryStandby) IsPrimary() bool {
+	select {
+	case <-p.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Serves returns whether this replica starts the endpoint. Every replica starts pooled endpoints, and only the
+// primary starts the others. Every replica starts every endpoint when p is nil.
+func (p *PrimaryStandby) Serves(endpoint *ngrokv1alpha1.AgentEndpoint) bool {
+	return p == nil || !isExclusive(endpoint) || p.IsPrimary()
+}
+
+// activeReplica returns the replica to report in the status of the endpoint, if it is served by the primary only
+func (p *PrimaryStandby) activeReplica(endpoint *ngrokv1alpha1.AgentEndpoint) string {
+	if p == nil || !isExclusive(endpoint) {
+		return ""
+	}
+	return p.Identity
+}
+
+// isExclusive returns whether the endpoint must be started by a single agent
+func isExclusive(endpoint *ngrokv1alpha1.AgentEndpoint) bool {
+	return !ptr.Deref(endpoint.Spec.PoolingEnabled, true)
+}
+
+// electedSource returns a source that enqueues every AgentEndpoint that doesn't allow pooling when this replica
+// becomes the primary, so that it starts them
+func (r *AgentEndpointReconciler) electedSource() source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		go func() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.PrimaryStandby.Elected:
+			}
+
+			r.Log.Info("elected as the primary agent-manager replica", "identity", r.PrimaryStandby.Identity)
+			endpoints := &ngrokv1alpha1.AgentEndpointList{}
+			if err := r.List(ctx, endpoints); err != nil {
+				r.Log.Error(err, "failed to list AgentEndpoints to start as the primary")
+				return
+			}
+			for _, endpoint := range endpoints.Items {
+				if isExclusive(&endpoint) {
+					queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: endpoint.Namespace, Name: endpoint.Name}})
+				}
+			}
+		}()
+		return nil
+	})
+}
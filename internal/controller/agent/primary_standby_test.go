@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+)
+
+func TestPrimaryStandbyServes(t *testing.T) {
+	pooled := &ngrokv1alpha1.AgentEndpoint{}
+	explicitlyPooled := &ngrokv1alpha1.AgentEndpoint{Spec: ngrokv1alpha1.AgentEndpointSpec{PoolingEnabled: new(true)}}
+	exclusive := &ngrokv1alpha1.AgentEndpoint{Spec: ngrokv1alpha1.AgentEndpointSpec{PoolingEnabled: new(false)}}
+
+	var disabled *PrimaryStandby
+	assert.True(t, disabled.Serves(exclusive))
+	assert.Empty(t, disabled.activeReplica(exclusive))
+
+	elected := make(chan struct{})
+	p := &PrimaryStandby{Identity: "agent-0", Elected: elected}
+	assert.False(t, p.IsPrimary())
+	assert.True(t, p.Serves(pooled))
+	assert.True(t, p.Serves(explicitlyPooled))
+	assert.False(t, p.Serves(exclusive))
+
+	close(elected)
+	assert.True(t, p.IsPrimary())
+	assert.True(t, p.Serves(exclusive))
+	assert.Equal(t, "agent-0", p.activeReplica(exclusive))
+	assert.Empty(t, p.activeReplica(pooled))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
 	"github.com/ngrok/ngrok-operator/internal/drain"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
 	"github.com/ngrok/ngrok-operator/internal/util"
@@ -61,11 +62,21 @@ type BaseController[T client.Object] struct {
 	// If draining, non-delete reconciles are skipped to prevent new finalizers.
 	DrainState DrainState
 
-	StatusID  func(obj T) string
+	StatusID func(obj T) string
+
+	// Adopt takes ownership of the existing ngrok API resource named by the ngrok.com/adopt annotation instead
+	// of creating a new one. It verifies that the resource matches the object and records its ID in the status,
+	// after which Update brings the resource in line with the spec. Adoption is not supported when nil.
+	Adopt func(ctx context.Context, obj T, id string) error
+
 	Create    func(ctx context.Context, obj T) error
 	Update    func(ctx context.Context, obj T) error
 	Delete    func(ctx context.Context, obj T) error
 	ErrResult func(op BaseControllerOp, obj T, err error) (ctrl.Result, error)
+
+	// RequeueAfter returns how long to wait before reconciling the object again after a successful create or
+	// update, e.g. for a scheduled change. The object is not requeued when it is nil or returns 0.
+	RequeueAfter func(obj T) time.Duration
 }
 
 // reconcile is the primary function that a manager calls for this controller to reconcile an event for the give client.Object
@@ -96,7 +107,28 @@ func (self *BaseController[T]) Reconcile(ctx context.Context, req ctrl.Request,
 			return ctrl.Result{}, err
 		}
 
-		if self.StatusID != nil && self.StatusID(obj) == "" {
+		adoptID := ""
+		if self.Adopt != nil && self.StatusID != nil && self.StatusID(obj) == "" {
+			id, err := annotations.ExtractAdoptID(obj)
+			if err != nil {
+				self.Recorder.Eventf(obj, nil, v1.EventTypeWarning, "AdoptError", "Adopt", fmt.Sprintf("Invalid %s annotation: %s", annotations.AdoptAnnotation, err.Error()))
+				return ctrl.Result{}, nil
+			}
+			adoptID = id
+		}
+
+		if adoptID != "" {
+			self.Recorder.Eventf(obj, nil, v1.EventTypeNormal, "Adopting", "Adopt", fmt.Sprintf("Adopting %s %s", objName, adoptID))
+			if err := self.Adopt(ctx, obj, adoptID); err != nil {
+				self.Recorder.Eventf(obj, nil, v1.EventTypeWarning, "AdoptError", "Adopt", fmt.Sprintf("Failed to adopt %s %s: %s", objName, adoptID, err.Error()))
+				return self.handleErr(CreateOp, obj, err)
+			}
+			if err := self.Update(ctx, obj); err != nil {
+				self.Recorder.Eventf(obj, nil, v1.EventTypeWarning, "UpdateError", "Update", fmt.Sprintf("Failed to update %s: %s", objName, err.Error()))
+				return self.handleErr(UpdateOp, obj, err)
+			}
+			self.Recorder.Eventf(obj, nil, v1.EventTypeNormal, "Adopted", "Adopt", fmt.Sprintf("Adopted %s %s", objName, adoptID))
+		} else if self.StatusID != nil && self.StatusID(obj) == "" {
 			self.Recorder.Eventf(obj, nil, v1.EventTypeNormal, "Creating", "Create", fmt.Sprintf("Creating %s", objName))
 			if err := self.Create(ctx, obj); err != nil {
 				self.Recorder.Eventf(obj, nil, v1.EventTypeWarning, "CreateError", "Create", fmt.Sprintf("Failed to Create %s: %s", objName, err.Error()))
@@ -111,6 +143,12 @@ func (self *BaseController[T]) Reconcile(ctx context.Context, req ctrl.Request,
 			}
 			self.Recorder.Eventf(obj, nil, v1.EventTypeNormal, "Updated", "Update", fmt.Sprintf("Updated %s", objName))
 		}
+
+		if self.RequeueAfter != nil {
+			if d := self.RequeueAfter(obj); d > 0 {
+				return ctrl.Result{RequeueAfter: d}, nil
+			}
+		}
 	} else if util.HasFinalizer(obj) {
 		if self.StatusID != nil && self.StatusID(obj) != "" {
 			sid := self.StatusID(obj)
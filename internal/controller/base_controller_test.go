@@ -215,6 +215,84 @@ func TestBaseController_Reconcile_Update(t *testing.T) {
 	assert.True(t, updateCalled, "Update should be called when StatusID returns non-empty")
 }
 
+func TestBaseController_Reconcile_Adopt(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	ingress := &netv1.Ingress{
+		Name:        "test-ingress",
+		Namespace:   "default",
+		Annotations: map[string]string{"ngrok.com/adopt": " existing-id "},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(ingress).Build()
+
+	adoptedID := ""
+	updateCalled := false
+	bc := &BaseController[*netv1.Ingress]{
+		Kube:     c,
+		Log:      logr.Discard(),
+		Recorder: events.NewFakeRecorder(10),
+		StatusID: func(_ *netv1.Ingress) string { return "" },
+		Adopt: func(_ context.Context, _ *netv1.Ingress, id string) error {
+			adoptedID = id
+			return nil
+		},
+		Create: func(_ context.Context, _ *netv1.Ingress) error {
+			t.Error("Create should not be called")
+			return nil
+		},
+		Update: func(_ context.Context, _ *netv1.Ingress) error {
+			updateCalled = true
+			return nil
+		},
+	}
+
+	result, err := bc.Reconcile(ctx, ctrl.Request{
+		Name: "test-ingress", Namespace: "default",
+	}, &netv1.Ingress{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.Equal(t, "existing-id", adoptedID)
+	assert.True(t, updateCalled, "Update should be called after adopting")
+}
+
+func TestBaseController_Reconcile_AdoptError(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	ingress := &netv1.Ingress{
+		Name:        "test-ingress",
+		Namespace:   "default",
+		Annotations: map[string]string{"ngrok.com/adopt": "missing-id"},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(ingress).Build()
+
+	bc := &BaseController[*netv1.Ingress]{
+		Kube:     c,
+		Log:      logr.Discard(),
+		Recorder: events.NewFakeRecorder(10),
+		StatusID: func(_ *netv1.Ingress) string { return "" },
+		Adopt: func(_ context.Context, _ *netv1.Ingress, _ string) error {
+			return &ngrok.Error{StatusCode: http.StatusNotFound}
+		},
+		Update: func(_ context.Context, _ *netv1.Ingress) error {
+			t.Error("Update should not be called when adopting fails")
+			return nil
+		},
+	}
+
+	_, err := bc.Reconcile(ctx, ctrl.Request{
+		Name: "test-ingress", Namespace: "default",
+	}, &netv1.Ingress{})
+
+	assert.Error(t, err)
+}
+
 func TestBaseController_Reconcile_Delete(t *testing.T) {
 	ctx := context.Background()
 	s := runtime.NewScheme()
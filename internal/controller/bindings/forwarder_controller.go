@@ -149,21 +149,9 @@ func (r *ForwarderReconciler) update(ctx context.Context, epb *bindingsv1alpha1.
 		"port", epb.Spec.Port,
 	)
 
-	// Get the KubernetesOperator
-
-	op := ngrokv1alpha1.KubernetesOperator{}
-	objectKey := client.ObjectKey{Name: r.KubernetesOperatorName, Namespace: epb.Namespace}
-	if err := r.Client.Get(ctx, objectKey, &op); err != nil {
-		return err
-	}
-
 	// Bindings should be enabled on the operator, if they aren't we can't do anything
-	if op.Spec.Binding == nil {
-		return errors.New("operator does not have binding configuration")
-	}
-
-	if op.Status.BindingsIngressEndpoint == "" {
-		return errors.New("operator binding configuration does not have an ingress endpoint")
+	if _, err := r.bindingOperator(ctx, epb.Namespace); err != nil {
+		return err
 	}
 
 	endpointURL, err := url.Parse(epb.Spec.EndpointURL)
@@ -177,14 +165,22 @@ func (r *ForwarderReconciler) update(ctx context.Context, epb *bindingsv1alpha1.
 		return err
 	}
 
-	ingressEndpoint, err := getIngressEndpointWithFallback(op.Status.BindingsIngressEndpoint, log)
-	if err != nil {
-		log.Error(err, "failed to determine bindings ingress endpoint")
-	}
-
 	cnxnHandler := func(conn net.Conn) error {
 		defer conn.Close()
 
+		// The KubernetesOperator is read for each connection, so that changes to its binding configuration apply to
+		// new connections without restarting the listener
+		op, err := r.bindingOperator(ctx, epb.Namespace)
+		if err != nil {
+			log.Error(err, "failed to get the operator binding configuration")
+			return err
+		}
+
+		ingressEndpoint, err := getIngressEndpointWithFallback(op.Status.BindingsIngressEndpoint, log)
+		if err != nil {
+			log.Error(err, "failed to determine bindings ingress endpoint")
+		}
+
 		log := log.WithValues(
 			"remoteAddr", conn.RemoteAddr(),
 			"ingress", map[string]string{
@@ -273,6 +269,24 @@ func (r *ForwarderReconciler) update(ctx context.Context, epb *bindingsv1alpha1.
 	return r.BindingsDriver.Listen(int32(epb.Spec.Port), cnxnHandler)
 }
 
+// bindingOperator returns the KubernetesOperator, if its bindings feature is configured
+func (r *ForwarderReconciler) bindingOperator(ctx context.Context, namespace string) (*ngrokv1alpha1.KubernetesOperator, error) {
+	op := &ngrokv1alpha1.KubernetesOperator{}
+	objectKey := client.ObjectKey{Name: r.KubernetesOperatorName, Namespace: namespace}
+	if err := r.Client.Get(ctx, objectKey, op); err != nil {
+		return nil, err
+	}
+
+	if op.Spec.Binding == nil {
+		return nil, errors.New("operator does not have binding configuration")
+	}
+
+	if op.Status.BindingsIngressEndpoint == "" {
+		return nil, errors.New("operator binding configuration does not have an ingress endpoint")
+	}
+	return op, nil
+}
+
 func (r *ForwarderReconciler) loadTLSCertificate(ctx context.Context, namespace, name string) (tls.Certificate, error) {
 	secret := v1.Secret{}
 	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
@@ -369,6 +369,7 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		&gatewayv1.HTTPRoute{},
 		// &corev1.Service{},
 		&ingressv1alpha1.Domain{},
+		&ingressv1alpha1.TCPAddress{},
 	}
 
 	bldr := ctrl.NewControllerManagedBy(mgr).For(&gatewayv1.Gateway{})
@@ -0,0 +1,214 @@
+package ingress
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	"github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/util"
+)
+
+const (
+	// domainCertificateRefIndex indexes Domains by the "namespace/name" of the
+	// Secret referenced by spec.certificateRef
+	domainCertificateRefIndex = "spec.certificateRef"
+)
+
+// errInvalidCertificateRef is wrapped by errors caused by the Secret referenced
+// by spec.certificateRef, so they can be reported on the CertificateReady
+// condition rather than as a failure to reserve the domain.
+var errInvalidCertificateRef = errors.New("invalid certificateRef")
+
+// managedCertificatePolicy is the certificate management policy restored when
+// a domain stops using an uploaded certificate. It matches the defaults the
+// ngrok API applies to new custom domains.
+var managedCertificatePolicy = ngrok.ReservedDomainCertPolicy{
+	Authority:      "letsencrypt",
+	PrivateKeyType: "ecdsa",
+}
+
+// indexDomainCertificateRef extracts the Secret key referenced by a Domain for indexing
+func indexDomainCertificateRef(o client.Object) []string {
+	domain, ok := o.(*v1alpha1.Domain)
+	if !ok || domain.Spec.CertificateRef == nil {
+		return nil
+	}
+	return []string{domain.Namespace + "/" + domain.Spec.CertificateRef.Name}
+}
+
+// findDomainsForSecret maps a Secret to the Domains that reference it, so that
+// renewed certificates are uploaded
+func (r *DomainReconciler) findDomainsForSecret(ctx context.Context, o client.Object) []reconcile.Request {
+	domains := &v1alpha1.DomainList{}
+	if err := r.List(ctx, domains, client.MatchingFields{domainCertificateRefIndex: o.GetNamespace() + "/" + o.GetName()}); err != nil {
+		r.Log.Error(err, "failed to list Domains for Secret", "secret", client.ObjectKeyFromObject(o))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(domains.Items))
+	for _, domain := range domains.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: domain.Namespace, Name: domain.Name},
+		})
+	}
+	return requests
+}
+
+// secretCertificate is a certificate and private key read from a kubernetes.io/tls Secret
+type secretCertificate struct {
+	certPEM     []byte
+	keyPEM      []byte
+	fingerprint string
+	notBefore   time.Time
+	notAfter    time.Time
+}
+
+// getSecretCertificate reads and validates the certificate referenced by the domain's spec.certificateRef
+func (r *DomainReconciler) getSecretCertificate(ctx context.Context, domain *v1alpha1.Domain) (*secretCertificate, error) {
+	key := client.ObjectKey{Namespace: domain.Namespace, Name: domain.Spec.CertificateRef.Name}
+
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Recorder.Eventf(domain, nil, v1.EventTypeWarning, "SecretNotFound", "Reconcile", "Failed to find Secret %s for certificateRef", key)
+			return nil, fmt.Errorf("%w: Secret %q not found", errInvalidCertificateRef, key.Name)
+		}
+		return nil, err
+	}
+
+	certPEM, ok := secret.Data[v1.TLSCertKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s data is missing from Secret %q", errInvalidCertificateRef, v1.TLSCertKey, key.Name)
+	}
+	keyPEM, ok := secret.Data[v1.TLSPrivateKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s data is missing from Secret %q", errInvalidCertificateRef, v1.TLSPrivateKeyKey, key.Name)
+	}
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse TLS certificate from Secret %q: %w", errInvalidCertificateRef, key.Name, err)
+	}
+
+	fingerprint := sha256.Sum256(pair.Leaf.Raw)
+	return &secretCertificate{
+		certPEM:     certPEM,
+		keyPEM:      keyPEM,
+		fingerprint: hex.EncodeToString(fingerprint[:]),
+		notBefore:   pair.Leaf.NotBefore,
+		notAfter:    pair.Leaf.NotAfter,
+	}, nil
+}
+
+// syncCertificate ensures the certificate in the Secret referenced by the domain
+// has been uploaded to ngrok. It returns the ID of the certificate to attach to
+// the reserved domain, which is empty when the domain uses an ngrok-managed
+// certificate, and the ID of a previously uploaded certificate to delete once
+// the domain no longer uses it.
+//
+// A newly uploaded certificate is recorded in status right away, so it is
+// reused rather than uploaded again if attaching it to the domain fails.
+func (r *DomainReconciler) syncCertificate(ctx context.Context, domain *v1alpha1.Domain) (certID, staleCertID string, err error) {
+	current := domain.Status.Certificate
+	if current.IsUploaded() {
+		staleCertID = current.ID
+	}
+
+	ref := domain.Spec.CertificateRef
+	if ref == nil {
+		return "", staleCertID, nil
+	}
+	// Also rejected by the CRD's validation, but Domains created before it was added may still set it
+	if util.IsNgrokManagedDomain(domain.Spec.Domain) {
+		return "", "", fmt.Errorf("%w: certificateRef is not supported for ngrok-managed domains", errInvalidCertificateRef)
+	}
+
+	cert, err := r.getSecretCertificate(ctx, domain)
+	if err != nil {
+		return "", "", err
+	}
+
+	if current.IsUploaded() && current.SecretName == ref.Name && current.Fingerprint == cert.fingerprint {
+		return current.ID, "", nil
+	}
+
+	uploaded, err := r.TLSCertificatesClient.Create(ctx, &ngrok.TLSCertificateCreate{
+		Description:    fmt.Sprintf("Uploaded by ngrok-operator from Secret %s/%s", domain.Namespace, ref.Name),
+		Metadata:       commonv1alpha1.MetadataAPIString(domain.Spec.Metadata),
+		CertificatePEM: string(cert.certPEM),
+		PrivateKeyPEM:  string(cert.keyPEM),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	domain.Status.Certificate = &v1alpha1.DomainStatusCertificateInfo{
+		ID:          uploaded.ID,
+		SecretName:  ref.Name,
+		Fingerprint: cert.fingerprint,
+		NotBefore:   &metav1.Time{Time: cert.notBefore},
+		NotAfter:    &metav1.Time{Time: cert.notAfter},
+	}
+	r.Recorder.Eventf(domain, nil, v1.EventTypeNormal, "CertificateUploaded", "UploadCertificate", "Uploaded certificate %s from Secret %s, expires at %s", uploaded.ID, ref.Name, cert.notAfter.Format(time.RFC3339))
+	return uploaded.ID, staleCertID, nil
+}
+
+// deleteUploadedCertificate deletes a certificate previously uploaded for the
+// domain. Failures are reported as events rather than errors, since the domain
+// itself is in the desired state.
+func (r *DomainReconciler) deleteUploadedCertificate(ctx context.Context, domain *v1alpha1.Domain, certID string) {
+	if certID == "" {
+		return
+	}
+
+	if err := r.TLSCertificatesClient.Delete(ctx, certID); err != nil && !ngrok.IsNotFound(err) {
+		r.Recorder.Eventf(domain, nil, v1.EventTypeWarning, "CertificateDeleteFailed", "DeleteCertificate", "Failed to delete uploaded certificate %s: %s", certID, err.Error())
+	}
+}
+
+// certificateRequeueAfter returns how long until the domain's uploaded certificate
+// expires, so that the domain is reconciled again to report it as expired. It
+// returns 0 for ngrok-managed certificates and expired ones.
+func certificateRequeueAfter(domain *v1alpha1.Domain) time.Duration {
+	cert := domain.Status.Certificate
+	if !cert.IsUploaded() || cert.NotAfter == nil {
+		return 0
+	}
+	d := time.Until(cert.NotAfter.Time)
+	if d <= 0 {
+		return 0
+	}
+	// Reconcile once NotAfter has passed rather than right before it
+	return d + time.Second
+}
+
+// buildCertificateInfo returns the status of the certificate attached to the
+// reserved domain, keeping the details of an uploaded certificate when it is
+// the one attached
+func buildCertificateInfo(current *v1alpha1.DomainStatusCertificateInfo, certificate *ngrok.Ref) *v1alpha1.DomainStatusCertificateInfo {
+	if certificate == nil || certificate.ID == "" {
+		return nil
+	}
+
+	if current.IsUploaded() && current.ID == certificate.ID {
+		return current
+	}
+
+	return &v1alpha1.DomainStatusCertificateInfo{
+		ID: certificate.ID,
+	}
+}
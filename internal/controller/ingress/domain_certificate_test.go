@@ -0,0 +1,238 @@
+package ingress
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+// testTLSSecret returns a kubernetes.io/tls Secret holding a fresh self-signed certificate
+func testTLSSecret(t *testing.T, name string, notAfter time.Time) *v1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "app.example.com"},
+		DNSNames:     []string{"app.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &v1.Secret{
+		Name:      name,
+		Namespace: "default",
+		Type:      v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			v1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func newCertificateTestReconciler(t *testing.T, objs ...client.Object) (*DomainReconciler, *nmockapi.TLSCertificatesClient) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+
+	certs := nmockapi.NewTLSCertificatesClient()
+	return &DomainReconciler{
+		Client:                fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Log:                   logr.Discard(),
+		Recorder:              events.NewFakeRecorder(10),
+		TLSCertificatesClient: certs,
+	}, certs
+}
+
+func testCertificateDomain(secretName string) *ingressv1alpha1.Domain {
+	domain := &ingressv1alpha1.Domain{
+		Name:      "app-example-com",
+		Namespace: "default",
+		Spec:      ingressv1alpha1.DomainSpec{Domain: "app.example.com"},
+		Status:    ingressv1alpha1.DomainStatus{ID: "rd_123"},
+	}
+	if secretName != "" {
+		domain.Spec.CertificateRef = &ingressv1alpha1.DomainCertificateRef{Name: secretName}
+	}
+	return domain
+}
+
+func TestSyncCertificate_NoCertificateRef(t *testing.T) {
+	r, _ := newCertificateTestReconciler(t)
+	domain := testCertificateDomain("")
+
+	certID, staleCertID, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+	assert.Empty(t, certID)
+	assert.Empty(t, staleCertID)
+}
+
+func TestSyncCertificate_UploadsAndReuses(t *testing.T) {
+	notAfter := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	r, certs := newCertificateTestReconciler(t, testTLSSecret(t, "app-tls", notAfter))
+	domain := testCertificateDomain("app-tls")
+
+	certID, staleCertID, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+	assert.NotEmpty(t, certID)
+	assert.Empty(t, staleCertID)
+
+	uploaded, err := certs.Get(context.Background(), certID)
+	require.NoError(t, err)
+	assert.Equal(t, "app.example.com", uploaded.SubjectCommonName)
+
+	require.NotNil(t, domain.Status.Certificate)
+	assert.Equal(t, certID, domain.Status.Certificate.ID)
+	assert.Equal(t, "app-tls", domain.Status.Certificate.SecretName)
+	assert.NotEmpty(t, domain.Status.Certificate.Fingerprint)
+	require.NotNil(t, domain.Status.Certificate.NotAfter)
+	assert.True(t, notAfter.Equal(domain.Status.Certificate.NotAfter.Time))
+
+	// An unchanged Secret is not uploaded again
+	again, staleCertID, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+	assert.Equal(t, certID, again)
+	assert.Empty(t, staleCertID)
+}
+
+func TestSyncCertificate_RenewedSecret(t *testing.T) {
+	secret := testTLSSecret(t, "app-tls", time.Now().Add(24*time.Hour))
+	r, _ := newCertificateTestReconciler(t, secret)
+	domain := testCertificateDomain("app-tls")
+
+	oldCertID, _, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+
+	renewed := testTLSSecret(t, "app-tls", time.Now().Add(90*24*time.Hour))
+	secret.Data = renewed.Data
+	require.NoError(t, r.Update(context.Background(), secret))
+
+	certID, staleCertID, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldCertID, certID)
+	assert.Equal(t, oldCertID, staleCertID)
+	assert.Equal(t, certID, domain.Status.Certificate.ID)
+}
+
+func TestSyncCertificate_CertificateRefRemoved(t *testing.T) {
+	r, _ := newCertificateTestReconciler(t)
+	domain := testCertificateDomain("")
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_123", SecretName: "app-tls"}
+
+	certID, staleCertID, err := r.syncCertificate(context.Background(), domain)
+	require.NoError(t, err)
+	assert.Empty(t, certID)
+	assert.Equal(t, "cert_123", staleCertID)
+}
+
+func TestSyncCertificate_InvalidSecret(t *testing.T) {
+	missingKey := testTLSSecret(t, "missing-key", time.Now().Add(time.Hour))
+	delete(missingKey.Data, v1.TLSPrivateKeyKey)
+	mismatched := testTLSSecret(t, "mismatched", time.Now().Add(time.Hour))
+	mismatched.Data[v1.TLSPrivateKeyKey] = testTLSSecret(t, "other", time.Now().Add(time.Hour)).Data[v1.TLSPrivateKeyKey]
+
+	tests := []struct {
+		name       string
+		secretName string
+	}{
+		{name: "secret not found", secretName: "does-not-exist"},
+		{name: "missing private key", secretName: "missing-key"},
+		{name: "mismatched private key", secretName: "mismatched"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newCertificateTestReconciler(t, missingKey, mismatched)
+			domain := testCertificateDomain(tt.secretName)
+
+			_, _, err := r.syncCertificate(context.Background(), domain)
+			assert.ErrorIs(t, err, errInvalidCertificateRef)
+			assert.Nil(t, domain.Status.Certificate)
+		})
+	}
+}
+
+func TestSyncCertificate_NgrokManagedDomain(t *testing.T) {
+	r, certs := newCertificateTestReconciler(t, testTLSSecret(t, "app-tls", time.Now().Add(time.Hour)))
+	domain := testCertificateDomain("app-tls")
+	domain.Spec.Domain = "app.ngrok.app"
+
+	_, _, err := r.syncCertificate(context.Background(), domain)
+	assert.ErrorIs(t, err, errInvalidCertificateRef)
+	assert.ErrorContains(t, err, "not supported for ngrok-managed domains")
+	assert.Nil(t, domain.Status.Certificate)
+
+	// Nothing was uploaded
+	iter := certs.List(&ngrok.Paging{})
+	assert.False(t, iter.Next(context.Background()))
+}
+
+func TestCertificateRequeueAfter(t *testing.T) {
+	domain := testCertificateDomain("app-tls")
+	assert.Zero(t, certificateRequeueAfter(domain))
+
+	notAfter := metav1.NewTime(time.Now().Add(time.Hour))
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_123", SecretName: "app-tls", NotAfter: &notAfter}
+	d := certificateRequeueAfter(domain)
+	assert.Greater(t, d, time.Hour-time.Minute)
+	assert.LessOrEqual(t, d, time.Hour+time.Second)
+
+	expired := metav1.NewTime(time.Now().Add(-time.Hour))
+	domain.Status.Certificate.NotAfter = &expired
+	assert.Zero(t, certificateRequeueAfter(domain))
+
+	// ngrok-managed certificates are renewed by ngrok
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_managed"}
+	assert.Zero(t, certificateRequeueAfter(domain))
+}
+
+func TestDeleteUploadedCertificate(t *testing.T) {
+	r, certs := newCertificateTestReconciler(t)
+	uploaded, err := certs.Create(context.Background(), &ngrok.TLSCertificateCreate{
+		CertificatePEM: string(testTLSSecret(t, "app-tls", time.Now().Add(time.Hour)).Data[v1.TLSCertKey]),
+	})
+	require.NoError(t, err)
+
+	r.deleteUploadedCertificate(context.Background(), testCertificateDomain(""), uploaded.ID)
+	_, err = certs.Get(context.Background(), uploaded.ID)
+	assert.True(t, ngrok.IsNotFound(err))
+
+	// Already deleted certificates are ignored
+	r.deleteUploadedCertificate(context.Background(), testCertificateDomain(""), uploaded.ID)
+}
+
+func TestBuildCertificateInfo(t *testing.T) {
+	uploaded := &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_uploaded", SecretName: "app-tls", Fingerprint: "abc"}
+
+	assert.Nil(t, buildCertificateInfo(uploaded, nil))
+	assert.Same(t, uploaded, buildCertificateInfo(uploaded, &ngrok.Ref{ID: "cert_uploaded"}))
+	assert.Equal(t, &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_managed"}, buildCertificateInfo(uploaded, &ngrok.Ref{ID: "cert_managed"}))
+	assert.Equal(t, &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_managed"}, buildCertificateInfo(nil, &ngrok.Ref{ID: "cert_managed"}))
+}
@@ -1,6 +1,8 @@
 package ingress
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -33,6 +35,14 @@ const (
 	ReasonDanglingDNSRecord       = "DanglingDNSRecord"
 	ReasonProtectedDomain         = "ProtectedDomain"
 	ReasonDomainCreationFailed    = "DomainCreationFailed"
+	ReasonCertificateUploaded     = "CertificateUploaded"
+	ReasonCertificateExpired      = "CertificateExpired"
+	ReasonInvalidCertificateRef   = "InvalidCertificateRef"
+	ReasonDNSVerified             = "DNSVerified"
+	ReasonDNSRecordMissing        = "DNSRecordMissing"
+	ReasonDNSRecordWrongTarget    = "DNSRecordWrongTarget"
+	ReasonDNSRecordProxied        = "DNSRecordProxied"
+	ReasonDNSNotVerified          = "DNSNotVerified"
 )
 
 // setReadyCondition sets the Ready condition based on the overall domain state
@@ -57,6 +67,15 @@ func setDNSConfiguredCondition(domain *ingressv1alpha1.Domain, configured bool,
 
 // updateDomainConditions updates all domain conditions based on the ngrok domain state and any creation errors
 func updateDomainConditions(domain *ingressv1alpha1.Domain, ngrokDomain *ngrok.ReservedDomain, createErr error) {
+	// Problems with the referenced certificate Secret are reported on the certificate
+	// rather than as a failure to reserve the domain
+	if errors.Is(createErr, errInvalidCertificateRef) {
+		message := createErr.Error()
+		setCertificateReadyCondition(domain, false, ReasonInvalidCertificateRef, message)
+		setDomainReadyCondition(domain, false, ReasonInvalidCertificateRef, message)
+		return
+	}
+
 	// Handle creation errors first
 	if createErr != nil {
 		message := ngrokapi.SanitizeErrorMessage(createErr.Error())
@@ -78,6 +97,29 @@ func updateDomainConditions(domain *ingressv1alpha1.Domain, ngrokDomain *ngrok.R
 
 	setDomainCreatedCondition(domain, true, ReasonDomainCreated, "Domain successfully reserved")
 
+	// Uploaded certificates are attached as soon as the domain is reserved, but
+	// expire unless the Secret is renewed. No certificate has to be issued, so
+	// the ngrok API doesn't check the DNS records; that is left to the DNS
+	// verification.
+	if cert := domain.Status.Certificate; cert.IsUploaded() {
+		if !isDNSVerified(domain) {
+			setDNSConfiguredCondition(domain, false, ReasonDNSNotVerified, dnsNotVerifiedMessage(domain))
+		}
+		if cert.NotAfter != nil && !cert.NotAfter.After(time.Now()) {
+			message := fmt.Sprintf("Certificate from Secret %q expired at %s", cert.SecretName, cert.NotAfter.Format(time.RFC3339))
+			setCertificateReadyCondition(domain, false, ReasonCertificateExpired, message)
+			setDomainReadyCondition(domain, false, ReasonCertificateExpired, message)
+			return
+		}
+		message := fmt.Sprintf("Certificate uploaded from Secret %q", cert.SecretName)
+		if cert.NotAfter != nil {
+			message += ", expires at " + cert.NotAfter.Format(time.RFC3339)
+		}
+		setCertificateReadyCondition(domain, true, ReasonCertificateUploaded, message)
+		setDomainReadyCondition(domain, true, ReasonDomainActive, "Domain ready for use")
+		return
+	}
+
 	// Check if its an ngrok domain. If so the DNS and certs are managed by ngrok
 	// and already setup so the domain is ready.
 	if isNgrokManagedDomain(ngrokDomain) {
@@ -2,6 +2,7 @@ package ingress
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -194,6 +195,94 @@ func TestUpdateDomainConditions_CustomDomainWithProvisioningJob(t *testing.T) {
 	assert.Contains(t, readyCondition.Message, "Retries at")
 }
 
+func TestUpdateDomainConditions_UploadedCertificate(t *testing.T) {
+	notAfter := metav1.NewTime(time.Now().Add(24 * time.Hour))
+	domain := createTestDomain("test-domain", "test.example.com", "rd_123")
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{
+		ID:         "cert_123",
+		SecretName: "test-tls",
+		NotAfter:   &notAfter,
+	}
+	// The API returns no certificate management policy once a certificate is uploaded
+	ngrokDomain := &ngrok.ReservedDomain{
+		ID:          "rd_123",
+		Domain:      "test.example.com",
+		Certificate: &ngrok.Ref{ID: "cert_123"},
+	}
+
+	updateDomainConditions(domain, ngrokDomain, nil)
+
+	certCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionCertificateReady)
+	assert.NotNil(t, certCondition)
+	assert.Equal(t, metav1.ConditionTrue, certCondition.Status)
+	assert.Equal(t, ReasonCertificateUploaded, certCondition.Reason)
+	assert.Contains(t, certCondition.Message, "test-tls")
+	assert.Contains(t, certCondition.Message, "expires at")
+
+	readyCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionDomainReady)
+	assert.NotNil(t, readyCondition)
+	assert.Equal(t, metav1.ConditionTrue, readyCondition.Status)
+
+	// The ngrok API doesn't check the DNS records of domains with uploaded certificates
+	dnsCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	assert.NotNil(t, dnsCondition)
+	assert.Equal(t, metav1.ConditionFalse, dnsCondition.Status)
+	assert.Equal(t, ReasonDNSNotVerified, dnsCondition.Reason)
+
+	// The outcome of the DNS verification is kept
+	setDNSConfiguredCondition(domain, true, ReasonDNSVerified, "test.example.com resolves to abc.ngrok-cname.com")
+	updateDomainConditions(domain, ngrokDomain, nil)
+	dnsCondition = meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	assert.Equal(t, metav1.ConditionTrue, dnsCondition.Status)
+	assert.Equal(t, ReasonDNSVerified, dnsCondition.Reason)
+}
+
+func TestUpdateDomainConditions_UploadedCertificateExpired(t *testing.T) {
+	notAfter := metav1.NewTime(time.Now().Add(-time.Hour))
+	domain := createTestDomain("test-domain", "test.example.com", "rd_123")
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{
+		ID:         "cert_123",
+		SecretName: "test-tls",
+		NotAfter:   &notAfter,
+	}
+	ngrokDomain := &ngrok.ReservedDomain{
+		ID:          "rd_123",
+		Domain:      "test.example.com",
+		Certificate: &ngrok.Ref{ID: "cert_123"},
+	}
+
+	updateDomainConditions(domain, ngrokDomain, nil)
+
+	certCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionCertificateReady)
+	assert.NotNil(t, certCondition)
+	assert.Equal(t, metav1.ConditionFalse, certCondition.Status)
+	assert.Equal(t, ReasonCertificateExpired, certCondition.Reason)
+
+	readyCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionDomainReady)
+	assert.NotNil(t, readyCondition)
+	assert.Equal(t, metav1.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, ReasonCertificateExpired, readyCondition.Reason)
+}
+
+func TestUpdateDomainConditions_InvalidCertificateRef(t *testing.T) {
+	domain := createTestDomain("test-domain", "test.example.com", "rd_123")
+	err := fmt.Errorf("%w: Secret %q not found", errInvalidCertificateRef, "test-tls")
+
+	updateDomainConditions(domain, nil, err)
+
+	certCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionCertificateReady)
+	assert.NotNil(t, certCondition)
+	assert.Equal(t, metav1.ConditionFalse, certCondition.Status)
+	assert.Equal(t, ReasonInvalidCertificateRef, certCondition.Reason)
+
+	readyCondition := meta.FindStatusCondition(domain.Status.Conditions, ConditionDomainReady)
+	assert.NotNil(t, readyCondition)
+	assert.Equal(t, ReasonInvalidCertificateRef, readyCondition.Reason)
+
+	// The domain itself was not reported as failing to reserve
+	assert.Nil(t, meta.FindStatusCondition(domain.Status.Conditions, ConditionDomainCreated))
+}
+
 func TestIsNgrokManagedDomain(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -27,16 +27,23 @@ package ingress
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -45,7 +52,10 @@ import (
 	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
 	"github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	basecontroller "github.com/ngrok/ngrok-operator/internal/controller"
+	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+	"github.com/ngrok/ngrok-operator/internal/drift"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/resolvers"
 	"github.com/ngrok/ngrok-operator/internal/util"
 )
 
@@ -59,6 +69,23 @@ type DomainReconciler struct {
 	DomainsClient ngrokapi.DomainClient
 	DrainState    basecontroller.DrainState
 
+	// TLSCertificatesClient uploads the certificates of Domains that reference
+	// a TLS Secret with spec.certificateRef
+	TLSCertificatesClient ngrokapi.TLSCertificatesClient
+
+	// ExternalDNS enables publishing the CNAME records of custom domains as
+	// external-dns DNSEndpoint resources. The DNSEndpoint CRD must be installed.
+	ExternalDNS bool
+
+	// DNSResolver resolves the CNAME records of custom domains from inside the
+	// cluster to report whether DNS is configured in the DNSConfigured condition.
+	// Verification is skipped when nil.
+	DNSResolver resolvers.DNSResolver
+
+	// DriftScanner periodically checks reserved domains for changes made outside of the operator. Drift is not
+	// checked when nil.
+	DriftScanner *drift.Scanner
+
 	controller *basecontroller.BaseController[*v1alpha1.Domain]
 }
 
@@ -67,6 +94,9 @@ func (r *DomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.DomainsClient == nil {
 		return errors.New("DomainsClient must be set")
 	}
+	if r.TLSCertificatesClient == nil {
+		return errors.New("TLSCertificatesClient must be set")
+	}
 
 	r.controller = &basecontroller.BaseController[*v1alpha1.Domain]{
 		Kube:       r.Client,
@@ -74,10 +104,12 @@ func (r *DomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Recorder:   r.Recorder,
 		DrainState: r.DrainState,
 
-		StatusID: func(cr *v1alpha1.Domain) string { return cr.Status.ID },
-		Create:   r.create,
-		Update:   r.update,
-		Delete:   r.delete,
+		StatusID:     func(cr *v1alpha1.Domain) string { return cr.Status.ID },
+		Adopt:        r.adopt,
+		Create:       r.create,
+		Update:       r.update,
+		Delete:       r.delete,
+		RequeueAfter: r.requeueAfter,
 		ErrResult: func(_ basecontroller.BaseControllerOp, _ *v1alpha1.Domain, err error) (reconcile.Result, error) {
 			retryableErrors := []int{
 				// Domain still attached to an edge, probably a race condition.
@@ -91,16 +123,42 @@ func (r *DomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			if ngrok.IsErrorCode(err, retryableErrors...) {
 				return ctrl.Result{}, err
 			}
+			if errors.Is(err, errDNSEndpointPending) {
+				return ctrl.Result{RequeueAfter: dnsEndpointPendingRequeue}, nil
+			}
 			return basecontroller.CtrlResultForErr(err)
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Domain{}).
-		WithEventFilter(predicate.Or(
-			predicate.AnnotationChangedPredicate{},
-			predicate.GenerationChangedPredicate{},
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Domain{}, domainCertificateRefIndex, indexDomainCertificateRef); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Domain{}, builder.WithPredicates(
+			predicate.Or(
+				predicate.AnnotationChangedPredicate{},
+				predicate.GenerationChangedPredicate{},
+			),
 		)).
+		// Upload renewed certificates. Secrets have no generation, so any change is considered.
+		Watches(&v1.Secret{}, r.controller.NewEnqueueRequestForMapFunc(r.findDomainsForSecret))
+	if r.ExternalDNS {
+		// Restore DNSEndpoints that are changed or deleted out from under us
+		endpoint := &unstructured.Unstructured{}
+		endpoint.SetGroupVersionKind(DNSEndpointGVK)
+		b = b.Watches(endpoint, handler.EnqueueRequestsFromMapFunc(findDomainForDNSEndpoint), builder.WithPredicates(
+			predicate.Or(
+				predicate.AnnotationChangedPredicate{},
+				predicate.GenerationChangedPredicate{},
+			),
+		))
+	}
+	if r.DriftScanner != nil {
+		b = b.WatchesRawSource(drift.Register(r.DriftScanner, "Domain", func() client.ObjectList { return &v1alpha1.DomainList{} }, r.checkDrift))
+	}
+
+	return b.
 		WithOptions(controller.Options{
 			// Use a custom rate limiter to exponentially backoff while certificates for domains provision
 			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
@@ -111,6 +169,26 @@ func (r *DomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// requeueAfter returns when a ready domain is reconciled again: to verify its
+// DNS records periodically, or to report its uploaded certificate as expired
+// once it is, whichever comes first
+func (r *DomainReconciler) requeueAfter(domain *v1alpha1.Domain) time.Duration {
+	d := r.dnsRequeueAfter(domain)
+	if cert := certificateRequeueAfter(domain); cert > 0 && (d == 0 || cert < d) {
+		d = cert
+	}
+	return d
+}
+
+// findDomainForDNSEndpoint maps a DNSEndpoint to the Domain it publishes records for
+func findDomainForDNSEndpoint(_ context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[labels.Domain]
+	if !ok || name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
@@ -159,6 +237,11 @@ func (r *DomainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 }
 
 func (r *DomainReconciler) create(ctx context.Context, domain *v1alpha1.Domain) error {
+	certID, staleCertID, err := r.syncCertificate(ctx, domain)
+	if err != nil {
+		return r.updateStatus(ctx, domain, nil, err)
+	}
+
 	// First check if the reserved domain already exists. The API is sometimes returning dangling CNAME records
 	// errors right now, so we'll check if the domain already exists before trying to create it.
 	resp, err := r.findReservedDomainByHostname(ctx, domain.Spec.Domain)
@@ -175,15 +258,40 @@ func (r *DomainReconciler) create(ctx context.Context, domain *v1alpha1.Domain)
 			Metadata:    commonv1alpha1.MetadataAPIString(domain.Spec.Metadata),
 			ResolvesTo:  buildResolvesToRequest(domain.Spec.GetResolvesTo()),
 		}
+		if certID != "" {
+			req.CertificateID = &certID
+		}
 		resp, err = r.DomainsClient.Create(ctx, req)
 		if err != nil {
 			return r.updateStatus(ctx, domain, resp, err)
 		}
+	} else if certID != "" && !hasCertificate(resp, certID) {
+		resp, err = r.DomainsClient.Update(ctx, &ngrok.ReservedDomainUpdate{
+			ID:            resp.ID,
+			CertificateID: &certID,
+		})
+		if err != nil {
+			return r.updateStatus(ctx, domain, nil, err)
+		}
 	}
 
+	r.deleteUploadedCertificate(ctx, domain, staleCertID)
 	return r.updateStatus(ctx, domain, resp, nil)
 }
 
+// adopt takes ownership of the existing reserved domain with the given ID, which must be for the domain in the spec
+func (r *DomainReconciler) adopt(ctx context.Context, domain *v1alpha1.Domain, id string) error {
+	resp, err := r.DomainsClient.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(resp.Domain, domain.Spec.Domain) {
+		return reconcile.TerminalError(fmt.Errorf("reserved domain %s is for %s, not %s", id, resp.Domain, domain.Spec.Domain))
+	}
+	domain.Status.ID = resp.ID
+	return nil
+}
+
 func (r *DomainReconciler) update(ctx context.Context, domain *v1alpha1.Domain) error {
 	resp, err := r.DomainsClient.Get(ctx, domain.Status.ID)
 	if err != nil {
@@ -197,38 +305,111 @@ func (r *DomainReconciler) update(ctx context.Context, domain *v1alpha1.Domain)
 		return r.updateStatus(ctx, domain, nil, err)
 	}
 
+	certID, staleCertID, err := r.syncCertificate(ctx, domain)
+	if err != nil {
+		return r.updateStatus(ctx, domain, nil, err)
+	}
+	// Attach a new or renewed uploaded certificate, or go back to an
+	// ngrok-managed certificate when certificateRef is removed
+	attachCert := certID != "" && !hasCertificate(resp, certID)
+	detachCert := certID == "" && staleCertID != "" && hasCertificate(resp, staleCertID)
+
 	// Only update the domain if updatable fields have changed
-	specResolvesTo := buildResolvesToRequest(domain.Spec.GetResolvesTo())
-	specMetadata := commonv1alpha1.MetadataAPIString(domain.Spec.Metadata)
-	if domain.Spec.Description == resp.Description &&
-		specMetadata == resp.Metadata &&
-		reflect.DeepEqual(specResolvesTo, resp.ResolvesTo) {
+	if len(domainDriftedFields(domain, resp)) == 0 && !attachCert && !detachCert {
 		// No changes needed, still update status to ensure conditions are current
+		r.deleteUploadedCertificate(ctx, domain, staleCertID)
 		return r.updateStatus(ctx, domain, resp, nil)
 	}
 
 	req := &ngrok.ReservedDomainUpdate{
 		ID:          domain.Status.ID,
 		Description: &domain.Spec.Description,
-		Metadata:    &specMetadata,
-		ResolvesTo:  specResolvesTo,
+		Metadata:    new(commonv1alpha1.MetadataAPIString(domain.Spec.Metadata)),
+		ResolvesTo:  buildResolvesToRequest(domain.Spec.GetResolvesTo()),
+	}
+	if attachCert {
+		req.CertificateID = &certID
+	}
+	if detachCert {
+		req.CertificateManagementPolicy = &managedCertificatePolicy
 	}
 	resp, err = r.DomainsClient.Update(ctx, req)
+	if err == nil {
+		r.deleteUploadedCertificate(ctx, domain, staleCertID)
+	}
 	return r.updateStatus(ctx, domain, resp, err)
 }
 
+// checkDrift reports the fields of the reserved domain that were changed outside of the operator
+func (r *DomainReconciler) checkDrift(ctx context.Context, domain *v1alpha1.Domain) ([]string, error) {
+	if domain.Status.ID == "" || domain.Generation != domain.Status.ObservedGeneration {
+		return nil, drift.ErrSkip
+	}
+	resp, err := r.DomainsClient.Get(ctx, domain.Status.ID)
+	if ngrok.IsNotFound(err) {
+		return []string{drift.FieldDeleted}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return domainDriftedFields(domain, resp), nil
+}
+
+// domainDriftedFields returns the updatable fields of the reserved domain that don't match the spec
+func domainDriftedFields(domain *v1alpha1.Domain, resp *ngrok.ReservedDomain) []string {
+	fields := []string{}
+	if resp.Description != domain.Spec.Description {
+		fields = append(fields, "description")
+	}
+	if resp.Metadata != commonv1alpha1.MetadataAPIString(domain.Spec.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if !reflect.DeepEqual(buildResolvesToRequest(domain.Spec.GetResolvesTo()), resp.ResolvesTo) {
+		fields = append(fields, "resolvesTo")
+	}
+	return fields
+}
+
 func (r *DomainReconciler) delete(ctx context.Context, domain *v1alpha1.Domain) error {
+	// Retained domains keep their DNS records, since the reserved domain still
+	// exists in ngrok and the records still point at it.
 	if domain.Spec.ReclaimPolicy != v1alpha1.DomainReclaimPolicyDelete {
 		return nil
 	}
 
+	// Remove the DNS records first and wait for the DNSEndpoint to be gone, e.g.
+	// until finalizers on it have run. external-dns then removes the record from
+	// the DNS provider on its next sync. Until it does, the ngrok API refuses to
+	// delete the domain with a dangling CNAME error (511), which is retried.
+	if err := r.deleteDNSEndpoint(ctx, domain); err != nil {
+		return err
+	}
+	if r.ExternalDNS {
+		remaining, err := r.getDNSEndpoint(ctx, domain)
+		if err != nil {
+			return err
+		}
+		if remaining != nil && ownsDNSEndpoint(domain, remaining) {
+			return errDNSEndpointPending
+		}
+	}
+
 	err := r.DomainsClient.Delete(ctx, domain.Status.ID)
 	if err == nil || ngrok.IsNotFound(err) {
 		domain.Status.ID = ""
+		// The certificate can only be deleted once no domain uses it
+		if domain.Status.Certificate.IsUploaded() {
+			r.deleteUploadedCertificate(ctx, domain, domain.Status.Certificate.ID)
+		}
 	}
 	return err
 }
 
+// hasCertificate returns true if the certificate with the given ID is attached to the reserved domain
+func hasCertificate(ngrokDomain *ngrok.ReservedDomain, certID string) bool {
+	return ngrokDomain.Certificate != nil && ngrokDomain.Certificate.ID == certID
+}
+
 // finds the reserved domain by the hostname. If it doesn't exist, returns nil
 func (r *DomainReconciler) findReservedDomainByHostname(ctx context.Context, domainName string) (*ngrok.ReservedDomain, error) {
 	iter := r.DomainsClient.List(&ngrok.Paging{})
@@ -250,13 +431,29 @@ func (r *DomainReconciler) updateStatus(ctx context.Context, domain *v1alpha1.Do
 		domain.Status.ACMEChallengeCNAMETarget = ngrokDomain.ACMEChallengeCNAMETarget
 		domain.Status.ResolvesTo = buildResolvesToStatus(ngrokDomain.ResolvesTo)
 
-		domain.Status.Certificate = buildCertificateInfo(ngrokDomain.Certificate)
+		domain.Status.Certificate = buildCertificateInfo(domain.Status.Certificate, ngrokDomain.Certificate)
 		domain.Status.CertificateManagementPolicy = buildCertificateManagementPolicy(ngrokDomain.CertificateManagementPolicy)
 		domain.Status.CertificateManagementStatus = buildCertificateManagementStatus(ngrokDomain.CertificateManagementStatus)
 	}
 
+	previousDNSConfigured := dnsConfiguredCondition(domain)
+	reconcileErr := createErr
+
+	// Verify the DNS records before the conditions are computed, so that their
+	// outcome is part of the Ready condition
+	var dns *dnsCheck
+	if ngrokDomain != nil && createErr == nil {
+		if err := r.reconcileDNSEndpoint(ctx, domain); err != nil {
+			r.Recorder.Eventf(domain, nil, v1.EventTypeWarning, "DNSEndpointFailed", "ReconcileDNSEndpoint", "Failed to publish DNS records: %s", err.Error())
+			reconcileErr = err
+		}
+		dns = r.verifyDomainDNS(ctx, domain)
+	}
+
 	updateDomainConditions(domain, ngrokDomain, createErr)
-	return r.controller.ReconcileStatus(ctx, domain, createErr)
+	r.applyDNSCheck(domain, dns, previousDNSConfigured)
+
+	return r.controller.ReconcileStatus(ctx, domain, reconcileErr)
 }
 
 func buildResolvesToStatus(resolvesTo []ngrok.ReservedDomainResolvesToEntry) []v1alpha1.DomainResolvesToEntry {
@@ -287,16 +484,6 @@ func buildResolvesToRequest(resolvesTo []v1alpha1.DomainResolvesToEntry) []ngrok
 	return result
 }
 
-func buildCertificateInfo(certificate *ngrok.Ref) *v1alpha1.DomainStatusCertificateInfo {
-	if certificate == nil || certificate.ID == "" {
-		return nil
-	}
-
-	return &v1alpha1.DomainStatusCertificateInfo{
-		ID: certificate.ID,
-	}
-}
-
 func buildCertificateManagementPolicy(policy *ngrok.ReservedDomainCertPolicy) *v1alpha1.DomainStatusCertificateManagementPolicy {
 	if policy == nil {
 		return nil
@@ -0,0 +1,151 @@
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+)
+
+// DNSEndpointGVK is the external-dns DNSEndpoint kind the Domain controller
+// publishes CNAME records with. external-dns is an optional dependency, so the
+// kind is handled as unstructured rather than importing its API types.
+var DNSEndpointGVK = schema.GroupVersionKind{
+	Group:   "externaldns.k8s.io",
+	Version: "v1alpha1",
+	Kind:    "DNSEndpoint",
+}
+
+// errDNSEndpointPending is returned while a deleted Domain waits for its
+// DNSEndpoint to be gone
+var errDNSEndpointPending = errors.New("waiting for the DNSEndpoint to be deleted")
+
+// dnsEndpointPendingRequeue is how often a deleted Domain checks whether its
+// DNSEndpoint is gone
+const dnsEndpointPendingRequeue = 5 * time.Second
+
+// acmeChallengePrefix is the label external ACME DNS-01 challenges are
+// delegated under
+const acmeChallengePrefix = "_acme-challenge."
+
+// buildDNSEndpointRecords returns the external-dns endpoints for the CNAME
+// records a custom domain needs: the domain itself and, for wildcard domains,
+// the ACME challenge record. Domains managed by ngrok have no CNAME target and
+// need no records.
+func buildDNSEndpointRecords(domain *v1alpha1.Domain) []any {
+	records := []any{}
+	if target := domain.Status.CNAMETarget; target != nil && *target != "" {
+		records = append(records, map[string]any{
+			"dnsName":    domain.Spec.Domain,
+			"recordType": "CNAME",
+			"targets":    []any{*target},
+		})
+	}
+	if target := domain.Status.ACMEChallengeCNAMETarget; target != nil && *target != "" {
+		records = append(records, map[string]any{
+			"dnsName":    acmeChallengePrefix + strings.TrimPrefix(domain.Spec.Domain, "*."),
+			"recordType": "CNAME",
+			"targets":    []any{*target},
+		})
+	}
+	return records
+}
+
+// reconcileDNSEndpoint creates, updates or removes the DNSEndpoint publishing
+// the CNAME records for the domain. The DNSEndpoint shares the Domain's name and
+// namespace and is labeled with the Domain it belongs to; an existing
+// DNSEndpoint without that label is never modified.
+func (r *DomainReconciler) reconcileDNSEndpoint(ctx context.Context, domain *v1alpha1.Domain) error {
+	if !r.ExternalDNS {
+		return nil
+	}
+
+	records := buildDNSEndpointRecords(domain)
+	if len(records) == 0 {
+		return r.deleteDNSEndpoint(ctx, domain)
+	}
+
+	existing, err := r.getDNSEndpoint(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		endpoint := &unstructured.Unstructured{}
+		endpoint.SetGroupVersionKind(DNSEndpointGVK)
+		endpoint.SetNamespace(domain.Namespace)
+		endpoint.SetName(domain.Name)
+		endpoint.SetLabels(map[string]string{labels.Domain: domain.Name})
+		endpoint.Object["spec"] = map[string]any{"endpoints": records}
+		if err := r.Create(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to create DNSEndpoint %s/%s: %w", domain.Namespace, domain.Name, err)
+		}
+		r.Log.V(1).Info("Created DNSEndpoint", "namespace", domain.Namespace, "name", domain.Name)
+		return nil
+	}
+
+	if !ownsDNSEndpoint(domain, existing) {
+		return fmt.Errorf("DNSEndpoint %s/%s already exists and is not managed for this Domain", domain.Namespace, domain.Name)
+	}
+
+	current, _, _ := unstructured.NestedSlice(existing.Object, "spec", "endpoints")
+	if reflect.DeepEqual(current, records) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(existing.Object, records, "spec", "endpoints"); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update DNSEndpoint %s/%s: %w", domain.Namespace, domain.Name, err)
+	}
+	r.Log.V(1).Info("Updated DNSEndpoint", "namespace", domain.Namespace, "name", domain.Name)
+	return nil
+}
+
+// deleteDNSEndpoint removes the DNSEndpoint publishing the CNAME records for the
+// domain, if the controller created one.
+func (r *DomainReconciler) deleteDNSEndpoint(ctx context.Context, domain *v1alpha1.Domain) error {
+	if !r.ExternalDNS {
+		return nil
+	}
+
+	existing, err := r.getDNSEndpoint(ctx, domain)
+	if err != nil || existing == nil || !ownsDNSEndpoint(domain, existing) {
+		return err
+	}
+
+	if err := r.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete DNSEndpoint %s/%s: %w", domain.Namespace, domain.Name, err)
+	}
+	r.Log.V(1).Info("Deleted DNSEndpoint", "namespace", domain.Namespace, "name", domain.Name)
+	return nil
+}
+
+// getDNSEndpoint returns the DNSEndpoint named after the domain, or nil if there is none
+func (r *DomainReconciler) getDNSEndpoint(ctx context.Context, domain *v1alpha1.Domain) (*unstructured.Unstructured, error) {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	err := r.Get(ctx, types.NamespacedName{Namespace: domain.Namespace, Name: domain.Name}, endpoint)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DNSEndpoint %s/%s: %w", domain.Namespace, domain.Name, err)
+	}
+	return endpoint, nil
+}
+
+func ownsDNSEndpoint(domain *v1alpha1.Domain, endpoint *unstructured.Unstructured) bool {
+	return endpoint.GetLabels()[labels.Domain] == domain.Name
+}
@@ -0,0 +1,147 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+)
+
+func newDNSEndpointTestReconciler(t *testing.T, objs ...client.Object) *DomainReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	scheme.AddKnownTypeWithName(DNSEndpointGVK, &unstructured.Unstructured{})
+	listGVK := DNSEndpointGVK
+	listGVK.Kind += "List"
+	scheme.AddKnownTypeWithName(listGVK, &unstructured.UnstructuredList{})
+
+	return &DomainReconciler{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Log:         logr.Discard(),
+		ExternalDNS: true,
+	}
+}
+
+func getTestDNSEndpoint(t *testing.T, r *DomainReconciler, name string) *unstructured.Unstructured {
+	t.Helper()
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	err := r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, endpoint)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return endpoint
+}
+
+func testDNSEndpointDomain(domainName string, cnameTarget, acmeTarget *string) *ingressv1alpha1.Domain {
+	return &ingressv1alpha1.Domain{
+		Name:      "app-example-com",
+		Namespace: "default",
+		Spec:      ingressv1alpha1.DomainSpec{Domain: domainName},
+		Status: ingressv1alpha1.DomainStatus{
+			ID:                       "rd_123",
+			CNAMETarget:              cnameTarget,
+			ACMEChallengeCNAMETarget: acmeTarget,
+		},
+	}
+}
+
+func TestBuildDNSEndpointRecords(t *testing.T) {
+	assert.Empty(t, buildDNSEndpointRecords(testDNSEndpointDomain("app.ngrok.app", nil, nil)))
+
+	records := buildDNSEndpointRecords(testDNSEndpointDomain("*.example.com", new("abc.ngrok-cname.com"), new("abc.acme.ngrok-cname.com")))
+	assert.Equal(t, []any{
+		map[string]any{"dnsName": "*.example.com", "recordType": "CNAME", "targets": []any{"abc.ngrok-cname.com"}},
+		map[string]any{"dnsName": "_acme-challenge.example.com", "recordType": "CNAME", "targets": []any{"abc.acme.ngrok-cname.com"}},
+	}, records)
+}
+
+func TestReconcileDNSEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		r := newDNSEndpointTestReconciler(t)
+		r.ExternalDNS = false
+		require.NoError(t, r.reconcileDNSEndpoint(ctx, testDNSEndpointDomain("app.example.com", new("abc.ngrok-cname.com"), nil)))
+		assert.Nil(t, getTestDNSEndpoint(t, r, "app-example-com"))
+	})
+
+	t.Run("creates, updates and removes records", func(t *testing.T) {
+		r := newDNSEndpointTestReconciler(t)
+		domain := testDNSEndpointDomain("app.example.com", new("abc.ngrok-cname.com"), nil)
+
+		require.NoError(t, r.reconcileDNSEndpoint(ctx, domain))
+		endpoint := getTestDNSEndpoint(t, r, "app-example-com")
+		require.NotNil(t, endpoint)
+		assert.Equal(t, "app-example-com", endpoint.GetLabels()[labels.Domain])
+		records, _, _ := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+		assert.Equal(t, buildDNSEndpointRecords(domain), records)
+
+		domain.Status.CNAMETarget = new("def.ngrok-cname.com")
+		require.NoError(t, r.reconcileDNSEndpoint(ctx, domain))
+		endpoint = getTestDNSEndpoint(t, r, "app-example-com")
+		records, _, _ = unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+		assert.Equal(t, buildDNSEndpointRecords(domain), records)
+
+		domain.Status.CNAMETarget = nil
+		require.NoError(t, r.reconcileDNSEndpoint(ctx, domain))
+		assert.Nil(t, getTestDNSEndpoint(t, r, "app-example-com"))
+	})
+
+	t.Run("does not modify an unmanaged DNSEndpoint", func(t *testing.T) {
+		unmanaged := &unstructured.Unstructured{}
+		unmanaged.SetGroupVersionKind(DNSEndpointGVK)
+		unmanaged.SetNamespace("default")
+		unmanaged.SetName("app-example-com")
+		r := newDNSEndpointTestReconciler(t, unmanaged)
+		domain := testDNSEndpointDomain("app.example.com", new("abc.ngrok-cname.com"), nil)
+
+		assert.ErrorContains(t, r.reconcileDNSEndpoint(ctx, domain), "not managed for this Domain")
+		require.NoError(t, r.deleteDNSEndpoint(ctx, domain))
+		assert.NotNil(t, getTestDNSEndpoint(t, r, "app-example-com"))
+	})
+}
+
+func TestDeleteWaitsForDNSEndpoint(t *testing.T) {
+	// A finalizer keeps the DNSEndpoint around after it is deleted
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	endpoint.SetNamespace("default")
+	endpoint.SetName("app-example-com")
+	endpoint.SetLabels(map[string]string{labels.Domain: "app-example-com"})
+	endpoint.SetFinalizers([]string{"example.com/cleanup"})
+	r := newDNSEndpointTestReconciler(t, endpoint)
+
+	domain := testDNSEndpointDomain("app.example.com", new("abc.ngrok-cname.com"), nil)
+	domain.Spec.ReclaimPolicy = ingressv1alpha1.DomainReclaimPolicyDelete
+
+	// The reserved domain isn't deleted while the DNSEndpoint exists
+	assert.ErrorIs(t, r.delete(t.Context(), domain), errDNSEndpointPending)
+	assert.Equal(t, "rd_123", domain.Status.ID)
+	assert.NotNil(t, getTestDNSEndpoint(t, r, "app-example-com"))
+}
+
+func TestFindDomainForDNSEndpoint(t *testing.T) {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetNamespace("default")
+	endpoint.SetName("app-example-com")
+	assert.Empty(t, findDomainForDNSEndpoint(context.Background(), endpoint))
+
+	endpoint.SetLabels(map[string]string{labels.Domain: "app-example-com"})
+	requests := findDomainForDNSEndpoint(context.Background(), endpoint)
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Namespace: "default", Name: "app-example-com"}, requests[0].NamespacedName)
+}
@@ -0,0 +1,221 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/resolvers"
+)
+
+const (
+	// dnsLookupTimeout bounds the DNS lookups for a single domain so a slow
+	// resolver doesn't hold up the reconcile
+	dnsLookupTimeout = 5 * time.Second
+
+	// dnsVerifyInterval is how often the DNS records of a ready custom domain
+	// are verified again
+	dnsVerifyInterval = 10 * time.Minute
+
+	// wildcardProbeLabel is the label resolved in place of "*" to check the
+	// records of wildcard domains
+	wildcardProbeLabel = "ngrok-operator-dns-check"
+)
+
+// cdnCNAMESuffixes are CNAME targets of CDNs that proxy traffic instead of
+// passing it through to ngrok
+var cdnCNAMESuffixes = []string{
+	".cdn.cloudflare.net",
+	".cloudfront.net",
+	".akamaiedge.net",
+	".edgekey.net",
+	".fastly.net",
+	".azureedge.net",
+}
+
+// dnsCheck is the outcome of verifying a domain's DNS records
+type dnsCheck struct {
+	configured bool
+	reason     string
+	message    string
+}
+
+// verifyDomainDNS resolves the CNAME records of a custom domain from inside the
+// cluster. It returns nil when the records weren't checked: for domains managed
+// by ngrok, which have no CNAME target, and when a lookup fails for reasons
+// other than a missing record.
+func (r *DomainReconciler) verifyDomainDNS(ctx context.Context, domain *v1alpha1.Domain) *dnsCheck {
+	if r.DNSResolver == nil || domain.Status.CNAMETarget == nil || *domain.Status.CNAMETarget == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	defer cancel()
+
+	baseDomain := strings.TrimPrefix(domain.Spec.Domain, "*.")
+	host := domain.Spec.Domain
+	if host != baseDomain {
+		host = wildcardProbeLabel + "." + baseDomain
+	}
+
+	check, err := r.checkCNAME(ctx, host, *domain.Status.CNAMETarget)
+	if err == nil && check.configured {
+		if target := domain.Status.ACMEChallengeCNAMETarget; target != nil && *target != "" {
+			check, err = r.checkCNAME(ctx, acmeChallengePrefix+baseDomain, *target)
+			if err == nil && !check.configured && check.reason == ReasonDNSRecordMissing {
+				check.reason = ReasonACMEChallengeRequired
+			}
+		}
+	}
+	if err != nil {
+		r.Log.V(1).Info("Unable to verify domain DNS records", "domain", domain.Spec.Domain, "error", err.Error())
+		return nil
+	}
+	return &check
+}
+
+// applyDNSCheck sets the DNSConfigured condition from the outcome of the DNS
+// verification, which is left as reported from the ngrok API when check is nil.
+// The Ready condition is left as reported from the ngrok API, since the
+// records seen from inside the cluster may differ from those clients see. An
+// event is emitted when the outcome changed since the previous DNSConfigured
+// condition.
+func (r *DomainReconciler) applyDNSCheck(domain *v1alpha1.Domain, check *dnsCheck, previous *metav1.Condition) {
+	if check == nil {
+		return
+	}
+
+	setDNSConfiguredCondition(domain, check.configured, check.reason, check.message)
+
+	if previous != nil && previous.Reason == check.reason && previous.Message == check.message {
+		return
+	}
+	if check.configured {
+		r.Recorder.Eventf(domain, nil, v1.EventTypeNormal, "DNSConfigured", "VerifyDNS", "%s", check.message)
+	} else {
+		r.Recorder.Eventf(domain, nil, v1.EventTypeWarning, "DNSMisconfigured", "VerifyDNS", "%s", check.message)
+	}
+}
+
+// dnsRequeueAfter returns how often the DNS records of a custom domain are
+// verified again, so that changes to them are reported. It returns 0 for
+// domains whose records aren't verified.
+func (r *DomainReconciler) dnsRequeueAfter(domain *v1alpha1.Domain) time.Duration {
+	if r.DNSResolver == nil || domain.Status.CNAMETarget == nil || *domain.Status.CNAMETarget == "" {
+		return 0
+	}
+	return dnsVerifyInterval
+}
+
+// checkCNAME verifies that host is a CNAME for target. CNAME chains continuing
+// past the target and CNAME flattening at the zone apex are both accepted. An
+// error is only returned when the records could not be looked up.
+func (r *DomainReconciler) checkCNAME(ctx context.Context, host, target string) (dnsCheck, error) {
+	target = normalizeDNSName(target)
+
+	canonical, err := r.DNSResolver.LookupCNAME(ctx, host)
+	if err != nil {
+		if resolvers.IsDNSNotFound(err) {
+			return missingDNSRecord(host, target), nil
+		}
+		return dnsCheck{}, err
+	}
+	canonical = normalizeDNSName(canonical)
+
+	if canonical == target {
+		return verifiedDNSRecord(host, target), nil
+	}
+
+	if canonical != normalizeDNSName(host) {
+		// The target may itself be a CNAME
+		if targetCanonical, err := r.DNSResolver.LookupCNAME(ctx, target); err == nil && normalizeDNSName(targetCanonical) == canonical {
+			return verifiedDNSRecord(host, target), nil
+		}
+		if slices.ContainsFunc(cdnCNAMESuffixes, func(suffix string) bool { return strings.HasSuffix(canonical, suffix) }) {
+			return dnsCheck{
+				reason:  ReasonDNSRecordProxied,
+				message: fmt.Sprintf("%s is proxied by a CDN through %s. Point it directly at %s with proxying disabled", host, canonical, target),
+			}, nil
+		}
+		return dnsCheck{
+			reason:  ReasonDNSRecordWrongTarget,
+			message: fmt.Sprintf("%s is a CNAME for %s instead of %s", host, canonical, target),
+		}, nil
+	}
+
+	// No CNAME record. The host may still resolve to the target's addresses
+	// when the DNS provider flattens the CNAME.
+	addrs, err := r.DNSResolver.LookupHost(ctx, host)
+	if err != nil {
+		if resolvers.IsDNSNotFound(err) {
+			return missingDNSRecord(host, target), nil
+		}
+		return dnsCheck{}, err
+	}
+	if targetAddrs, err := r.DNSResolver.LookupHost(ctx, target); err == nil && slices.ContainsFunc(addrs, func(addr string) bool { return slices.Contains(targetAddrs, addr) }) {
+		return verifiedDNSRecord(host, target), nil
+	}
+	return dnsCheck{
+		reason:  ReasonDNSRecordWrongTarget,
+		message: fmt.Sprintf("%s resolves to %s but is not a CNAME for %s", host, strings.Join(addrs, ", "), target),
+	}, nil
+}
+
+func verifiedDNSRecord(host, target string) dnsCheck {
+	return dnsCheck{
+		configured: true,
+		reason:     ReasonDNSVerified,
+		message:    fmt.Sprintf("%s resolves to %s", host, target),
+	}
+}
+
+func missingDNSRecord(host, target string) dnsCheck {
+	return dnsCheck{
+		reason:  ReasonDNSRecordMissing,
+		message: fmt.Sprintf("No DNS record found for %s. Create a CNAME record pointing to %s", host, target),
+	}
+}
+
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// isDNSVerified returns true if the domain's DNSConfigured condition is the
+// outcome of verifying its DNS records, rather than reported from the ngrok API
+func isDNSVerified(domain *v1alpha1.Domain) bool {
+	cond := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	if cond == nil {
+		return false
+	}
+	return slices.Contains([]string{
+		ReasonDNSVerified,
+		ReasonDNSRecordMissing,
+		ReasonDNSRecordWrongTarget,
+		ReasonDNSRecordProxied,
+		ReasonACMEChallengeRequired,
+	}, cond.Reason)
+}
+
+// dnsNotVerifiedMessage describes the record a custom domain needs before its DNS has been verified
+func dnsNotVerifiedMessage(domain *v1alpha1.Domain) string {
+	if target := domain.Status.CNAMETarget; target != nil && *target != "" {
+		return fmt.Sprintf("DNS records have not been verified yet. Create a CNAME record for %s pointing to %s", domain.Spec.Domain, *target)
+	}
+	return "DNS records have not been verified yet"
+}
+
+// dnsConfiguredCondition returns a copy of the domain's DNSConfigured condition, if any
+func dnsConfiguredCondition(domain *v1alpha1.Domain) *metav1.Condition {
+	cond := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	if cond == nil {
+		return nil
+	}
+	return cond.DeepCopy()
+}
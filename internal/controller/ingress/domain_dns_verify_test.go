@@ -0,0 +1,236 @@
+package ingress
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/resolvers"
+)
+
+const testCNAMETarget = "abc.def.ngrok-cname.com"
+
+// failingDNSResolver fails every lookup, like a resolver that can't be reached
+type failingDNSResolver struct{}
+
+func (failingDNSResolver) LookupCNAME(context.Context, string) (string, error) {
+	return "", &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+}
+
+func (failingDNSResolver) LookupHost(context.Context, string) ([]string, error) {
+	return nil, &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+}
+
+func TestCheckCNAME(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(r *resolvers.StaticDNSResolver)
+		configured bool
+		reason     string
+		message    string
+	}{
+		{
+			name: "cname to target",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddCNAME("app.example.com", testCNAMETarget)
+			},
+			configured: true,
+			reason:     ReasonDNSVerified,
+		},
+		{
+			name: "cname chain continuing past target",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddCNAME("app.example.com", testCNAMETarget)
+				r.AddCNAME(testCNAMETarget, "edge.ngrok.io")
+				r.AddHost("edge.ngrok.io", "1.2.3.4")
+			},
+			configured: true,
+			reason:     ReasonDNSVerified,
+		},
+		{
+			name: "flattened cname",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddHost("app.example.com", "1.2.3.4")
+				r.AddHost(testCNAMETarget, "1.2.3.4")
+			},
+			configured: true,
+			reason:     ReasonDNSVerified,
+		},
+		{
+			name:    "missing record",
+			setup:   func(*resolvers.StaticDNSResolver) {},
+			reason:  ReasonDNSRecordMissing,
+			message: "No DNS record found for app.example.com",
+		},
+		{
+			name: "cname to another target",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddCNAME("app.example.com", "old.example.net")
+				r.AddHost("old.example.net", "9.9.9.9")
+			},
+			reason:  ReasonDNSRecordWrongTarget,
+			message: "app.example.com is a CNAME for old.example.net instead of " + testCNAMETarget,
+		},
+		{
+			name: "cname to a cdn",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddCNAME("app.example.com", "app.example.com.cdn.cloudflare.net")
+				r.AddHost("app.example.com.cdn.cloudflare.net", "104.16.1.1")
+			},
+			reason:  ReasonDNSRecordProxied,
+			message: "proxied by a CDN",
+		},
+		{
+			name: "address record",
+			setup: func(r *resolvers.StaticDNSResolver) {
+				r.AddHost("app.example.com", "10.0.0.1")
+				r.AddHost(testCNAMETarget, "1.2.3.4")
+			},
+			reason:  ReasonDNSRecordWrongTarget,
+			message: "app.example.com resolves to 10.0.0.1 but is not a CNAME for " + testCNAMETarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dns := resolvers.NewStaticDNSResolver()
+			tt.setup(dns)
+			r := &DomainReconciler{DNSResolver: dns}
+
+			check, err := r.checkCNAME(context.Background(), "app.example.com", testCNAMETarget)
+			require.NoError(t, err)
+			assert.Equal(t, tt.configured, check.configured)
+			assert.Equal(t, tt.reason, check.reason)
+			assert.Contains(t, check.message, tt.message)
+		})
+	}
+}
+
+func testDNSVerifyDomain(domainName string, acmeTarget *string) *ingressv1alpha1.Domain {
+	domain := createTestDomain("test-domain", domainName, "rd_123")
+	domain.Status.CNAMETarget = new(testCNAMETarget)
+	domain.Status.ACMEChallengeCNAMETarget = acmeTarget
+	setDNSConfiguredCondition(domain, false, ReasonProvisioningError, "Certificate provisioning in progress")
+	return domain
+}
+
+func TestVerifyDomainDNS(t *testing.T) {
+	dns := resolvers.NewStaticDNSResolver()
+	dns.AddCNAME("app.example.com", testCNAMETarget)
+	recorder := events.NewFakeRecorder(10)
+	r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder, DNSResolver: dns}
+
+	domain := testDNSVerifyDomain("app.example.com", nil)
+	r.applyDNSCheck(domain, r.verifyDomainDNS(context.Background(), domain), dnsConfiguredCondition(domain))
+
+	cond := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReasonDNSVerified, cond.Reason)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "DNSConfigured")
+
+	// No event when nothing changed
+	r.applyDNSCheck(domain, r.verifyDomainDNS(context.Background(), domain), dnsConfiguredCondition(domain))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestVerifyDomainDNS_Wildcard(t *testing.T) {
+	acmeTarget := "example.com.acme.ngrok-cname.com"
+	dns := resolvers.NewStaticDNSResolver()
+	dns.AddCNAME(wildcardProbeLabel+".example.com", testCNAMETarget)
+	recorder := events.NewFakeRecorder(10)
+	r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder, DNSResolver: dns}
+
+	domain := testDNSVerifyDomain("*.example.com", &acmeTarget)
+	r.applyDNSCheck(domain, r.verifyDomainDNS(context.Background(), domain), dnsConfiguredCondition(domain))
+
+	cond := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReasonACMEChallengeRequired, cond.Reason)
+	assert.Contains(t, cond.Message, "_acme-challenge.example.com")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "DNSMisconfigured")
+
+	dns.AddCNAME("_acme-challenge.example.com", acmeTarget)
+	r.applyDNSCheck(domain, r.verifyDomainDNS(context.Background(), domain), dnsConfiguredCondition(domain))
+	cond = meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+}
+
+func TestVerifyDomainDNS_Skipped(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+
+	t.Run("no resolver", func(t *testing.T) {
+		r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder}
+		domain := testDNSVerifyDomain("app.example.com", nil)
+		assert.Nil(t, r.verifyDomainDNS(context.Background(), domain))
+		assert.Equal(t, ReasonProvisioningError, meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured).Reason)
+	})
+
+	t.Run("ngrok managed domain", func(t *testing.T) {
+		r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder, DNSResolver: resolvers.NewStaticDNSResolver()}
+		domain := testDNSVerifyDomain("app.ngrok.app", nil)
+		domain.Status.CNAMETarget = nil
+		assert.Nil(t, r.verifyDomainDNS(context.Background(), domain))
+		assert.Equal(t, ReasonProvisioningError, meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured).Reason)
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder, DNSResolver: failingDNSResolver{}}
+		domain := testDNSVerifyDomain("app.example.com", nil)
+		assert.Nil(t, r.verifyDomainDNS(context.Background(), domain))
+		assert.Equal(t, ReasonProvisioningError, meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured).Reason)
+	})
+
+	assert.Empty(t, recorder.Events)
+}
+
+func TestApplyDNSCheck_KeepsReady(t *testing.T) {
+	dns := resolvers.NewStaticDNSResolver()
+	dns.AddCNAME("app.example.com", "old.example.net")
+	recorder := events.NewFakeRecorder(10)
+	r := &DomainReconciler{Log: logr.Discard(), Recorder: recorder, DNSResolver: dns}
+
+	// The ngrok API reports the domain as ready, but its record points elsewhere
+	domain := testDNSVerifyDomain("app.example.com", nil)
+	setDomainReadyCondition(domain, true, ReasonDomainActive, "Domain ready for use")
+	r.applyDNSCheck(domain, r.verifyDomainDNS(context.Background(), domain), dnsConfiguredCondition(domain))
+
+	// Only DNSConfigured reports it, since clients may see different records than the cluster
+	assert.True(t, IsDomainReady(domain))
+	cond := meta.FindStatusCondition(domain.Status.Conditions, ConditionDNSConfigured)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReasonDNSRecordWrongTarget, cond.Reason)
+	assert.Contains(t, cond.Message, "old.example.net")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "DNSMisconfigured")
+}
+
+func TestDomainRequeueAfter(t *testing.T) {
+	domain := testDNSVerifyDomain("app.example.com", nil)
+
+	r := &DomainReconciler{}
+	assert.Zero(t, r.requeueAfter(domain))
+
+	r.DNSResolver = resolvers.NewStaticDNSResolver()
+	assert.Equal(t, dnsVerifyInterval, r.requeueAfter(domain))
+
+	// An uploaded certificate expiring before the next verification
+	notAfter := metav1.NewTime(time.Now().Add(time.Minute))
+	domain.Status.Certificate = &ingressv1alpha1.DomainStatusCertificateInfo{ID: "cert_123", SecretName: "app-tls", NotAfter: &notAfter}
+	d := r.requeueAfter(domain)
+	assert.Greater(t, d, time.Duration(0))
+	assert.Less(t, d, 2*time.Minute)
+}
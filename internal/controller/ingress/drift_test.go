@@ -0,0 +1,74 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/drift"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+func TestDomainCheckDrift(t *testing.T) {
+	ctx := context.Background()
+	domains := nmockapi.NewDomainClient()
+	r := &DomainReconciler{DomainsClient: domains}
+
+	resp, err := domains.Create(ctx, &ngrok.ReservedDomainCreate{Domain: "app.example.com"})
+	require.NoError(t, err)
+
+	domain := &ingressv1alpha1.Domain{
+		Name:      "app-example-com",
+		Namespace: "default",
+		Spec:      ingressv1alpha1.DomainSpec{Domain: "app.example.com"},
+		Status:    ingressv1alpha1.DomainStatus{ID: resp.ID},
+	}
+
+	fields, err := r.checkDrift(ctx, domain)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	_, err = domains.Update(ctx, &ngrok.ReservedDomainUpdate{ID: resp.ID, Description: new("edited in the dashboard")})
+	require.NoError(t, err)
+	fields, err = r.checkDrift(ctx, domain)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"description"}, fields)
+
+	// Spec changes that haven't been reconciled aren't drift
+	domain.Generation = 2
+	_, err = r.checkDrift(ctx, domain)
+	assert.ErrorIs(t, err, drift.ErrSkip)
+
+	domain.Status.ObservedGeneration = 2
+	require.NoError(t, domains.Delete(ctx, resp.ID))
+	fields, err = r.checkDrift(ctx, domain)
+	require.NoError(t, err)
+	assert.Equal(t, []string{drift.FieldDeleted}, fields)
+}
+
+func TestIPPolicyCheckDrift(t *testing.T) {
+	ctx := context.Background()
+	r := newIPPolicySourcesTestReconciler(t)
+	policy := testSourcesIPPolicy()
+	policy.Spec.Description = "office"
+
+	remotePolicy, err := r.IPPoliciesClient.(*nmockapi.IPPolicyClient).Create(ctx, &ngrok.IPPolicyCreate{Description: "office"})
+	require.NoError(t, err)
+	policy.Status.ID = remotePolicy.ID
+	require.NoError(t, r.createOrUpdateIPPolicyRules(ctx, policy))
+
+	fields, err := r.checkDrift(ctx, policy)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	// A rule added in the dashboard
+	_, err = r.IPPolicyRulesClient.Create(ctx, &ngrok.IPPolicyRuleCreate{IPPolicyID: remotePolicy.ID, CIDR: "192.0.2.0/24", Action: new(IPPolicyRuleActionAllow)})
+	require.NoError(t, err)
+	fields, err = r.checkDrift(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rules"}, fields)
+}
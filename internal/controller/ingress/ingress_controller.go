@@ -6,6 +6,7 @@ import (
 	"github.com/go-logr/logr"
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
 	"github.com/ngrok/ngrok-operator/internal/controller"
 	"github.com/ngrok/ngrok-operator/internal/deprecation"
 	internalerrors "github.com/ngrok/ngrok-operator/internal/errors"
@@ -17,6 +18,8 @@ import (
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // This implements the Reconciler for the controller-runtime
@@ -38,6 +41,7 @@ func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		&netv1.IngressClass{},
 		&corev1.Service{},
 		&ingressv1alpha1.Domain{},
+		&ingressv1alpha1.IPPolicy{},
 		&ngrokv1alpha1.NgrokTrafficPolicy{},
 	}
 
@@ -48,9 +52,72 @@ func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			managerdriver.NewControllerEventHandler(obj.GetObjectKind().GroupVersionKind().Kind, r.Driver, r.Client))
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &netv1.Ingress{}, ingressSecretIndex, indexIngressSecrets); err != nil {
+		return err
+	}
+
+	// Secrets referenced by feature annotations (basic auth, OIDC) are loaded into the store when the
+	// Ingresses referencing them are reconciled
+	builder = builder.Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findIngressesForSecret))
+
 	return builder.Complete(r)
 }
 
+// ingressSecretIndex indexes Ingresses by the names of the Secrets referenced by their feature annotations
+const ingressSecretIndex = "metadata.annotations.secrets"
+
+// indexIngressSecrets extracts the names of the Secrets referenced by an Ingress for indexing
+func indexIngressSecrets(o client.Object) []string {
+	features, err := annotations.ExtractFeatureAnnotations(o)
+	if err != nil {
+		return nil
+	}
+	return features.SecretNames()
+}
+
+// findIngressesForSecret maps a Secret to the Ingresses in its namespace that reference it by a feature
+// annotation
+func (r *IngressReconciler) findIngressesForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	ingresses := &netv1.IngressList{}
+	if err := r.Client.List(ctx, ingresses, client.InNamespace(secret.GetNamespace()), client.MatchingFields{ingressSecretIndex: secret.GetName()}); err != nil {
+		r.Log.Error(err, "failed to list Ingresses for Secret", "secret", client.ObjectKeyFromObject(secret))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(ingresses.Items))
+	for _, ingress := range ingresses.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ingress)})
+	}
+	return requests
+}
+
+// storeReferencedSecrets loads the Secrets referenced by the Ingress's feature annotations into
+// the driver's store, and removes the ones that no longer exist
+func (r *IngressReconciler) storeReferencedSecrets(ctx context.Context, ingress *netv1.Ingress) error {
+	features, err := annotations.ExtractFeatureAnnotations(ingress)
+	if err != nil {
+		// Invalid annotations are reported when the Ingress is translated
+		return nil
+	}
+	for _, name := range features.SecretNames() {
+		key := client.ObjectKey{Namespace: ingress.Namespace, Name: name}
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, key, secret); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				if err := r.Driver.DeleteNamedSecret(key); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		if err := r.Driver.UpdateSecret(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // This reconcile function is called by the controller-runtime manager.
 // It is invoked whenever there is an event that occurs for a resource
 // being watched (in our case, ingress objects). If you tail the controller
@@ -135,5 +202,10 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	if err := r.storeReferencedSecrets(ctx, ingress); err != nil {
+		log.Error(err, "Failed to store Secrets referenced by the ingress")
+		return ctrl.Result{}, err
+	}
+
 	return managerdriver.HandleSyncResult(r.Driver.Sync(ctx, r.Client))
 }
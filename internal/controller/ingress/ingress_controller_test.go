@@ -1,7 +1,20 @@
 package ingress
 
 import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/pkg/managerdriver"
 )
 
 //nolint:unused
@@ -15,3 +28,74 @@ func makeTestBackend(serviceName string, servicePort int32) netv1.IngressBackend
 		},
 	}
 }
+
+func TestStoreReferencedSecrets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, netv1.AddToScheme(scheme))
+
+	// The Secret exists before the Ingress that references it
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	driver := managerdriver.NewDriver(logr.Discard(), scheme, "k8s.ngrok.com/ingress-controller", types.NamespacedName{Name: "ngrok-operator", Namespace: "ngrok-operator"})
+	r := &IngressReconciler{Client: c, Driver: driver}
+
+	ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ing",
+		Namespace:   "default",
+		Annotations: map[string]string{annotations.BasicAuthSecretAnnotation: "creds"},
+	}}
+	require.NoError(t, r.storeReferencedSecrets(t.Context(), ingress))
+	stored, err := driver.GetStore().GetSecretV1("creds", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "creds", stored.Name)
+
+	// Secrets that don't exist yet are stored when they are created
+	ingress.Annotations[annotations.BasicAuthSecretAnnotation] = "missing"
+	require.NoError(t, r.storeReferencedSecrets(t.Context(), ingress))
+	_, err = driver.GetStore().GetSecretV1("missing", "default")
+	assert.Error(t, err)
+
+	// Deleted Secrets are removed from the store
+	ingress.Annotations[annotations.BasicAuthSecretAnnotation] = "creds"
+	require.NoError(t, c.Delete(t.Context(), secret))
+	require.NoError(t, r.storeReferencedSecrets(t.Context(), ingress))
+	_, err = driver.GetStore().GetSecretV1("creds", "default")
+	assert.Error(t, err)
+}
+
+func TestFindIngressesForSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, netv1.AddToScheme(scheme))
+
+	referencing := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        "referencing",
+		Namespace:   "default",
+		Annotations: map[string]string{annotations.BasicAuthSecretAnnotation: "creds"},
+	}}
+	other := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        "other",
+		Namespace:   "default",
+		Annotations: map[string]string{annotations.BasicAuthSecretAnnotation: "other-creds"},
+	}}
+	otherNamespace := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        "referencing",
+		Namespace:   "other",
+		Annotations: map[string]string{annotations.BasicAuthSecretAnnotation: "creds"},
+	}}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(referencing, other, otherNamespace).
+		WithIndex(&netv1.Ingress{}, ingressSecretIndex, indexIngressSecrets).
+		Build()
+	r := &IngressReconciler{Client: c, Log: logr.Discard()}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"}}
+	requests := r.findIngressesForSecret(t.Context(), secret)
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "referencing", Namespace: "default"}, requests[0].NamespacedName)
+
+	unreferenced := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "default"}}
+	assert.Empty(t, r.findIngressesForSecret(t.Context(), unreferenced))
+}
@@ -25,6 +25,7 @@ const (
 	ReasonIPPolicyRulesConfigurationError = "IPPolicyRulesConfigurationError"
 	ReasonIPPolicyInvalidCIDR             = "IPPolicyInvalidCIDR"
 	ReasonIPPolicyCreationFailed          = "IPPolicyCreationFailed"
+	ReasonIPPolicySourceError             = "IPPolicySourceError"
 )
 
 // setIPPolicyReadyCondition sets the Ready condition based on the overall IP policy state
@@ -42,6 +42,7 @@ import (
 	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/controller"
+	"github.com/ngrok/ngrok-operator/internal/drift"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
 )
 
@@ -62,6 +63,10 @@ type IPPolicyReconciler struct {
 	IPPolicyRulesClient ngrokapi.IPPolicyRulesClient
 	DrainState          controller.DrainState
 
+	// DriftScanner periodically checks IP policies and their rules for changes made outside of the operator.
+	// Drift is not checked when nil.
+	DriftScanner *drift.Scanner
+
 	controller *controller.BaseController[*ingressv1alpha1.IPPolicy]
 }
 
@@ -81,19 +86,37 @@ func (r *IPPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		DrainState: r.DrainState,
 
 		StatusID: func(cr *ingressv1alpha1.IPPolicy) string { return cr.Status.ID },
+		Adopt:    r.adopt,
 		Create:   r.create,
 		Update:   r.update,
 		Delete:   r.delete,
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&ingressv1alpha1.IPPolicy{}, builder.WithPredicates(
 			predicate.Or(
 				predicate.AnnotationChangedPredicate{},
 				predicate.GenerationChangedPredicate{},
 			),
 		)).
-		Complete(r)
+		Watches(
+			&v1.Node{},
+			r.controller.NewEnqueueRequestForMapFunc(r.findIPPoliciesForNode),
+			builder.WithPredicates(sourceAddressesChanged(nodeExternalIPs)),
+		).
+		Watches(
+			&v1.ConfigMap{},
+			r.controller.NewEnqueueRequestForMapFunc(r.findIPPoliciesForConfigMap),
+		).
+		Watches(
+			&v1.Service{},
+			r.controller.NewEnqueueRequestForMapFunc(r.findIPPoliciesForService),
+			builder.WithPredicates(sourceAddressesChanged(serviceIPs)),
+		)
+	if r.DriftScanner != nil {
+		b = b.WatchesRawSource(drift.Register(r.DriftScanner, "IPPolicy", func() client.ObjectList { return &ingressv1alpha1.IPPolicyList{} }, r.checkDrift))
+	}
+	return b.Complete(r)
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -125,6 +148,16 @@ func (r *IPPolicyReconciler) create(ctx context.Context, policy *ingressv1alpha1
 	return r.controller.ReconcileStatus(ctx, policy, err)
 }
 
+// adopt takes ownership of the existing IP policy with the given ID. Its rules are replaced by the rules of the spec.
+func (r *IPPolicyReconciler) adopt(ctx context.Context, policy *ingressv1alpha1.IPPolicy, id string) error {
+	remotePolicy, err := r.IPPoliciesClient.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	policy.Status.ID = remotePolicy.ID
+	return nil
+}
+
 func (r *IPPolicyReconciler) update(ctx context.Context, policy *ingressv1alpha1.IPPolicy) error {
 	remotePolicy, err := r.IPPoliciesClient.Get(ctx, policy.Status.ID)
 	if err != nil {
@@ -143,8 +176,7 @@ func (r *IPPolicyReconciler) update(ctx context.Context, policy *ingressv1alpha1
 	// if it was not already set.
 	setIPPolicyCreatedCondition(policy, true, ReasonIPPolicyCreated, "IP Policy already exists")
 
-	if remotePolicy.Description != policy.Spec.Description ||
-		remotePolicy.Metadata != commonv1alpha1.MetadataAPIString(policy.Spec.Metadata) {
+	if len(ipPolicyDriftedFields(policy, remotePolicy)) > 0 {
 		r.Recorder.Eventf(policy, nil, v1.EventTypeNormal, "Updating", "Update", fmt.Sprintf("Updating IPPolicy %s", policy.Name))
 		_, err := r.IPPoliciesClient.Update(ctx, &ngrok.IPPolicyUpdate{
 			ID:          policy.Status.ID,
@@ -165,6 +197,50 @@ func (r *IPPolicyReconciler) update(ctx context.Context, policy *ingressv1alpha1
 	return r.controller.ReconcileStatus(ctx, policy, err)
 }
 
+// checkDrift reports the fields of the IP policy, and whether its rules, were changed outside of the operator
+func (r *IPPolicyReconciler) checkDrift(ctx context.Context, policy *ingressv1alpha1.IPPolicy) ([]string, error) {
+	if policy.Status.ID == "" || policy.Generation != policy.Status.ObservedGeneration {
+		return nil, drift.ErrSkip
+	}
+	remotePolicy, err := r.IPPoliciesClient.Get(ctx, policy.Status.ID)
+	if ngrok.IsNotFound(err) {
+		return []string{drift.FieldDeleted}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields := ipPolicyDriftedFields(policy, remotePolicy)
+
+	// Resolve the sources on a copy, so the status written by the scanner isn't changed
+	specRules, err := r.effectiveIPPolicyRules(ctx, policy.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	remoteRules, err := r.getRemotePolicyRules(ctx, policy.Status.ID)
+	if err != nil {
+		return nil, err
+	}
+	diff := newIPPolicyDiff(policy.Status.ID, remoteRules, specRules)
+	for diff.Next() {
+		if len(diff.NeedsCreate()) > 0 || len(diff.NeedsDelete()) > 0 || len(diff.NeedsUpdate()) > 0 {
+			return append(fields, "rules"), nil
+		}
+	}
+	return fields, nil
+}
+
+// ipPolicyDriftedFields returns the updatable fields of the IP policy that don't match the spec
+func ipPolicyDriftedFields(policy *ingressv1alpha1.IPPolicy, remotePolicy *ngrok.IPPolicy) []string {
+	fields := []string{}
+	if remotePolicy.Description != policy.Spec.Description {
+		fields = append(fields, "description")
+	}
+	if remotePolicy.Metadata != commonv1alpha1.MetadataAPIString(policy.Spec.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	return fields
+}
+
 func (r *IPPolicyReconciler) delete(ctx context.Context, policy *ingressv1alpha1.IPPolicy) error {
 	err := r.IPPoliciesClient.Delete(ctx, policy.Status.ID)
 	if err == nil || ngrok.IsNotFound(err) {
@@ -176,6 +252,12 @@ func (r *IPPolicyReconciler) delete(ctx context.Context, policy *ingressv1alpha1
 func (r *IPPolicyReconciler) createOrUpdateIPPolicyRules(ctx context.Context, policy *ingressv1alpha1.IPPolicy) error {
 	log := ctrl.LoggerFrom(ctx)
 
+	specRules, err := r.effectiveIPPolicyRules(ctx, policy)
+	if err != nil {
+		setIPPolicyRulesConfiguredCondition(policy, false, ReasonIPPolicySourceError, err.Error())
+		return err
+	}
+
 	remoteRules, err := r.getRemotePolicyRules(ctx, policy.Status.ID)
 	if err != nil {
 		return err
@@ -185,7 +267,7 @@ func (r *IPPolicyReconciler) createOrUpdateIPPolicyRules(ctx context.Context, po
 		setIPPolicyRulesConfiguredCondition(policy, false, ReasonIPPolicyRulesConfigurationError, "No rules configured for IP Policy")
 	}
 
-	iter := newIPPolicyDiff(policy.Status.ID, remoteRules, policy.Spec.Rules)
+	iter := newIPPolicyDiff(policy.Status.ID, remoteRules, specRules)
 
 	setConditionsBasedOnErr := func(err error) {
 		if err == nil {
@@ -0,0 +1,306 @@
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+)
+
+// maxReportedInvalidCIDRs bounds the number of invalid ConfigMap entries listed in the status of a source
+const maxReportedInvalidCIDRs = 5
+
+// sourceCIDRs are the CIDRs computed from a source of an IPPolicy
+type sourceCIDRs struct {
+	cidrs   []string
+	message string
+}
+
+// effectiveIPPolicyRules returns the rules of the policy's spec followed by the rules computed from its sources, and
+// records the number of rules applied from each source in the policy's status. A CIDR that is already in the spec or an
+// earlier source is skipped so that each CIDR has a single action. An error is returned when a source could not be read,
+// in which case the remote rules should be left as they are.
+func (r *IPPolicyReconciler) effectiveIPPolicyRules(ctx context.Context, policy *ingressv1alpha1.IPPolicy) ([]ingressv1alpha1.IPPolicyRule, error) {
+	rules := slices.Clone(policy.Spec.Rules)
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		seen[rule.CIDR] = true
+	}
+
+	statuses := make([]ingressv1alpha1.IPPolicySourceStatus, 0, len(policy.Spec.Sources))
+	for _, source := range policy.Spec.Sources {
+		computed, err := r.resolveIPPolicySource(ctx, policy.Namespace, source)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve source %q: %w", source.Name, err)
+		}
+
+		status := ingressv1alpha1.IPPolicySourceStatus{Name: source.Name, Message: computed.message}
+		for _, cidr := range computed.cidrs {
+			if seen[cidr] {
+				continue
+			}
+			seen[cidr] = true
+			rules = append(rules, sourceIPPolicyRule(source, cidr))
+			status.RuleCount++
+		}
+		statuses = append(statuses, status)
+	}
+
+	policy.Status.Sources = nil
+	if len(statuses) > 0 {
+		policy.Status.Sources = statuses
+	}
+	return rules, nil
+}
+
+func (r *IPPolicyReconciler) resolveIPPolicySource(ctx context.Context, namespace string, source ingressv1alpha1.IPPolicyRuleSource) (sourceCIDRs, error) {
+	switch {
+	case source.Nodes != nil:
+		return r.resolveNodesSource(ctx, source.Nodes)
+	case source.ConfigMap != nil:
+		return r.resolveConfigMapSource(ctx, namespace, source.ConfigMap)
+	case source.Services != nil:
+		return r.resolveServicesSource(ctx, namespace, source.Services)
+	default:
+		return sourceCIDRs{message: "No nodes, configMap or services set on the source"}, nil
+	}
+}
+
+func (r *IPPolicyReconciler) resolveNodesSource(ctx context.Context, source *ingressv1alpha1.IPPolicyNodesSource) (sourceCIDRs, error) {
+	selector, err := nodesSourceSelector(source)
+	if err != nil {
+		return sourceCIDRs{message: fmt.Sprintf("Invalid node selector: %s", err)}, nil
+	}
+
+	nodes := &v1.NodeList{}
+	if err := r.Client.List(ctx, nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return sourceCIDRs{}, err
+	}
+
+	computed := sourceCIDRs{}
+	for _, node := range nodes.Items {
+		for _, ip := range nodeExternalIPs(&node) {
+			computed.cidrs = appendCIDRForIP(computed.cidrs, ip)
+		}
+	}
+	if len(computed.cidrs) == 0 {
+		computed.message = "No matching Nodes have an ExternalIP address"
+	}
+	return computed, nil
+}
+
+func (r *IPPolicyReconciler) resolveConfigMapSource(ctx context.Context, namespace string, source *ingressv1alpha1.IPPolicyConfigMapSource) (sourceCIDRs, error) {
+	configMap := &v1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.Name}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return sourceCIDRs{message: fmt.Sprintf("ConfigMap %q not found", source.Name)}, nil
+		}
+		return sourceCIDRs{}, err
+	}
+
+	value, ok := configMap.Data[source.Key]
+	if !ok {
+		return sourceCIDRs{message: fmt.Sprintf("ConfigMap %q has no key %q", source.Name, source.Key)}, nil
+	}
+
+	cidrs, invalid := parseIPPolicyCIDRs(value)
+	computed := sourceCIDRs{cidrs: cidrs}
+	if len(invalid) > 0 {
+		reported := invalid[:min(len(invalid), maxReportedInvalidCIDRs)]
+		computed.message = fmt.Sprintf("Ignored %d invalid entries: %s", len(invalid), strings.Join(reported, ", "))
+	}
+	return computed, nil
+}
+
+func (r *IPPolicyReconciler) resolveServicesSource(ctx context.Context, namespace string, source *ingressv1alpha1.IPPolicyServicesSource) (sourceCIDRs, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&source.Selector)
+	if err != nil {
+		return sourceCIDRs{message: fmt.Sprintf("Invalid service selector: %s", err)}, nil
+	}
+
+	services := &v1.ServiceList{}
+	if err := r.Client.List(ctx, services, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return sourceCIDRs{}, err
+	}
+
+	computed := sourceCIDRs{}
+	for _, svc := range services.Items {
+		for _, ip := range serviceIPs(&svc) {
+			computed.cidrs = appendCIDRForIP(computed.cidrs, ip)
+		}
+	}
+	if len(computed.cidrs) == 0 {
+		computed.message = "No matching Services have a load balancer or external IP"
+	}
+	return computed, nil
+}
+
+// sourceIPPolicyRule returns the rule for a CIDR computed from a source. The name of the source is recorded in the
+// metadata of the rule so that it can be traced back to the source in the ngrok dashboard.
+func sourceIPPolicyRule(source ingressv1alpha1.IPPolicyRuleSource, cidr string) ingressv1alpha1.IPPolicyRule {
+	metadata, _ := json.Marshal(map[string]string{
+		"owned-by":        "ngrok-operator",
+		"ippolicy-source": source.Name,
+	})
+	return ingressv1alpha1.IPPolicyRule{
+		Description: "Created by ngrok-operator",
+		Metadata:    metadata,
+		CIDR:        cidr,
+		Action:      source.Action,
+	}
+}
+
+// parseIPPolicyCIDRs parses a list of CIDRs separated by whitespace or commas, ignoring text following a #. Bare IP
+// addresses are converted to a range of a single address. Entries that are neither are returned separately.
+func parseIPPolicyCIDRs(value string) (cidrs []string, invalid []string) {
+	for line := range strings.Lines(value) {
+		line, _, _ = strings.Cut(line, "#")
+		entries := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		})
+		for _, entry := range entries {
+			if prefix, err := netip.ParsePrefix(entry); err == nil {
+				cidrs = appendStringUnique(cidrs, prefix.String())
+				continue
+			}
+			if addr, err := netip.ParseAddr(entry); err == nil {
+				cidrs = appendStringUnique(cidrs, singleAddressCIDR(addr))
+				continue
+			}
+			invalid = append(invalid, entry)
+		}
+	}
+	return cidrs, invalid
+}
+
+// appendCIDRForIP appends the single address CIDR of the ip to cidrs, ignoring anything that is not an IP address
+func appendCIDRForIP(cidrs []string, ip string) []string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return cidrs
+	}
+	return appendStringUnique(cidrs, singleAddressCIDR(addr))
+}
+
+func singleAddressCIDR(addr netip.Addr) string {
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()).String()
+}
+
+func appendStringUnique(s []string, v string) []string {
+	if slices.Contains(s, v) {
+		return s
+	}
+	return append(s, v)
+}
+
+func nodesSourceSelector(source *ingressv1alpha1.IPPolicyNodesSource) (labels.Selector, error) {
+	if source.Selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(source.Selector)
+}
+
+// nodeExternalIPs returns the ExternalIP addresses of a Node
+func nodeExternalIPs(node *v1.Node) []string {
+	ips := []string{}
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == v1.NodeExternalIP {
+			ips = append(ips, addr.Address)
+		}
+	}
+	return ips
+}
+
+// serviceIPs returns the load balancer ingress IPs and external IPs of a Service
+func serviceIPs(svc *v1.Service) []string {
+	ips := slices.Clone(svc.Spec.ExternalIPs)
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			ips = append(ips, ingress.IP)
+		}
+	}
+	return ips
+}
+
+// sourceAddressesChanged only lets through updates to Nodes and Services that can change the rules computed from them,
+// ignoring the frequent status updates that don't touch their addresses
+func sourceAddressesChanged[T client.Object](addresses func(T) []string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, okOld := e.ObjectOld.(T)
+			newObj, okNew := e.ObjectNew.(T)
+			if !okOld || !okNew {
+				return true
+			}
+			return !labels.Equals(oldObj.GetLabels(), newObj.GetLabels()) ||
+				!slices.Equal(addresses(oldObj), addresses(newObj))
+		},
+	}
+}
+
+// findIPPoliciesForNode maps a Node to the IPPolicies with a nodes source selecting it
+func (r *IPPolicyReconciler) findIPPoliciesForNode(ctx context.Context, obj client.Object) []ctrl.Request {
+	return r.findIPPoliciesForSource(ctx, "", func(source ingressv1alpha1.IPPolicyRuleSource) bool {
+		if source.Nodes == nil {
+			return false
+		}
+		selector, err := nodesSourceSelector(source.Nodes)
+		return err == nil && selector.Matches(labels.Set(obj.GetLabels()))
+	})
+}
+
+// findIPPoliciesForConfigMap maps a ConfigMap to the IPPolicies in its namespace with a configMap source referencing it
+func (r *IPPolicyReconciler) findIPPoliciesForConfigMap(ctx context.Context, obj client.Object) []ctrl.Request {
+	return r.findIPPoliciesForSource(ctx, obj.GetNamespace(), func(source ingressv1alpha1.IPPolicyRuleSource) bool {
+		return source.ConfigMap != nil && source.ConfigMap.Name == obj.GetName()
+	})
+}
+
+// findIPPoliciesForService maps a Service to the IPPolicies in its namespace with a services source selecting it
+func (r *IPPolicyReconciler) findIPPoliciesForService(ctx context.Context, obj client.Object) []ctrl.Request {
+	return r.findIPPoliciesForSource(ctx, obj.GetNamespace(), func(source ingressv1alpha1.IPPolicyRuleSource) bool {
+		if source.Services == nil {
+			return false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&source.Services.Selector)
+		return err == nil && selector.Matches(labels.Set(obj.GetLabels()))
+	})
+}
+
+// findIPPoliciesForSource returns a request for each IPPolicy in the namespace, or in every namespace when it is empty,
+// with a source that matches
+func (r *IPPolicyReconciler) findIPPoliciesForSource(ctx context.Context, namespace string, matches func(ingressv1alpha1.IPPolicyRuleSource) bool) []ctrl.Request {
+	log := ctrl.LoggerFrom(ctx)
+
+	policies := &ingressv1alpha1.IPPolicyList{}
+	opts := []client.ListOption{}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := r.Client.List(ctx, policies, opts...); err != nil {
+		log.Error(err, "failed to list IPPolicies")
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, policy := range policies.Items {
+		if slices.ContainsFunc(policy.Spec.Sources, matches) {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
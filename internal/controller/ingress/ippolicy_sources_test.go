@@ -0,0 +1,200 @@
+package ingress
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+func newIPPolicySourcesTestReconciler(t *testing.T, objs ...client.Object) *IPPolicyReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+
+	policies := nmockapi.NewIPPolicyClient()
+	return &IPPolicyReconciler{
+		Client:              fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Log:                 logr.Discard(),
+		IPPoliciesClient:    policies,
+		IPPolicyRulesClient: nmockapi.NewIPPolicyRuleClient(policies),
+	}
+}
+
+func testSourceNode(name, pool string, addresses ...v1.NodeAddress) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+		Status:     v1.NodeStatus{Addresses: addresses},
+	}
+}
+
+func testSourcesIPPolicy(sources ...ingressv1alpha1.IPPolicyRuleSource) *ingressv1alpha1.IPPolicy {
+	return &ingressv1alpha1.IPPolicy{
+		Name:      "policy",
+		Namespace: "default",
+		Spec: ingressv1alpha1.IPPolicySpec{
+			Rules:   []ingressv1alpha1.IPPolicyRule{{CIDR: "10.0.0.0/8", Action: IPPolicyRuleActionAllow}},
+			Sources: sources,
+		},
+		Status: ingressv1alpha1.IPPolicyStatus{ID: "ipp_123"},
+	}
+}
+
+func TestParseIPPolicyCIDRs(t *testing.T) {
+	cidrs, invalid := parseIPPolicyCIDRs("# office\n10.0.0.0/8, 192.168.1.1\n\t2001:db8::/32 # vpn\nnot-an-ip 10.0.0.0/8\n")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::/32"}, cidrs)
+	assert.Equal(t, []string{"not-an-ip"}, invalid)
+}
+
+func TestEffectiveIPPolicyRules(t *testing.T) {
+	r := newIPPolicySourcesTestReconciler(t,
+		testSourceNode("node-a", "edge",
+			v1.NodeAddress{Type: v1.NodeInternalIP, Address: "172.16.0.1"},
+			v1.NodeAddress{Type: v1.NodeExternalIP, Address: "34.1.1.1"},
+		),
+		testSourceNode("node-b", "batch", v1.NodeAddress{Type: v1.NodeExternalIP, Address: "34.2.2.2"}),
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "default"},
+			Data:       map[string]string{"cidrs": "10.0.0.0/8\n34.1.1.1\n203.0.113.0/24\nbogus"},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "default", Labels: map[string]string{"role": "egress"}},
+			Spec:       v1.ServiceSpec{ExternalIPs: []string{"198.51.100.7"}},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{
+				{IP: "198.51.100.8"},
+				{Hostname: "lb.example.com"},
+			}}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "other", Labels: map[string]string{"role": "egress"}},
+			Spec:       v1.ServiceSpec{ExternalIPs: []string{"198.51.100.9"}},
+		},
+	)
+
+	policy := testSourcesIPPolicy(
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:   "edge-nodes",
+			Action: IPPolicyRuleActionAllow,
+			Nodes:  &ingressv1alpha1.IPPolicyNodesSource{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "edge"}}},
+		},
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:      "office",
+			Action:    IPPolicyRuleActionDeny,
+			ConfigMap: &ingressv1alpha1.IPPolicyConfigMapSource{Name: "office", Key: "cidrs"},
+		},
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:     "egress",
+			Action:   IPPolicyRuleActionAllow,
+			Services: &ingressv1alpha1.IPPolicyServicesSource{Selector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "egress"}}},
+		},
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:      "missing",
+			Action:    IPPolicyRuleActionAllow,
+			ConfigMap: &ingressv1alpha1.IPPolicyConfigMapSource{Name: "missing", Key: "cidrs"},
+		},
+	)
+
+	rules, err := r.effectiveIPPolicyRules(context.Background(), policy)
+	require.NoError(t, err)
+
+	actions := map[string]string{}
+	for _, rule := range rules {
+		actions[rule.CIDR] = rule.Action
+	}
+	assert.Equal(t, map[string]string{
+		"10.0.0.0/8":      IPPolicyRuleActionAllow,
+		"34.1.1.1/32":     IPPolicyRuleActionAllow,
+		"203.0.113.0/24":  IPPolicyRuleActionDeny,
+		"198.51.100.7/32": IPPolicyRuleActionAllow,
+		"198.51.100.8/32": IPPolicyRuleActionAllow,
+	}, actions)
+	assert.Contains(t, string(rules[1].Metadata), `"ippolicy-source":"edge-nodes"`)
+
+	require.Len(t, policy.Status.Sources, 4)
+	assert.Equal(t, ingressv1alpha1.IPPolicySourceStatus{Name: "edge-nodes", RuleCount: 1}, policy.Status.Sources[0])
+	assert.Equal(t, int32(1), policy.Status.Sources[1].RuleCount)
+	assert.Contains(t, policy.Status.Sources[1].Message, "bogus")
+	assert.Equal(t, ingressv1alpha1.IPPolicySourceStatus{Name: "egress", RuleCount: 2}, policy.Status.Sources[2])
+	assert.Equal(t, int32(0), policy.Status.Sources[3].RuleCount)
+	assert.Contains(t, policy.Status.Sources[3].Message, "not found")
+}
+
+func TestCreateOrUpdateIPPolicyRules_SourceChanges(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "default"},
+		Data:       map[string]string{"cidrs": "203.0.113.0/24 198.51.100.0/24"},
+	}
+	r := newIPPolicySourcesTestReconciler(t, configMap)
+	rulesClient := r.IPPolicyRulesClient.(*nmockapi.IPPolicyRuleClient)
+
+	policy := testSourcesIPPolicy(ingressv1alpha1.IPPolicyRuleSource{
+		Name:      "office",
+		Action:    IPPolicyRuleActionDeny,
+		ConfigMap: &ingressv1alpha1.IPPolicyConfigMapSource{Name: "office", Key: "cidrs"},
+	})
+
+	require.NoError(t, r.createOrUpdateIPPolicyRules(context.Background(), policy))
+	assert.Equal(t, []string{"10.0.0.0/8", "198.51.100.0/24", "203.0.113.0/24"}, remoteIPPolicyCIDRs(rulesClient))
+	kept := ruleIDForCIDR(rulesClient, "203.0.113.0/24")
+
+	// Only the CIDRs that changed are created and deleted
+	configMap.Data["cidrs"] = "203.0.113.0/24 192.0.2.0/24"
+	require.NoError(t, r.Client.Update(context.Background(), configMap))
+	require.NoError(t, r.createOrUpdateIPPolicyRules(context.Background(), policy))
+	assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.0/24", "203.0.113.0/24"}, remoteIPPolicyCIDRs(rulesClient))
+	assert.Equal(t, kept, ruleIDForCIDR(rulesClient, "203.0.113.0/24"))
+	assert.Equal(t, []ingressv1alpha1.IPPolicySourceStatus{{Name: "office", RuleCount: 2}}, policy.Status.Sources)
+}
+
+func TestFindIPPoliciesForSource(t *testing.T) {
+	policy := testSourcesIPPolicy(
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:      "office",
+			Action:    IPPolicyRuleActionDeny,
+			ConfigMap: &ingressv1alpha1.IPPolicyConfigMapSource{Name: "office", Key: "cidrs"},
+		},
+		ingressv1alpha1.IPPolicyRuleSource{
+			Name:   "nodes",
+			Action: IPPolicyRuleActionAllow,
+			Nodes:  &ingressv1alpha1.IPPolicyNodesSource{},
+		},
+	)
+	r := newIPPolicySourcesTestReconciler(t, policy)
+	ctx := context.Background()
+
+	assert.Len(t, r.findIPPoliciesForConfigMap(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "default"}}), 1)
+	assert.Empty(t, r.findIPPoliciesForConfigMap(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "other"}}))
+	assert.Len(t, r.findIPPoliciesForNode(ctx, testSourceNode("node-a", "edge")), 1)
+	assert.Empty(t, r.findIPPoliciesForService(ctx, &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "default"}}))
+}
+
+func remoteIPPolicyCIDRs(rulesClient *nmockapi.IPPolicyRuleClient) []string {
+	cidrs := []string{}
+	for _, rule := range rulesClient.Items() {
+		cidrs = append(cidrs, rule.CIDR)
+	}
+	slices.Sort(cidrs)
+	return cidrs
+}
+
+func ruleIDForCIDR(rulesClient *nmockapi.IPPolicyRuleClient, cidr string) string {
+	for _, rule := range rulesClient.Items() {
+		if rule.CIDR == cidr {
+			return rule.ID
+		}
+	}
+	return ""
+}
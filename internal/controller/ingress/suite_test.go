@@ -141,11 +141,12 @@ var _ = BeforeSuite(func() {
 	domainClient = nmockapi.NewDomainClient()
 
 	err = (&DomainReconciler{
-		Client:        k8sManager.GetClient(),
-		Log:           logf.Log.WithName("controllers").WithName("Domain"),
-		Recorder:      k8sManager.GetEventRecorder("domain-controller"),
-		Scheme:        k8sManager.GetScheme(),
-		DomainsClient: domainClient,
+		Client:                k8sManager.GetClient(),
+		Log:                   logf.Log.WithName("controllers").WithName("Domain"),
+		Recorder:              k8sManager.GetEventRecorder("domain-controller"),
+		Scheme:                k8sManager.GetScheme(),
+		DomainsClient:         domainClient,
+		TLSCertificatesClient: nmockapi.NewTLSCertificatesClient(),
 	}).SetupWithManager(k8sManager)
 
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,64 @@
+package ingress
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller/conditions"
+	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+)
+
+const (
+	// condition types for TCPAddress
+	ConditionTCPAddressReady    = "Ready"
+	ConditionTCPAddressReserved = "TCPAddressReserved"
+
+	// condition reasons for TCPAddress
+	ReasonTCPAddressActive             = "TCPAddressActive"
+	ReasonTCPAddressReserved           = "TCPAddressReserved"
+	ReasonTCPAddressReservationFailed  = "TCPAddressReservationFailed"
+	ReasonTCPAddressNotFound           = "TCPAddressNotFound"
+	ReasonTCPAddressUpdateFailed       = "TCPAddressUpdateFailed"
+	ReasonTCPAddressWaitingReservation = "WaitingForReservation"
+)
+
+// setTCPAddressReadyCondition sets the Ready condition based on the overall TCP address state
+func setTCPAddressReadyCondition(addr *ingressv1alpha1.TCPAddress, ready bool, reason, message string) {
+	conditions.Set(&addr.Status.Conditions, addr.Generation, ConditionTCPAddressReady, ready, reason, message)
+}
+
+// setTCPAddressReservedCondition sets the TCPAddressReserved condition
+func setTCPAddressReservedCondition(addr *ingressv1alpha1.TCPAddress, reserved bool, reason, message string) {
+	conditions.Set(&addr.Status.Conditions, addr.Generation, ConditionTCPAddressReserved, reserved, reason, message)
+}
+
+// updateTCPAddressConditions sets the conditions of the TCP address from the outcome of reserving or updating it
+func updateTCPAddressConditions(addr *ingressv1alpha1.TCPAddress, reason string, err error) {
+	if err != nil {
+		message := ngrokapi.SanitizeErrorMessage(err.Error())
+		if reason != ReasonTCPAddressUpdateFailed {
+			setTCPAddressReservedCondition(addr, false, reason, message)
+		}
+		setTCPAddressReadyCondition(addr, false, reason, message)
+		return
+	}
+
+	if addr.Status.ID == "" {
+		setTCPAddressReservedCondition(addr, false, ReasonTCPAddressWaitingReservation, "Waiting for the address to be reserved")
+		setTCPAddressReadyCondition(addr, false, ReasonTCPAddressWaitingReservation, "Waiting for the address to be reserved")
+		return
+	}
+
+	setTCPAddressReservedCondition(addr, true, ReasonTCPAddressReserved, "Address "+addr.Status.Address+" is reserved")
+	setTCPAddressReadyCondition(addr, true, ReasonTCPAddressActive, "TCP address is active")
+}
+
+// IsTCPAddressReady returns true if the address has been reserved and its Ready condition is true
+func IsTCPAddressReady(addr *ingressv1alpha1.TCPAddress) bool {
+	if addr.Status.ID == "" || addr.Status.Address == "" {
+		return false
+	}
+	readyCondition := meta.FindStatusCondition(addr.Status.Conditions, ConditionTCPAddressReady)
+	return readyCondition != nil && readyCondition.Status == metav1.ConditionTrue
+}
@@ -0,0 +1,204 @@
+/*
+MIT License
+
+Copyright (c) 2022 ngrok, Inc.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller"
+	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+)
+
+// TCPAddressReconciler reconciles a TCPAddress object
+type TCPAddressReconciler struct {
+	client.Client
+
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+
+	TCPAddressesClient ngrokapi.TCPAddressesClient
+	DrainState         controller.DrainState
+
+	controller *controller.BaseController[*ingressv1alpha1.TCPAddress]
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TCPAddressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.TCPAddressesClient == nil {
+		return errors.New("TCPAddressesClient must be set")
+	}
+
+	r.controller = &controller.BaseController[*ingressv1alpha1.TCPAddress]{
+		Kube:       r.Client,
+		Log:        r.Log,
+		Recorder:   r.Recorder,
+		DrainState: r.DrainState,
+
+		StatusID: func(cr *ingressv1alpha1.TCPAddress) string { return cr.Status.ID },
+		Adopt:    r.adopt,
+		Create:   r.create,
+		Update:   r.update,
+		Delete:   r.delete,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ingressv1alpha1.TCPAddress{}, builder.WithPredicates(
+			predicate.Or(
+				predicate.AnnotationChangedPredicate{},
+				predicate.GenerationChangedPredicate{},
+			),
+		)).
+		Complete(r)
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *TCPAddressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.controller.Reconcile(ctx, req, new(ingressv1alpha1.TCPAddress))
+}
+
+func (r *TCPAddressReconciler) create(ctx context.Context, addr *ingressv1alpha1.TCPAddress) error {
+	// Adopt an existing reservation rather than reserving a new address
+	if addr.Spec.Address != "" {
+		remoteAddr, err := r.findReservedAddr(ctx, addr.Spec.Address)
+		if err != nil {
+			return r.updateStatus(ctx, addr, nil, ReasonTCPAddressReservationFailed, err)
+		}
+		if remoteAddr == nil {
+			err := fmt.Errorf("reserved address %s does not exist in the ngrok account", addr.Spec.Address)
+			return r.updateStatus(ctx, addr, nil, ReasonTCPAddressNotFound, err)
+		}
+		return r.syncReservedAddr(ctx, addr, remoteAddr)
+	}
+
+	remoteAddr, err := r.TCPAddressesClient.Create(ctx, &ngrok.ReservedAddrCreate{
+		Description: addr.Spec.Description,
+		Metadata:    commonv1alpha1.MetadataAPIString(addr.Spec.Metadata),
+		Region:      addr.Spec.Region,
+	})
+	if err != nil {
+		return r.updateStatus(ctx, addr, nil, ReasonTCPAddressReservationFailed, err)
+	}
+	return r.updateStatus(ctx, addr, remoteAddr, "", nil)
+}
+
+// adopt takes ownership of the existing reserved address with the given ID, which must be the address in the spec
+// when one is set
+func (r *TCPAddressReconciler) adopt(ctx context.Context, addr *ingressv1alpha1.TCPAddress, id string) error {
+	remoteAddr, err := r.TCPAddressesClient.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if addr.Spec.Address != "" && remoteAddr.Addr != addr.Spec.Address {
+		return reconcile.TerminalError(fmt.Errorf("reserved address %s is %s, not %s", id, remoteAddr.Addr, addr.Spec.Address))
+	}
+	addr.Status.ID = remoteAddr.ID
+	return nil
+}
+
+func (r *TCPAddressReconciler) update(ctx context.Context, addr *ingressv1alpha1.TCPAddress) error {
+	remoteAddr, err := r.TCPAddressesClient.Get(ctx, addr.Status.ID)
+	if err != nil {
+		if ngrok.IsNotFound(err) {
+			// Clear status so we reserve (or adopt) the address again
+			addr.Status = ingressv1alpha1.TCPAddressStatus{}
+			return r.controller.ReconcileStatus(ctx, addr, err)
+		}
+		return r.updateStatus(ctx, addr, nil, ReasonTCPAddressReservationFailed, err)
+	}
+
+	return r.syncReservedAddr(ctx, addr, remoteAddr)
+}
+
+func (r *TCPAddressReconciler) delete(ctx context.Context, addr *ingressv1alpha1.TCPAddress) error {
+	// Retained addresses stay reserved so they can be adopted again
+	if addr.Spec.ReclaimPolicy != ingressv1alpha1.TCPAddressReclaimPolicyDelete {
+		return nil
+	}
+
+	err := r.TCPAddressesClient.Delete(ctx, addr.Status.ID)
+	if err == nil || ngrok.IsNotFound(err) {
+		addr.Status.ID = ""
+	}
+	return err
+}
+
+// syncReservedAddr updates the description and metadata of the reserved address if they have changed
+func (r *TCPAddressReconciler) syncReservedAddr(ctx context.Context, addr *ingressv1alpha1.TCPAddress, remoteAddr *ngrok.ReservedAddr) error {
+	specMetadata := commonv1alpha1.MetadataAPIString(addr.Spec.Metadata)
+	if remoteAddr.Description == addr.Spec.Description && remoteAddr.Metadata == specMetadata {
+		return r.updateStatus(ctx, addr, remoteAddr, "", nil)
+	}
+
+	r.Recorder.Eventf(addr, nil, v1.EventTypeNormal, "Updating", "Update", fmt.Sprintf("Updating TCPAddress %s", addr.Name))
+	updatedAddr, err := r.TCPAddressesClient.Update(ctx, &ngrok.ReservedAddrUpdate{
+		ID:          remoteAddr.ID,
+		Description: new(addr.Spec.Description),
+		Metadata:    new(specMetadata),
+	})
+	if err != nil {
+		return r.updateStatus(ctx, addr, remoteAddr, ReasonTCPAddressUpdateFailed, err)
+	}
+	r.Recorder.Eventf(addr, nil, v1.EventTypeNormal, "Updated", "Update", fmt.Sprintf("Updated TCPAddress %s", addr.Name))
+	return r.updateStatus(ctx, addr, updatedAddr, "", nil)
+}
+
+// findReservedAddr finds the reserved address by its host:port. If it doesn't exist, returns nil
+func (r *TCPAddressReconciler) findReservedAddr(ctx context.Context, hostport string) (*ngrok.ReservedAddr, error) {
+	iter := r.TCPAddressesClient.List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		if remoteAddr := iter.Item(); remoteAddr.Addr == hostport {
+			return remoteAddr, nil
+		}
+	}
+	return nil, iter.Err()
+}
+
+// updateStatus records the reserved address and the outcome of reconciling it in the status
+func (r *TCPAddressReconciler) updateStatus(ctx context.Context, addr *ingressv1alpha1.TCPAddress, remoteAddr *ngrok.ReservedAddr, reason string, err error) error {
+	if remoteAddr != nil {
+		addr.Status.ID = remoteAddr.ID
+		addr.Status.Address = remoteAddr.Addr
+		addr.Status.Region = remoteAddr.Region
+	}
+	updateTCPAddressConditions(addr, reason, err)
+	return r.controller.ReconcileStatus(ctx, addr, err)
+}
@@ -0,0 +1,155 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+func newTCPAddressTestReconciler(t *testing.T, addr *ingressv1alpha1.TCPAddress) (*TCPAddressReconciler, *nmockapi.TCPAddressesClient) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(addr).WithStatusSubresource(addr).Build()
+	recorder := events.NewFakeRecorder(10)
+	addrs := nmockapi.NewTCPAddressClient()
+	r := &TCPAddressReconciler{
+		Client:             kube,
+		Log:                logr.Discard(),
+		Recorder:           recorder,
+		TCPAddressesClient: addrs,
+	}
+	r.controller = &controller.BaseController[*ingressv1alpha1.TCPAddress]{
+		Kube:     kube,
+		Log:      logr.Discard(),
+		Recorder: recorder,
+	}
+	return r, addrs
+}
+
+func countReservedAddrs(t *testing.T, addrs *nmockapi.TCPAddressesClient) int {
+	t.Helper()
+	count := 0
+	iter := addrs.List(&ngrok.Paging{})
+	for iter.Next(context.Background()) {
+		count++
+	}
+	require.NoError(t, iter.Err())
+	return count
+}
+
+func testTCPAddress(address string) *ingressv1alpha1.TCPAddress {
+	return &ingressv1alpha1.TCPAddress{
+		Name:      "my-addr",
+		Namespace: "default",
+		Spec: ingressv1alpha1.TCPAddressSpec{
+			Address:       address,
+			Description:   "my address",
+			Metadata:      commonv1alpha1.MetadataFromLegacyString(`{"owned-by":"ngrok-operator"}`),
+			ReclaimPolicy: ingressv1alpha1.TCPAddressReclaimPolicyDelete,
+		},
+	}
+}
+
+func TestTCPAddressReconciler_CreateReservesAddress(t *testing.T) {
+	addr := testTCPAddress("")
+	r, addrs := newTCPAddressTestReconciler(t, addr)
+
+	require.NoError(t, r.create(context.Background(), addr))
+	require.NotEmpty(t, addr.Status.ID)
+	remoteAddr, err := addrs.Get(context.Background(), addr.Status.ID)
+	require.NoError(t, err)
+	assert.Equal(t, remoteAddr.Addr, addr.Status.Address)
+	assert.Equal(t, "my address", remoteAddr.Description)
+	assert.Equal(t, "tcp://"+remoteAddr.Addr, addr.URL())
+	assert.True(t, IsTCPAddressReady(addr))
+}
+
+func TestTCPAddressReconciler_CreateAdoptsAddress(t *testing.T) {
+	ctx := context.Background()
+	addr := testTCPAddress("")
+	r, addrs := newTCPAddressTestReconciler(t, addr)
+
+	existing, err := addrs.Create(ctx, &ngrok.ReservedAddrCreate{Description: "Reserved for default/my-svc"})
+	require.NoError(t, err)
+	addr.Spec.Address = existing.Addr
+
+	require.NoError(t, r.create(ctx, addr))
+	assert.Equal(t, existing.ID, addr.Status.ID)
+	assert.Equal(t, existing.Addr, addr.Status.Address)
+	assert.Equal(t, 1, countReservedAddrs(t, addrs))
+
+	// The description is brought in line with the spec
+	remoteAddr, err := addrs.Get(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "my address", remoteAddr.Description)
+	assert.True(t, IsTCPAddressReady(addr))
+}
+
+func TestTCPAddressReconciler_CreateAdoptMissingAddress(t *testing.T) {
+	addr := testTCPAddress("1.tcp.ngrok.io:12345")
+	r, addrs := newTCPAddressTestReconciler(t, addr)
+
+	require.Error(t, r.create(context.Background(), addr))
+	assert.Empty(t, addr.Status.ID)
+	assert.Zero(t, countReservedAddrs(t, addrs))
+	assert.False(t, IsTCPAddressReady(addr))
+
+	cond := meta.FindStatusCondition(addr.Status.Conditions, ConditionTCPAddressReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReasonTCPAddressNotFound, cond.Reason)
+}
+
+func TestTCPAddressReconciler_UpdateRecreatesMissingAddress(t *testing.T) {
+	addr := testTCPAddress("")
+	addr.Status = ingressv1alpha1.TCPAddressStatus{ID: "ra_missing", Address: "1.tcp.ngrok.io:12345"}
+	r, _ := newTCPAddressTestReconciler(t, addr)
+
+	err := r.update(context.Background(), addr)
+	require.Error(t, err)
+	assert.True(t, ngrok.IsNotFound(err))
+	assert.Empty(t, addr.Status.ID)
+	assert.Empty(t, addr.Status.Address)
+}
+
+func TestTCPAddressReconciler_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delete", func(t *testing.T) {
+		addr := testTCPAddress("")
+		r, addrs := newTCPAddressTestReconciler(t, addr)
+		require.NoError(t, r.create(ctx, addr))
+
+		require.NoError(t, r.delete(ctx, addr))
+		assert.Empty(t, addr.Status.ID)
+		assert.Zero(t, countReservedAddrs(t, addrs))
+	})
+
+	t.Run("retain", func(t *testing.T) {
+		addr := testTCPAddress("")
+		addr.Spec.ReclaimPolicy = ingressv1alpha1.TCPAddressReclaimPolicyRetain
+		r, addrs := newTCPAddressTestReconciler(t, addr)
+		require.NoError(t, r.create(ctx, addr))
+
+		require.NoError(t, r.delete(ctx, addr))
+		assert.Equal(t, 1, countReservedAddrs(t, addrs))
+	})
+}
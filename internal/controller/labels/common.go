@@ -10,3 +10,15 @@ const prefix = "ngrok.com/"
 const legacyPrefix = "k8s.ngrok.com/"
 
 // LEGACY-PREFIX-MIGRATION: END
+
+// Domain identifies the Domain a generated resource, such as an external-dns
+// DNSEndpoint, publishes DNS records for.
+const Domain = prefix + "domain"
+
+// ServicePort identifies the port of a LoadBalancer Service that a generated
+// endpoint or TCPAddress serves, by port name, or by port number for unnamed ports.
+const ServicePort = prefix + "service-port"
+
+// Service identifies the LoadBalancer Service, by name, that a generated
+// TCPAddress was created for.
+const Service = prefix + "service"
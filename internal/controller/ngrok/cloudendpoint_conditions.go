@@ -7,6 +7,7 @@ import (
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/controller/conditions"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 )
 
@@ -23,6 +24,7 @@ const (
 	ReasonCloudEndpointCreated        = "CloudEndpointCreated"
 	ReasonCloudEndpointCreationFailed = "CloudEndpointCreationFailed"
 	ReasonDomainNotReady              = "DomainNotReady"
+	ReasonInvalidSchedule             = "InvalidSchedule"
 	ReasonPending                     = "Pending"
 	ReasonUnknown                     = "Unknown"
 )
@@ -65,9 +67,16 @@ func calculateCloudEndpointReadyCondition(clep *ngrokv1alpha1.CloudEndpoint, dom
 	// Overall ready status — all required sub-conditions must be true
 	ready := cloudEndpointCreated && trafficPolicyReady && domainReady
 
+	// An endpoint whose schedule is offline is expected to not be created
+	expiredCondition := meta.FindStatusCondition(clep.Status.Conditions, schedule.ConditionExpired)
+	expired := expiredCondition != nil && expiredCondition.Status == metav1.ConditionTrue
+
 	// Determine reason and message based on state
 	var reason, message string
 	switch {
+	case expired:
+		reason = expiredCondition.Reason
+		message = expiredCondition.Message
 	case ready:
 		reason = ReasonCloudEndpointActive
 		message = "CloudEndpoint is active and ready"
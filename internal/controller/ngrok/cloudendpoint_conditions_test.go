@@ -9,6 +9,7 @@ import (
 
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 )
 
 // Helper function to create a test CloudEndpoint
@@ -185,3 +186,29 @@ func TestCalculateCloudEndpointReadyCondition_UnknownState(t *testing.T) {
 	assert.Equal(t, ReasonCloudEndpointActive, readyCondition.Reason)
 	assert.Equal(t, "CloudEndpoint is active and ready", readyCondition.Message)
 }
+
+func TestCalculateCloudEndpointReadyCondition_Expired(t *testing.T) {
+	// An expired endpoint reports its schedule, even while its domain isn't ready
+	endpoint := createTestCloudEndpointWithConditions("test-endpoint", "default", []metav1.Condition{
+		{
+			Type:    ConditionCloudEndpointCreated,
+			Status:  metav1.ConditionFalse,
+			Reason:  schedule.ReasonScheduleWaiting,
+			Message: "Offline until 2026-03-04T12:00:00Z",
+		},
+		{
+			Type:    schedule.ConditionExpired,
+			Status:  metav1.ConditionTrue,
+			Reason:  schedule.ReasonScheduleWaiting,
+			Message: "Offline until 2026-03-04T12:00:00Z",
+		},
+	})
+
+	calculateCloudEndpointReadyCondition(endpoint, nil)
+
+	readyCondition := meta.FindStatusCondition(endpoint.Status.Conditions, ConditionCloudEndpointReady)
+	assert.NotNil(t, readyCondition)
+	assert.Equal(t, metav1.ConditionFalse, readyCondition.Status)
+	assert.Equal(t, schedule.ReasonScheduleWaiting, readyCondition.Reason)
+	assert.Equal(t, "Offline until 2026-03-04T12:00:00Z", readyCondition.Message)
+}
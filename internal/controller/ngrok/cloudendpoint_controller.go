@@ -32,6 +32,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/events"
@@ -39,6 +40,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/go-logr/logr"
 	"github.com/ngrok/ngrok-api-go/v7"
@@ -48,7 +50,10 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/controller"
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	domainpkg "github.com/ngrok/ngrok-operator/internal/domain"
+	"github.com/ngrok/ngrok-operator/internal/drift"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
+	"github.com/ngrok/ngrok-operator/internal/schedule"
 	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 )
 
@@ -67,6 +72,14 @@ type CloudEndpointReconciler struct {
 	DefaultDomainReclaimPolicy *ingressv1alpha1.DomainReclaimPolicy
 	DomainManager              *domainpkg.Manager
 	TrafficPolicyManager       *trafficpolicypkg.Manager
+
+	// OperatorConfig, when set, provides the default domain reclaim policy from the KubernetesOperator instead of
+	// DefaultDomainReclaimPolicy
+	OperatorConfig *operatorconfig.Store
+
+	// DriftScanner periodically checks cloud endpoints for changes made outside of the operator. Drift is not
+	// checked when nil.
+	DriftScanner *drift.Scanner
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -89,6 +102,9 @@ func (r *CloudEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		if r.DefaultDomainReclaimPolicy != nil {
 			opts = append(opts, domainpkg.WithDefaultDomainReclaimPolicy(*r.DefaultDomainReclaimPolicy))
 		}
+		if r.OperatorConfig != nil {
+			opts = append(opts, domainpkg.WithOperatorConfig(r.OperatorConfig))
+		}
 
 		dm, err := domainpkg.NewManager(r.Client, r.Recorder, opts...)
 		if err != nil {
@@ -108,9 +124,13 @@ func (r *CloudEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		DrainState: r.DrainState,
 
 		StatusID: func(clep *ngrokv1alpha1.CloudEndpoint) string { return clep.Status.ID },
+		Adopt:    r.adopt,
 		Create:   r.create,
 		Update:   r.update,
 		Delete:   r.delete,
+		RequeueAfter: func(clep *ngrokv1alpha1.CloudEndpoint) time.Duration {
+			return schedule.RequeueAfter(clep.Spec.Schedule, clep.CreationTimestamp.Time)
+		},
 		ErrResult: func(_ controller.BaseControllerOp, cr *ngrokv1alpha1.CloudEndpoint, err error) (ctrl.Result, error) {
 			retryableErrors := []int{
 				// 18016 and 18017 are state based errors that can happen when endpoint pooling for a given URL
@@ -150,7 +170,7 @@ func (r *CloudEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&ngrokv1alpha1.CloudEndpoint{}, builder.WithPredicates(
 			predicate.Or(
 				predicate.AnnotationChangedPredicate{},
@@ -164,8 +184,11 @@ func (r *CloudEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&ingressv1alpha1.Domain{},
 			r.controller.NewEnqueueRequestForMapFunc(r.findCloudEndpointsForDomain),
-		).
-		Complete(r)
+		)
+	if r.DriftScanner != nil {
+		b = b.WatchesRawSource(drift.Register(r.DriftScanner, "CloudEndpoint", func() client.ObjectList { return &ngrokv1alpha1.CloudEndpointList{} }, r.checkDrift))
+	}
+	return b.Complete(r)
 }
 
 // indexCloudEndpointTrafficPolicyRefs returns the composite key for the
@@ -218,6 +241,10 @@ func (r *CloudEndpointReconciler) create(ctx context.Context, clep *ngrokv1alpha
 		return r.updateStatus(ctx, clep, nil, domainResult, err)
 	}
 
+	if online, err := r.applySchedule(ctx, clep, domainResult); !online {
+		return err
+	}
+
 	return r.createWithPolicy(ctx, clep, domainResult, policy)
 }
 
@@ -249,6 +276,44 @@ func (r *CloudEndpointReconciler) createWithPolicy(ctx context.Context, clep *ng
 	return r.recordWriteSuccess(ctx, clep, ngrokClep, domainResult, "CloudEndpoint created successfully")
 }
 
+// adopt takes ownership of the existing cloud endpoint with the given ID. Agent endpoints belong to the agent session
+// that started them and can't be adopted.
+func (r *CloudEndpointReconciler) adopt(ctx context.Context, clep *ngrokv1alpha1.CloudEndpoint, id string) error {
+	endpoint, err := r.NgrokClientset.Endpoints().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if endpoint.Type != "cloud" {
+		return reconcile.TerminalError(fmt.Errorf("endpoint %s is an %s endpoint, only cloud endpoints can be adopted", id, endpoint.Type))
+	}
+	clep.Status.ID = endpoint.ID
+	return nil
+}
+
+// checkDrift reports the fields of the cloud endpoint that were changed outside of the operator
+func (r *CloudEndpointReconciler) checkDrift(ctx context.Context, clep *ngrokv1alpha1.CloudEndpoint) ([]string, error) {
+	if clep.Status.ID == "" || clep.Generation != clep.Status.ObservedGeneration {
+		return nil, drift.ErrSkip
+	}
+
+	// Resolve the traffic policy on a copy, so the conditions written by the scanner aren't changed
+	resolved := clep.DeepCopy()
+	r.normalizeLegacyTrafficPolicy(resolved, false)
+	result, err := r.TrafficPolicyManager.Resolve(ctx, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := r.NgrokClientset.Endpoints().Get(ctx, clep.Status.ID)
+	if ngrok.IsNotFound(err) {
+		return []string{drift.FieldDeleted}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return endpointDriftedFields(current, resolved.Spec, result.Policy), nil
+}
+
 // Update is called when we have a status ID and want to update the resource in the ngrok API
 // If it fails to find the resource by ID, create a new one instead
 func (r *CloudEndpointReconciler) update(ctx context.Context, clep *ngrokv1alpha1.CloudEndpoint) error {
@@ -262,6 +327,10 @@ func (r *CloudEndpointReconciler) update(ctx context.Context, clep *ngrokv1alpha
 		return r.updateStatus(ctx, clep, nil, domainResult, err)
 	}
 
+	if online, err := r.applySchedule(ctx, clep, domainResult); !online {
+		return err
+	}
+
 	// Fetch current endpoint state from the ngrok API so we can compare
 	// before issuing an update. This avoids redundant API writes on every
 	// requeue cycle (e.g. while waiting for a domain to become ready).
@@ -310,6 +379,34 @@ func (r *CloudEndpointReconciler) update(ctx context.Context, clep *ngrokv1alpha
 	return r.recordWriteSuccess(ctx, clep, ngrokClep, domainResult, "CloudEndpoint updated successfully")
 }
 
+// applySchedule records the state of the endpoint's schedule in the Expired condition. While the schedule is
+// offline, it removes the endpoint from ngrok, writes the status and returns false. The endpoint is created again
+// by the reconcile requested for when the schedule comes back online.
+func (r *CloudEndpointReconciler) applySchedule(ctx context.Context, clep *ngrokv1alpha1.CloudEndpoint, domainResult *domainpkg.DomainResult) (bool, error) {
+	state, err := schedule.Evaluate(clep.Spec.Schedule, clep.CreationTimestamp.Time, time.Now())
+	if err != nil {
+		setCloudEndpointCreatedCondition(clep, false, ReasonInvalidSchedule, fmt.Sprintf("Invalid schedule: %v", err))
+		r.Recorder.Eventf(clep, nil, v1.EventTypeWarning, ReasonInvalidSchedule, "Reconcile", fmt.Sprintf("Invalid schedule: %v", err))
+		return false, r.updateStatus(ctx, clep, nil, domainResult, reconcile.TerminalError(err))
+	}
+	schedule.SetCondition(&clep.Status.Conditions, clep.Generation, clep.Spec.Schedule, state)
+	if state.Active {
+		return true, nil
+	}
+
+	if clep.Status.ID != "" {
+		if err := r.NgrokClientset.Endpoints().Delete(ctx, clep.Status.ID); err != nil && !ngrok.IsNotFound(err) {
+			return false, r.updateStatus(ctx, clep, nil, domainResult, err)
+		}
+		r.Recorder.Eventf(clep, nil, v1.EventTypeNormal, "Expired", "Reconcile", fmt.Sprintf("Removed endpoint %s from ngrok until its schedule is online", clep.Status.ID))
+		clep.Status.ID = ""
+		clep.Status.AssignedURL = ""
+	}
+	expired := meta.FindStatusCondition(clep.Status.Conditions, schedule.ConditionExpired)
+	setCloudEndpointCreatedCondition(clep, false, expired.Reason, expired.Message)
+	return false, r.updateStatus(ctx, clep, nil, domainResult, nil)
+}
+
 // recordWriteSuccess marks the resolved traffic policy as applied and the
 // endpoint as created, then writes status. Called after a downstream
 // create/update (or a skipped no-op update) succeeds.
@@ -530,23 +627,29 @@ func (r *CloudEndpointReconciler) findCloudEndpointsForDomain(ctx context.Contex
 // against the desired state derived from the CloudEndpoint spec and the resolved
 // traffic policy. Returns true if an API update call is necessary.
 func endpointNeedsUpdate(current *ngrok.Endpoint, spec ngrokv1alpha1.CloudEndpointSpec, policy string) bool {
+	return len(endpointDriftedFields(current, spec, policy)) > 0
+}
+
+// endpointDriftedFields returns the fields of the endpoint that don't match the spec and resolved traffic policy
+func endpointDriftedFields(current *ngrok.Endpoint, spec ngrokv1alpha1.CloudEndpointSpec, policy string) []string {
+	fields := []string{}
 	if current.URL != spec.URL {
-		return true
+		fields = append(fields, "url")
 	}
 	if current.Description != spec.Description {
-		return true
+		fields = append(fields, "description")
 	}
 	if current.Metadata != commonv1alpha1.MetadataAPIString(spec.Metadata) {
-		return true
+		fields = append(fields, "metadata")
 	}
 	if current.TrafficPolicy != policy {
-		return true
+		fields = append(fields, "trafficPolicy")
 	}
 	if !slices.Equal(current.Bindings, spec.Bindings) {
-		return true
+		fields = append(fields, "bindings")
 	}
 	if spec.PoolingEnabled != nil && current.PoolingEnabled != *spec.PoolingEnabled {
-		return true
+		fields = append(fields, "poolingEnabled")
 	}
-	return false
+	return fields
 }
@@ -174,6 +174,24 @@ func TestEndpointNeedsUpdate(t *testing.T) {
 	}
 }
 
+func TestEndpointDriftedFields(t *testing.T) {
+	endpoint := &ngrok.Endpoint{
+		URL:           "https://example.ngrok.app",
+		Description:   "edited in the dashboard",
+		Metadata:      `{"owned-by":"ngrok-operator"}`,
+		TrafficPolicy: `{}`,
+		Bindings:      []string{"public"},
+	}
+	spec := ngrokv1alpha1.CloudEndpointSpec{
+		URL:         "https://example.ngrok.app",
+		Description: "Created by the ngrok-operator",
+		Metadata:    commonv1alpha1.MetadataFromMap(map[string]string{"owned-by": "ngrok-operator"}),
+		Bindings:    []string{"public"},
+	}
+
+	assert.Equal(t, []string{"description", "trafficPolicy"}, endpointDriftedFields(endpoint, spec, `{"on_http_request":[]}`))
+}
+
 // TestIndexCloudEndpointTrafficPolicyRefs covers the legacy-vs-canonical
 // fallback gating. The key bug to guard against: when a user has both
 // `spec.trafficPolicy.inline` (or `.policy`) AND `spec.trafficPolicyName`
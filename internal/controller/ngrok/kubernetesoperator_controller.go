@@ -55,6 +55,7 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/controller"
 	"github.com/ngrok/ngrok-operator/internal/drain"
 	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 )
 
 var featureMap = map[string]string{
@@ -96,6 +97,10 @@ type KubernetesOperatorReconciler struct {
 	// BindingCertRenewalWindow controls how far ahead of expiry bindings certificates
 	// are renewed. Defaults to 30 days when unset.
 	BindingCertRenewalWindow time.Duration
+
+	// OperatorConfig resolves the configuration reported in status.effectiveConfig.
+	// The status doesn't report it when nil.
+	OperatorConfig *operatorconfig.Store
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -215,7 +220,7 @@ func (r *KubernetesOperatorReconciler) create(ctx context.Context, ko *ngrokv1al
 		}
 
 		createParams.Binding = &ngrok.KubernetesOperatorBindingCreate{
-			EndpointSelectors: ko.Spec.Binding.EndpointSelectors,
+			EndpointSelectors: ko.GetEndpointSelectors(),
 			CSR:               string(tlsSecret.Data["tls.csr"]),
 		}
 	}
@@ -301,6 +306,16 @@ func (r *KubernetesOperatorReconciler) delete(ctx context.Context, ko *ngrokv1al
 func (r *KubernetesOperatorReconciler) updateStatus(ctx context.Context, ko *ngrokv1alpha1.KubernetesOperator, ngrokKo *ngrok.KubernetesOperator, err error) error {
 	existsInNgrokAPI := ngrokKo != nil && ngrokKo.ID != ""
 
+	if r.DrainOrchestrator != nil {
+		if planErr := r.DrainOrchestrator.PlanDrain(ctx, ko); planErr != nil {
+			ctrl.LoggerFrom(ctx).Error(planErr, "failed to publish drain plan")
+		}
+	}
+
+	if r.OperatorConfig != nil {
+		ko.Status.EffectiveConfig = new(r.OperatorConfig.Resolve(ko))
+	}
+
 	if existsInNgrokAPI {
 		ko.Status.ID = ngrokKo.ID
 		ko.Status.URI = ngrokKo.URI
@@ -401,7 +416,7 @@ func (r *KubernetesOperatorReconciler) _update(ctx context.Context, ko *ngrokv1a
 		}
 
 		updateParams.Binding = &ngrok.KubernetesOperatorBindingUpdate{
-			EndpointSelectors: ko.Spec.Binding.EndpointSelectors,
+			EndpointSelectors: ko.GetEndpointSelectors(),
 			CSR:               new(string(tlsSecret.Data["tls.csr"])),
 		}
 	}
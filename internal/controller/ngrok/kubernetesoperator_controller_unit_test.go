@@ -8,9 +8,11 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/ngrok/ngrok-api-go/v7"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/controller"
 	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
@@ -242,6 +244,50 @@ func TestUpdate_ExistingOperatorConfigurationFailureRemainsRegistered(t *testing
 	assert.Equal(t, ngrokv1alpha1.KubernetesOperatorReasonConfigurationFailed, ready.Reason)
 }
 
+func TestUpdateStatus_EffectiveConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+
+	ko := &ngrokv1alpha1.KubernetesOperator{
+		Name:      "ko",
+		Namespace: "test-ns",
+		Spec: ngrokv1alpha1.KubernetesOperatorSpec{
+			Binding: &ngrokv1alpha1.KubernetesOperatorBinding{EndpointSelectors: []string{"true"}},
+			Config: &ngrokv1alpha1.KubernetesOperatorConfig{
+				NgrokMetadata: map[string]string{"team": "platform"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ko).
+		WithStatusSubresource(ko).
+		Build()
+	reconciler := &KubernetesOperatorReconciler{
+		Client: fakeClient,
+		controller: &controller.BaseController[*ngrokv1alpha1.KubernetesOperator]{
+			Kube:     fakeClient,
+			Log:      logr.Discard(),
+			Recorder: events.NewFakeRecorder(10),
+		},
+		OperatorConfig: operatorconfig.NewStore(operatorconfig.Defaults{
+			DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete,
+			NgrokMetadata:              map[string]string{"cluster": "prod"},
+		}),
+	}
+
+	require.NoError(t, reconciler.updateStatus(context.Background(), ko, &ngrok.KubernetesOperator{ID: "k8sop_123"}, nil))
+
+	persisted := &ngrokv1alpha1.KubernetesOperator{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(ko), persisted))
+	assert.Equal(t, &ngrokv1alpha1.KubernetesOperatorEffectiveConfig{
+		DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete,
+		NgrokMetadata:              map[string]string{"team": "platform"},
+		EndpointSelectors:          []string{"true"},
+		DrainPolicy:                ngrokv1alpha1.DrainPolicyRetain,
+	}, persisted.Status.EffectiveConfig)
+}
+
 func TestKubernetesOperatorReconcilePredicate(t *testing.T) {
 	now := metav1.Now()
 	base := &ngrokv1alpha1.KubernetesOperator{
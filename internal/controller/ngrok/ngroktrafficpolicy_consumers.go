@@ -0,0 +1,419 @@
+package ngrok
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// maxTrafficPolicyConsumers mirrors the MaxItems marker on
+	// NgrokTrafficPolicyStatus.Consumers.
+	maxTrafficPolicyConsumers = 64
+	// maxTrafficPolicyConsumerMessage mirrors the MaxLength marker on
+	// NgrokTrafficPolicyConsumer.Message.
+	maxTrafficPolicyConsumerMessage = 1024
+)
+
+const (
+	// ReasonConsumerNotReported is used for consumers that have not reported a
+	// TrafficPolicyApplied result because they have not been reconciled yet.
+	ReasonConsumerNotReported = "NotReported"
+	// ReasonConsumerReferenceOnly is used for consumers that merge the policy
+	// into generated endpoints instead of reporting on it directly. They are
+	// listed for reference and never counted as pending.
+	ReasonConsumerReferenceOnly = "ReferenceOnly"
+)
+
+// listTrafficPolicyConsumers returns every resource in the policy's namespace
+// that references it, with the apply result each one reports.
+func (r *NgrokTrafficPolicyReconciler) listTrafficPolicyConsumers(ctx context.Context, tp *ngrokv1alpha1.NgrokTrafficPolicy) ([]ngrokv1alpha1.NgrokTrafficPolicyConsumer, error) {
+	key := trafficpolicypkg.LookupKey(tp)
+	consumers := []ngrokv1alpha1.NgrokTrafficPolicyConsumer{}
+
+	var clepList ngrokv1alpha1.CloudEndpointList
+	if err := r.List(ctx, &clepList, client.InNamespace(tp.Namespace), client.MatchingFields{trafficpolicypkg.RefIndex: key}); err != nil {
+		return nil, fmt.Errorf("listing CloudEndpoints: %w", err)
+	}
+	for _, clep := range clepList.Items {
+		consumers = append(consumers, consumerFromConditions("CloudEndpoint", &clep, clep.Status.Conditions))
+	}
+
+	var aepList ngrokv1alpha1.AgentEndpointList
+	if err := r.List(ctx, &aepList, client.InNamespace(tp.Namespace), client.MatchingFields{trafficpolicypkg.RefIndex: key}); err != nil {
+		return nil, fmt.Errorf("listing AgentEndpoints: %w", err)
+	}
+	for _, aep := range aepList.Items {
+		consumers = append(consumers, consumerFromConditions("AgentEndpoint", &aep, aep.Status.Conditions))
+	}
+
+	svcConsumers, err := r.listServiceConsumers(ctx, tp)
+	if err != nil {
+		return nil, err
+	}
+	consumers = append(consumers, svcConsumers...)
+
+	if r.Driver != nil {
+		consumers = append(consumers, r.listTranslatedConsumers(tp)...)
+	}
+
+	return consumers, nil
+}
+
+// listServiceConsumers returns the Services that reference the policy through
+// the traffic-policy annotation. The Service controller inlines the policy
+// into the endpoints it owns, so a Service's apply result is the aggregate of
+// those endpoints' TrafficPolicyApplied conditions.
+func (r *NgrokTrafficPolicyReconciler) listServiceConsumers(ctx context.Context, tp *ngrokv1alpha1.NgrokTrafficPolicy) ([]ngrokv1alpha1.NgrokTrafficPolicyConsumer, error) {
+	var svcList corev1.ServiceList
+	if err := r.List(ctx, &svcList, client.InNamespace(tp.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing Services: %w", err)
+	}
+
+	var services []client.Object
+	for i := range svcList.Items {
+		if policyRefFromAnnotation(&svcList.Items[i]) == client.ObjectKeyFromObject(tp) {
+			services = append(services, &svcList.Items[i])
+		}
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	// Group the conditions of every endpoint in the namespace by the UID of
+	// the Service that controls it.
+	owned := map[types.UID][][]metav1.Condition{}
+	var aepList ngrokv1alpha1.AgentEndpointList
+	if err := r.List(ctx, &aepList, client.InNamespace(tp.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing AgentEndpoints: %w", err)
+	}
+	for _, aep := range aepList.Items {
+		if owner := metav1.GetControllerOf(&aep); owner != nil && owner.Kind == "Service" {
+			owned[owner.UID] = append(owned[owner.UID], aep.Status.Conditions)
+		}
+	}
+	var clepList ngrokv1alpha1.CloudEndpointList
+	if err := r.List(ctx, &clepList, client.InNamespace(tp.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing CloudEndpoints: %w", err)
+	}
+	for _, clep := range clepList.Items {
+		if owner := metav1.GetControllerOf(&clep); owner != nil && owner.Kind == "Service" {
+			owned[owner.UID] = append(owned[owner.UID], clep.Status.Conditions)
+		}
+	}
+
+	consumers := make([]ngrokv1alpha1.NgrokTrafficPolicyConsumer, 0, len(services))
+	for _, svc := range services {
+		consumers = append(consumers, consumerFromOwnedEndpoints("Service", svc, owned[svc.GetUID()]))
+	}
+	return consumers, nil
+}
+
+// listTranslatedConsumers returns the Ingresses, Gateways and HTTPRoutes from
+// the driver's store that reference the policy. These kinds are translated
+// into endpoints whose policy merges several sources, so they cannot report
+// an apply result for this policy on its own and are listed as reference-only.
+func (r *NgrokTrafficPolicyReconciler) listTranslatedConsumers(tp *ngrokv1alpha1.NgrokTrafficPolicy) []ngrokv1alpha1.NgrokTrafficPolicyConsumer {
+	store := r.Driver.GetStore()
+	key := client.ObjectKeyFromObject(tp)
+
+	var objs []client.Object
+	for _, ing := range store.ListNgrokIngressesV1() {
+		objs = append(objs, ing)
+	}
+	for _, gw := range store.ListNgrokGateways() {
+		objs = append(objs, gw)
+	}
+	for _, route := range store.ListHTTPRoutes() {
+		objs = append(objs, route)
+	}
+
+	var consumers []ngrokv1alpha1.NgrokTrafficPolicyConsumer
+	for _, obj := range objs {
+		if obj.GetNamespace() != tp.Namespace || !slices.Contains(trafficPolicyRefsForObject(obj), key) {
+			continue
+		}
+		consumers = append(consumers, ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+			Kind:      consumerKind(obj),
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Applied:   metav1.ConditionUnknown,
+			Reason:    ReasonConsumerReferenceOnly,
+			Message:   "Policy is merged into the endpoints generated for this resource; see their TrafficPolicyApplied conditions",
+		})
+	}
+	return consumers
+}
+
+// trafficPolicyRefsForObject returns the NgrokTrafficPolicies referenced by
+// any kind that can consume one. It is used both to filter consumers and to
+// map watch events back to the policies that need their status refreshed.
+func trafficPolicyRefsForObject(obj client.Object) []types.NamespacedName {
+	var refs []types.NamespacedName
+	add := func(name string) {
+		ref := types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}
+		if name != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+
+	switch o := obj.(type) {
+	case *ngrokv1alpha1.CloudEndpoint:
+		for _, k := range indexCloudEndpointTrafficPolicyRefs(o) {
+			add(nameFromIndexKey(k))
+		}
+	case *ngrokv1alpha1.AgentEndpoint:
+		for _, k := range trafficpolicypkg.IndexKeyForObject(o) {
+			add(nameFromIndexKey(k))
+		}
+	case *netv1.Ingress:
+		add(policyRefFromAnnotation(o).Name)
+		if o.Spec.DefaultBackend != nil {
+			add(policyNameFromIngressBackend(o.Spec.DefaultBackend))
+		}
+		for _, rule := range o.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				add(policyNameFromIngressBackend(&path.Backend))
+			}
+		}
+	case *gatewayv1.HTTPRoute:
+		for _, rule := range o.Spec.Rules {
+			for _, filter := range rule.Filters {
+				add(policyNameFromHTTPRouteFilter(filter))
+			}
+			for _, backendRef := range rule.BackendRefs {
+				for _, filter := range backendRef.Filters {
+					add(policyNameFromHTTPRouteFilter(filter))
+				}
+			}
+		}
+	default:
+		// Services and Gateways reference a policy only through the annotation
+		add(policyRefFromAnnotation(obj).Name)
+	}
+	return refs
+}
+
+// policyRefFromAnnotation returns the policy named by the traffic-policy
+// annotation, or an empty name when the annotation is missing or invalid.
+func policyRefFromAnnotation(obj client.Object) types.NamespacedName {
+	name, err := annotations.ExtractNgrokTrafficPolicyFromAnnotations(obj)
+	if err != nil || name == "" {
+		return types.NamespacedName{}
+	}
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}
+}
+
+func policyNameFromIngressBackend(backend *netv1.IngressBackend) string {
+	ref := backend.Resource
+	if ref == nil || ref.Kind != "NgrokTrafficPolicy" {
+		return ""
+	}
+	return ref.Name
+}
+
+func policyNameFromHTTPRouteFilter(filter gatewayv1.HTTPRouteFilter) string {
+	if filter.Type != gatewayv1.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil {
+		return ""
+	}
+	if filter.ExtensionRef.Kind != "NgrokTrafficPolicy" {
+		return ""
+	}
+	return string(filter.ExtensionRef.Name)
+}
+
+// nameFromIndexKey extracts the name half of a "<namespace>/<name>" RefIndex key.
+func nameFromIndexKey(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
+// consumerKind returns the Kind used for obj in the consumer list. Objects
+// from the informer cache usually have an empty TypeMeta, so the kind is
+// derived from the Go type.
+func consumerKind(obj client.Object) string {
+	switch obj.(type) {
+	case *netv1.Ingress:
+		return "Ingress"
+	case *gatewayv1.Gateway:
+		return "Gateway"
+	case *gatewayv1.HTTPRoute:
+		return "HTTPRoute"
+	default:
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+}
+
+// consumerFromConditions builds a consumer entry from the TrafficPolicyApplied
+// condition on an endpoint that references the policy directly.
+func consumerFromConditions(kind string, obj client.Object, conditions []metav1.Condition) ngrokv1alpha1.NgrokTrafficPolicyConsumer {
+	consumer := ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+		Kind:      kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Applied:   metav1.ConditionUnknown,
+		Reason:    ReasonConsumerNotReported,
+		Message:   fmt.Sprintf("%s has not reported a traffic policy result yet", kind),
+	}
+	if cond := meta.FindStatusCondition(conditions, trafficpolicypkg.ConditionTrafficPolicy); cond != nil {
+		consumer.Applied = cond.Status
+		consumer.Reason = cond.Reason
+		consumer.Message = cond.Message
+	}
+	consumer.Message = truncateConsumerMessage(consumer.Message)
+	return consumer
+}
+
+// consumerFromOwnedEndpoints builds a consumer entry for a resource whose
+// policy is applied through the endpoints it owns. Any failing endpoint fails
+// the consumer; it is applied only once every endpoint reports success.
+func consumerFromOwnedEndpoints(kind string, obj client.Object, endpointConditions [][]metav1.Condition) ngrokv1alpha1.NgrokTrafficPolicyConsumer {
+	consumer := ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+		Kind:      kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Applied:   metav1.ConditionUnknown,
+		Reason:    ReasonConsumerNotReported,
+		Message:   fmt.Sprintf("No endpoints have reported a traffic policy result for this %s yet", kind),
+	}
+
+	applied := 0
+	for _, conditions := range endpointConditions {
+		cond := meta.FindStatusCondition(conditions, trafficpolicypkg.ConditionTrafficPolicy)
+		if cond == nil {
+			continue
+		}
+		if cond.Status == metav1.ConditionFalse {
+			consumer.Applied = metav1.ConditionFalse
+			consumer.Reason = cond.Reason
+			consumer.Message = truncateConsumerMessage(cond.Message)
+			return consumer
+		}
+		if cond.Status == metav1.ConditionTrue {
+			applied++
+		}
+	}
+
+	if len(endpointConditions) > 0 && applied == len(endpointConditions) {
+		consumer.Applied = metav1.ConditionTrue
+		consumer.Reason = trafficpolicypkg.ReasonTrafficPolicyApplied
+		consumer.Message = "Traffic policy successfully applied"
+	}
+	return consumer
+}
+
+// summarizeTrafficPolicyConsumers counts the consumers by apply result and
+// returns a deterministically ordered list capped at maxTrafficPolicyConsumers.
+// Failed consumers sort first so that they survive truncation, followed by
+// those that have not reported a result. Reference-only consumers sort last
+// and are not counted as pending, since they never report a result.
+func summarizeTrafficPolicyConsumers(consumers []ngrokv1alpha1.NgrokTrafficPolicyConsumer) (ngrokv1alpha1.NgrokTrafficPolicyConsumerCounts, []ngrokv1alpha1.NgrokTrafficPolicyConsumer) {
+	counts := ngrokv1alpha1.NgrokTrafficPolicyConsumerCounts{Total: int32(len(consumers))}
+	for _, c := range consumers {
+		switch {
+		case c.Reason == ReasonConsumerReferenceOnly:
+		case c.Applied == metav1.ConditionTrue:
+			counts.Applied++
+		case c.Applied == metav1.ConditionFalse:
+			counts.Failed++
+		default:
+			counts.Pending++
+		}
+	}
+
+	rank := func(c ngrokv1alpha1.NgrokTrafficPolicyConsumer) int {
+		switch {
+		case c.Reason == ReasonConsumerReferenceOnly:
+			return 3
+		case c.Applied == metav1.ConditionFalse:
+			return 0
+		case c.Applied == metav1.ConditionTrue:
+			return 2
+		default:
+			return 1
+		}
+	}
+	sorted := slices.Clone(consumers)
+	slices.SortFunc(sorted, func(a, b ngrokv1alpha1.NgrokTrafficPolicyConsumer) int {
+		return cmp.Or(
+			cmp.Compare(rank(a), rank(b)),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	if len(sorted) > maxTrafficPolicyConsumers {
+		sorted = sorted[:maxTrafficPolicyConsumers]
+	}
+	if len(sorted) == 0 {
+		sorted = nil
+	}
+	return counts, sorted
+}
+
+// truncateConsumerMessage shortens msg to maxTrafficPolicyConsumerMessage
+// characters without splitting a multi-byte rune.
+func truncateConsumerMessage(msg string) string {
+	if utf8.RuneCountInString(msg) <= maxTrafficPolicyConsumerMessage {
+		return msg
+	}
+	runes := []rune(msg)
+	return string(runes[:maxTrafficPolicyConsumerMessage-3]) + "..."
+}
+
+// requestsForTrafficPolicyRefs maps a consumer watch event to reconcile
+// requests for every policy the consumer references.
+func requestsForTrafficPolicyRefs(_ context.Context, obj client.Object) []ctrl.Request {
+	return requestsForRefs(trafficPolicyRefsForObject(obj))
+}
+
+// requestsForEndpointConsumer maps an endpoint watch event to reconcile
+// requests for the policies it references. Endpoints controlled by a Service
+// only carry the Service's policy inline, so the policy named by the owner
+// Service's annotation is requeued as well; its consumer entry aggregates
+// those endpoints' conditions.
+func (r *NgrokTrafficPolicyReconciler) requestsForEndpointConsumer(ctx context.Context, obj client.Object) []ctrl.Request {
+	refs := trafficPolicyRefsForObject(obj)
+
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "Service" {
+		return requestsForRefs(refs)
+	}
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.Log.Error(err, "failed to get owner Service of endpoint", "endpoint", client.ObjectKeyFromObject(obj))
+		}
+		return requestsForRefs(refs)
+	}
+	if ref := policyRefFromAnnotation(svc); ref.Name != "" && svc.UID == owner.UID && !slices.Contains(refs, ref) {
+		refs = append(refs, ref)
+	}
+	return requestsForRefs(refs)
+}
+
+func requestsForRefs(refs []types.NamespacedName) []ctrl.Request {
+	requests := make([]ctrl.Request, 0, len(refs))
+	for _, ref := range refs {
+		requests = append(requests, ctrl.Request{NamespacedName: ref})
+	}
+	return requests
+}
@@ -0,0 +1,363 @@
+package ngrok
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-logr/logr"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func tpApplied(status metav1.ConditionStatus, reason, message string) []metav1.Condition {
+	return []metav1.Condition{{
+		Type:    trafficpolicypkg.ConditionTrafficPolicy,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}}
+}
+
+func TestListTrafficPolicyConsumers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	tp := &ngrokv1alpha1.NgrokTrafficPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "tp", Namespace: "default"},
+	}
+	ref := &ngrokv1alpha1.TrafficPolicyCfg{Reference: &ngrokv1alpha1.K8sObjectRef{Name: "tp"}}
+
+	appliedClep := &ngrokv1alpha1.CloudEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "applied", Namespace: "default"},
+		Spec:       ngrokv1alpha1.CloudEndpointSpec{URL: "https://a.example.com", TrafficPolicy: &ngrokv1alpha1.CloudEndpointTrafficPolicyCfg{Reference: ref.Reference}},
+		Status:     ngrokv1alpha1.CloudEndpointStatus{Conditions: tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok")},
+	}
+	otherPolicyClep := &ngrokv1alpha1.CloudEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: ngrokv1alpha1.CloudEndpointSpec{URL: "https://b.example.com", TrafficPolicy: &ngrokv1alpha1.CloudEndpointTrafficPolicyCfg{
+			Reference: &ngrokv1alpha1.K8sObjectRef{Name: "other"},
+		}},
+	}
+	failedAep := &ngrokv1alpha1.AgentEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "default"},
+		Spec:       ngrokv1alpha1.AgentEndpointSpec{URL: "https://c.example.com", TrafficPolicy: ref},
+		Status:     ngrokv1alpha1.AgentEndpointStatus{Conditions: tpApplied(metav1.ConditionFalse, trafficpolicypkg.ReasonTrafficPolicyError, "bad policy")},
+	}
+	pendingAep := &ngrokv1alpha1.AgentEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+		Spec:       ngrokv1alpha1.AgentEndpointSpec{URL: "https://d.example.com", TrafficPolicy: ref},
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "svc",
+			Namespace:   "default",
+			UID:         types.UID("svc-uid"),
+			Annotations: map[string]string{"ngrok.com/traffic-policy": "tp"},
+		},
+	}
+	svcEndpoint := &ngrokv1alpha1.AgentEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc-endpoint",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       "svc",
+				UID:        svc.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec:   ngrokv1alpha1.AgentEndpointSpec{URL: "tcp://1.tcp.ngrok.io:1234", TrafficPolicy: &ngrokv1alpha1.TrafficPolicyCfg{Inline: []byte(`{}`)}},
+		Status: ngrokv1alpha1.AgentEndpointStatus{Conditions: tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok")},
+	}
+	unannotatedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(tp, appliedClep, otherPolicyClep, failedAep, pendingAep, svc, svcEndpoint, unannotatedSvc).
+		WithIndex(&ngrokv1alpha1.CloudEndpoint{}, trafficpolicypkg.RefIndex, indexCloudEndpointTrafficPolicyRefs).
+		WithIndex(&ngrokv1alpha1.AgentEndpoint{}, trafficpolicypkg.RefIndex, trafficpolicypkg.IndexKeyForObject).
+		Build()
+
+	r := &NgrokTrafficPolicyReconciler{Client: c}
+	consumers, err := r.listTrafficPolicyConsumers(context.Background(), tp)
+	require.NoError(t, err)
+
+	counts, list := summarizeTrafficPolicyConsumers(consumers)
+	assert.Equal(t, ngrokv1alpha1.NgrokTrafficPolicyConsumerCounts{Total: 4, Applied: 2, Failed: 1, Pending: 1}, counts)
+
+	require.Len(t, list, 4)
+	assert.Equal(t, ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+		Kind: "AgentEndpoint", Name: "failed", Namespace: "default",
+		Applied: metav1.ConditionFalse, Reason: trafficpolicypkg.ReasonTrafficPolicyError, Message: "bad policy",
+	}, list[0])
+	assert.Equal(t, "pending", list[1].Name)
+	assert.Equal(t, metav1.ConditionUnknown, list[1].Applied)
+	assert.Equal(t, ReasonConsumerNotReported, list[1].Reason)
+	assert.Equal(t, "CloudEndpoint", list[2].Kind)
+	assert.Equal(t, "applied", list[2].Name)
+	assert.Equal(t, "Service", list[3].Kind)
+	assert.Equal(t, metav1.ConditionTrue, list[3].Applied)
+}
+
+func TestConsumerFromOwnedEndpoints(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+
+	tests := []struct {
+		name       string
+		conditions [][]metav1.Condition
+		want       metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "no endpoints",
+			want:       metav1.ConditionUnknown,
+			wantReason: ReasonConsumerNotReported,
+		},
+		{
+			name: "all applied",
+			conditions: [][]metav1.Condition{
+				tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok"),
+				tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok"),
+			},
+			want:       metav1.ConditionTrue,
+			wantReason: trafficpolicypkg.ReasonTrafficPolicyApplied,
+		},
+		{
+			name: "one not reported",
+			conditions: [][]metav1.Condition{
+				tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok"),
+				nil,
+			},
+			want:       metav1.ConditionUnknown,
+			wantReason: ReasonConsumerNotReported,
+		},
+		{
+			name: "any failure wins",
+			conditions: [][]metav1.Condition{
+				tpApplied(metav1.ConditionTrue, trafficpolicypkg.ReasonTrafficPolicyApplied, "ok"),
+				tpApplied(metav1.ConditionFalse, trafficpolicypkg.ReasonTrafficPolicyError, "bad"),
+			},
+			want:       metav1.ConditionFalse,
+			wantReason: trafficpolicypkg.ReasonTrafficPolicyError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := consumerFromOwnedEndpoints("Service", svc, tt.conditions)
+			assert.Equal(t, tt.want, got.Applied)
+			assert.Equal(t, tt.wantReason, got.Reason)
+		})
+	}
+}
+
+func TestTrafficPolicyRefsForObject(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []types.NamespacedName
+	}{
+		{
+			name: "ingress annotation and resource backends are deduplicated",
+			obj: &netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ing",
+					Namespace:   "ns",
+					Annotations: map[string]string{"ngrok.com/traffic-policy": "tp-a"},
+				},
+				Spec: netv1.IngressSpec{
+					DefaultBackend: &netv1.IngressBackend{
+						Resource: &corev1.TypedLocalObjectReference{Kind: "NgrokTrafficPolicy", Name: "tp-b"},
+					},
+					Rules: []netv1.IngressRule{{
+						IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{
+								{Backend: netv1.IngressBackend{Resource: &corev1.TypedLocalObjectReference{Kind: "NgrokTrafficPolicy", Name: "tp-a"}}},
+								{Backend: netv1.IngressBackend{Resource: &corev1.TypedLocalObjectReference{Kind: "ConfigMap", Name: "ignored"}}},
+							},
+						}},
+					}},
+				},
+			},
+			want: []types.NamespacedName{{Namespace: "ns", Name: "tp-a"}, {Namespace: "ns", Name: "tp-b"}},
+		},
+		{
+			name: "httproute extension ref filters",
+			obj: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{{
+						Filters: []gatewayv1.HTTPRouteFilter{{
+							Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+							ExtensionRef: &gatewayv1.LocalObjectReference{Kind: "NgrokTrafficPolicy", Name: "tp-rule"},
+						}},
+						BackendRefs: []gatewayv1.HTTPBackendRef{{
+							Filters: []gatewayv1.HTTPRouteFilter{{
+								Type:         gatewayv1.HTTPRouteFilterExtensionRef,
+								ExtensionRef: &gatewayv1.LocalObjectReference{Kind: "NgrokTrafficPolicy", Name: "tp-backend"},
+							}},
+						}},
+					}},
+				},
+			},
+			want: []types.NamespacedName{{Namespace: "ns", Name: "tp-rule"}, {Namespace: "ns", Name: "tp-backend"}},
+		},
+		{
+			name: "service annotation",
+			obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+				Name:        "svc",
+				Namespace:   "ns",
+				Annotations: map[string]string{"ngrok.com/traffic-policy": "tp"},
+			}},
+			want: []types.NamespacedName{{Namespace: "ns", Name: "tp"}},
+		},
+		{
+			name: "agent endpoint with inline policy",
+			obj: &ngrokv1alpha1.AgentEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "aep", Namespace: "ns"},
+				Spec:       ngrokv1alpha1.AgentEndpointSpec{TrafficPolicy: &ngrokv1alpha1.TrafficPolicyCfg{Inline: []byte(`{}`)}},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, trafficPolicyRefsForObject(tt.obj))
+		})
+	}
+}
+
+func TestRequestsForEndpointConsumer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "svc",
+			Namespace:   "ns",
+			UID:         types.UID("svc-uid"),
+			Annotations: map[string]string{"ngrok.com/traffic-policy": "tp"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build()
+	r := &NgrokTrafficPolicyReconciler{Client: c, Log: logr.Discard()}
+
+	ownedBy := func(name string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: "v1", Kind: "Service", Name: name, UID: uid, Controller: ptr.To(true)}}
+	}
+	inline := &ngrokv1alpha1.TrafficPolicyCfg{Inline: []byte(`{}`)}
+
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []ctrl.Request
+	}{
+		{
+			name: "service-owned endpoint requeues the service's policy",
+			obj: &ngrokv1alpha1.AgentEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "aep", Namespace: "ns", OwnerReferences: ownedBy("svc", svc.UID)},
+				Spec:       ngrokv1alpha1.AgentEndpointSpec{TrafficPolicy: inline},
+			},
+			want: []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "tp"}}},
+		},
+		{
+			name: "endpoint owned by a deleted service",
+			obj: &ngrokv1alpha1.CloudEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "clep", Namespace: "ns", OwnerReferences: ownedBy("gone", "gone-uid")},
+			},
+			want: []ctrl.Request{},
+		},
+		{
+			name: "endpoint owned by a recreated service",
+			obj: &ngrokv1alpha1.AgentEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "aep", Namespace: "ns", OwnerReferences: ownedBy("svc", "old-uid")},
+				Spec:       ngrokv1alpha1.AgentEndpointSpec{TrafficPolicy: inline},
+			},
+			want: []ctrl.Request{},
+		},
+		{
+			name: "unowned endpoint with a reference",
+			obj: &ngrokv1alpha1.AgentEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "aep", Namespace: "ns"},
+				Spec: ngrokv1alpha1.AgentEndpointSpec{TrafficPolicy: &ngrokv1alpha1.TrafficPolicyCfg{
+					Reference: &ngrokv1alpha1.K8sObjectRef{Name: "direct"},
+				}},
+			},
+			want: []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "direct"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.requestsForEndpointConsumer(t.Context(), tt.obj))
+		})
+	}
+}
+
+func TestTruncateConsumerMessage(t *testing.T) {
+	short := "ok"
+	assert.Equal(t, short, truncateConsumerMessage(short))
+
+	long := strings.Repeat("é", maxTrafficPolicyConsumerMessage+1)
+	got := truncateConsumerMessage(long)
+	assert.True(t, utf8.ValidString(got))
+	assert.Equal(t, maxTrafficPolicyConsumerMessage, utf8.RuneCountInString(got))
+	assert.True(t, strings.HasSuffix(got, "..."))
+}
+
+func TestSummarizeTrafficPolicyConsumersTruncates(t *testing.T) {
+	var consumers []ngrokv1alpha1.NgrokTrafficPolicyConsumer
+	for i := range maxTrafficPolicyConsumers + 5 {
+		consumers = append(consumers, ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+			Kind:    "AgentEndpoint",
+			Name:    fmt.Sprintf("ep-%03d", i),
+			Applied: metav1.ConditionTrue,
+		})
+	}
+	consumers = append(consumers, ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+		Kind:    "CloudEndpoint",
+		Name:    "zz-failed",
+		Applied: metav1.ConditionFalse,
+	})
+
+	counts, list := summarizeTrafficPolicyConsumers(consumers)
+	assert.Equal(t, int32(maxTrafficPolicyConsumers+6), counts.Total)
+	assert.Equal(t, int32(1), counts.Failed)
+	require.Len(t, list, maxTrafficPolicyConsumers)
+	assert.Equal(t, "zz-failed", list[0].Name, "failed consumers must survive truncation")
+}
+
+func TestSummarizeTrafficPolicyConsumersReferenceOnly(t *testing.T) {
+	consumers := []ngrokv1alpha1.NgrokTrafficPolicyConsumer{
+		{Kind: "Ingress", Name: "ing", Applied: metav1.ConditionUnknown, Reason: ReasonConsumerReferenceOnly},
+		{Kind: "AgentEndpoint", Name: "pending", Applied: metav1.ConditionUnknown, Reason: ReasonConsumerNotReported},
+		{Kind: "CloudEndpoint", Name: "applied", Applied: metav1.ConditionTrue},
+	}
+
+	counts, list := summarizeTrafficPolicyConsumers(consumers)
+	assert.Equal(t, ngrokv1alpha1.NgrokTrafficPolicyConsumerCounts{Total: 3, Applied: 1, Pending: 1}, counts)
+	require.Len(t, list, 3)
+	assert.Equal(t, "ing", list[2].Name, "reference-only consumers sort last")
+}
@@ -27,18 +27,27 @@ package ngrok
 import (
 	"context"
 	"reflect"
+	"sync"
 
 	"github.com/go-logr/logr"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	trafficpolicypkg "github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	"github.com/ngrok/ngrok-operator/internal/util"
 	"github.com/ngrok/ngrok-operator/pkg/managerdriver"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 const (
@@ -53,22 +62,29 @@ type NgrokTrafficPolicyReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder events.EventRecorder
 	Driver   *managerdriver.Driver
+
+	// GatewayEnabled watches Gateways and HTTPRoutes for policy references.
+	// It must only be set when the Gateway API CRDs are installed.
+	GatewayEnabled bool
+
+	// syncedGenerations records, per policy, the generation for which the
+	// driver last synced endpoints successfully. Consumer status changes
+	// requeue the policy far more often than its spec changes, and only the
+	// latter require a sync.
+	syncedGenerations sync.Map
 }
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the NgrokTrafficPolicy object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
+// Reconcile validates the NgrokTrafficPolicy, records every resource that
+// references it along with the result of applying it, and re-syncs the
+// driver's endpoints when the policy itself changed.
 func (r *NgrokTrafficPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
+	log := log.FromContext(ctx)
 
 	policy := &ngrokv1alpha1.NgrokTrafficPolicy{}
 	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.syncedGenerations.Delete(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -78,16 +94,30 @@ func (r *NgrokTrafficPolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 	setTrafficPolicyConditions(policy, parsedTrafficPolicy, parseErr)
 	policy.SetObservedGeneration(policy.Generation)
 
+	consumers, err := r.listTrafficPolicyConsumers(ctx, policy)
+	if err != nil {
+		// Keep the previously recorded consumers rather than clearing them on
+		// a transient list failure.
+		log.Error(err, "failed to list traffic policy consumers")
+	} else {
+		policy.Status.ConsumerCounts, policy.Status.Consumers = summarizeTrafficPolicyConsumers(consumers)
+	}
+
 	if !reflect.DeepEqual(prevStatus, policy.Status) {
 		if err := r.Status().Update(ctx, policy); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	if synced, ok := r.syncedGenerations.Load(req.NamespacedName); ok && synced.(int64) == policy.Generation {
+		return ctrl.Result{}, err
+	}
+
 	if parseErr != nil {
 		r.Recorder.Eventf(policy, nil, v1.EventTypeWarning, EventTrafficPolicyParseFailed, "Validate", "Failed to parse Traffic Policy, possibly malformed.")
 		// A malformed policy will not fix itself; wait for a spec change.
-		return ctrl.Result{}, nil
+		r.syncedGenerations.Store(req.NamespacedName, policy.Generation)
+		return ctrl.Result{}, err
 	}
 
 	if parsedTrafficPolicy.IsLegacyPolicy() {
@@ -98,16 +128,66 @@ func (r *NgrokTrafficPolicyReconciler) Reconcile(ctx context.Context, req ctrl.R
 		r.Recorder.Eventf(policy, nil, v1.EventTypeWarning, EventPolicyDeprecation, "Validate", "Traffic Policy has 'enabled' set. This is a legacy option that will stop being supported soon.")
 	}
 
-	return managerdriver.HandleSyncResult(r.Driver.SyncEndpoints(ctx, r.Client))
+	result, syncErr := managerdriver.HandleSyncResult(r.Driver.SyncEndpoints(ctx, r.Client))
+	if syncErr == nil && result.IsZero() {
+		r.syncedGenerations.Store(req.NamespacedName, policy.Generation)
+	}
+	return result, syncErr
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NgrokTrafficPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&ngrokv1alpha1.NgrokTrafficPolicy{}).
-		WithEventFilter(predicate.Or(
+	// The AgentEndpoint controller registers this index in agent-manager;
+	// the api-manager needs its own copy to find AgentEndpoints that
+	// reference a policy.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ngrokv1alpha1.AgentEndpoint{}, trafficpolicypkg.RefIndex, trafficpolicypkg.IndexKeyForObject); err != nil {
+		return err
+	}
+
+	// Consumers are watched so their references and TrafficPolicyApplied
+	// conditions are reflected in the policy's status.
+	consumerPredicates := builder.WithPredicates(predicate.Or(
+		predicate.AnnotationChangedPredicate{},
+		predicate.GenerationChangedPredicate{},
+		trafficPolicyConditionChangedPredicate{},
+	))
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&ngrokv1alpha1.NgrokTrafficPolicy{}, builder.WithPredicates(predicate.Or(
 			predicate.AnnotationChangedPredicate{},
 			predicate.GenerationChangedPredicate{},
-		)).
-		Complete(r)
+		))).
+		Watches(&ngrokv1alpha1.CloudEndpoint{}, handler.EnqueueRequestsFromMapFunc(r.requestsForEndpointConsumer), consumerPredicates).
+		Watches(&ngrokv1alpha1.AgentEndpoint{}, handler.EnqueueRequestsFromMapFunc(r.requestsForEndpointConsumer), consumerPredicates).
+		Watches(&v1.Service{}, handler.EnqueueRequestsFromMapFunc(requestsForTrafficPolicyRefs), consumerPredicates).
+		Watches(&netv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(requestsForTrafficPolicyRefs), consumerPredicates)
+
+	if r.GatewayEnabled {
+		b = b.
+			Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(requestsForTrafficPolicyRefs), consumerPredicates).
+			Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(requestsForTrafficPolicyRefs), consumerPredicates)
+	}
+
+	return b.Complete(r)
+}
+
+// trafficPolicyConditionChangedPredicate passes updates that change an
+// endpoint's TrafficPolicyApplied condition, which the owning policy
+// aggregates into its consumer status.
+type trafficPolicyConditionChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (trafficPolicyConditionChangedPredicate) Update(e event.UpdateEvent) bool {
+	oldEp, ok := e.ObjectOld.(ngrokv1alpha1.EndpointWithTrafficPolicy)
+	if !ok {
+		return false
+	}
+	newEp, ok := e.ObjectNew.(ngrokv1alpha1.EndpointWithTrafficPolicy)
+	if !ok {
+		return false
+	}
+	oldCond := meta.FindStatusCondition(*oldEp.GetConditions(), trafficpolicypkg.ConditionTrafficPolicy)
+	newCond := meta.FindStatusCondition(*newEp.GetConditions(), trafficpolicypkg.ConditionTrafficPolicy)
+	return !reflect.DeepEqual(oldCond, newCond)
 }
@@ -30,27 +30,30 @@ import (
 	"maps"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/ngrok/ngrok-api-go/v7"
 	common "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/annotations"
 	"github.com/ngrok/ngrok-operator/internal/controller"
+	ingresscontroller "github.com/ngrok/ngrok-operator/internal/controller/ingress"
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	"github.com/ngrok/ngrok-operator/internal/deprecation"
 	"github.com/ngrok/ngrok-operator/internal/errors"
 	"github.com/ngrok/ngrok-operator/internal/ir"
-	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
 	"github.com/ngrok/ngrok-operator/internal/resolvers"
 	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	"github.com/ngrok/ngrok-operator/internal/util"
 	"github.com/ngrok/ngrok-operator/pkg/managerdriver"
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -70,7 +73,10 @@ const (
 	// TrafficPolicyIndexKey is the field-index name for Services indexed by the
 	// traffic policy their annotation references (either annotation prefix).
 	// The name is opaque — it is an index key, not an object field path.
-	TrafficPolicyIndexKey  = "ngrok-operator.trafficpolicy-by-name"
+	TrafficPolicyIndexKey = "ngrok-operator.trafficpolicy-by-name"
+	// TCPAddressIndexKey is the field-index name for TCP Services indexed by the
+	// TCPAddress they listen on.
+	TCPAddressIndexKey     = "ngrok-operator.tcpaddress-by-name"
 	NgrokLoadBalancerClass = "ngrok"
 )
 
@@ -90,7 +96,6 @@ type ServiceReconciler struct {
 
 	IPPolicyResolver resolvers.IPPolicyResolver
 	SecretResolver   resolvers.SecretResolver
-	TCPAddresses     ngrokapi.TCPAddressesClient
 }
 
 type ShouldHandleServicePredicate = TypedShouldHandleServicePredicate[client.Object]
@@ -138,10 +143,6 @@ func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.SecretResolver = resolvers.NewDefaultSecretResovler(mgr.GetClient())
 	}
 
-	if r.TCPAddresses == nil {
-		return errors.New("TCPAddresses client is required")
-	}
-
 	owns := []client.Object{
 		&ngrokv1alpha1.AgentEndpoint{},
 		&ngrokv1alpha1.CloudEndpoint{},
@@ -158,6 +159,11 @@ func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&ngrokv1alpha1.NgrokTrafficPolicy{},
 			handler.EnqueueRequestsFromMapFunc(r.findServicesForTrafficPolicy),
+		).
+		// Watch TCP addresses for when they are reserved
+		Watches(
+			&ingressv1alpha1.TCPAddress{},
+			handler.EnqueueRequestsFromMapFunc(r.findServicesForTCPAddress),
 		)
 
 	// Index the subresources by their owner references
@@ -200,6 +206,18 @@ func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	// Index the services by the TCP addresses their ports listen on
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Service{}, TCPAddressIndexKey, func(obj client.Object) []string {
+		svc, ok := obj.(*corev1.Service)
+		if !ok {
+			return nil
+		}
+		return tcpAddressNamesForService(svc)
+	})
+	if err != nil {
+		return err
+	}
+
 	return controller.Complete(r)
 }
 
@@ -279,10 +297,17 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// LEGACY-PREFIX-MIGRATION (read-side cleanup): drop this scan
 	deprecation.ScanAnnotations(log, r.Recorder, svc)
 
-	if len(svc.Spec.Ports) < 1 {
+	ports := servicePorts(svc)
+	for _, p := range svc.Spec.Ports {
+		if !isTCPPort(p) {
+			r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "UnsupportedPortProtocol", "Reconcile", "Port %s uses protocol %s, only TCP ports are supported", servicePortKey(p), p.Protocol)
+		}
+	}
+	if len(ports) < 1 {
 		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "NoPorts", "Reconcile", "Unable to handle service with no ports")
 		return ctrl.Result{}, nil
 	}
+	r.warnUnsupportedServiceFields(svc)
 
 	log.Info("Registering and syncing finalizers")
 	if err := util.RegisterAndSyncFinalizer(ctx, r.Client, svc); err != nil {
@@ -299,7 +324,13 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToGetMappingStrategy", "Reconcile", err.Error())
 	}
 
-	desired, err = r.buildEndpoints(ctx, svc, mappingStrategy)
+	desired, err = r.buildEndpoints(ctx, svc, ports, mappingStrategy)
+	if errors.IsErrTCPAddressNotReady(err) {
+		// The TCPAddress watch requeues the service once the address is reserved
+		log.Info("Waiting for TCP address", "reason", err.Error())
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeNormal, "WaitingForTCPAddress", "Reconcile", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		log.Error(err, "Failed to build desired endpoints")
 		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToBuildEndpoints", "Reconcile", err.Error())
@@ -318,14 +349,14 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	// Determine which object to use for updating the service status based on mapping strategy
-	statusObject, err := r.getObjectForStatusUpdate(mappingStrategy, ownedResources)
+	// Determine which objects to use for updating the service status based on mapping strategy
+	statusObjects, err := r.getObjectsForStatusUpdate(mappingStrategy, ports, ownedResources)
 	if err != nil {
-		log.Error(err, "Failed to determine object for status update")
+		log.Error(err, "Failed to determine objects for status update")
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	if err := subResourceReconcilers.UpdateServiceStatus(ctx, r.Client, svc, statusObject); err != nil {
+	if err := updateStatus(ctx, r.Client, svc, statusObjects); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update service status: %w", err)
 	}
 
@@ -364,6 +395,276 @@ func (r *ServiceReconciler) findServicesForTrafficPolicy(ctx context.Context, po
 	return requests
 }
 
+func (r *ServiceReconciler) findServicesForTCPAddress(ctx context.Context, addr client.Object) []reconcile.Request {
+	log := r.Log
+
+	services := &corev1.ServiceList{}
+	listOpts := &client.ListOptions{
+		Namespace:     addr.GetNamespace(),
+		FieldSelector: fields.OneTermEqualSelector(TCPAddressIndexKey, addr.GetName()),
+	}
+	if err := r.Client.List(ctx, services, listOpts); err != nil {
+		log.Error(err, "Failed to list services for TCP address")
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(services.Items))
+	for i, svc := range services.Items {
+		requests[i] = reconcile.Request{
+			Namespace: svc.GetNamespace(),
+			Name:      svc.GetName(),
+		}
+		log.V(3).Info("Triggering reconciliation for service", "namespace", svc.GetNamespace(), "name", svc.GetName())
+	}
+	return requests
+}
+
+// servicePort is a port of a LoadBalancer service that is served by endpoints of its own
+type servicePort struct {
+	corev1.ServicePort
+	// key identifies the port in the service-port label of its endpoints and in the per-port annotations
+	key string
+	// first is true for the first port, which the url and tcp-address annotations of the service apply to
+	first bool
+}
+
+// servicePorts returns the TCP ports of the service in the order they are declared
+func servicePorts(svc *corev1.Service) []servicePort {
+	ports := []servicePort{}
+	for _, p := range svc.Spec.Ports {
+		if !isTCPPort(p) {
+			continue
+		}
+		ports = append(ports, servicePort{ServicePort: p, key: servicePortKey(p), first: len(ports) == 0})
+	}
+	return ports
+}
+
+func isTCPPort(p corev1.ServicePort) bool {
+	return p.Protocol == "" || p.Protocol == corev1.ProtocolTCP
+}
+
+// servicePortKey returns the name of the port, or its number when it has no name
+func servicePortKey(p corev1.ServicePort) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return strconv.Itoa(int(p.Port))
+}
+
+// lookup returns the value for the port in a per-port annotation, which is keyed by port name or number
+func (p servicePort) lookup(m map[string]string) (string, bool) {
+	if v, ok := m[p.Name]; ok && p.Name != "" {
+		return v, true
+	}
+	v, ok := m[strconv.Itoa(int(p.Port))]
+	return v, ok
+}
+
+// endpointServicePort returns the key of the service port an endpoint serves. Endpoints created before each port
+// had endpoints of its own have no service-port label, and serve the first port.
+func endpointServicePort(obj client.Object, ports []servicePort) string {
+	if key := obj.GetLabels()[labels.ServicePort]; key != "" {
+		return key
+	}
+	if len(ports) > 0 {
+		return ports[0].key
+	}
+	return ""
+}
+
+// getListenerURLForPort returns the URL the endpoint of the port listens on. Ports can set one with the
+// ngrok.com/port-urls annotation. Otherwise the first port listens on the URL of the ngrok.com/url annotation, and
+// the other ports on a TCP address.
+func getListenerURLForPort(svc *corev1.Service, p servicePort) (string, error) {
+	portURLs, err := annotations.ExtractPortURLs(svc)
+	if err != nil {
+		return "", err
+	}
+	if listenerURL, ok := p.lookup(portURLs); ok {
+		return listenerURL, nil
+	}
+	if !p.first {
+		return "tcp://", nil
+	}
+
+	listenerURL, err := annotations.ExtractURL(svc)
+	if err == nil {
+		return listenerURL, nil
+	}
+	if !errors.IsMissingAnnotations(err) {
+		return "", err
+	}
+	// No URL annotation, assume TCP as the default
+	return "tcp://", nil
+}
+
+// tcpAddressForPort returns the name of the TCPAddress a TCP port listens on, and whether the service references it
+// rather than it being created for the port. Ports can reference one with the ngrok.com/port-tcp-addresses
+// annotation, and the first port with the ngrok.com/tcp-address annotation. Otherwise the first port listens on a
+// TCPAddress named after the service, and the other ports on one named after the service and the port.
+func tcpAddressForPort(svc *corev1.Service, p servicePort) (string, bool, error) {
+	names, err := annotations.ExtractPortTCPAddresses(svc)
+	if err != nil {
+		return "", false, err
+	}
+	if name, ok := p.lookup(names); ok {
+		return name, true, nil
+	}
+	if !p.first {
+		return svc.Name + "-" + p.key, false, nil
+	}
+
+	name, err := annotations.ExtractTCPAddress(svc)
+	if err != nil || name != "" {
+		return name, true, err
+	}
+	return svc.Name, false, nil
+}
+
+// tcpAddressNamesForService returns the names of the TCPAddresses the ports of a TCP service listen on
+func tcpAddressNamesForService(svc *corev1.Service) []string {
+	if !shouldHandleService(svc) {
+		return nil
+	}
+
+	names := []string{}
+	for _, p := range servicePorts(svc) {
+		if listenerURL, err := getListenerURLForPort(svc, p); err != nil || listenerURL != "tcp://" {
+			continue
+		}
+		if name, _, err := tcpAddressForPort(svc, p); err == nil && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// getTCPAddressURL returns the tcp:// URL of the TCPAddress the port listens on. When the service doesn't
+// reference a TCPAddress for the port, one is created for it. It is not owned by the service, so the address stays
+// reserved when the service is deleted and recreated.
+func (r *ServiceReconciler) getTCPAddressURL(ctx context.Context, svc *corev1.Service, p servicePort) (string, error) {
+	name, referenced, err := tcpAddressForPort(svc, p)
+	if err != nil {
+		return "", err
+	}
+
+	addr := &ingressv1alpha1.TCPAddress{}
+	err = r.Client.Get(ctx, client.ObjectKey{Namespace: svc.Namespace, Name: name}, addr)
+	switch {
+	case apierrors.IsNotFound(err) && referenced:
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "TCPAddressNotFound", "Reconcile", "TCPAddress %s/%s not found", svc.Namespace, name)
+		return "", errors.NewErrTCPAddressNotReady(fmt.Sprintf("TCPAddress %s/%s not found", svc.Namespace, name))
+	case apierrors.IsNotFound(err):
+		addr, err = r.createTCPAddress(ctx, svc, p, name)
+		if err != nil {
+			r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToReserveTCPAddr", "Reconcile", err.Error())
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	}
+
+	if !referenced {
+		if err := checkTCPAddressOwner(addr, svc, p); err != nil {
+			return "", err
+		}
+	}
+
+	if !referenced && isAdoptedAddressMissing(addr) {
+		// The address the service previously listened on is no longer reserved. Replace the TCPAddress
+		// created for the service with one that reserves a new address.
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "TCPAddrNotReserved", "Reconcile", "The computed TCP address is not reserved, recomputing")
+		if err := r.Client.Delete(ctx, addr); client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		if p.first {
+			if err := r.clearComputedURLAnnotation(ctx, svc); err != nil {
+				return "", err
+			}
+		}
+		return "", errors.NewErrTCPAddressNotReady(fmt.Sprintf("TCPAddress %s/%s is being recreated", svc.Namespace, name))
+	}
+
+	if !ingresscontroller.IsTCPAddressReady(addr) {
+		return "", errors.NewErrTCPAddressNotReady(fmt.Sprintf("TCPAddress %s/%s has not been reserved yet", svc.Namespace, name))
+	}
+	return addr.URL(), nil
+}
+
+// createTCPAddress creates the TCPAddress for a port whose service doesn't reference one. Services that reserved an
+// address before TCPAddresses existed recorded it in their computed-url annotation, which the TCPAddress of the
+// first port adopts.
+func (r *ServiceReconciler) createTCPAddress(ctx context.Context, svc *corev1.Service, p servicePort, name string) (*ingressv1alpha1.TCPAddress, error) {
+	addr := &ingressv1alpha1.TCPAddress{
+		Name:      name,
+		Namespace: svc.Namespace,
+		Labels:    r.ControllerLabels.Labels(),
+		Spec: ingressv1alpha1.TCPAddressSpec{
+			Description:   fmt.Sprintf("Reserved for %s/%s", svc.Namespace, svc.Name),
+			Metadata:      common.MetadataFromMap(map[string]string{"namespace": svc.Namespace, "name": svc.Name}),
+			ReclaimPolicy: ingressv1alpha1.TCPAddressReclaimPolicyDelete,
+		},
+	}
+	if !p.first {
+		addr.Spec.Description = fmt.Sprintf("Reserved for port %s of %s/%s", p.key, svc.Namespace, svc.Name)
+		addr.Spec.Metadata = common.MetadataFromMap(map[string]string{"namespace": svc.Namespace, "name": svc.Name, "port": p.key})
+	}
+
+	maps.Copy(addr.Labels, tcpAddressLabels(svc, p))
+
+	if computedURL, err := annotations.ExtractComputedURL(svc); err == nil && p.first {
+		if parsedURL, err := url.Parse(computedURL); err == nil && parsedURL.Scheme == "tcp" && parsedURL.Port() != "" {
+			addr.Spec.Address = parsedURL.Host
+		}
+	}
+
+	if err := r.Client.Create(ctx, addr); err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// tcpAddressLabels returns the labels identifying the service port a TCPAddress is created for. The TCPAddress of
+// the first port is named after the service, so it only identifies the service.
+func tcpAddressLabels(svc *corev1.Service, p servicePort) map[string]string {
+	l := map[string]string{labels.Service: svc.Name}
+	if !p.first {
+		l[labels.ServicePort] = p.key
+	}
+	return l
+}
+
+// checkTCPAddressOwner returns an error if a TCPAddress found by its default name was created for another service
+// or port, which happens when the default names collide, e.g. for port metrics of Service db and for Service
+// db-metrics. TCPAddresses created before they were labeled are reused.
+func checkTCPAddressOwner(addr *ingressv1alpha1.TCPAddress, svc *corev1.Service, p servicePort) error {
+	owner, ok := addr.Labels[labels.Service]
+	if !ok {
+		return nil
+	}
+	want := tcpAddressLabels(svc, p)
+	if owner == want[labels.Service] && addr.Labels[labels.ServicePort] == want[labels.ServicePort] {
+		return nil
+	}
+
+	usedBy := fmt.Sprintf("Service %s", owner)
+	if port := addr.Labels[labels.ServicePort]; port != "" {
+		usedBy = fmt.Sprintf("port %s of Service %s", port, owner)
+	}
+	return fmt.Errorf("TCPAddress %s/%s is used by %s, reference another one for port %s with the %s annotation",
+		addr.Namespace, addr.Name, usedBy, p.key, annotations.PortTCPAddressesAnnotation)
+}
+
+// isAdoptedAddressMissing returns true if the TCPAddress failed to adopt its address because it is not reserved
+func isAdoptedAddressMissing(addr *ingressv1alpha1.TCPAddress) bool {
+	if addr.Spec.Address == "" {
+		return false
+	}
+	cond := meta.FindStatusCondition(addr.Status.Conditions, ingresscontroller.ConditionTCPAddressReserved)
+	return cond != nil && cond.Reason == ingresscontroller.ReasonTCPAddressNotFound
+}
+
 func (r *ServiceReconciler) clearComputedURLAnnotation(ctx context.Context, svc *corev1.Service) error {
 	a := svc.GetAnnotations()
 	delete(a, annotations.ComputedURLAnnotation)
@@ -394,24 +695,12 @@ func (r *ServiceReconciler) setComputedURLAnnotation(ctx context.Context, svc *c
 	return r.Client.Update(ctx, svc)
 }
 
-func (r *ServiceReconciler) tcpAddressIsReserved(ctx context.Context, hostport string) (bool, error) {
-	iter := r.TCPAddresses.List(&ngrok.Paging{})
-	for iter.Next(ctx) {
-		addr := iter.Item()
-		if addr.Addr == hostport {
-			return true, nil
-		}
-	}
-	return false, iter.Err()
-}
-
-// buildEndpoints creates a CloudEndpoint and an AgentEndpoint for the given LoadBalancer service. The CloudEndpoint
-// will serve as the public endpoint for the service where we attach the traffic policy if one exists, the AgentEndpoint will
-// serve as the internal endpoint.
-func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Service, mappingStrategy ir.IRMappingStrategy) ([]client.Object, error) {
+// buildEndpoints creates a CloudEndpoint and an AgentEndpoint for each port of the given LoadBalancer service. The
+// CloudEndpoint will serve as the public endpoint for the port where we attach the traffic policy if one exists, the
+// AgentEndpoint will serve as the internal endpoint.
+func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Service, ports []servicePort, mappingStrategy ir.IRMappingStrategy) ([]client.Object, error) {
 	log := ctrl.LoggerFrom(ctx)
 
-	port := svc.Spec.Ports[0].Port
 	objects := make([]client.Object, 0)
 
 	// Get whether endpoint pooling should be enabled/disabled from annotations
@@ -429,10 +718,13 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 		return objects, err
 	}
 
-	// The final traffic policy that will be applied to the listener endpoint
-	tp := trafficpolicy.NewTrafficPolicy()
+	endpointSchedule, err := annotations.ExtractEndpointSchedule(svc)
+	if err != nil {
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "InvalidEndpointSchedule", "Reconcile", err.Error())
+		return objects, err
+	}
 
-	// If an explicit traffic policy is defined on the service, merge it with the existing traffic policy
+	// If an explicit traffic policy is defined on the service, it is merged into the traffic policy of each port
 	// before adding the forward-internal action.
 	// TODO: We still need to handle legacy traffic policy conversion
 	policy, err := getNgrokTrafficPolicyForService(ctx, r.Client, svc)
@@ -440,6 +732,35 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 		log.Error(err, "Failed to get traffic policy")
 		return objects, err
 	}
+
+	for _, p := range ports {
+		portObjects, err := r.buildPortEndpoints(ctx, svc, p, mappingStrategy, policy, useEndpointPooling, useBindings, endpointSchedule)
+		if err != nil {
+			return objects, err
+		}
+		objects = append(objects, portObjects...)
+	}
+	return objects, nil
+}
+
+// buildPortEndpoints creates the endpoints of a single port of the service. The endpoint schedule applies to the
+// public endpoint; the internal endpoint of the verbose strategy is unreachable while it is offline anyway.
+func (r *ServiceReconciler) buildPortEndpoints(ctx context.Context, svc *corev1.Service, p servicePort, mappingStrategy ir.IRMappingStrategy, policy *ngrokv1alpha1.NgrokTrafficPolicy, useEndpointPooling *bool, useBindings []string, endpointSchedule *ngrokv1alpha1.EndpointSchedule) ([]client.Object, error) {
+	port := p.Port
+	objects := make([]client.Object, 0)
+
+	// The final traffic policy that will be applied to the listener endpoint
+	tp := trafficpolicy.NewTrafficPolicy()
+	// Only clients in the load balancer source ranges may connect, like with other load balancer providers. They
+	// are checked before the rules of the service's own traffic policy.
+	if len(svc.Spec.LoadBalancerSourceRanges) > 0 {
+		tp.AddRuleOnTCPConnect(trafficpolicy.Rule{
+			Name: "Load-Balancer-Source-Ranges",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRestrictIPsAction(trafficpolicy.RestrictIPsConfig{
+				Allow: loadBalancerSourceRanges(svc),
+			})},
+		})
+	}
 	if policy != nil {
 		explicitTP, err := trafficpolicy.NewTrafficPolicyFromJSON(policy.Spec.Policy)
 		if err != nil {
@@ -454,123 +775,90 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 		return objects, err
 	}
 
-	listenerEndpointURL, err := r.getListenerURL(svc)
+	listenerEndpointURL, err := getListenerURLForPort(svc, p)
 	if err != nil {
 		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToGetListenerURL", "Reconcile", err.Error())
 		return objects, err
 	}
 
-	var computedEndpointURL string
-
+	computedEndpointURL := listenerEndpointURL
 	if listenerEndpointURL == "tcp://" {
-		// The user has either not set a 'url' or 'domain' annotation, and desires a TCP endpoint.
-
-		// First, try to get the computed URL annotation. See the comment in the annotations package
-		// for more information on why we are temporarily doing this.
-		computedEndpointURL, err = annotations.ExtractComputedURL(svc)
+		// The user has either not set a 'url' or 'domain' annotation, and desires a TCP endpoint
+		// listening on the address reserved by a TCPAddress.
+		computedEndpointURL, err = r.getTCPAddressURL(ctx, svc, p)
 		if err != nil {
-			if !errors.IsMissingAnnotations(err) {
-				return objects, err
-			}
-			// We need to reserve a TCP address & update the service with the computed URL
-			addr, err := r.TCPAddresses.Create(ctx, &ngrok.ReservedAddrCreate{
-				Description: fmt.Sprintf("Reserved for %s/%s", svc.Namespace, svc.Name),
-				Metadata:    fmt.Sprintf(`{"namespace":"%s","name":"%s"}`, svc.Namespace, svc.Name),
-			})
-			if err != nil {
-				r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToReserveTCPAddr", "Reconcile", err.Error())
-				return objects, err
-			}
-
-			// Update the service with the computed URL
-			computedEndpointURL = fmt.Sprintf("tcp://%s", addr.Addr)
-			if err := r.setComputedURLAnnotation(ctx, svc, computedEndpointURL); err != nil {
-				return objects, err
-			}
-		} else {
-			// We have a computed URL, most likely from the url or domain not being set,
-			// implying the user wants a TCP address to be reserved. Let's use that, after
-			// verifying that it exists.
-			parsedURL, parseErr := url.Parse(computedEndpointURL)
-			if parseErr != nil {
-				r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "FailedToParseComputedURL", "Reconcile", parseErr.Error())
-				// If we can't parse the URL, we need to clear the computed URL annotation
-				if err := r.clearComputedURLAnnotation(ctx, svc); err != nil {
-					return objects, err
-				}
-				return objects, parseErr
-			}
-
-			if parsedURL.Scheme == "tcp" {
-				// Check that the Address is still reserved
-				reserved, err := r.tcpAddressIsReserved(ctx, parsedURL.Host)
-				if err != nil {
-					return objects, err
-				}
-				if !reserved {
-					r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "TCPAddrNotReserved", "Reconcile", "The computed TCP address is not reserved, recomputing")
-					if err := r.clearComputedURLAnnotation(ctx, svc); err != nil {
-						return objects, err
-					}
-				} else {
-					// LEGACY-PREFIX-MIGRATION: the value above may have been read
-					// from the legacy computed-url key on a Service stamped by a
-					// previous operator version. Re-stamp it so the new key gets
-					// written (setComputedURLAnnotation no-ops once both keys
-					// already match). Without this, the happy TCP path never
-					// writes the annotation, so a legacy-only Service is never
-					// migrated and would lose its reserved TCP address once the
-					// legacy-read fallback is removed.
-					if err := r.setComputedURLAnnotation(ctx, svc, computedEndpointURL); err != nil {
-						return objects, err
-					}
-				}
-			} else {
-				// The computed URL is not a TCP URL, so we also need to clear it
-				if err := r.clearComputedURLAnnotation(ctx, svc); err != nil {
-					return objects, err
-				}
-			}
+			return objects, err
 		}
-	} else {
-		// For non-TCP endpoints (e.g., TLS), set the computed URL to the listener URL
-		// so that updateStatus can use it as the single source of truth
-		if err := r.setComputedURLAnnotation(ctx, svc, listenerEndpointURL); err != nil {
+	}
+	// The computed URL of the first port is recorded on the service. For non-TCP endpoints (e.g., TLS), it is the
+	// listener URL.
+	if p.first {
+		if err := r.setComputedURLAnnotation(ctx, svc, computedEndpointURL); err != nil {
 			return objects, err
 		}
-		computedEndpointURL = listenerEndpointURL
+	}
+
+	// Endpoints of ports other than the first are named after the port
+	namePrefix := svc.Name + "-"
+	if !p.first {
+		namePrefix = fmt.Sprintf("%s-%s-", svc.Name, p.key)
+	}
+	endpointLabels := r.ControllerLabels.Labels()
+	endpointLabels[labels.ServicePort] = p.key
+
+	// HTTP(S) endpoints connect to the service the same way as an Ingress backend, everything else is forwarded
+	// over TCP
+	isHTTP := isHTTPURL(computedEndpointURL)
+	upstream := ngrokv1alpha1.EndpointUpstream{
+		URL: fmt.Sprintf("tcp://%s.%s.%s:%d", svc.Name, svc.Namespace, r.ClusterDomain, port),
+	}
+	if isHTTP {
+		scheme, appProtocol := managerdriver.ServicePortUpstream(ctrl.LoggerFrom(ctx), svc, &p.ServicePort)
+		upstream = ngrokv1alpha1.EndpointUpstream{
+			URL:      fmt.Sprintf("%s%s.%s.%s:%d", scheme, svc.Name, svc.Namespace, r.ClusterDomain, port),
+			Protocol: appProtocol,
+		}
 	}
 
 	switch mappingStrategy {
 	// For the default/collapse strategy, make a single AgentEndpoint
 	case ir.IRMappingStrategy_EndpointsCollapsed:
 		agentEndpoint := &ngrokv1alpha1.AgentEndpoint{
-			GenerateName: svc.Name + "-",
+			GenerateName: namePrefix,
 			Namespace:    svc.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(svc, corev1.SchemeGroupVersion.WithKind("Service")),
 			},
-			Labels: r.ControllerLabels.Labels(),
+			Labels: endpointLabels,
 			Spec: ngrokv1alpha1.AgentEndpointSpec{
-				URL:      computedEndpointURL,
-				Bindings: useBindings,
-				Upstream: ngrokv1alpha1.EndpointUpstream{
-					URL: fmt.Sprintf("tcp://%s.%s.%s:%d", svc.Name, svc.Namespace, r.ClusterDomain, port),
-				},
+				URL:            computedEndpointURL,
+				Bindings:       useBindings,
+				PoolingEnabled: useEndpointPooling,
+				Upstream:       upstream,
 				TrafficPolicy: &ngrokv1alpha1.TrafficPolicyCfg{
 					Inline: rawPolicy,
 				},
+				Schedule: endpointSchedule,
 			},
 		}
 		objects = append(objects, agentEndpoint)
 	// For the verbose strategy, make a CloudEndpoint that routes to an AgentEndpoint
 	case ir.IRMappingStrategy_EndpointsVerbose:
 		internalURL := fmt.Sprintf("tcp://%s.%s.%s.internal:%d", svc.UID, svc.Name, svc.Namespace, port)
-		tp.AddRuleOnTCPConnect(trafficpolicy.Rule{
+		if isHTTP {
+			// Internal HTTP endpoints can't listen on a port, so it is part of the hostname instead
+			internalURL = fmt.Sprintf("https://%s.%s.%s-%d.internal", svc.UID, svc.Name, svc.Namespace, port)
+		}
+		forwardRule := trafficpolicy.Rule{
 			Actions: []trafficpolicy.Action{
 				trafficpolicy.NewForwardInternalAction(internalURL),
 			},
-		})
+		}
+		if isHTTP {
+			tp.AddRuleOnHTTPRequest(forwardRule)
+		} else {
+			tp.AddRuleOnTCPConnect(forwardRule)
+		}
 
 		// We've added a new rule to the traffic policy, so we need to re-marshall it
 		rawPolicy, err = json.Marshal(tp)
@@ -579,12 +867,12 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 		}
 
 		cloudEndpoint := &ngrokv1alpha1.CloudEndpoint{
-			GenerateName: svc.Name + "-",
+			GenerateName: namePrefix,
 			Namespace:    svc.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(svc, corev1.SchemeGroupVersion.WithKind("Service")),
 			},
-			Labels: r.ControllerLabels.Labels(),
+			Labels: endpointLabels,
 			Spec: ngrokv1alpha1.CloudEndpointSpec{
 				URL:            computedEndpointURL,
 				Bindings:       useBindings,
@@ -597,22 +885,21 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 					Inline: rawPolicy,
 					Policy: rawPolicy, //nolint:staticcheck // SA1019: deliberate legacy dual-write, see above
 				},
+				Schedule: endpointSchedule,
 			},
 		}
 		objects = append(objects, cloudEndpoint)
 
 		agentEndpoint := &ngrokv1alpha1.AgentEndpoint{
-			GenerateName: svc.Name + "-internal-",
+			GenerateName: namePrefix + "internal-",
 			Namespace:    svc.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(svc, corev1.SchemeGroupVersion.WithKind("Service")),
 			},
-			Labels: r.ControllerLabels.Labels(),
+			Labels: maps.Clone(endpointLabels),
 			Spec: ngrokv1alpha1.AgentEndpointSpec{
-				URL: internalURL,
-				Upstream: ngrokv1alpha1.EndpointUpstream{
-					URL: fmt.Sprintf("tcp://%s.%s.%s:%d", svc.Name, svc.Namespace, r.ClusterDomain, port),
-				},
+				URL:      internalURL,
+				Upstream: upstream,
 			},
 		}
 		objects = append(objects, agentEndpoint)
@@ -621,42 +908,77 @@ func (r *ServiceReconciler) buildEndpoints(ctx context.Context, svc *corev1.Serv
 	return objects, nil
 }
 
-func (r *ServiceReconciler) getListenerURL(svc *corev1.Service) (string, error) {
-	urlAnnotation, err := annotations.ExtractURL(svc)
-	if err == nil {
-		return urlAnnotation, nil
-	}
-
-	if !errors.IsMissingAnnotations(err) {
-		return "", err
-	}
-
-	// No URL annotation, assume TCP as the default
-	return "tcp://", nil
-}
-
-func (r *ServiceReconciler) getObjectForStatusUpdate(mappingStrategy ir.IRMappingStrategy, ownedResources []client.Object) (client.Object, error) {
+// getObjectsForStatusUpdate returns the endpoint that is reachable from outside the cluster for each port, in the
+// order of the ports
+func (r *ServiceReconciler) getObjectsForStatusUpdate(mappingStrategy ir.IRMappingStrategy, ports []servicePort, ownedResources []client.Object) ([]ngrokv1alpha1.EndpointWithDomain, error) {
+	candidates := []ngrokv1alpha1.EndpointWithDomain{}
+	var kind string
 	switch mappingStrategy {
 	case ir.IRMappingStrategy_EndpointsCollapsed:
-		// We should only have 1 owned resource (the AgentEndpoint)
-		if len(ownedResources) != 1 {
-			return nil, fmt.Errorf("expected 1 owned resource, got %d", len(ownedResources))
+		// Each port has an AgentEndpoint
+		kind = "AgentEndpoint"
+		for _, owned := range ownedResources {
+			if e, ok := owned.(*ngrokv1alpha1.AgentEndpoint); ok {
+				candidates = append(candidates, e)
+			}
 		}
-		return ownedResources[0], nil
 	case ir.IRMappingStrategy_EndpointsVerbose:
-		// We should have 2 owned resources (the CloudEndpoint and AgentEndpoint)
-		if len(ownedResources) != 2 {
-			return nil, fmt.Errorf("expected 2 owned resources, got %d", len(ownedResources))
-		}
+		// Each port has a CloudEndpoint and an internal AgentEndpoint
+		kind = "CloudEndpoint"
 		for _, owned := range ownedResources {
-			if _, ok := owned.(*ngrokv1alpha1.CloudEndpoint); ok {
-				return owned, nil
+			if e, ok := owned.(*ngrokv1alpha1.CloudEndpoint); ok {
+				candidates = append(candidates, e)
 			}
 		}
-		return nil, errors.New("could not find CloudEndpoint among owned resources")
 	default:
 		return nil, fmt.Errorf("unknown mapping strategy: %s", mappingStrategy)
 	}
+
+	if len(candidates) != len(ports) {
+		return nil, fmt.Errorf("expected %d owned %s resources, got %d", len(ports), kind, len(candidates))
+	}
+
+	endpoints := make([]ngrokv1alpha1.EndpointWithDomain, 0, len(ports))
+	for _, p := range ports {
+		i := slices.IndexFunc(candidates, func(e ngrokv1alpha1.EndpointWithDomain) bool {
+			return endpointServicePort(e, ports) == p.key
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("could not find %s for port %s among owned resources", kind, p.key)
+		}
+		endpoints = append(endpoints, candidates[i])
+	}
+	return endpoints, nil
+}
+
+// loadBalancerSourceRanges returns the CIDRs of the service's loadBalancerSourceRanges. The API server allows
+// surrounding whitespace, which the restrict-ips action does not.
+func loadBalancerSourceRanges(svc *corev1.Service) []string {
+	ranges := make([]string, 0, len(svc.Spec.LoadBalancerSourceRanges))
+	for _, cidr := range svc.Spec.LoadBalancerSourceRanges {
+		ranges = append(ranges, strings.TrimSpace(cidr))
+	}
+	return ranges
+}
+
+// warnUnsupportedServiceFields emits warning events for standard Service fields that ngrok load balancers can't
+// honor. Traffic reaches the service from the agent through its cluster IP rather than through the nodes, and the
+// agent doesn't select upstream pods itself.
+func (r *ServiceReconciler) warnUnsupportedServiceFields(svc *corev1.Service) {
+	if svc.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "UnsupportedSessionAffinity", "Reconcile",
+			"sessionAffinity ClientIP is not supported by ngrok load balancers, connections from all clients of an agent share the agent's IP")
+	}
+	if svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+		r.Recorder.Eventf(svc, nil, corev1.EventTypeWarning, "UnsupportedExternalTrafficPolicy", "Reconcile",
+			"externalTrafficPolicy Local has no effect on ngrok load balancers, traffic reaches the service from the agent rather than through nodes")
+	}
+}
+
+// isHTTPURL reports whether an endpoint URL has the http or https scheme
+func isHTTPURL(endpointURL string) bool {
+	u, err := url.Parse(endpointURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
 }
 
 func shouldHandleService(svc *corev1.Service) bool {
@@ -667,7 +989,6 @@ func shouldHandleService(svc *corev1.Service) bool {
 type serviceSubresourceReconciler interface {
 	GetOwnedResources(context.Context, client.Client, *corev1.Service) ([]client.Object, error)
 	Reconcile(context.Context, client.Client, []client.Object) error
-	UpdateServiceStatus(context.Context, client.Client, *corev1.Service, client.Object) error
 }
 
 type serviceSubresourceReconcilers []serviceSubresourceReconciler
@@ -695,16 +1016,6 @@ func (r serviceSubresourceReconcilers) Reconcile(ctx context.Context, c client.C
 	return g.Wait()
 }
 
-func (r serviceSubresourceReconcilers) UpdateServiceStatus(ctx context.Context, c client.Client, svc *corev1.Service, o client.Object) error {
-	g, gctx := errgroup.WithContext(ctx)
-	for _, srr := range r {
-		g.Go(func() error {
-			return srr.UpdateServiceStatus(gctx, c, svc, o)
-		})
-	}
-	return g.Wait()
-}
-
 type baseSubresourceReconciler[T any, PT interface {
 	*T
 	client.Object
@@ -713,7 +1024,6 @@ type baseSubresourceReconciler[T any, PT interface {
 	listOwned     func(context.Context, client.Client, ...client.ListOption) ([]T, error)
 	matches       func(T, T) bool
 	mergeExisting func(T, PT)
-	updateStatus  func(context.Context, client.Client, *corev1.Service, PT) error
 }
 
 func (r *baseSubresourceReconciler[T, PT]) GetOwnedResources(ctx context.Context, c client.Client, svc *corev1.Service) ([]client.Object, error) {
@@ -765,17 +1075,36 @@ func (r *baseSubresourceReconciler[T, PT]) Reconcile(ctx context.Context, c clie
 		return nil
 	}
 
-	// We only support one desired resource of a particular type for now
-	// If there are cases where we need to create multiple cloud or agent endpoints, we will need to change this handling
-	if len(desired) > 1 {
-		return errors.New("multiple desired resources not supported")
+	// Match the owned resources to the desired ones by the service port they serve. Desired resources are in port
+	// order, and owned resources without a service-port label serve the first port.
+	ports := make([]servicePort, 0, len(desired))
+	for _, d := range desired {
+		ports = append(ports, servicePort{key: d.GetLabels()[labels.ServicePort]})
+	}
+	existing := map[string]PT{}
+	for _, e := range r.owned {
+		key := endpointServicePort(e, ports)
+		_, duplicate := existing[key]
+		stillDesired := slices.ContainsFunc(ports, func(p servicePort) bool { return p.key == key })
+		if duplicate || !stillDesired {
+			log.Info(fmt.Sprintf("Deleting %T that is no longer desired", e), "name", e.GetName(), "port", key)
+			if err := c.Delete(ctx, e); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		existing[key] = e
 	}
 
-	d := desired[0]
-
-	// We have a single desired resource and an existing resource, make them match
-	if len(r.owned) == 1 {
-		var e = r.owned[0]
+	for i, d := range desired {
+		e, ok := existing[ports[i].key]
+		if !ok {
+			log.Info(fmt.Sprintf("Creating %T", d), "port", ports[i].key)
+			if err := c.Create(ctx, d); err != nil {
+				return err
+			}
+			continue
+		}
 
 		log.Info(fmt.Sprintf("Updating %T", e), "desired", d, "existing", e)
 		// Fetch the existing resource as it may have been updated
@@ -785,7 +1114,7 @@ func (r *baseSubresourceReconciler[T, PT]) Reconcile(ctx context.Context, c clie
 
 		if r.matches(*d, *e) {
 			log.V(5).Info(fmt.Sprintf("%T matches desired state, no update needed", e))
-			return nil
+			continue
 		}
 
 		r.mergeExisting(*d, e)
@@ -795,31 +1124,8 @@ func (r *baseSubresourceReconciler[T, PT]) Reconcile(ctx context.Context, c clie
 			log.Error(err, fmt.Sprintf("Failed to update %T", e))
 			return err
 		}
-		return nil
-	}
-
-	// If by this point we have more than one owned resource, something is wrong.
-	// Delete the owned resources.
-	if len(r.owned) > 1 {
-		log.Info(fmt.Sprintf("Found multiple %T resources owned by the service, deleting before creating", d))
-		for _, e := range r.owned {
-			if err := c.Delete(ctx, e); err != nil {
-				return err
-			}
-		}
 	}
-
-	log.Info(fmt.Sprintf("Creating %T", d))
-	return c.Create(ctx, d)
-}
-
-func (r *baseSubresourceReconciler[T, PT]) UpdateServiceStatus(ctx context.Context, c client.Client, svc *corev1.Service, o client.Object) error {
-	v, ok := o.(PT)
-	if !ok {
-		return nil
-	}
-
-	return r.updateStatus(ctx, c, svc, v)
+	return nil
 }
 
 func newServiceCloudEndpointReconciler() serviceSubresourceReconciler {
@@ -839,9 +1145,6 @@ func newServiceCloudEndpointReconciler() serviceSubresourceReconciler {
 			existing.Spec = desired.Spec
 			existing.Labels = desired.Labels
 		},
-		updateStatus: func(ctx context.Context, c client.Client, svc *corev1.Service, endpoint *ngrokv1alpha1.CloudEndpoint) error {
-			return updateStatus(ctx, c, svc, endpoint)
-		},
 	}
 }
 
@@ -862,9 +1165,6 @@ func newServiceAgentEndpointReconciler() serviceSubresourceReconciler {
 			existing.Spec = desired.Spec
 			existing.Labels = desired.Labels
 		},
-		updateStatus: func(ctx context.Context, c client.Client, svc *corev1.Service, endpoint *ngrokv1alpha1.AgentEndpoint) error {
-			return updateStatus(ctx, c, svc, endpoint)
-		},
 	}
 }
 
@@ -882,80 +1182,23 @@ func getNgrokTrafficPolicyForService(ctx context.Context, c client.Client, svc *
 	return policy, err
 }
 
-func updateStatus(ctx context.Context, c client.Client, svc *corev1.Service, endpoint ngrokv1alpha1.EndpointWithDomain) error {
-	clearIngressStatus := func(svc *corev1.Service) error {
-		if len(svc.Status.LoadBalancer.Ingress) == 0 {
-			return nil
-		}
-		svc.Status.LoadBalancer.Ingress = nil
-		return c.Status().Update(ctx, svc)
-	}
-
-	hostname := ""
-	port := int32(443)
-
-	// Check if the computed URL is set, if so, let's parse and use it
-	computedURL, err := annotations.ExtractComputedURL(svc)
-	switch {
-	case err == nil:
-		// Let's parse out the host and port
-		targetURL, err := url.Parse(computedURL)
+// updateStatus sets a load balancer ingress entry on the service for the endpoint of each port. Entries are only
+// set for endpoints whose address is known.
+func updateStatus(ctx context.Context, c client.Client, svc *corev1.Service, endpoints []ngrokv1alpha1.EndpointWithDomain) error {
+	var newIngressStatus []corev1.LoadBalancerIngress
+	for _, endpoint := range endpoints {
+		ingress, err := ingressStatusForEndpoint(ctx, c, svc, endpoint)
 		if err != nil {
 			return err
 		}
-		hostname = targetURL.Hostname()
-		if p := targetURL.Port(); p != "" {
-			x, err := strconv.ParseInt(p, 10, 32)
-			if err != nil {
-				return err
-			}
-			port = int32(x)
-		}
-
-		// For TLS endpoints, we need to wait for the domainRef to be set on the endpoint
-		// so we can look up the CNAME target from the Domain CRD. This applies to both
-		// ngrok domains (*.ngrok.app) and custom domains - all TLS endpoints get a domainRef.
-		// TCP endpoints don't have domains, so they can use the hostname directly.
-		if targetURL.Scheme == "tls" {
-			dr := endpoint.GetDomainRef()
-			if dr == nil {
-				// domainRef not yet set by the CloudEndpoint/AgentEndpoint controller.
-				// Clear the status and wait for it to be populated.
-				return clearIngressStatus(svc)
-			}
-
-			domain := &ingressv1alpha1.Domain{}
-			if err := c.Get(ctx, dr.ToClientObjectKey(svc.Namespace), domain); err != nil {
-				// If we can't fetch the domain, we can't determine the CNAME target.
-				// Clear the status until the domain is available.
-				return clearIngressStatus(svc)
-			}
-
-			// Use CNAME target if available (for custom domains), otherwise use the domain itself
-			if domain.Status.CNAMETarget != nil && *domain.Status.CNAMETarget != "" {
-				hostname = *domain.Status.CNAMETarget
-			}
+		if ingress != nil {
+			newIngressStatus = append(newIngressStatus, *ingress)
 		}
-	case !errors.IsMissingAnnotations(err): // Some other error
-		return err
-	default: // computedURL not present, clear status until endpoint is ready
-		return clearIngressStatus(svc)
-	}
-
-	newIngressStatus := []corev1.LoadBalancerIngress{
-		{
-			Hostname: hostname,
-			Ports: []corev1.PortStatus{
-				{
-					Port:     port,
-					Protocol: corev1.ProtocolTCP,
-				},
-			},
-		},
 	}
 
 	// If the status is already set correctly, do nothing
-	if reflect.DeepEqual(svc.Status.LoadBalancer.Ingress, newIngressStatus) {
+	if reflect.DeepEqual(svc.Status.LoadBalancer.Ingress, newIngressStatus) ||
+		(len(svc.Status.LoadBalancer.Ingress) == 0 && len(newIngressStatus) == 0) {
 		return nil
 	}
 
@@ -963,3 +1206,65 @@ func updateStatus(ctx context.Context, c client.Client, svc *corev1.Service, end
 	svc.Status.LoadBalancer.Ingress = newIngressStatus
 	return c.Status().Update(ctx, svc)
 }
+
+// ingressStatusForEndpoint returns the load balancer ingress entry for the endpoint of a port, or nil while its
+// address isn't known
+func ingressStatusForEndpoint(ctx context.Context, c client.Client, svc *corev1.Service, endpoint ngrokv1alpha1.EndpointWithDomain) (*corev1.LoadBalancerIngress, error) {
+	endpointURL := endpoint.GetURL()
+	if endpointURL == "" {
+		return nil, nil
+	}
+
+	// Let's parse out the host and port
+	targetURL, err := url.Parse(endpointURL)
+	if err != nil {
+		return nil, err
+	}
+	hostname := targetURL.Hostname()
+	port := int32(443)
+	if targetURL.Scheme == "http" {
+		port = 80
+	}
+	if p := targetURL.Port(); p != "" {
+		x, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		port = int32(x)
+	}
+
+	// For TLS and HTTP(S) endpoints, we need to wait for the domainRef to be set on the endpoint
+	// so we can look up the CNAME target from the Domain CRD. This applies to both
+	// ngrok domains (*.ngrok.app) and custom domains - all of these endpoints get a domainRef.
+	// TCP endpoints don't have domains, so they can use the hostname directly.
+	if targetURL.Scheme != "tcp" {
+		dr := endpoint.GetDomainRef()
+		if dr == nil {
+			// domainRef not yet set by the CloudEndpoint/AgentEndpoint controller.
+			// Leave the port out of the status and wait for it to be populated.
+			return nil, nil
+		}
+
+		domain := &ingressv1alpha1.Domain{}
+		if err := c.Get(ctx, dr.ToClientObjectKey(svc.Namespace), domain); err != nil {
+			// If we can't fetch the domain, we can't determine the CNAME target.
+			// Leave the port out of the status until the domain is available.
+			return nil, nil
+		}
+
+		// Use CNAME target if available (for custom domains), otherwise use the domain itself
+		if domain.Status.CNAMETarget != nil && *domain.Status.CNAMETarget != "" {
+			hostname = *domain.Status.CNAMETarget
+		}
+	}
+
+	return &corev1.LoadBalancerIngress{
+		Hostname: hostname,
+		Ports: []corev1.PortStatus{
+			{
+				Port:     port,
+				Protocol: corev1.ProtocolTCP,
+			},
+		},
+	}, nil
+}
@@ -113,6 +113,16 @@ func AddAnnotation(key, value string) ServiceModifier {
 	}
 }
 
+func AddPort(name string, port int32) ServiceModifier {
+	return func(svc *corev1.Service) {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:     name,
+			Protocol: corev1.ProtocolTCP,
+			Port:     port,
+		})
+	}
+}
+
 func SetMappingStrategy(strategy annotations.MappingStrategy) ServiceModifier {
 	return func(svc *corev1.Service) {
 		AddAnnotation(annotations.MappingStrategyAnnotation, string(strategy))(svc)
@@ -232,6 +242,19 @@ var _ = Describe("ServiceController", func() {
 					})
 				})
 
+				It("Should create a TCPAddress named after the service", func() {
+					Eventually(func(g Gomega) {
+						addr := &ingressv1alpha1.TCPAddress{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), addr)).To(Succeed())
+						g.Expect(addr.OwnerReferences).To(BeEmpty())
+						g.Expect(addr.Status.Address).NotTo(BeEmpty())
+
+						fetched := &corev1.Service{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), fetched)).To(Succeed())
+						g.Expect(fetched.GetAnnotations()[annotations.ComputedURLAnnotation]).To(Equal(addr.URL()))
+					}, timeout, interval).Should(Succeed())
+				})
+
 				// LEGACY-PREFIX-MIGRATION: a TCP Service stamped by a
 				// pre-migration operator carries only the legacy computed-url
 				// key. The reserved-address happy path must re-stamp the new
@@ -271,6 +294,119 @@ var _ = Describe("ServiceController", func() {
 				})
 			})
 
+			When("the service references a TCPAddress", func() {
+				var addr *ingressv1alpha1.TCPAddress
+
+				BeforeEach(func() {
+					addr = &ingressv1alpha1.TCPAddress{
+						Name:      "shared-addr",
+						Namespace: namespace,
+						Spec: ingressv1alpha1.TCPAddressSpec{
+							ReclaimPolicy: ingressv1alpha1.TCPAddressReclaimPolicyDelete,
+						},
+					}
+					Expect(k8sClient.Create(ctx, addr)).To(Succeed())
+					modifiers.Add(AddAnnotation(annotations.TCPAddressAnnotation, addr.Name))
+				})
+
+				It("Should listen on the referenced TCPAddress", func() {
+					Eventually(func(g Gomega) {
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(addr), addr)).To(Succeed())
+						g.Expect(addr.Status.Address).NotTo(BeEmpty())
+
+						fetched := &corev1.Service{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), fetched)).To(Succeed())
+						g.Expect(fetched.GetAnnotations()[annotations.ComputedURLAnnotation]).To(Equal(addr.URL()))
+
+						By("not creating a TCPAddress for the service")
+						err := k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), &ingressv1alpha1.TCPAddress{})
+						g.Expect(err).To(HaveOccurred())
+					}, timeout, interval).Should(Succeed())
+				})
+			})
+
+			When("the service has multiple ports", func() {
+				BeforeEach(func() {
+					modifiers.Add(AddPort("metrics", 9090))
+				})
+
+				It("Should create an agent endpoint for each port", func() {
+					kginkgo.EventuallyWithAgentEndpoints(ctx, namespace, func(g Gomega, aeps []ngrokv1alpha1.AgentEndpoint) {
+						g.Expect(aeps).To(HaveLen(2))
+
+						ports := []string{}
+						for _, aep := range aeps {
+							ports = append(ports, aep.Labels[labels.ServicePort])
+						}
+						g.Expect(ports).To(ConsistOf("tcp", "metrics"))
+					})
+				})
+
+				It("Should reserve a TCPAddress for each port", func() {
+					Eventually(func(g Gomega) {
+						first := &ingressv1alpha1.TCPAddress{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), first)).To(Succeed())
+						g.Expect(first.Status.Address).NotTo(BeEmpty())
+
+						metrics := &ingressv1alpha1.TCPAddress{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: svc.Name + "-metrics"}, metrics)).To(Succeed())
+						g.Expect(metrics.Status.Address).NotTo(BeEmpty())
+
+						By("setting the computed-url of the service to the address of the first port")
+						fetched := &corev1.Service{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), fetched)).To(Succeed())
+						g.Expect(fetched.GetAnnotations()[annotations.ComputedURLAnnotation]).To(Equal(first.URL()))
+					}, timeout, interval).Should(Succeed())
+				})
+
+				It("Should report an ingress status for each port", func() {
+					Eventually(func(g Gomega) {
+						fetched := &corev1.Service{}
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), fetched)).To(Succeed())
+						g.Expect(fetched.Status.LoadBalancer.Ingress).To(HaveLen(2))
+						for _, ingress := range fetched.Status.LoadBalancer.Ingress {
+							g.Expect(ingress.Hostname).NotTo(BeEmpty())
+							g.Expect(ingress.Ports).To(HaveLen(1))
+						}
+					}, timeout, interval).Should(Succeed())
+				})
+
+				When("a port references a TCPAddress", func() {
+					var addr *ingressv1alpha1.TCPAddress
+
+					BeforeEach(func() {
+						addr = &ingressv1alpha1.TCPAddress{
+							Name:      "metrics-addr",
+							Namespace: namespace,
+							Spec: ingressv1alpha1.TCPAddressSpec{
+								ReclaimPolicy: ingressv1alpha1.TCPAddressReclaimPolicyDelete,
+							},
+						}
+						Expect(k8sClient.Create(ctx, addr)).To(Succeed())
+						modifiers.Add(AddAnnotation(annotations.PortTCPAddressesAnnotation, `{"metrics":"metrics-addr"}`))
+					})
+
+					It("Should listen on the referenced TCPAddress", func() {
+						Eventually(func(g Gomega) {
+							g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(addr), addr)).To(Succeed())
+							g.Expect(addr.Status.Address).NotTo(BeEmpty())
+
+							aeps, err := getAgentEndpoints(k8sClient, namespace)
+							g.Expect(err).NotTo(HaveOccurred())
+							urls := []string{}
+							for _, aep := range aeps.Items {
+								urls = append(urls, aep.Spec.URL)
+							}
+							g.Expect(urls).To(ContainElement(addr.URL()))
+
+							By("not creating a TCPAddress for the port")
+							err = k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: svc.Name + "-metrics"}, &ingressv1alpha1.TCPAddress{})
+							g.Expect(err).To(HaveOccurred())
+						}, timeout, interval).Should(Succeed())
+					})
+				})
+			})
+
 			When("the service has a legacy-prefixed annotation", func() {
 				BeforeEach(func() {
 					modifiers.Add(AddAnnotation("k8s.ngrok.com/url", "tcp://"))
@@ -458,6 +594,19 @@ var _ = Describe("ServiceController", func() {
 					}).WithTimeout(timeout).WithPolling(interval).Should(Succeed())
 				})
 
+				When("service has a pooling annotation", func() {
+					BeforeEach(func() {
+						modifiers.Add(AddAnnotation(annotations.EndpointPoolingAnnotation, "false"))
+					})
+
+					It("should set pooling on the agent endpoint", func() {
+						kginkgo.EventuallyWithAgentEndpoints(ctx, namespace, func(g Gomega, aeps []ngrokv1alpha1.AgentEndpoint) {
+							g.Expect(aeps).To(HaveLen(1))
+							g.Expect(aeps[0].Spec.PoolingEnabled).To(Equal(ptr.To(false)))
+						})
+					})
+				})
+
 				When("service has a traffic policy annotation", func() {
 					var (
 						policy     *ngrokv1alpha1.NgrokTrafficPolicy
@@ -646,6 +795,31 @@ var _ = Describe("ServiceController", func() {
 				})
 			})
 
+			When("service has https:// URL annotation", func() {
+				const httpsDomain = "web.ngrok.app"
+
+				BeforeEach(func() {
+					modifiers.Add(AddAnnotation(Annotation_URL, "https://"+httpsDomain))
+				})
+
+				It("should create an agent endpoint with the HTTPS URL and an HTTP upstream", func() {
+					kginkgo.EventuallyWithAgentEndpoints(ctx, namespace, func(g Gomega, aeps []ngrokv1alpha1.AgentEndpoint) {
+						g.Expect(aeps).To(HaveLen(1))
+
+						aep := aeps[0]
+						g.Expect(aep.Spec.URL).To(Equal("https://" + httpsDomain))
+						g.Expect(aep.Spec.Upstream.URL).To(HavePrefix("http://" + svc.Name + "."))
+					})
+				})
+
+				It("should not reserve a TCP address", func() {
+					Consistently(func(g Gomega) {
+						err := k8sClient.Get(ctx, client.ObjectKeyFromObject(svc), &ingressv1alpha1.TCPAddress{})
+						g.Expect(err).To(HaveOccurred())
+					}, duration, interval).Should(Succeed())
+				})
+			})
+
 			When("service has tls:// URL annotation", func() {
 				const (
 					tlsDomain  = "example.ngrok.app"
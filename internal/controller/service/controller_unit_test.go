@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	common "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+	"github.com/ngrok/ngrok-operator/internal/errors"
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+)
+
+func testMultiPortService(annots map[string]string) *corev1.Service {
+	return &corev1.Service{
+		Name:        "app",
+		Namespace:   "default",
+		Annotations: annots,
+		Spec: corev1.ServiceSpec{
+			Type:              corev1.ServiceTypeLoadBalancer,
+			LoadBalancerClass: new(NgrokLoadBalancerClass),
+			Ports: []corev1.ServicePort{
+				{Name: "web", Protocol: corev1.ProtocolTCP, Port: 80},
+				{Name: "dns", Protocol: corev1.ProtocolUDP, Port: 53},
+				{Protocol: corev1.ProtocolTCP, Port: 9090},
+			},
+		},
+	}
+}
+
+func TestServicePorts(t *testing.T) {
+	ports := servicePorts(testMultiPortService(nil))
+	require.Len(t, ports, 2)
+	assert.Equal(t, "web", ports[0].key)
+	assert.True(t, ports[0].first)
+	assert.Equal(t, "9090", ports[1].key)
+	assert.False(t, ports[1].first)
+}
+
+func TestGetListenerURLForPort(t *testing.T) {
+	svc := testMultiPortService(map[string]string{
+		annotations.URLAnnotation:      "tls://app.example.com",
+		annotations.PortURLsAnnotation: `{"9090":"tls://metrics.example.com"}`,
+	})
+	ports := servicePorts(svc)
+
+	url, err := getListenerURLForPort(svc, ports[0])
+	require.NoError(t, err)
+	assert.Equal(t, "tls://app.example.com", url)
+
+	url, err = getListenerURLForPort(svc, ports[1])
+	require.NoError(t, err)
+	assert.Equal(t, "tls://metrics.example.com", url)
+
+	delete(svc.Annotations, annotations.PortURLsAnnotation)
+	url, err = getListenerURLForPort(svc, ports[1])
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://", url)
+}
+
+func TestTCPAddressForPort(t *testing.T) {
+	svc := testMultiPortService(nil)
+	ports := servicePorts(svc)
+
+	name, referenced, err := tcpAddressForPort(svc, ports[0])
+	require.NoError(t, err)
+	assert.Equal(t, "app", name)
+	assert.False(t, referenced)
+
+	name, referenced, err = tcpAddressForPort(svc, ports[1])
+	require.NoError(t, err)
+	assert.Equal(t, "app-9090", name)
+	assert.False(t, referenced)
+
+	svc.Annotations = map[string]string{
+		annotations.TCPAddressAnnotation:       "shared",
+		annotations.PortTCPAddressesAnnotation: `{"9090":"metrics"}`,
+	}
+	name, referenced, err = tcpAddressForPort(svc, ports[0])
+	require.NoError(t, err)
+	assert.Equal(t, "shared", name)
+	assert.True(t, referenced)
+
+	name, referenced, err = tcpAddressForPort(svc, ports[1])
+	require.NoError(t, err)
+	assert.Equal(t, "metrics", name)
+	assert.True(t, referenced)
+
+	assert.Equal(t, []string{"shared", "metrics"}, tcpAddressNamesForService(svc))
+}
+
+func TestGetTCPAddressURL_Collision(t *testing.T) {
+	// Port metrics of Service db and Service db-metrics both default to a TCPAddress named db-metrics
+	db := testMultiPortService(nil)
+	db.Name = "db"
+	db.Spec.Ports = []corev1.ServicePort{
+		{Name: "postgres", Protocol: corev1.ProtocolTCP, Port: 5432},
+		{Name: "metrics", Protocol: corev1.ProtocolTCP, Port: 9090},
+	}
+	dbMetrics := testMultiPortService(nil)
+	dbMetrics.Name = "db-metrics"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	r := &ServiceReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(db, dbMetrics).Build(),
+		Recorder: events.NewFakeRecorder(10),
+	}
+
+	// The TCPAddress is created for the first port of db-metrics
+	_, err := r.getTCPAddressURL(context.Background(), dbMetrics, servicePorts(dbMetrics)[0])
+	require.ErrorContains(t, err, "has not been reserved yet")
+	addr := &ingressv1alpha1.TCPAddress{}
+	require.NoError(t, r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "db-metrics"}, addr))
+	assert.Equal(t, "db-metrics", addr.Labels[labels.Service])
+	assert.NotContains(t, addr.Labels, labels.ServicePort)
+
+	// and isn't reused for port metrics of db
+	_, err = r.getTCPAddressURL(context.Background(), db, servicePorts(db)[1])
+	require.ErrorContains(t, err, "TCPAddress default/db-metrics is used by Service db-metrics")
+	assert.False(t, errors.IsErrTCPAddressNotReady(err))
+
+	// TCPAddresses created before they were labeled are reused
+	addr.Labels = nil
+	require.NoError(t, r.Client.Update(context.Background(), addr))
+	_, err = r.getTCPAddressURL(context.Background(), db, servicePorts(db)[1])
+	assert.True(t, errors.IsErrTCPAddressNotReady(err))
+}
+
+func TestEndpointServicePort(t *testing.T) {
+	ports := servicePorts(testMultiPortService(nil))
+
+	labeled := &ngrokv1alpha1.AgentEndpoint{Labels: map[string]string{labels.ServicePort: "9090"}}
+	assert.Equal(t, "9090", endpointServicePort(labeled, ports))
+
+	// Endpoints created before each port had its own serve the first port
+	legacy := &ngrokv1alpha1.AgentEndpoint{}
+	assert.Equal(t, "web", endpointServicePort(legacy, ports))
+}
+
+func TestBuildEndpoints_HTTP(t *testing.T) {
+	svc := testMultiPortService(map[string]string{annotations.URLAnnotation: "https://app.example.com"})
+	svc.UID = "1234"
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "web", Protocol: corev1.ProtocolTCP, Port: 8080, AppProtocol: new("kubernetes.io/h2c")},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	r := &ServiceReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build(),
+		Recorder:      events.NewFakeRecorder(10),
+		ClusterDomain: common.DefaultClusterDomain,
+	}
+
+	objects, err := r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsCollapsed)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	aep := objects[0].(*ngrokv1alpha1.AgentEndpoint)
+	assert.Equal(t, "https://app.example.com", aep.Spec.URL)
+	assert.Equal(t, "http://app.default.svc.cluster.local:8080", aep.Spec.Upstream.URL)
+	assert.Equal(t, new(common.ApplicationProtocol_HTTP2), aep.Spec.Upstream.Protocol)
+	assert.Equal(t, "https://app.example.com", svc.Annotations[annotations.ComputedURLAnnotation])
+
+	objects, err = r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsVerbose)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	clep := objects[0].(*ngrokv1alpha1.CloudEndpoint)
+	assert.Equal(t, "https://app.example.com", clep.Spec.URL)
+	assert.Contains(t, string(clep.Spec.TrafficPolicy.Inline), `"on_http_request"`)
+	assert.Contains(t, string(clep.Spec.TrafficPolicy.Inline), "https://1234.app.default-8080.internal")
+	internal := objects[1].(*ngrokv1alpha1.AgentEndpoint)
+	assert.Equal(t, "https://1234.app.default-8080.internal", internal.Spec.URL)
+	assert.Equal(t, "http://app.default.svc.cluster.local:8080", internal.Spec.Upstream.URL)
+}
+
+func TestBuildEndpoints_LoadBalancerSourceRanges(t *testing.T) {
+	svc := testMultiPortService(map[string]string{annotations.URLAnnotation: "tls://app.example.com"})
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+	svc.Spec.LoadBalancerSourceRanges = []string{"192.0.2.0/24", " 2001:db8::/32 "}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	r := &ServiceReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build(),
+		Recorder:      events.NewFakeRecorder(10),
+		ClusterDomain: common.DefaultClusterDomain,
+	}
+
+	objects, err := r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsVerbose)
+	require.NoError(t, err)
+	clep := objects[0].(*ngrokv1alpha1.CloudEndpoint)
+	tp, err := trafficpolicy.NewTrafficPolicyFromJSON(clep.Spec.TrafficPolicy.Inline)
+	require.NoError(t, err)
+
+	// The source ranges are checked before forwarding to the internal endpoint
+	require.Len(t, tp.OnTCPConnect, 2)
+	assert.Equal(t, "Load-Balancer-Source-Ranges", tp.OnTCPConnect[0].Name)
+	assert.Equal(t, trafficpolicy.ActionType_RestrictIPs, tp.OnTCPConnect[0].Actions[0].Type)
+	assert.Contains(t, string(clep.Spec.TrafficPolicy.Inline), `"allow":["192.0.2.0/24","2001:db8::/32"]`)
+	assert.Equal(t, trafficpolicy.ActionType_ForwardInternal, tp.OnTCPConnect[1].Actions[0].Type)
+}
+
+func TestBuildEndpoints_Schedule(t *testing.T) {
+	svc := testMultiPortService(map[string]string{
+		annotations.URLAnnotation:              "tls://app.example.com",
+		annotations.EndpointScheduleAnnotation: `{"ttl":"2h"}`,
+	})
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	r := &ServiceReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build(),
+		Recorder:      events.NewFakeRecorder(10),
+		ClusterDomain: common.DefaultClusterDomain,
+	}
+
+	objects, err := r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsCollapsed)
+	require.NoError(t, err)
+	aep := objects[0].(*ngrokv1alpha1.AgentEndpoint)
+	require.NotNil(t, aep.Spec.Schedule)
+	assert.Equal(t, 2*time.Hour, aep.Spec.Schedule.TTL.Duration)
+
+	// Only the public endpoint has the schedule
+	objects, err = r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsVerbose)
+	require.NoError(t, err)
+	assert.NotNil(t, objects[0].(*ngrokv1alpha1.CloudEndpoint).Spec.Schedule)
+	assert.Nil(t, objects[1].(*ngrokv1alpha1.AgentEndpoint).Spec.Schedule)
+
+	svc.Annotations[annotations.EndpointScheduleAnnotation] = `{"cron":"0 9 * * *"}`
+	_, err = r.buildEndpoints(context.Background(), svc, servicePorts(svc), ir.IRMappingStrategy_EndpointsCollapsed)
+	assert.Error(t, err)
+}
+
+func TestWarnUnsupportedServiceFields(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+	r := &ServiceReconciler{Recorder: recorder}
+
+	svc := testMultiPortService(nil)
+	r.warnUnsupportedServiceFields(svc)
+	assert.Empty(t, recorder.Events)
+
+	svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	r.warnUnsupportedServiceFields(svc)
+	require.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "UnsupportedSessionAffinity")
+	assert.Contains(t, <-recorder.Events, "UnsupportedExternalTrafficPolicy")
+}
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	ingresscontroller "github.com/ngrok/ngrok-operator/internal/controller/ingress"
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
 	"github.com/ngrok/ngrok-operator/internal/testutils"
@@ -126,11 +127,19 @@ var _ = BeforeSuite(func() {
 		Log:              logf.Log.WithName("controllers").WithName("Service"),
 		Recorder:         k8sManager.GetEventRecorder("service-controller"),
 		Scheme:           k8sManager.GetScheme(),
-		TCPAddresses:     tcpAddrsClient,
 		ControllerLabels: labels.NewControllerLabelValues(controllerLabelNamespace, controllerLabelName),
 	}).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = (&ingresscontroller.TCPAddressReconciler{
+		Client:             k8sManager.GetClient(),
+		Log:                logf.Log.WithName("controllers").WithName("TCPAddress"),
+		Recorder:           k8sManager.GetEventRecorder("tcp-address-controller"),
+		Scheme:             k8sManager.GetScheme(),
+		TCPAddressesClient: tcpAddrsClient,
+	}).SetupWithManager(k8sManager)
+	Expect(err).NotTo(HaveOccurred())
+
 	go func() {
 		defer GinkgoRecover()
 		err = k8sManager.Start(ctx)
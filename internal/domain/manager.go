@@ -19,6 +19,7 @@ import (
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/controller/ingress"
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 	"github.com/ngrok/ngrok-operator/internal/util"
 )
 
@@ -30,6 +31,8 @@ const (
 	ReasonDomainReady    = "DomainReady"
 	ReasonDomainCreating = "DomainCreating"
 	ReasonNgrokAPIError  = "NgrokAPIError"
+
+	ReasonTCPAddressNotReady = "TCPAddressNotReady"
 )
 
 var (
@@ -62,6 +65,14 @@ func WithDefaultDomainReclaimPolicy(policy ingressv1alpha1.DomainReclaimPolicy)
 	}
 }
 
+// WithOperatorConfig makes the Domain Manager read the default domain reclaim policy from the operator configuration
+// each time it creates a Domain, instead of the policy set by WithDefaultDomainReclaimPolicy
+func WithOperatorConfig(config *operatorconfig.Store) ManagerOption {
+	return func(m *Manager) {
+		m.operatorConfig = config
+	}
+}
+
 // WithControllerLabels sets the controller labels for the Domain Manager
 func WithControllerLabels(clv labels.ControllerLabelValues) ManagerOption {
 	return func(m *Manager) {
@@ -74,6 +85,7 @@ type Manager struct {
 	Client                     client.Client
 	Recorder                   events.EventRecorder
 	defaultDomainReclaimPolicy *ingressv1alpha1.DomainReclaimPolicy
+	operatorConfig             *operatorconfig.Store
 	controllerLabels           *labels.ControllerLabelValues
 }
 
@@ -151,11 +163,15 @@ func (m *Manager) checkSkippedDomains(ctx context.Context, endpoint ngrokv1alpha
 		}, nil
 	}
 
-	// Skip TCP ngrok URLs
+	// Skip TCP ngrok URLs, waiting on the TCPAddress that reserves the address if there is one
 	if parsedURL.Scheme == "tcp" {
+		endpoint.SetDomainRef(nil)
+		if result, err := m.checkTCPAddress(ctx, endpoint, parsedURL.Host); result != nil || err != nil {
+			return result, err
+		}
+
 		msg := "Domain ready (TCP ngrok URL - no domain reservation needed)"
 		m.setDomainCondition(endpoint, true, ReasonDomainReady, msg)
-		endpoint.SetDomainRef(nil)
 		return &DomainResult{
 			IsReady:      true,
 			ReadyReason:  ReasonDomainReady,
@@ -178,6 +194,48 @@ func (m *Manager) checkSkippedDomains(ctx context.Context, endpoint ngrokv1alpha
 	return nil, nil
 }
 
+// checkTCPAddress reports the state of the TCPAddress in the endpoint's namespace that reserves the given host:port,
+// so endpoints listening on an address that isn't reserved yet wait for it. Returns nil when no TCPAddress reserves it.
+func (m *Manager) checkTCPAddress(ctx context.Context, endpoint ngrokv1alpha1.EndpointWithDomain, hostport string) (*DomainResult, error) {
+	if hostport == "" {
+		return nil, nil
+	}
+
+	addrs := &ingressv1alpha1.TCPAddressList{}
+	if err := m.Client.List(ctx, addrs, client.InNamespace(endpoint.GetNamespace())); err != nil {
+		m.setDomainCondition(endpoint, false, ReasonNgrokAPIError, err.Error())
+		return nil, err
+	}
+
+	for _, addr := range addrs.Items {
+		if addr.Status.Address != hostport && addr.Spec.Address != hostport {
+			continue
+		}
+
+		if ingress.IsTCPAddressReady(&addr) {
+			msg := fmt.Sprintf("Domain ready (TCP address reserved by TCPAddress %s)", addr.Name)
+			m.setDomainCondition(endpoint, true, ReasonDomainReady, msg)
+			return &DomainResult{
+				IsReady:      true,
+				ReadyReason:  ReasonDomainReady,
+				ReadyMessage: msg,
+			}, nil
+		}
+
+		msg := fmt.Sprintf("Waiting for TCPAddress %s to reserve %s", addr.Name, hostport)
+		if readyCondition := meta.FindStatusCondition(addr.Status.Conditions, ingress.ConditionTCPAddressReady); readyCondition != nil {
+			msg = fmt.Sprintf("%s: %s", msg, readyCondition.Message)
+		}
+		m.setDomainCondition(endpoint, false, ReasonTCPAddressNotReady, msg)
+		return &DomainResult{
+			IsReady:      false,
+			ReadyReason:  ReasonTCPAddressNotReady,
+			ReadyMessage: msg,
+		}, nil
+	}
+	return nil, nil
+}
+
 // getOrCreateDomain gets an existing domain or creates a new one
 func (m *Manager) getOrCreateDomain(ctx context.Context, endpoint ngrokv1alpha1.EndpointWithDomain, domain string) (*DomainResult, error) {
 	log := ctrl.LoggerFrom(ctx).WithValues("domain", domain)
@@ -274,7 +332,9 @@ func (m *Manager) createNewDomain(ctx context.Context, endpoint ngrokv1alpha1.En
 		m.controllerLabels.EnsureLabels(newDomain)
 	}
 
-	if m.defaultDomainReclaimPolicy != nil {
+	if m.operatorConfig != nil {
+		newDomain.Spec.ReclaimPolicy = m.operatorConfig.Get().DefaultDomainReclaimPolicy
+	} else if m.defaultDomainReclaimPolicy != nil {
 		newDomain.Spec.ReclaimPolicy = *m.defaultDomainReclaimPolicy
 	}
 
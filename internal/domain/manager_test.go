@@ -15,7 +15,9 @@ import (
 
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller/ingress"
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 )
 
 // Test setup helpers
@@ -330,6 +332,22 @@ func TestManager_EnsureDomainExists_CreateNewDomainWithReclaimPolicy(t *testing.
 	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, createdDomain.Spec.ReclaimPolicy)
 }
 
+func TestManager_EnsureDomainExists_CreateNewDomainWithOperatorConfig(t *testing.T) {
+	config := operatorconfig.NewStore(operatorconfig.Defaults{DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyRetain})
+	manager, c := newTestManagerWithOpts(t, []ManagerOption{
+		WithDefaultDomainReclaimPolicy(ingressv1alpha1.DomainReclaimPolicyDelete),
+		WithOperatorConfig(config),
+	})
+	endpoint := createTestEndpoint("test-endpoint", "default", "https://example.com")
+
+	_, err := manager.EnsureDomainExists(t.Context(), endpoint)
+	require.NoError(t, err)
+
+	var createdDomain ingressv1alpha1.Domain
+	require.NoError(t, c.Get(t.Context(), types.NamespacedName{Name: "example-com", Namespace: "default"}, &createdDomain))
+	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, createdDomain.Spec.ReclaimPolicy)
+}
+
 func TestManager_setDomainCondition(t *testing.T) {
 	manager, _ := newTestManager(t)
 	endpoint := createTestEndpoint("test-endpoint", "default", "https://example.com")
@@ -491,6 +509,38 @@ func TestManager_EnsureDomainExists_SkipsTCPURLs(t *testing.T) {
 	}
 }
 
+func TestManager_EnsureDomainExists_WaitsForTCPAddress(t *testing.T) {
+	addr := &ingressv1alpha1.TCPAddress{
+		Name: "game-server", Namespace: "default",
+		Spec: ingressv1alpha1.TCPAddressSpec{Address: "1.tcp.ngrok.io:12345"},
+	}
+	manager, c := newTestManager(t, addr)
+	endpoint := createTestEndpoint("tcp-endpoint", "default", "tcp://1.tcp.ngrok.io:12345")
+
+	result, err := manager.EnsureDomainExists(t.Context(), endpoint)
+	require.NoError(t, err)
+	assert.False(t, result.IsReady)
+	assert.Equal(t, ReasonTCPAddressNotReady, result.ReadyReason)
+	assertDomainCondition(t, endpoint, metav1.ConditionFalse, "game-server")
+
+	addr.Status = ingressv1alpha1.TCPAddressStatus{
+		ID:      "ra_123",
+		Address: "1.tcp.ngrok.io:12345",
+		Conditions: []metav1.Condition{{
+			Type:   ingress.ConditionTCPAddressReady,
+			Status: metav1.ConditionTrue,
+			Reason: ingress.ReasonTCPAddressActive,
+		}},
+	}
+	require.NoError(t, c.Update(t.Context(), addr))
+
+	result, err = manager.EnsureDomainExists(t.Context(), endpoint)
+	require.NoError(t, err)
+	assert.True(t, result.IsReady)
+	assertDomainCondition(t, endpoint, metav1.ConditionTrue, "game-server")
+	assertNoDomainCreated(t, c)
+}
+
 func TestManager_EnsureDomainExists_KubernetesBinding_DeletesStaleDomain(t *testing.T) {
 	existingDomain := createReadyDomain("example-com", "default", "example.com")
 	manager, c := newTestManager(t, existingDomain)
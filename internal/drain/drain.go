@@ -51,6 +51,12 @@ type Drainer struct {
 	Log    logr.Logger
 	// Policy determines whether to delete ngrok API resources or just remove finalizers
 	Policy ngrokv1alpha1.DrainPolicy
+	// DryRun reports the action each resource would get without changing anything
+	DryRun bool
+	// Previous holds the per-resource records of earlier drain attempts. Resources
+	// recorded as drained that no longer need draining are carried forward and
+	// counted as completed instead of being processed again.
+	Previous []ngrokv1alpha1.KubernetesOperatorDrainResource
 }
 
 type DrainResult struct {
@@ -58,6 +64,8 @@ type DrainResult struct {
 	Completed int
 	Failed    int
 	Errors    []error
+	// Resources records the action and outcome for each resource, in drain order
+	Resources []ngrokv1alpha1.KubernetesOperatorDrainResource
 }
 
 func (r *DrainResult) Progress() string {
@@ -84,46 +92,103 @@ type resourceHandler struct {
 	name        string
 	list        client.ObjectList
 	skipNoMatch bool                                       // true for optional CRDs like Gateway API
+	action      ngrokv1alpha1.DrainAction                  // reported for each resource of this type
 	drainFunc   func(context.Context, client.Object) error // drainUserResource or drainOperatorResource
 }
 
-func (d *Drainer) DrainAll(ctx context.Context) (*DrainResult, error) {
-	result := &DrainResult{}
+// handlers returns the resource types to drain, in order. User resources come
+// first so nothing is left blocked on our finalizers, then endpoints are drained
+// before the domains, IP policies and TCP addresses they reference.
+func (d *Drainer) handlers() []resourceHandler {
+	operatorAction := ngrokv1alpha1.DrainActionRetain
+	if d.Policy == ngrokv1alpha1.DrainPolicyDelete {
+		operatorAction = ngrokv1alpha1.DrainActionDelete
+	}
+	userAction := ngrokv1alpha1.DrainActionRemoveFinalizer
 
-	handlers := []resourceHandler{
+	return []resourceHandler{
 		// User resources: only remove finalizers so they're not blocked
-		{"HTTPRoute", &gatewayv1.HTTPRouteList{}, true, d.drainUserResource},
-		{"TCPRoute", &gatewayv1alpha2.TCPRouteList{}, true, d.drainUserResource},
-		{"TLSRoute", &gatewayv1alpha2.TLSRouteList{}, true, d.drainUserResource},
-		{"Ingress", &netv1.IngressList{}, false, d.drainUserResource},
-		{"Service", &corev1.ServiceList{}, false, d.drainUserResource},
-		{"Gateway", &gatewayv1.GatewayList{}, true, d.drainUserResource},
+		{"HTTPRoute", &gatewayv1.HTTPRouteList{}, true, userAction, d.drainUserResource},
+		{"TCPRoute", &gatewayv1alpha2.TCPRouteList{}, true, userAction, d.drainUserResource},
+		{"TLSRoute", &gatewayv1alpha2.TLSRouteList{}, true, userAction, d.drainUserResource},
+		{"Ingress", &netv1.IngressList{}, false, userAction, d.drainUserResource},
+		{"Service", &corev1.ServiceList{}, false, userAction, d.drainUserResource},
+		{"Gateway", &gatewayv1.GatewayList{}, true, userAction, d.drainUserResource},
 		// Operator resources: delete or retain based on policy
-		{"CloudEndpoint", &ngrokv1alpha1.CloudEndpointList{}, false, d.drainOperatorResource},
-		{"AgentEndpoint", &ngrokv1alpha1.AgentEndpointList{}, false, d.drainOperatorResource},
-		{"Domain", &ingressv1alpha1.DomainList{}, false, d.drainOperatorResource},
-		{"IPPolicy", &ingressv1alpha1.IPPolicyList{}, false, d.drainOperatorResource},
-		{"BoundEndpoint", &bindingsv1alpha1.BoundEndpointList{}, false, d.drainOperatorResource},
+		{"CloudEndpoint", &ngrokv1alpha1.CloudEndpointList{}, false, operatorAction, d.drainOperatorResource},
+		{"AgentEndpoint", &ngrokv1alpha1.AgentEndpointList{}, false, operatorAction, d.drainOperatorResource},
+		{"Domain", &ingressv1alpha1.DomainList{}, false, operatorAction, d.drainOperatorResource},
+		{"IPPolicy", &ingressv1alpha1.IPPolicyList{}, false, operatorAction, d.drainOperatorResource},
+		{"TCPAddress", &ingressv1alpha1.TCPAddressList{}, false, operatorAction, d.drainOperatorResource},
+		{"BoundEndpoint", &bindingsv1alpha1.BoundEndpointList{}, false, operatorAction, d.drainOperatorResource},
+	}
+}
+
+func (d *Drainer) DrainAll(ctx context.Context) (*DrainResult, error) {
+	result := &DrainResult{}
+	handlers := d.handlers()
+
+	// Index the resources drained by earlier attempts by type so they can be
+	// carried forward in drain order.
+	previous := map[string][]ngrokv1alpha1.KubernetesOperatorDrainResource{}
+	if !d.DryRun {
+		for _, r := range d.Previous {
+			if r.State == ngrokv1alpha1.DrainResourceStateDrained {
+				previous[r.Kind] = append(previous[r.Kind], r)
+			}
+		}
 	}
 
 	for _, h := range handlers {
-		d.Log.Info("Draining resource type", "type", h.name)
-		completed, total, failed, errs := d.drainList(ctx, h.name, h.list, h.skipNoMatch, h.drainFunc)
+		if d.DryRun {
+			d.Log.Info("Planning drain of resource type", "type", h.name)
+		} else {
+			d.Log.Info("Draining resource type", "type", h.name)
+		}
+		resources, errs := d.drainList(ctx, h.name, h.list, h.skipNoMatch, h.action, h.drainFunc)
+
+		// Carry forward resources drained by an earlier attempt unless they need
+		// draining again, e.g. because they were recreated.
+		seen := map[string]bool{}
+		for _, r := range resources {
+			seen[drainResourceKey(r)] = true
+		}
+		for _, r := range previous[h.name] {
+			if !seen[drainResourceKey(r)] {
+				resources = append(resources, r)
+			}
+		}
+
+		completed, failed := 0, 0
+		for _, r := range resources {
+			switch r.State {
+			case ngrokv1alpha1.DrainResourceStateDrained:
+				completed++
+			case ngrokv1alpha1.DrainResourceStateFailed:
+				failed++
+			}
+		}
 		result.Completed += completed
-		result.Total += total
+		result.Total += len(resources)
 		result.Failed += failed
 		result.Errors = append(result.Errors, errs...)
+		result.Resources = append(result.Resources, resources...)
 		d.Log.Info("Finished draining resource type",
 			"type", h.name,
 			"completed", completed,
-			"total", total,
+			"total", len(resources),
 			"errors", len(errs),
+			"dryRun", d.DryRun,
 		)
 	}
 
 	return result, nil
 }
 
+func drainResourceKey(r ngrokv1alpha1.KubernetesOperatorDrainResource) string {
+	return r.Namespace + "/" + r.Name
+}
+
 func (d *Drainer) drainUserResource(ctx context.Context, obj client.Object) error {
 	if err := util.RemoveAndSyncFinalizer(ctx, d.Client, obj); err != nil {
 		return fmt.Errorf("failed to remove finalizer from %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
@@ -138,15 +203,19 @@ func (d *Drainer) drainOperatorResource(ctx context.Context, obj client.Object)
 		// Delete mode: Delete the CR without removing finalizer first.
 		// The controller will handle ngrok API cleanup during the delete reconcile,
 		// then remove the finalizer itself. This ensures proper cleanup ordering.
-		if err := d.Client.Delete(ctx, obj); err != nil {
-			if client.IgnoreNotFound(err) != nil {
-				return fmt.Errorf("failed to delete %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		// A resource already being deleted, e.g. by an earlier drain attempt that
+		// timed out waiting, only needs the wait.
+		if obj.GetDeletionTimestamp().IsZero() {
+			if err := d.Client.Delete(ctx, obj); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return fmt.Errorf("failed to delete %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+				}
+				// Already gone, nothing more to do
+				d.Log.V(1).Info("Resource already deleted", "namespace", obj.GetNamespace(), "name", obj.GetName())
+				return nil
 			}
-			// Already gone, nothing more to do
-			d.Log.V(1).Info("Resource already deleted", "namespace", obj.GetNamespace(), "name", obj.GetName())
-			return nil
+			d.Log.V(1).Info("Issued delete for operator resource", "namespace", obj.GetNamespace(), "name", obj.GetName())
 		}
-		d.Log.V(1).Info("Issued delete for operator resource", "namespace", obj.GetNamespace(), "name", obj.GetName())
 
 		// Wait for the resource to be fully deleted (finalizer removed by controller).
 		// This ensures the controller has finished processing the delete before we continue.
@@ -189,20 +258,23 @@ func (d *Drainer) waitForDeletion(ctx context.Context, obj client.Object, key ty
 }
 
 // drainList is a generic helper that lists resources, iterates items with our finalizer,
-// and calls the provided drain function. It handles optional CRD skip logic for Gateway API types.
+// and calls the provided drain function, recording the outcome for each item. In
+// dry-run mode items are recorded as planned without calling the drain function.
+// It handles optional CRD skip logic for Gateway API types.
 func (d *Drainer) drainList(
 	ctx context.Context,
 	kind string,
 	list client.ObjectList,
 	skipNoMatch bool,
+	action ngrokv1alpha1.DrainAction,
 	drainOne func(context.Context, client.Object) error,
-) (completed, total, failed int, errs []error) {
+) (resources []ngrokv1alpha1.KubernetesOperatorDrainResource, errs []error) {
 	if err := d.Client.List(ctx, list); err != nil {
 		if skipNoMatch && meta.IsNoMatchError(err) {
 			d.Log.V(1).Info(kind + " CRD not installed, skipping")
-			return 0, 0, 0, nil
+			return nil, nil
 		}
-		return 0, 0, 0, []error{fmt.Errorf("failed to list %s: %w", kind, err)}
+		return nil, []error{fmt.Errorf("failed to list %s: %w", kind, err)}
 	}
 
 	if err := meta.EachListItem(list, func(obj runtime.Object) error {
@@ -214,17 +286,27 @@ func (d *Drainer) drainList(
 			return nil
 		}
 
-		total++
-		if err := drainOne(ctx, co); err != nil {
-			failed++
-			errs = append(errs, err)
-		} else {
-			completed++
+		resource := ngrokv1alpha1.KubernetesOperatorDrainResource{
+			Kind:      kind,
+			Namespace: co.GetNamespace(),
+			Name:      co.GetName(),
+			Action:    action,
+			State:     ngrokv1alpha1.DrainResourceStatePlanned,
+		}
+		if !d.DryRun {
+			if err := drainOne(ctx, co); err != nil {
+				resource.State = ngrokv1alpha1.DrainResourceStateFailed
+				resource.Message = err.Error()
+				errs = append(errs, err)
+			} else {
+				resource.State = ngrokv1alpha1.DrainResourceStateDrained
+			}
 		}
+		resources = append(resources, resource)
 		return nil
 	}); err != nil {
-		return completed, total, failed, append(errs, fmt.Errorf("failed to iterate %s list: %w", kind, err))
+		return resources, append(errs, fmt.Errorf("failed to iterate %s list: %w", kind, err))
 	}
 
-	return completed, total, failed, errs
+	return resources, errs
 }
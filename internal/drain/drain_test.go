@@ -34,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -369,17 +370,16 @@ func TestDrainer_drainList_ListError(t *testing.T) {
 		Policy: ngrokv1alpha1.DrainPolicyRetain,
 	}
 
-	completed, total, failed, errs := drainer.drainList(
+	resources, errs := drainer.drainList(
 		context.Background(),
 		"Domain",
 		&ingressv1alpha1.DomainList{},
 		false,
+		ngrokv1alpha1.DrainActionRemoveFinalizer,
 		drainer.drainUserResource,
 	)
 
-	assert.Equal(t, 0, completed)
-	assert.Equal(t, 0, total)
-	assert.Equal(t, 0, failed)
+	assert.Empty(t, resources)
 	require.Len(t, errs, 1)
 	assert.Contains(t, errs[0].Error(), "failed to list Domain")
 	assert.Contains(t, errs[0].Error(), "connection refused")
@@ -412,17 +412,16 @@ func TestDrainer_drainList_NoMatchError_SkipsOptionalCRD(t *testing.T) {
 		Policy: ngrokv1alpha1.DrainPolicyRetain,
 	}
 
-	completed, total, failed, errs := drainer.drainList(
+	resources, errs := drainer.drainList(
 		context.Background(),
 		"HTTPRoute",
 		&gatewayv1.HTTPRouteList{},
 		true,
+		ngrokv1alpha1.DrainActionRemoveFinalizer,
 		drainer.drainUserResource,
 	)
 
-	assert.Equal(t, 0, completed)
-	assert.Equal(t, 0, total)
-	assert.Equal(t, 0, failed)
+	assert.Empty(t, resources)
 	assert.Len(t, errs, 0, "should skip NoMatch error when skipNoMatch is true")
 }
 
@@ -454,6 +453,34 @@ func TestDrainer_DrainOperatorResource_AlreadyDeleted(t *testing.T) {
 	require.NoError(t, err, "should succeed when resource is already deleted (NotFound)")
 }
 
+func TestDrainer_DrainOperatorResource_AlreadyTerminating(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+
+	// The domain is already being deleted by an earlier drain attempt and the
+	// controller has since finished cleanup, so it is no longer found.
+	domain := &ingressv1alpha1.Domain{
+		Name:              "terminating",
+		Namespace:         "ngrok-operator",
+		Finalizers:        []string{util.FinalizerName},
+		DeletionTimestamp: new(metav1.Now()),
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+
+	drainer := &Drainer{
+		Client: &errorClient{Client: fakeClient, deleteErr: errors.New("delete should not be re-issued")},
+		Log:    logr.Discard(),
+		Policy: ngrokv1alpha1.DrainPolicyDelete,
+	}
+
+	err := drainer.drainOperatorResource(context.Background(), domain)
+	require.NoError(t, err)
+}
+
 func TestDrainer_DrainOperatorResource_DeleteError(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
@@ -539,3 +566,154 @@ func TestDrainer_DrainOperatorResource_RetainUpdateError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to remove finalizer")
 }
+
+func TestDrainer_DrainAll_DryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	require.NoError(t, bindingsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1.Install(scheme))
+	require.NoError(t, gatewayv1alpha2.Install(scheme))
+
+	ingress := &netv1.Ingress{
+		Name:       "app",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+	domain := &ingressv1alpha1.Domain{
+		Name:       "app-example-com",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+	cloudEndpoint := &ngrokv1alpha1.CloudEndpoint{
+		Name:       "app",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ingress, domain, cloudEndpoint).
+		Build()
+
+	drainer := &Drainer{
+		Client: fakeClient,
+		Log:    logr.Discard(),
+		Policy: ngrokv1alpha1.DrainPolicyDelete,
+		DryRun: true,
+	}
+
+	result, err := drainer.DrainAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Zero(t, result.Completed)
+	assert.False(t, result.HasErrors())
+	assert.Equal(t, []ngrokv1alpha1.KubernetesOperatorDrainResource{
+		{Kind: "Ingress", Namespace: "default", Name: "app", Action: ngrokv1alpha1.DrainActionRemoveFinalizer, State: ngrokv1alpha1.DrainResourceStatePlanned},
+		{Kind: "CloudEndpoint", Namespace: "default", Name: "app", Action: ngrokv1alpha1.DrainActionDelete, State: ngrokv1alpha1.DrainResourceStatePlanned},
+		{Kind: "Domain", Namespace: "default", Name: "app-example-com", Action: ngrokv1alpha1.DrainActionDelete, State: ngrokv1alpha1.DrainResourceStatePlanned},
+	}, result.Resources)
+
+	// Nothing is changed by a dry-run
+	for _, obj := range []client.Object{&netv1.Ingress{}, &ngrokv1alpha1.CloudEndpoint{}} {
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "app", Namespace: "default"}, obj))
+		assert.True(t, util.HasFinalizer(obj))
+	}
+	var fetchedDomain ingressv1alpha1.Domain
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "app-example-com", Namespace: "default"}, &fetchedDomain))
+	assert.True(t, util.HasFinalizer(&fetchedDomain))
+}
+
+func TestDrainer_DrainAll_ResumesFromPrevious(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	require.NoError(t, bindingsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1.Install(scheme))
+	require.NoError(t, gatewayv1alpha2.Install(scheme))
+
+	// "retry" failed in the previous attempt, and "recreated" was drained but
+	// holds the finalizer again. "done" was drained and is no longer present.
+	retry := &ingressv1alpha1.IPPolicy{
+		Name:       "retry",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+	recreated := &ngrokv1alpha1.CloudEndpoint{
+		Name:       "recreated",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(retry, recreated).
+		Build()
+
+	retain := ngrokv1alpha1.DrainActionRetain
+	drainer := &Drainer{
+		Client: fakeClient,
+		Log:    logr.Discard(),
+		Policy: ngrokv1alpha1.DrainPolicyRetain,
+		Previous: []ngrokv1alpha1.KubernetesOperatorDrainResource{
+			{Kind: "CloudEndpoint", Namespace: "default", Name: "recreated", Action: retain, State: ngrokv1alpha1.DrainResourceStateDrained},
+			{Kind: "IPPolicy", Namespace: "default", Name: "done", Action: retain, State: ngrokv1alpha1.DrainResourceStateDrained},
+			{Kind: "IPPolicy", Namespace: "default", Name: "retry", Action: retain, State: ngrokv1alpha1.DrainResourceStateFailed, Message: "conflict"},
+		},
+	}
+
+	result, err := drainer.DrainAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 3, result.Completed)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, []ngrokv1alpha1.KubernetesOperatorDrainResource{
+		{Kind: "CloudEndpoint", Namespace: "default", Name: "recreated", Action: retain, State: ngrokv1alpha1.DrainResourceStateDrained},
+		{Kind: "IPPolicy", Namespace: "default", Name: "retry", Action: retain, State: ngrokv1alpha1.DrainResourceStateDrained},
+		{Kind: "IPPolicy", Namespace: "default", Name: "done", Action: retain, State: ngrokv1alpha1.DrainResourceStateDrained},
+	}, result.Resources)
+
+	var fetched ngrokv1alpha1.CloudEndpoint
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Name: "recreated", Namespace: "default"}, &fetched))
+	assert.False(t, util.HasFinalizer(&fetched), "recreated resource should be drained again")
+}
+
+func TestDrainer_DrainAll_RecordsFailures(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	require.NoError(t, bindingsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1.Install(scheme))
+	require.NoError(t, gatewayv1alpha2.Install(scheme))
+
+	domain := &ingressv1alpha1.Domain{
+		Name:       "app-example-com",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(domain).
+		Build()
+
+	drainer := &Drainer{
+		Client: &errorClient{Client: fakeClient, deleteErr: errors.New("forbidden")},
+		Log:    logr.Discard(),
+		Policy: ngrokv1alpha1.DrainPolicyDelete,
+	}
+
+	result, err := drainer.DrainAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, ngrokv1alpha1.DrainResourceStateFailed, result.Resources[0].State)
+	assert.Equal(t, ngrokv1alpha1.DrainActionDelete, result.Resources[0].Action)
+	assert.Contains(t, result.Resources[0].Message, "forbidden")
+}
@@ -41,6 +41,7 @@ import (
 const (
 	maxDrainErrors      = 20
 	maxDrainErrorLength = 1024
+	maxDrainResources   = 500
 )
 
 // Outcome represents the result of a drain operation
@@ -133,6 +134,11 @@ func (o *Orchestrator) HandleDrain(ctx context.Context, ko *ngrokv1alpha1.Kubern
 		Log:    log,
 		Policy: ko.GetDrainPolicy(),
 	}
+	// Resume from the per-resource records of earlier attempts, ignoring any
+	// dry-run plan published before deletion.
+	if ko.Status.Drain != nil && !ko.Status.Drain.DryRun {
+		drainer.Previous = ko.Status.Drain.Resources
+	}
 
 	result, err := drainer.DrainAll(ctx)
 	if err != nil {
@@ -176,6 +182,34 @@ func (o *Orchestrator) HandleDrain(ctx context.Context, ko *ngrokv1alpha1.Kubern
 	return OutcomeComplete, nil
 }
 
+// PlanDrain publishes the drain plan on the KubernetesOperator status when
+// spec.drain.dryRun is set, listing each resource a drain would process under
+// the current policy without changing anything. A previously published plan is
+// cleared once dry-run is turned off. The caller is responsible for persisting
+// the status.
+func (o *Orchestrator) PlanDrain(ctx context.Context, ko *ngrokv1alpha1.KubernetesOperator) error {
+	if !ko.IsDrainDryRun() {
+		if ko.Status.Drain != nil && ko.Status.Drain.DryRun {
+			ko.Status.Drain = nil
+		}
+		return nil
+	}
+
+	drainer := &Drainer{
+		Client: o.client,
+		Log:    o.log.WithValues("namespace", ko.Namespace, "name", ko.Name),
+		Policy: ko.GetDrainPolicy(),
+		DryRun: true,
+	}
+	result, err := drainer.DrainAll(ctx)
+	if err != nil {
+		return err
+	}
+	o.setDrainProgress(ko, result)
+	ko.Status.Drain.DryRun = true
+	return nil
+}
+
 // setDrainProgress records structured drain progress on the KubernetesOperator status
 func (o *Orchestrator) setDrainProgress(ko *ngrokv1alpha1.KubernetesOperator, result *DrainResult) {
 	if result == nil {
@@ -186,6 +220,7 @@ func (o *Orchestrator) setDrainProgress(ko *ngrokv1alpha1.KubernetesOperator, re
 		FailedResources:  result.Failed,
 		TotalResources:   result.Total,
 		Errors:           boundedDrainErrors(result.ErrorStrings()),
+		Resources:        boundedDrainResources(result.Resources),
 	}
 }
 
@@ -208,3 +243,30 @@ func boundedDrainErrors(errors []string) []string {
 	}
 	return bounded
 }
+
+// boundedDrainResources limits the per-resource records to what fits in the
+// status. Failed and planned resources are kept in preference to drained ones,
+// since drained resources no longer hold a finalizer and will not be processed
+// again by a retry.
+func boundedDrainResources(resources []ngrokv1alpha1.KubernetesOperatorDrainResource) []ngrokv1alpha1.KubernetesOperatorDrainResource {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	drop := max(len(resources)-maxDrainResources, 0)
+	bounded := make([]ngrokv1alpha1.KubernetesOperatorDrainResource, 0, len(resources)-drop)
+	for _, r := range resources {
+		if drop > 0 && r.State == ngrokv1alpha1.DrainResourceStateDrained {
+			drop--
+			continue
+		}
+		if utf8.RuneCountInString(r.Message) > maxDrainErrorLength {
+			r.Message = string([]rune(r.Message)[:maxDrainErrorLength])
+		}
+		bounded = append(bounded, r)
+	}
+	if len(bounded) > maxDrainResources {
+		bounded = bounded[:maxDrainResources]
+	}
+	return bounded
+}
@@ -35,6 +35,7 @@ import (
 	bindingsv1alpha1 "github.com/ngrok/ngrok-operator/api/bindings/v1alpha1"
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
 	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -335,6 +336,82 @@ func TestBoundedDrainErrors(t *testing.T) {
 	}
 }
 
+func TestBoundedDrainResources(t *testing.T) {
+	assert.Nil(t, boundedDrainResources(nil))
+
+	resources := make([]ngrokv1alpha1.KubernetesOperatorDrainResource, maxDrainResources+5)
+	for i := range resources {
+		resources[i] = ngrokv1alpha1.KubernetesOperatorDrainResource{
+			Kind:  "Domain",
+			Name:  fmt.Sprintf("%03d", i),
+			State: ngrokv1alpha1.DrainResourceStateDrained,
+		}
+	}
+	resources[0].State = ngrokv1alpha1.DrainResourceStateFailed
+	resources[0].Message = strings.Repeat("x", maxDrainErrorLength+5)
+
+	bounded := boundedDrainResources(resources)
+	require.Len(t, bounded, maxDrainResources)
+	assert.Equal(t, ngrokv1alpha1.DrainResourceStateFailed, bounded[0].State, "failed resources should be retained")
+	assert.Len(t, []rune(bounded[0].Message), maxDrainErrorLength)
+	assert.Equal(t, "006", bounded[1].Name, "the earliest drained resources should be dropped")
+}
+
+func TestOrchestrator_PlanDrain(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	ko := &ngrokv1alpha1.KubernetesOperator{
+		Name:      "my-release",
+		Namespace: "ngrok-operator",
+		Spec: ngrokv1alpha1.KubernetesOperatorSpec{
+			Drain: &ngrokv1alpha1.DrainConfig{
+				Policy: ngrokv1alpha1.DrainPolicyRetain,
+				DryRun: true,
+			},
+		},
+	}
+	cloudEndpoint := &ngrokv1alpha1.CloudEndpoint{
+		Name:       "app",
+		Namespace:  "default",
+		Finalizers: []string{util.FinalizerName},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ko, cloudEndpoint).
+		Build()
+
+	orchestrator := NewOrchestrator(OrchestratorConfig{
+		Client:         client,
+		Recorder:       events.NewFakeRecorder(10),
+		Log:            logr.Discard(),
+		K8sOpNamespace: "ngrok-operator",
+		K8sOpName:      "my-release",
+	})
+
+	ctx := context.Background()
+	require.NoError(t, orchestrator.PlanDrain(ctx, ko))
+	require.NotNil(t, ko.Status.Drain)
+	assert.True(t, ko.Status.Drain.DryRun)
+	assert.Equal(t, 1, ko.Status.Drain.TotalResources)
+	assert.Zero(t, ko.Status.Drain.DrainedResources)
+	assert.Equal(t, []ngrokv1alpha1.KubernetesOperatorDrainResource{{
+		Kind:      "CloudEndpoint",
+		Namespace: "default",
+		Name:      "app",
+		Action:    ngrokv1alpha1.DrainActionRetain,
+		State:     ngrokv1alpha1.DrainResourceStatePlanned,
+	}}, ko.Status.Drain.Resources)
+
+	// Planning must not start a drain
+	assert.False(t, orchestrator.State().IsDraining(ctx))
+
+	// Turning dry-run off clears the plan
+	ko.Spec.Drain.DryRun = false
+	require.NoError(t, orchestrator.PlanDrain(ctx, ko))
+	assert.Nil(t, ko.Status.Drain)
+}
+
 type listErrorClient struct {
 	client.Client
 }
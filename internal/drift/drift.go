@@ -0,0 +1,211 @@
+// Package drift periodically compares the resources the operator manages in the ngrok API against the CRs they
+// were created from. Resources that were changed outside of the operator, e.g. in the ngrok dashboard, are
+// reported with a Drifted condition and metrics, and are optionally reverted by reconciling the CR again.
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/ngrok/ngrok-operator/internal/controller/conditions"
+)
+
+const (
+	// ConditionDrifted is True when the ngrok API resource no longer matches the spec
+	ConditionDrifted = "Drifted"
+
+	ReasonDriftDetected   = "DriftDetected"
+	ReasonDriftCorrecting = "DriftCorrecting"
+	ReasonNoDrift         = "NoDrift"
+
+	// FieldDeleted is returned by a CheckFunc when the ngrok API resource no longer exists
+	FieldDeleted = "(deleted)"
+)
+
+// Policy is what the scanner does when it finds a resource that has drifted
+type Policy string
+
+const (
+	// PolicyReport only reports drift. The resource is brought back in line with the spec the next time the
+	// spec changes.
+	PolicyReport Policy = "Report"
+	// PolicyCorrect reports drift and reconciles the CR, which reverts the resource to match the spec
+	PolicyCorrect Policy = "Correct"
+)
+
+// ValidatePolicy returns the Policy for policy, or an error if it is not a valid policy
+func ValidatePolicy(policy string) (Policy, error) {
+	switch Policy(policy) {
+	case PolicyReport, PolicyCorrect:
+		return Policy(policy), nil
+	default:
+		return "", fmt.Errorf("invalid drift policy: %s. Allowed Values are: %v", policy, []Policy{PolicyReport, PolicyCorrect})
+	}
+}
+
+// ErrSkip is returned by a CheckFunc when the object can't be compared with the ngrok API yet, e.g. because it
+// has no ID or has spec changes that haven't been reconciled
+var ErrSkip = errors.New("not comparable")
+
+// Object is a CR whose drift is reported in its status conditions
+type Object interface {
+	client.Object
+	GetConditions() *[]metav1.Condition
+}
+
+// CheckFunc compares obj against its ngrok API resource and returns the names of the fields that differ from the
+// spec, or FieldDeleted if the resource no longer exists
+type CheckFunc[T Object] func(ctx context.Context, obj T) ([]string, error)
+
+var (
+	driftDetectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ngrok_operator_drift_detected_total",
+		Help: "Number of times a managed ngrok API resource was found to differ from its CR",
+	}, []string{"kind"})
+	driftedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ngrok_operator_drifted_resources",
+		Help: "Number of managed ngrok API resources that differed from their CR in the last scan",
+	}, []string{"kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftDetectedTotal, driftedResources)
+}
+
+// Scanner checks every CR of the registered kinds for drift. Its Scan runs periodically on the leader as a
+// periodic.LeaderTask.
+type Scanner struct {
+	Client   client.Client
+	Log      logr.Logger
+	Recorder events.EventRecorder
+	Policy   Policy
+
+	kinds []*kind
+}
+
+type kind struct {
+	name    string
+	newList func() client.ObjectList
+	check   func(ctx context.Context, obj client.Object) ([]string, error)
+	events  chan event.GenericEvent
+}
+
+// Register adds a kind to scan. newList returns an empty list of the kind. The returned source enqueues CRs that
+// have drifted when the policy is PolicyCorrect, and must be watched by the kind's controller.
+func Register[T Object](s *Scanner, name string, newList func() client.ObjectList, check CheckFunc[T]) source.Source {
+	k := &kind{
+		name:    name,
+		newList: newList,
+		check: func(ctx context.Context, obj client.Object) ([]string, error) {
+			return check(ctx, obj.(T))
+		},
+		events: make(chan event.GenericEvent),
+	}
+	s.kinds = append(s.kinds, k)
+	return source.Channel(k.events, &handler.EnqueueRequestForObject{})
+}
+
+// Scan checks every CR of the registered kinds once
+func (s *Scanner) Scan(ctx context.Context) {
+	for _, k := range s.kinds {
+		drifted, err := s.scanKind(ctx, k)
+		if err != nil {
+			s.Log.Error(err, "failed to scan for drift", "kind", k.name)
+			continue
+		}
+		driftedResources.WithLabelValues(k.name).Set(float64(drifted))
+	}
+}
+
+func (s *Scanner) scanKind(ctx context.Context, k *kind) (int, error) {
+	list := k.newList()
+	if err := s.Client.List(ctx, list); err != nil {
+		return 0, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return 0, err
+	}
+
+	drifted := 0
+	for _, item := range items {
+		obj, ok := item.(Object)
+		if !ok || !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		log := s.Log.WithValues("kind", k.name, "namespace", obj.GetNamespace(), "name", obj.GetName())
+
+		fields, err := k.check(ctx, obj)
+		if errors.Is(err, ErrSkip) {
+			continue
+		}
+		if err != nil {
+			log.V(1).Info("unable to check for drift", "error", err.Error())
+			continue
+		}
+
+		if len(fields) > 0 {
+			drifted++
+			driftDetectedTotal.WithLabelValues(k.name).Inc()
+			log.Info("drift detected", "fields", fields, "policy", s.Policy)
+		}
+		if err := s.report(ctx, obj, fields); err != nil {
+			log.Error(err, "failed to report drift")
+		}
+
+		if len(fields) > 0 && s.Policy == PolicyCorrect {
+			select {
+			case k.events <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return drifted, ctx.Err()
+			}
+		}
+	}
+	return drifted, nil
+}
+
+// report records the drifted fields in the Drifted condition. A warning event is emitted when drift is first found.
+func (s *Scanner) report(ctx context.Context, obj Object, fields []string) error {
+	previous := meta.FindStatusCondition(*obj.GetConditions(), ConditionDrifted)
+	wasDrifted := previous != nil && previous.Status == metav1.ConditionTrue
+
+	var reason, message string
+	switch {
+	case len(fields) == 0:
+		reason, message = ReasonNoDrift, "Matches the ngrok API"
+	case s.Policy == PolicyCorrect:
+		reason, message = ReasonDriftCorrecting, driftMessage(fields)+"; reverting to the spec"
+	default:
+		reason, message = ReasonDriftDetected, driftMessage(fields)
+	}
+	if previous != nil && previous.Reason == reason && previous.Message == message {
+		return nil
+	}
+
+	conditions.Set(obj.GetConditions(), obj.GetGeneration(), ConditionDrifted, len(fields) > 0, reason, message)
+	if len(fields) > 0 && !wasDrifted && s.Recorder != nil {
+		s.Recorder.Eventf(obj, nil, v1.EventTypeWarning, "Drifted", "DriftScan", message)
+	}
+	return s.Client.Status().Update(ctx, obj)
+}
+
+func driftMessage(fields []string) string {
+	if len(fields) == 1 && fields[0] == FieldDeleted {
+		return "Deleted in the ngrok API"
+	}
+	return "Changed in the ngrok API: " + strings.Join(fields, ", ")
+}
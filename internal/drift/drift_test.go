@@ -0,0 +1,135 @@
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+)
+
+func newTestScanner(t *testing.T, policy Policy, objs ...client.Object) (*Scanner, *events.FakeRecorder) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+
+	recorder := events.NewFakeRecorder(10)
+	return &Scanner{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&ingressv1alpha1.IPPolicy{}).
+			Build(),
+		Log:      logr.Discard(),
+		Recorder: recorder,
+		Policy:   policy,
+	}, recorder
+}
+
+func testPolicy(name, id string) *ingressv1alpha1.IPPolicy {
+	return &ingressv1alpha1.IPPolicy{
+		Name:      name,
+		Namespace: "default",
+		Status:    ingressv1alpha1.IPPolicyStatus{ID: id},
+	}
+}
+
+// checkByID reports the fields configured for each ID. Objects without an ID are skipped.
+func checkByID(fields map[string][]string) CheckFunc[*ingressv1alpha1.IPPolicy] {
+	return func(_ context.Context, policy *ingressv1alpha1.IPPolicy) ([]string, error) {
+		if policy.Status.ID == "" {
+			return nil, ErrSkip
+		}
+		return fields[policy.Status.ID], nil
+	}
+}
+
+func getTestPolicy(t *testing.T, s *Scanner, name string) *ingressv1alpha1.IPPolicy {
+	t.Helper()
+	policy := &ingressv1alpha1.IPPolicy{}
+	require.NoError(t, s.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, policy))
+	return policy
+}
+
+func TestValidatePolicy(t *testing.T) {
+	policy, err := ValidatePolicy("Correct")
+	require.NoError(t, err)
+	assert.Equal(t, PolicyCorrect, policy)
+
+	_, err = ValidatePolicy("Revert")
+	assert.ErrorContains(t, err, "invalid drift policy")
+}
+
+func TestScan_Report(t *testing.T) {
+	s, recorder := newTestScanner(t, PolicyReport,
+		testPolicy("drifted", "ipp_1"),
+		testPolicy("clean", "ipp_2"),
+		testPolicy("deleted", "ipp_3"),
+		testPolicy("pending", ""),
+	)
+	Register(s, "IPPolicy", func() client.ObjectList { return &ingressv1alpha1.IPPolicyList{} }, checkByID(map[string][]string{
+		"ipp_1": {"description", "rules"},
+		"ipp_3": {FieldDeleted},
+	}))
+
+	before := testutil.ToFloat64(driftDetectedTotal.WithLabelValues("IPPolicy"))
+	s.Scan(context.Background())
+
+	drifted := meta.FindStatusCondition(getTestPolicy(t, s, "drifted").Status.Conditions, ConditionDrifted)
+	require.NotNil(t, drifted)
+	assert.Equal(t, metav1.ConditionTrue, drifted.Status)
+	assert.Equal(t, ReasonDriftDetected, drifted.Reason)
+	assert.Equal(t, "Changed in the ngrok API: description, rules", drifted.Message)
+
+	clean := meta.FindStatusCondition(getTestPolicy(t, s, "clean").Status.Conditions, ConditionDrifted)
+	require.NotNil(t, clean)
+	assert.Equal(t, ReasonNoDrift, clean.Reason)
+
+	deleted := meta.FindStatusCondition(getTestPolicy(t, s, "deleted").Status.Conditions, ConditionDrifted)
+	require.NotNil(t, deleted)
+	assert.Equal(t, "Deleted in the ngrok API", deleted.Message)
+
+	assert.Empty(t, getTestPolicy(t, s, "pending").Status.Conditions)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(driftedResources.WithLabelValues("IPPolicy")))
+	assert.Equal(t, before+2, testutil.ToFloat64(driftDetectedTotal.WithLabelValues("IPPolicy")))
+	assert.Len(t, recorder.Events, 2)
+
+	// Drift that was already reported doesn't emit another event
+	s.Scan(context.Background())
+	assert.Len(t, recorder.Events, 2)
+}
+
+func TestScan_Correct(t *testing.T) {
+	s, _ := newTestScanner(t, PolicyCorrect, testPolicy("drifted", "ipp_1"), testPolicy("clean", "ipp_2"))
+	Register(s, "IPPolicy", func() client.ObjectList { return &ingressv1alpha1.IPPolicyList{} }, checkByID(map[string][]string{
+		"ipp_1": {"metadata"},
+	}))
+
+	enqueued := []string{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range s.kinds[0].events {
+			enqueued = append(enqueued, e.Object.GetName())
+		}
+	}()
+	s.Scan(context.Background())
+	close(s.kinds[0].events)
+	<-done
+
+	assert.Equal(t, []string{"drifted"}, enqueued)
+	drifted := meta.FindStatusCondition(getTestPolicy(t, s, "drifted").Status.Conditions, ConditionDrifted)
+	require.NotNil(t, drifted)
+	assert.Equal(t, ReasonDriftCorrecting, drifted.Reason)
+}
@@ -0,0 +1,125 @@
+// Package endpointstatus periodically projects what the ngrok API reports about the endpoints serving the URLs of
+// CloudEndpoints and AgentEndpoints into their status, such as how many agents have started a pooled AgentEndpoint
+// and which regions serve it.
+package endpointstatus
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+)
+
+// endpointTypeCloud is the type of cloud endpoints in the ngrok API. Every other type is started by an agent.
+const endpointTypeCloud = "cloud"
+
+// Poller updates status.api of every CloudEndpoint and AgentEndpoint. Its Poll runs periodically on the leader as a
+// periodic.LeaderTask, so that replicas don't race to update the status.
+type Poller struct {
+	Client    client.Client
+	Endpoints ngrokapi.Lister[*ngrok.Endpoint]
+	Log       logr.Logger
+}
+
+// Poll lists the endpoints in the ngrok API once and updates the status of every CloudEndpoint and AgentEndpoint
+// whose endpoints changed
+func (p *Poller) Poll(ctx context.Context) {
+	cloud, agent, err := p.listEndpoints(ctx)
+	if err != nil {
+		p.Log.Error(err, "failed to list endpoints in the ngrok API")
+		return
+	}
+
+	cleps := &ngrokv1alpha1.CloudEndpointList{}
+	if err := p.Client.List(ctx, cleps); err != nil {
+		p.Log.Error(err, "failed to list CloudEndpoints")
+	} else {
+		for i := range cleps.Items {
+			clep := &cleps.Items[i]
+			p.update(ctx, clep, &clep.Status.API, Summarize(clep.Status.AssignedURL, cloud))
+		}
+	}
+
+	aeps := &ngrokv1alpha1.AgentEndpointList{}
+	if err := p.Client.List(ctx, aeps); err != nil {
+		p.Log.Error(err, "failed to list AgentEndpoints")
+	} else {
+		for i := range aeps.Items {
+			aep := &aeps.Items[i]
+			p.update(ctx, aep, &aep.Status.API, Summarize(aep.Status.AssignedURL, agent))
+		}
+	}
+}
+
+// listEndpoints returns the cloud and agent endpoints in the ngrok API by URL
+func (p *Poller) listEndpoints(ctx context.Context) (cloud, agent map[string][]*ngrok.Endpoint, err error) {
+	cloud = map[string][]*ngrok.Endpoint{}
+	agent = map[string][]*ngrok.Endpoint{}
+	iter := p.Endpoints.List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		endpoint := iter.Item()
+		if endpoint.Type == endpointTypeCloud {
+			cloud[endpoint.URL] = append(cloud[endpoint.URL], endpoint)
+		} else {
+			agent[endpoint.URL] = append(agent[endpoint.URL], endpoint)
+		}
+	}
+	return cloud, agent, iter.Err()
+}
+
+// update sets the status of obj to want if it differs from current
+func (p *Poller) update(ctx context.Context, obj client.Object, current **ngrokv1alpha1.EndpointAPIStatus, want *ngrokv1alpha1.EndpointAPIStatus) {
+	if !obj.GetDeletionTimestamp().IsZero() || apiequality.Semantic.DeepEqual(*current, want) {
+		return
+	}
+	*current = want
+	if err := p.Client.Status().Update(ctx, obj); err != nil {
+		// The status is refreshed on the next poll
+		p.Log.V(1).Info("unable to update endpoint status", "namespace", obj.GetNamespace(), "name", obj.GetName(), "error", err.Error())
+	}
+}
+
+// Summarize returns the status of the endpoints serving url in endpointsByURL, or nil when url is empty because the
+// endpoint has not been created
+func Summarize(url string, endpointsByURL map[string][]*ngrok.Endpoint) *ngrokv1alpha1.EndpointAPIStatus {
+	if url == "" {
+		return nil
+	}
+
+	endpoints := endpointsByURL[url]
+	status := &ngrokv1alpha1.EndpointAPIStatus{PoolSize: int32(len(endpoints))}
+	for _, endpoint := range endpoints {
+		if endpoint.Region != "" && !slices.Contains(status.Regions, endpoint.Region) {
+			status.Regions = append(status.Regions, endpoint.Region)
+		}
+		// The endpoints normally share a principal, pick one the same way on every poll if they don't
+		if endpoint.Principal != nil && (status.Principal == "" || endpoint.Principal.ID < status.Principal) {
+			status.Principal = endpoint.Principal.ID
+		}
+		if createdAt, ok := parseTime(endpoint.CreatedAt); ok && (status.CreatedAt == nil || createdAt.Before(status.CreatedAt)) {
+			status.CreatedAt = createdAt
+		}
+		if updatedAt, ok := parseTime(endpoint.UpdatedAt); ok && (status.UpdatedAt == nil || status.UpdatedAt.Before(updatedAt)) {
+			status.UpdatedAt = updatedAt
+		}
+	}
+	slices.Sort(status.Regions)
+	return status
+}
+
+// parseTime parses an RFC 3339 timestamp of the ngrok API to the precision it is stored with in status
+func parseTime(value string) (*metav1.Time, bool) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false
+	}
+	return new(metav1.NewTime(t).Rfc3339Copy()), true
+}
@@ -0,0 +1,93 @@
+package endpointstatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+type endpointLister []*ngrok.Endpoint
+
+func (l endpointLister) List(_ *ngrok.Paging) ngrok.Iter[*ngrok.Endpoint] {
+	return nmockapi.NewIter([]*ngrok.Endpoint(l), nil)
+}
+
+func at(hour int) *metav1.Time {
+	return new(metav1.NewTime(time.Date(2026, time.March, 4, hour, 0, 0, 0, time.UTC)))
+}
+
+func TestSummarize(t *testing.T) {
+	byURL := map[string][]*ngrok.Endpoint{
+		"https://app.example.com": {
+			{Region: "us", CreatedAt: "2026-03-04T10:00:00Z", UpdatedAt: "2026-03-04T11:00:00Z", Principal: &ngrok.Ref{ID: "bot_2"}},
+			{Region: "eu", CreatedAt: "2026-03-04T09:00:00Z", UpdatedAt: "2026-03-04T12:00:00Z", Principal: &ngrok.Ref{ID: "bot_1"}},
+			{Region: "us", CreatedAt: "not a time"},
+		},
+	}
+
+	assert.Nil(t, Summarize("", byURL))
+	assert.Equal(t, &ngrokv1alpha1.EndpointAPIStatus{PoolSize: 0}, Summarize("https://missing.example.com", byURL))
+	assert.Equal(t, &ngrokv1alpha1.EndpointAPIStatus{
+		PoolSize:  3,
+		Regions:   []string{"eu", "us"},
+		Principal: "bot_1",
+		CreatedAt: at(9),
+		UpdatedAt: at(12),
+	}, Summarize("https://app.example.com", byURL))
+}
+
+func TestPoll(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+
+	clep := &ngrokv1alpha1.CloudEndpoint{
+		Name:      "clep",
+		Namespace: "default",
+		Status:    ngrokv1alpha1.CloudEndpointStatus{ID: "ep_1", AssignedURL: "https://app.example.com"},
+	}
+	aep := &ngrokv1alpha1.AgentEndpoint{
+		Name:      "aep",
+		Namespace: "default",
+		Status:    ngrokv1alpha1.AgentEndpointStatus{AssignedURL: "https://svc.internal"},
+	}
+	pending := &ngrokv1alpha1.AgentEndpoint{Name: "pending", Namespace: "default"}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(clep, aep, pending).
+		WithStatusSubresource(clep, aep, pending).
+		Build()
+
+	p := &Poller{
+		Client: c,
+		Endpoints: endpointLister{
+			{ID: "ep_1", Type: "cloud", URL: "https://app.example.com", Region: "global"},
+			// Agent endpoints on the same URL as a cloud endpoint are not part of its pool
+			{ID: "ep_2", Type: "agent", URL: "https://app.example.com", Region: "us"},
+			{ID: "ep_3", Type: "agent", URL: "https://svc.internal", Region: "us"},
+			{ID: "ep_4", Type: "agent", URL: "https://svc.internal", Region: "eu"},
+		},
+		Log: logr.Discard(),
+	}
+	p.Poll(t.Context())
+
+	got := &ngrokv1alpha1.CloudEndpoint{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(clep), got))
+	assert.Equal(t, &ngrokv1alpha1.EndpointAPIStatus{PoolSize: 1, Regions: []string{"global"}}, got.Status.API)
+
+	gotAep := &ngrokv1alpha1.AgentEndpoint{}
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(aep), gotAep))
+	assert.Equal(t, &ngrokv1alpha1.EndpointAPIStatus{PoolSize: 2, Regions: []string{"eu", "us"}}, gotAep.Status.API)
+
+	require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(pending), gotAep))
+	assert.Nil(t, gotAep.Status.API)
+}
@@ -179,3 +179,23 @@ func NewErrModulesetNotConvertibleToTrafficPolicy(message string) error {
 func (e ErrModulesetNotConvertibleToTrafficPolicy) Error() string {
 	return fmt.Sprintf("moduleset not convertible to traffic policy: %s", e.message)
 }
+
+// ErrTCPAddressNotReady is meant to be used when a resource listens on a TCPAddress
+// that has not been reserved yet, so the caller can wait for it rather than retry.
+type ErrTCPAddressNotReady struct {
+	message string
+}
+
+func NewErrTCPAddressNotReady(message string) ErrTCPAddressNotReady {
+	return ErrTCPAddressNotReady{message: message}
+}
+
+func (e ErrTCPAddressNotReady) Error() string {
+	return fmt.Sprintf("TCP address not ready: %s", e.message)
+}
+
+// IsErrTCPAddressNotReady: Reflect: returns true if the error is a ErrTCPAddressNotReady
+func IsErrTCPAddressNotReady(err error) bool {
+	_, ok := err.(ErrTCPAddressNotReady)
+	return ok
+}
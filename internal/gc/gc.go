@@ -0,0 +1,366 @@
+// Package gc finds resources in the ngrok API that were created by this installation of the operator but no longer
+// have a CR, e.g. because the CR's finalizer was removed by hand or the operator was uninstalled with the Retain
+// drain policy, and deletes them once they have been orphaned for a grace period.
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/drain"
+	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
+)
+
+// Kinds of CRs whose ngrok API resources are collected
+const (
+	KindCloudEndpoint = "CloudEndpoint"
+	KindDomain        = "Domain"
+	KindIPPolicy      = "IPPolicy"
+	KindTCPAddress    = "TCPAddress"
+)
+
+// AllKinds is every kind the collector supports, in the order they are collected. Endpoints go first so that the
+// domains they use can be deleted in the same pass.
+var AllKinds = []string{KindCloudEndpoint, KindDomain, KindIPPolicy, KindTCPAddress}
+
+// DefaultKinds are the kinds collected unless others are configured. Domains and TCPAddresses with the Retain reclaim
+// policy are deliberately left in the ngrok API when their CR is deleted, and can't be told apart from orphans once
+// the CR is gone, so they are only collected on request.
+var DefaultKinds = []string{KindCloudEndpoint, KindIPPolicy}
+
+// ValidateKinds returns an error if any of the kinds is not supported by the collector
+func ValidateKinds(kinds []string) error {
+	for _, kind := range kinds {
+		if !slices.Contains(AllKinds, kind) {
+			return fmt.Errorf("unsupported kind %q, must be one of %v", kind, AllKinds)
+		}
+	}
+	return nil
+}
+
+var (
+	orphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ngrok_operator_orphaned_resources",
+		Help: "Number of ngrok API resources owned by this installation that had no CR in the last collection",
+	}, []string{"kind"})
+	orphansDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ngrok_operator_orphaned_resources_deleted_total",
+		Help: "Number of orphaned ngrok API resources deleted by the garbage collector",
+	}, []string{"kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedResources, orphansDeletedTotal)
+}
+
+// Collector deletes the orphaned ngrok API resources of this installation. Its Collect runs periodically on the
+// leader as a periodic.LeaderTask.
+type Collector struct {
+	Client    client.Reader
+	Clientset ngrokapi.Clientset
+	Log       logr.Logger
+	// DrainState pauses collection while the operator is draining
+	DrainState drain.State
+
+	// GracePeriod is how long a resource must be orphaned before it is deleted. It covers the time between a
+	// controller creating a resource and recording its ID in the CR's status.
+	GracePeriod time.Duration
+	// DryRun reports orphaned resources without deleting them
+	DryRun bool
+	// Kinds limits collection to these kinds. The DefaultKinds are collected when empty.
+	Kinds []string
+
+	// OperatorID returns the ngrok API ID of this installation's KubernetesOperator, which is added to the metadata
+	// of the resources it creates. It is empty until the KubernetesOperator is registered.
+	OperatorID func() string
+	// Owners are the values of the owned-by metadata key set by this installation
+	Owners []string
+	// Metadata is the custom metadata of the ngrokMetadata flag. Resources created before the operator ID was added
+	// to their metadata are only collected if their metadata contains all of it, or all of a ConfigMetadata.
+	Metadata map[string]string
+	// ConfigMetadata returns the custom metadata in effect, which the KubernetesOperator's spec.config can change
+	// while the operator runs
+	ConfigMetadata func() map[string]string
+
+	// configMetadata is every custom metadata returned by ConfigMetadata since the collector started, so that
+	// resources created with a previous one are still collected
+	configMetadata []map[string]string
+
+	// orphanedSince records when each orphaned resource was first found, by kind and ID
+	orphanedSince map[string]map[string]time.Time
+	now           func() time.Time
+}
+
+// resource is an ngrok API resource owned by this installation
+type resource struct {
+	id          string
+	description string
+}
+
+// collector lists the IDs referenced by the CRs of a kind and the ngrok API resources of the kind owned by this
+// installation
+type collector struct {
+	referencedIDs func(ctx context.Context) (map[string]bool, error)
+	owned         func(ctx context.Context) ([]resource, error)
+	delete        func(ctx context.Context, id string) error
+}
+
+// Collect finds the orphaned resources of each kind once and deletes those that are past the grace period
+func (c *Collector) Collect(ctx context.Context) {
+	if drain.IsDraining(ctx, c.DrainState) {
+		c.Log.V(1).Info("skipping garbage collection while draining")
+		return
+	}
+	if c.orphanedSince == nil {
+		c.orphanedSince = map[string]map[string]time.Time{}
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	if c.ConfigMetadata != nil {
+		if m := c.ConfigMetadata(); len(m) > 0 && !slices.ContainsFunc(c.configMetadata, func(known map[string]string) bool {
+			return maps.Equal(known, m)
+		}) {
+			c.configMetadata = append(c.configMetadata, maps.Clone(m))
+		}
+	}
+
+	collectors := map[string]collector{
+		KindCloudEndpoint: c.cloudEndpoints(),
+		KindDomain:        c.domains(),
+		KindIPPolicy:      c.ipPolicies(),
+		KindTCPAddress:    c.tcpAddresses(),
+	}
+
+	kinds := c.Kinds
+	if len(kinds) == 0 {
+		kinds = DefaultKinds
+	}
+	for _, kind := range AllKinds {
+		if !slices.Contains(kinds, kind) {
+			continue
+		}
+		count, err := c.collectKind(ctx, kind, collectors[kind])
+		if err != nil {
+			// The orphans that were already found are kept, so their grace period isn't reset
+			c.Log.Error(err, "failed to collect orphaned resources", "kind", kind)
+			continue
+		}
+		orphanedResources.WithLabelValues(kind).Set(float64(count))
+	}
+}
+
+// collectKind handles the orphans of one kind and returns the number of orphans found
+func (c *Collector) collectKind(ctx context.Context, kind string, k collector) (int, error) {
+	// The CRs are listed first, so a resource created after the list is at worst treated as orphaned for its
+	// grace period rather than deleted
+	referenced, err := k.referencedIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing %s CRs: %w", kind, err)
+	}
+	owned, err := k.owned(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing %s resources: %w", kind, err)
+	}
+
+	now := c.now()
+	count := 0
+	orphaned := map[string]time.Time{}
+	for _, o := range owned {
+		if referenced[o.id] {
+			continue
+		}
+		count++
+		log := c.Log.WithValues("kind", kind, "id", o.id, "description", o.description)
+
+		since, seen := c.orphanedSince[kind][o.id]
+		if !seen {
+			since = now
+			log.Info("found orphaned ngrok API resource", "dryRun", c.DryRun, "gracePeriod", c.GracePeriod)
+		}
+		if c.DryRun || now.Sub(since) < c.GracePeriod {
+			orphaned[o.id] = since
+			continue
+		}
+
+		if err := k.delete(ctx, o.id); err != nil && !ngrok.IsNotFound(err) {
+			log.Error(err, "failed to delete orphaned ngrok API resource")
+			orphaned[o.id] = since
+			continue
+		}
+		orphansDeletedTotal.WithLabelValues(kind).Inc()
+		log.Info("deleted orphaned ngrok API resource", "orphanedFor", now.Sub(since).Round(time.Second))
+	}
+	c.orphanedSince[kind] = orphaned
+	return count, nil
+}
+
+// owns reports whether a resource with the given API metadata was created by this installation, i.e. it has the ID
+// of this installation's KubernetesOperator. Resources created before the ID was added to their metadata are
+// identified by the installation's owners and custom metadata instead.
+func (c *Collector) owns(metadata string) bool {
+	m := map[string]string{}
+	if err := json.Unmarshal([]byte(metadata), &m); err != nil {
+		return false
+	}
+	if id, ok := m[operatorconfig.OperatorIDMetadataKey]; ok {
+		return c.OperatorID != nil && id != "" && id == c.OperatorID()
+	}
+
+	if c.ownsByMetadata(m, c.Metadata) {
+		return true
+	}
+	return slices.ContainsFunc(c.configMetadata, func(custom map[string]string) bool {
+		return c.ownsByMetadata(m, custom)
+	})
+}
+
+// ownsByMetadata reports whether the metadata of a resource contains all of the custom metadata and one of the
+// owners of this installation
+func (c *Collector) ownsByMetadata(m, custom map[string]string) bool {
+	// Without custom metadata, the resources of every installation using the same ngrok account look alike
+	if len(custom) == 0 {
+		return false
+	}
+	for k, v := range custom {
+		if m[k] != v {
+			return false
+		}
+	}
+	// Custom metadata may override the owner
+	if _, ok := custom["owned-by"]; ok {
+		return true
+	}
+	return slices.Contains(c.Owners, m["owned-by"])
+}
+
+// referencedIDs returns a function that lists the CRs of a kind and returns the ngrok API IDs in their status and
+// ngrok.com/adopt annotation
+func (c *Collector) referencedIDs(newList func() client.ObjectList, statusID func(client.Object) string) func(context.Context) (map[string]bool, error) {
+	return func(ctx context.Context) (map[string]bool, error) {
+		list := newList()
+		if err := c.Client.List(ctx, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := map[string]bool{}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			if id := statusID(obj); id != "" {
+				ids[id] = true
+			}
+			// A CR that is adopting a resource references it before its status is written
+			if id, _ := annotations.ExtractAdoptID(obj); id != "" {
+				ids[id] = true
+			}
+		}
+		return ids, nil
+	}
+}
+
+func (c *Collector) cloudEndpoints() collector {
+	return collector{
+		referencedIDs: c.referencedIDs(
+			func() client.ObjectList { return &ngrokv1alpha1.CloudEndpointList{} },
+			func(obj client.Object) string { return obj.(*ngrokv1alpha1.CloudEndpoint).Status.ID },
+		),
+		owned: func(ctx context.Context) ([]resource, error) {
+			owned := []resource{}
+			iter := c.Clientset.Endpoints().List(&ngrok.Paging{})
+			for iter.Next(ctx) {
+				endpoint := iter.Item()
+				// Agent endpoints belong to the agent session that started them
+				if endpoint.Type == "cloud" && c.owns(endpoint.Metadata) {
+					owned = append(owned, resource{id: endpoint.ID, description: endpoint.URL})
+				}
+			}
+			return owned, iter.Err()
+		},
+		delete: c.Clientset.Endpoints().Delete,
+	}
+}
+
+func (c *Collector) domains() collector {
+	return collector{
+		referencedIDs: c.referencedIDs(
+			func() client.ObjectList { return &ingressv1alpha1.DomainList{} },
+			func(obj client.Object) string { return obj.(*ingressv1alpha1.Domain).Status.ID },
+		),
+		owned: func(ctx context.Context) ([]resource, error) {
+			owned := []resource{}
+			iter := c.Clientset.Domains().List(&ngrok.Paging{})
+			for iter.Next(ctx) {
+				domain := iter.Item()
+				if c.owns(domain.Metadata) {
+					owned = append(owned, resource{id: domain.ID, description: domain.Domain})
+				}
+			}
+			return owned, iter.Err()
+		},
+		delete: c.Clientset.Domains().Delete,
+	}
+}
+
+func (c *Collector) ipPolicies() collector {
+	return collector{
+		referencedIDs: c.referencedIDs(
+			func() client.ObjectList { return &ingressv1alpha1.IPPolicyList{} },
+			func(obj client.Object) string { return obj.(*ingressv1alpha1.IPPolicy).Status.ID },
+		),
+		owned: func(ctx context.Context) ([]resource, error) {
+			owned := []resource{}
+			iter := c.Clientset.IPPolicies().List(&ngrok.Paging{})
+			for iter.Next(ctx) {
+				policy := iter.Item()
+				if c.owns(policy.Metadata) {
+					owned = append(owned, resource{id: policy.ID, description: policy.Description})
+				}
+			}
+			return owned, iter.Err()
+		},
+		delete: c.Clientset.IPPolicies().Delete,
+	}
+}
+
+func (c *Collector) tcpAddresses() collector {
+	return collector{
+		referencedIDs: c.referencedIDs(
+			func() client.ObjectList { return &ingressv1alpha1.TCPAddressList{} },
+			func(obj client.Object) string { return obj.(*ingressv1alpha1.TCPAddress).Status.ID },
+		),
+		owned: func(ctx context.Context) ([]resource, error) {
+			owned := []resource{}
+			iter := c.Clientset.TCPAddresses().List(&ngrok.Paging{})
+			for iter.Next(ctx) {
+				addr := iter.Item()
+				if c.owns(addr.Metadata) {
+					owned = append(owned, resource{id: addr.ID, description: addr.Addr})
+				}
+			}
+			return owned, iter.Err()
+		},
+		delete: c.Clientset.TCPAddresses().Delete,
+	}
+}
@@ -0,0 +1,219 @@
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/drain"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+const installMetadata = `{"owned-by":"ngrok-operator","cluster":"prod"}`
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestCollector(t *testing.T, clientset *nmockapi.Clientset, objs ...client.Object) (*Collector, *testClock) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return &Collector{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Clientset:   clientset,
+		Log:         logr.Discard(),
+		GracePeriod: time.Hour,
+		Owners:      []string{"ngrok-operator", "kubernetes-gateway-api"},
+		Metadata:    map[string]string{"cluster": "prod"},
+		now:         clock.Now,
+	}, clock
+}
+
+func TestOwns(t *testing.T) {
+	c := &Collector{
+		Owners:     []string{"ngrok-operator"},
+		Metadata:   map[string]string{"cluster": "prod"},
+		OperatorID: func() string { return "k8sop_123" },
+	}
+	assert.True(t, c.owns(installMetadata))
+
+	// Resources with an operator ID are identified by it alone
+	assert.True(t, c.owns(`{"owned-by":"ngrok-operator","k8s-operator-id":"k8sop_123"}`))
+	assert.False(t, c.owns(`{"owned-by":"ngrok-operator","cluster":"prod","k8s-operator-id":"k8sop_456"}`))
+
+	assert.False(t, c.owns(`{"owned-by":"ngrok-operator","cluster":"staging"}`))
+	assert.False(t, c.owns(`{"owned-by":"someone-else","cluster":"prod"}`))
+	assert.False(t, c.owns(`not json`))
+	assert.False(t, c.owns(""))
+
+	c.Metadata["owned-by"] = "platform-team"
+	assert.True(t, c.owns(`{"owned-by":"platform-team","cluster":"prod"}`))
+
+	// Without custom metadata, only resources with the operator ID are owned
+	c.Metadata = nil
+	assert.False(t, c.owns(installMetadata))
+	assert.True(t, c.owns(`{"owned-by":"ngrok-operator","k8s-operator-id":"k8sop_123"}`))
+
+	// The operator ID isn't known until the KubernetesOperator is registered
+	c.OperatorID = func() string { return "" }
+	assert.False(t, c.owns(`{"owned-by":"ngrok-operator","k8s-operator-id":""}`))
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+
+	managed, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "managed.example.com", Metadata: installMetadata})
+	require.NoError(t, err)
+	orphaned, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "orphaned.example.com", Metadata: installMetadata})
+	require.NoError(t, err)
+	adopting, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "adopting.example.com", Metadata: installMetadata})
+	require.NoError(t, err)
+	other, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "other.example.com", Metadata: `{"owned-by":"ngrok-operator","cluster":"staging"}`})
+	require.NoError(t, err)
+
+	orphanedEndpoint, err := clientset.Endpoints().Create(ctx, &ngrok.EndpointCreate{Type: "cloud", URL: "https://orphaned.example.com", Metadata: new(installMetadata)})
+	require.NoError(t, err)
+	agentEndpoint, err := clientset.Endpoints().Create(ctx, &ngrok.EndpointCreate{Type: "agent", URL: "https://agent.example.com", Metadata: new(installMetadata)})
+	require.NoError(t, err)
+
+	c, clock := newTestCollector(t, clientset,
+		&ingressv1alpha1.Domain{
+			Name:      "managed-example-com",
+			Namespace: "default",
+			Status:    ingressv1alpha1.DomainStatus{ID: managed.ID},
+		},
+		&ingressv1alpha1.Domain{
+			Name:        "adopting-example-com",
+			Namespace:   "default",
+			Annotations: map[string]string{annotations.AdoptAnnotation: adopting.ID},
+		},
+	)
+	c.Kinds = AllKinds
+
+	// Orphans are only reported within the grace period
+	c.Collect(ctx)
+	assert.Equal(t, float64(1), testutil.ToFloat64(orphanedResources.WithLabelValues(KindDomain)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(orphanedResources.WithLabelValues(KindCloudEndpoint)))
+	_, err = clientset.Domains().Get(ctx, orphaned.ID)
+	require.NoError(t, err)
+
+	deletedBefore := testutil.ToFloat64(orphansDeletedTotal.WithLabelValues(KindDomain))
+	clock.now = clock.now.Add(time.Hour)
+	c.Collect(ctx)
+
+	_, err = clientset.Domains().Get(ctx, orphaned.ID)
+	assert.True(t, ngrok.IsNotFound(err))
+	_, err = clientset.Endpoints().Get(ctx, orphanedEndpoint.ID)
+	assert.True(t, ngrok.IsNotFound(err))
+	assert.Equal(t, deletedBefore+1, testutil.ToFloat64(orphansDeletedTotal.WithLabelValues(KindDomain)))
+
+	for _, id := range []string{managed.ID, adopting.ID, other.ID} {
+		_, err = clientset.Domains().Get(ctx, id)
+		assert.NoError(t, err)
+	}
+	_, err = clientset.Endpoints().Get(ctx, agentEndpoint.ID)
+	assert.NoError(t, err)
+}
+
+func TestCollect_DryRunAndDraining(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+	policy, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "orphaned", Metadata: installMetadata})
+	require.NoError(t, err)
+
+	c, clock := newTestCollector(t, clientset)
+	c.Kinds = []string{KindIPPolicy}
+	c.DryRun = true
+	c.Collect(ctx)
+	clock.now = clock.now.Add(2 * time.Hour)
+	c.Collect(ctx)
+	_, err = clientset.IPPolicies().Get(ctx, policy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(orphanedResources.WithLabelValues(KindIPPolicy)))
+
+	// Turning off dry-run deletes orphans that were found more than a grace period ago
+	c.DryRun = false
+	c.DrainState = drain.AlwaysDraining{}
+	c.Collect(ctx)
+	_, err = clientset.IPPolicies().Get(ctx, policy.ID)
+	require.NoError(t, err)
+
+	c.DrainState = drain.NeverDraining{}
+	c.Collect(ctx)
+	_, err = clientset.IPPolicies().Get(ctx, policy.ID)
+	assert.True(t, ngrok.IsNotFound(err))
+}
+
+func TestCollect_DefaultKinds(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+	domain, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "retained.example.com", Metadata: installMetadata})
+	require.NoError(t, err)
+	addr, err := clientset.TCPAddresses().Create(ctx, &ngrok.ReservedAddrCreate{Metadata: installMetadata})
+	require.NoError(t, err)
+	policy, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "orphaned", Metadata: installMetadata})
+	require.NoError(t, err)
+
+	// Domains and TCP addresses may have been retained on purpose
+	c, clock := newTestCollector(t, clientset)
+	c.Collect(ctx)
+	clock.now = clock.now.Add(2 * time.Hour)
+	c.Collect(ctx)
+
+	_, err = clientset.Domains().Get(ctx, domain.ID)
+	assert.NoError(t, err)
+	_, err = clientset.TCPAddresses().Get(ctx, addr.ID)
+	assert.NoError(t, err)
+	_, err = clientset.IPPolicies().Get(ctx, policy.ID)
+	assert.True(t, ngrok.IsNotFound(err))
+}
+
+func TestCollect_ConfigMetadata(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+	before, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "before", Metadata: `{"owned-by":"ngrok-operator","team":"a"}`})
+	require.NoError(t, err)
+	after, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "after", Metadata: `{"owned-by":"ngrok-operator","team":"b"}`})
+	require.NoError(t, err)
+	other, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "other", Metadata: `{"owned-by":"ngrok-operator","team":"c"}`})
+	require.NoError(t, err)
+
+	// The metadata on the KubernetesOperator changed from team a to team b while the operator ran
+	c, clock := newTestCollector(t, clientset)
+	configMetadata := map[string]string{"team": "a"}
+	c.ConfigMetadata = func() map[string]string { return configMetadata }
+	c.Collect(ctx)
+	configMetadata = map[string]string{"team": "b"}
+	c.Collect(ctx)
+	clock.now = clock.now.Add(2 * time.Hour)
+	c.Collect(ctx)
+
+	for _, id := range []string{before.ID, after.ID} {
+		_, err = clientset.IPPolicies().Get(ctx, id)
+		assert.True(t, ngrok.IsNotFound(err))
+	}
+	_, err = clientset.IPPolicies().Get(ctx, other.ID)
+	assert.NoError(t, err)
+}
+
+func TestValidateKinds(t *testing.T) {
+	require.NoError(t, ValidateKinds([]string{KindDomain, KindTCPAddress}))
+	assert.ErrorContains(t, ValidateKinds([]string{"AgentEndpoint"}), `unsupported kind "AgentEndpoint"`)
+}
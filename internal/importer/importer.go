@@ -0,0 +1,308 @@
+// Package importer discovers resources that already exist in the ngrok API and
+// generates the CRs that adopt them, so that domains, IP policies, cloud
+// endpoints and TCP addresses created outside of the operator can be brought
+// under management without being recreated.
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/ngrokapi"
+)
+
+// Kinds of CRs that can be imported
+const (
+	KindDomain        = "Domain"
+	KindIPPolicy      = "IPPolicy"
+	KindCloudEndpoint = "CloudEndpoint"
+	KindTCPAddress    = "TCPAddress"
+)
+
+// AllKinds is every kind the importer supports, in the order they are imported
+var AllKinds = []string{KindDomain, KindIPPolicy, KindCloudEndpoint, KindTCPAddress}
+
+// Options configures which resources are imported and how the CRs are generated
+type Options struct {
+	// Namespace is the namespace the CRs are generated in
+	Namespace string
+	// Kinds limits the import to these kinds. All kinds are imported when empty.
+	Kinds []string
+	// IncludeManaged also imports resources whose metadata marks them as owned by an ngrok-operator
+	IncludeManaged bool
+}
+
+// Importer lists resources in the ngrok API and converts them into CRs
+type Importer struct {
+	clientset ngrokapi.Clientset
+	opts      Options
+
+	names map[string]bool
+}
+
+// New returns an Importer that reads from the given clientset
+func New(clientset ngrokapi.Clientset, opts Options) *Importer {
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	return &Importer{clientset: clientset, opts: opts}
+}
+
+// ValidateKinds returns an error if any of the kinds is not supported by the importer
+func ValidateKinds(kinds []string) error {
+	for _, kind := range kinds {
+		if !slices.Contains(AllKinds, kind) {
+			return fmt.Errorf("unsupported kind %q, must be one of %v", kind, AllKinds)
+		}
+	}
+	return nil
+}
+
+// Import returns a CR for every importable resource in the ngrok API. Each CR has the ngrok.com/adopt annotation
+// and its status ID set to the ID of the resource, so the operator takes ownership of it instead of creating a new one.
+func (i *Importer) Import(ctx context.Context) ([]client.Object, error) {
+	if err := ValidateKinds(i.opts.Kinds); err != nil {
+		return nil, err
+	}
+
+	i.names = map[string]bool{}
+	importers := map[string]func(context.Context) ([]client.Object, error){
+		KindDomain:        i.importDomains,
+		KindIPPolicy:      i.importIPPolicies,
+		KindCloudEndpoint: i.importCloudEndpoints,
+		KindTCPAddress:    i.importTCPAddresses,
+	}
+
+	objs := []client.Object{}
+	for _, kind := range AllKinds {
+		if len(i.opts.Kinds) > 0 && !slices.Contains(i.opts.Kinds, kind) {
+			continue
+		}
+		kindObjs, err := importers[kind](ctx)
+		if err != nil {
+			return nil, fmt.Errorf("importing %s resources: %w", kind, err)
+		}
+		objs = append(objs, kindObjs...)
+	}
+	return objs, nil
+}
+
+func (i *Importer) importDomains(ctx context.Context) ([]client.Object, error) {
+	objs := []client.Object{}
+	iter := i.clientset.Domains().List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		domain := iter.Item()
+		if i.skip(domain.Metadata) {
+			continue
+		}
+
+		var resolvesTo []ingressv1alpha1.DomainResolvesToEntry
+		for _, entry := range domain.ResolvesTo {
+			resolvesTo = append(resolvesTo, ingressv1alpha1.DomainResolvesToEntry{Value: entry.Value})
+		}
+
+		cr := &ingressv1alpha1.Domain{
+			ObjectMeta: i.objectMeta(KindDomain, ingressv1alpha1.HyphenatedDomainNameFromURL(domain.Domain), domain.ID),
+			Spec: ingressv1alpha1.DomainSpec{
+				Description: domain.Description,
+				Metadata:    importMetadata(domain.Metadata),
+				Domain:      domain.Domain,
+				ResolvesTo:  resolvesTo,
+				// Deleting an imported CR should not release a domain that was reserved outside of the operator
+				ReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyRetain,
+			},
+			Status: ingressv1alpha1.DomainStatus{ID: domain.ID},
+		}
+		cr.SetGroupVersionKind(ingressv1alpha1.GroupVersion.WithKind(KindDomain))
+		objs = append(objs, cr)
+	}
+	return objs, iter.Err()
+}
+
+func (i *Importer) importIPPolicies(ctx context.Context) ([]client.Object, error) {
+	rules := map[string][]ingressv1alpha1.IPPolicyRule{}
+	ruleIter := i.clientset.IPPolicyRules().List(&ngrok.Paging{})
+	for ruleIter.Next(ctx) {
+		rule := ruleIter.Item()
+		rules[rule.IPPolicy.ID] = append(rules[rule.IPPolicy.ID], ingressv1alpha1.IPPolicyRule{
+			Description: rule.Description,
+			Metadata:    importMetadata(rule.Metadata),
+			CIDR:        rule.CIDR,
+			Action:      rule.Action,
+		})
+	}
+	if err := ruleIter.Err(); err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{}
+	iter := i.clientset.IPPolicies().List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		policy := iter.Item()
+		if i.skip(policy.Metadata) {
+			continue
+		}
+
+		name := policy.Description
+		if name == "" {
+			name = policy.ID
+		}
+		cr := &ingressv1alpha1.IPPolicy{
+			ObjectMeta: i.objectMeta(KindIPPolicy, name, policy.ID),
+			Spec: ingressv1alpha1.IPPolicySpec{
+				Description: policy.Description,
+				Metadata:    importMetadata(policy.Metadata),
+				Rules:       rules[policy.ID],
+			},
+			Status: ingressv1alpha1.IPPolicyStatus{ID: policy.ID},
+		}
+		cr.SetGroupVersionKind(ingressv1alpha1.GroupVersion.WithKind(KindIPPolicy))
+		objs = append(objs, cr)
+	}
+	return objs, iter.Err()
+}
+
+func (i *Importer) importCloudEndpoints(ctx context.Context) ([]client.Object, error) {
+	objs := []client.Object{}
+	iter := i.clientset.Endpoints().List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		endpoint := iter.Item()
+		// Agent endpoints live only as long as the agent session that started them
+		if endpoint.Type != "cloud" || i.skip(endpoint.Metadata) {
+			continue
+		}
+
+		var trafficPolicy *ngrokv1alpha1.CloudEndpointTrafficPolicyCfg
+		if strings.TrimSpace(endpoint.TrafficPolicy) != "" {
+			inline, err := yaml.YAMLToJSON([]byte(endpoint.TrafficPolicy))
+			if err != nil {
+				return nil, fmt.Errorf("parsing traffic policy of endpoint %s: %w", endpoint.ID, err)
+			}
+			trafficPolicy = &ngrokv1alpha1.CloudEndpointTrafficPolicyCfg{Inline: inline}
+		}
+
+		var poolingEnabled *bool
+		if endpoint.PoolingEnabled {
+			poolingEnabled = new(true)
+		}
+
+		cr := &ngrokv1alpha1.CloudEndpoint{
+			ObjectMeta: i.objectMeta(KindCloudEndpoint, endpointName(endpoint.URL), endpoint.ID),
+			Spec: ngrokv1alpha1.CloudEndpointSpec{
+				URL:            endpoint.URL,
+				PoolingEnabled: poolingEnabled,
+				TrafficPolicy:  trafficPolicy,
+				Description:    endpoint.Description,
+				Metadata:       importMetadata(endpoint.Metadata),
+				Bindings:       endpoint.Bindings,
+			},
+			Status: ngrokv1alpha1.CloudEndpointStatus{ID: endpoint.ID},
+		}
+		cr.SetGroupVersionKind(ngrokv1alpha1.GroupVersion.WithKind(KindCloudEndpoint))
+		objs = append(objs, cr)
+	}
+	return objs, iter.Err()
+}
+
+func (i *Importer) importTCPAddresses(ctx context.Context) ([]client.Object, error) {
+	objs := []client.Object{}
+	iter := i.clientset.TCPAddresses().List(&ngrok.Paging{})
+	for iter.Next(ctx) {
+		addr := iter.Item()
+		if i.skip(addr.Metadata) {
+			continue
+		}
+
+		cr := &ingressv1alpha1.TCPAddress{
+			ObjectMeta: i.objectMeta(KindTCPAddress, addr.Addr, addr.ID),
+			Spec: ingressv1alpha1.TCPAddressSpec{
+				Description: addr.Description,
+				Metadata:    importMetadata(addr.Metadata),
+				Address:     addr.Addr,
+				// Deleting an imported CR should not release an address that was reserved outside of the operator
+				ReclaimPolicy: ingressv1alpha1.TCPAddressReclaimPolicyRetain,
+			},
+			Status: ingressv1alpha1.TCPAddressStatus{ID: addr.ID},
+		}
+		cr.SetGroupVersionKind(ingressv1alpha1.GroupVersion.WithKind(KindTCPAddress))
+		objs = append(objs, cr)
+	}
+	return objs, iter.Err()
+}
+
+// skip reports whether a resource with the given API metadata is already owned by an ngrok-operator and should
+// not be imported
+func (i *Importer) skip(metadata string) bool {
+	if i.opts.IncludeManaged {
+		return false
+	}
+	m := map[string]string{}
+	if err := json.Unmarshal([]byte(metadata), &m); err != nil {
+		return false
+	}
+	return m["owned-by"] == "ngrok-operator"
+}
+
+// objectMeta returns the metadata of an imported CR. The name is derived from base and made unique among the CRs
+// of the same kind by appending the ID of the resource.
+func (i *Importer) objectMeta(kind, base, id string) metav1.ObjectMeta {
+	name := resourceName(base)
+	if name == "" || i.names[kind+"/"+name] {
+		name = resourceName(base + "-" + id)
+	}
+	i.names[kind+"/"+name] = true
+
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   i.opts.Namespace,
+		Annotations: map[string]string{annotations.AdoptAnnotation: id},
+	}
+}
+
+// importMetadata converts metadata from the ngrok API into CR metadata. A flat JSON object of strings uses the
+// object form, anything else is kept verbatim in the legacy string form.
+func importMetadata(metadata string) json.RawMessage {
+	m := map[string]string{}
+	if err := json.Unmarshal([]byte(metadata), &m); err == nil {
+		return commonv1alpha1.MetadataFromMap(m)
+	}
+	return commonv1alpha1.MetadataFromLegacyString(metadata)
+}
+
+// endpointName derives a CR name from the host and port of an endpoint URL
+func endpointName(endpointURL string) string {
+	u, err := url.Parse(endpointURL)
+	if err != nil || u.Host == "" {
+		return endpointURL
+	}
+	name := ingressv1alpha1.HyphenatedDomainNameFromURL(u.Hostname())
+	if port := u.Port(); port != "" {
+		name += "-" + port
+	}
+	return name
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// resourceName converts s into a valid Kubernetes resource name
+func resourceName(s string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-")
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/mocks/nmockapi"
+)
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+
+	domain, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{
+		Domain:     "app.example.com",
+		ResolvesTo: []ngrok.ReservedDomainResolvesToEntry{{Value: "eu"}},
+	})
+	require.NoError(t, err)
+
+	policy, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "Office IPs", Metadata: `{"team":"net"}`})
+	require.NoError(t, err)
+	_, err = clientset.IPPolicyRules().Create(ctx, &ngrok.IPPolicyRuleCreate{IPPolicyID: policy.ID, CIDR: "10.0.0.0/8", Action: new("allow")})
+	require.NoError(t, err)
+	_, err = clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: "managed", Metadata: `{"owned-by":"ngrok-operator"}`})
+	require.NoError(t, err)
+
+	endpoint, err := clientset.Endpoints().Create(ctx, &ngrok.EndpointCreate{
+		Type:          "cloud",
+		URL:           "https://app.example.com",
+		TrafficPolicy: "on_http_request:\n  - actions:\n      - type: deny\n",
+		Bindings:      []string{"public"},
+	})
+	require.NoError(t, err)
+	_, err = clientset.Endpoints().Create(ctx, &ngrok.EndpointCreate{Type: "agent", URL: "https://agent.example.com"})
+	require.NoError(t, err)
+
+	addr, err := clientset.TCPAddresses().Create(ctx, &ngrok.ReservedAddrCreate{Description: "legacy"})
+	require.NoError(t, err)
+
+	objs, err := New(clientset, Options{Namespace: "ngrok"}).Import(ctx)
+	require.NoError(t, err)
+	require.Len(t, objs, 4)
+	for _, obj := range objs {
+		assert.Equal(t, "ngrok", obj.GetNamespace())
+	}
+
+	domainCR := objs[0].(*ingressv1alpha1.Domain)
+	assert.Equal(t, "app-example-com", domainCR.Name)
+	assert.Equal(t, domain.ID, domainCR.Annotations[annotations.AdoptAnnotation])
+	assert.Equal(t, domain.ID, domainCR.Status.ID)
+	assert.Equal(t, []ingressv1alpha1.DomainResolvesToEntry{{Value: "eu"}}, domainCR.Spec.ResolvesTo)
+	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, domainCR.Spec.ReclaimPolicy)
+	assert.Equal(t, "Domain", domainCR.GroupVersionKind().Kind)
+
+	policyCR := objs[1].(*ingressv1alpha1.IPPolicy)
+	assert.Equal(t, "office-ips", policyCR.Name)
+	assert.Equal(t, policy.ID, policyCR.Status.ID)
+	assert.JSONEq(t, `{"team":"net"}`, string(policyCR.Spec.Metadata))
+	require.Len(t, policyCR.Spec.Rules, 1)
+	assert.Equal(t, "10.0.0.0/8", policyCR.Spec.Rules[0].CIDR)
+	assert.Equal(t, "allow", policyCR.Spec.Rules[0].Action)
+
+	endpointCR := objs[2].(*ngrokv1alpha1.CloudEndpoint)
+	assert.Equal(t, "app-example-com", endpointCR.Name)
+	assert.Equal(t, endpoint.ID, endpointCR.Status.ID)
+	assert.Equal(t, []string{"public"}, endpointCR.Spec.Bindings)
+	require.NotNil(t, endpointCR.Spec.TrafficPolicy)
+	assert.JSONEq(t, `{"on_http_request":[{"actions":[{"type":"deny"}]}]}`, string(endpointCR.Spec.TrafficPolicy.Inline))
+
+	addrCR := objs[3].(*ingressv1alpha1.TCPAddress)
+	assert.Equal(t, addr.Addr, addrCR.Spec.Address)
+	assert.Equal(t, addr.ID, addrCR.Status.ID)
+	assert.Equal(t, addr.ID, addrCR.Annotations[annotations.AdoptAnnotation])
+}
+
+func TestImportOptions(t *testing.T) {
+	ctx := context.Background()
+	clientset := nmockapi.NewClientset()
+
+	for _, description := range []string{"dupe", "dupe"} {
+		_, err := clientset.IPPolicies().Create(ctx, &ngrok.IPPolicyCreate{Description: description, Metadata: `{"owned-by":"ngrok-operator"}`})
+		require.NoError(t, err)
+	}
+	_, err := clientset.Domains().Create(ctx, &ngrok.ReservedDomainCreate{Domain: "app.example.com"})
+	require.NoError(t, err)
+
+	objs, err := New(clientset, Options{Kinds: []string{KindIPPolicy}}).Import(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+
+	objs, err = New(clientset, Options{Kinds: []string{KindIPPolicy}, IncludeManaged: true}).Import(ctx)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "default", objs[0].GetNamespace())
+	assert.NotEqual(t, objs[0].GetName(), objs[1].GetName())
+
+	_, err = New(clientset, Options{Kinds: []string{"Ingress"}}).Import(ctx)
+	assert.ErrorContains(t, err, `unsupported kind "Ingress"`)
+}
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, "1-tcp-ngrok-io-12345", resourceName("1.tcp.ngrok.io:12345"))
+	assert.Equal(t, "wildcard-example-com", endpointName("https://*.example.com"))
+	assert.Equal(t, "app-example-com-8443", endpointName("tls://app.example.com:8443"))
+}
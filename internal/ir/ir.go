@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	common "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 )
 
@@ -102,6 +103,9 @@ type IRVirtualHost struct {
 	// Bindings to set on generated Endpoints
 	Bindings []string
 
+	// Schedule to set on the public endpoint generated from this virtual host
+	Schedule *ngrokv1alpha1.EndpointSchedule
+
 	// Defines how this VirtualHost will be translated
 	MappingStrategy IRMappingStrategy
 
@@ -229,6 +233,9 @@ type IRRoute struct {
 }
 
 type IRHTTPMatch struct {
+	// Host restricts the match to requests for a single hostname, or any subdomain of a wildcard hostname such
+	// as *.example.com. It is only set for routes that share a wildcard virtual host with other hostnames.
+	Host        *string
 	Path        *string
 	PathType    *IRPathMatchType
 	Headers     []IRHeaderMatch
@@ -299,6 +306,8 @@ type IRObjectRef struct {
 // SortRoutes sorts the routes for an IRVirtualHost.
 // The ordering is chosen so that the most specific (best-match) routes come first.
 // The order of criteria is:
+//  0. Host: Routes restricted to a host come before those without. Exact hosts come before wildcard hosts,
+//     and more specific wildcards before less specific ones, so that the best matching host always wins.
 //  1. Path: Routes with a defined path come before those without.
 //     For routes with paths, an exact match is preferred over prefix which is preferred over regex.
 //     For the same type, longer paths are preferred, then lexicographical order.
@@ -323,6 +332,25 @@ func (h *IRVirtualHost) SortRoutes() {
 			return true // i has match criteria, j doesn't => i should come first
 		}
 
+		// 0. Compare Host.
+		switch {
+		case mi.Host != nil && mj.Host == nil:
+			return true
+		case mi.Host == nil && mj.Host != nil:
+			return false
+		case mi.Host != nil && mj.Host != nil && *mi.Host != *mj.Host:
+			orderI := hostOrder(*mi.Host)
+			orderJ := hostOrder(*mj.Host)
+			if orderI != orderJ {
+				return orderI < orderJ
+			}
+			// More specific wildcards are longer
+			if len(*mi.Host) != len(*mj.Host) {
+				return len(*mi.Host) > len(*mj.Host)
+			}
+			return *mi.Host < *mj.Host
+		}
+
 		// 1. Compare Path.
 		// If only one route specifies a path, that route is more specific.
 		switch {
@@ -396,6 +424,15 @@ func (h *IRVirtualHost) SortRoutes() {
 	})
 }
 
+// hostOrder returns an integer to order host matches.
+// Exact hosts are more specific than wildcard hosts.
+func hostOrder(host string) int {
+	if strings.HasPrefix(host, "*.") {
+		return 1
+	}
+	return 0
+}
+
 // pathTypeOrder returns an integer to order the path types.
 // Lower values are considered more specific.
 func pathTypeOrder(pt IRPathMatchType) int {
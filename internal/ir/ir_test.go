@@ -348,6 +348,69 @@ func TestSortRoutes(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Host sorting: exact hosts before wildcards before no host",
+			routes: []*IRRoute{
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Path:     new("/longer-path"),
+						PathType: new(IRPathType_Prefix),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("*.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("*.api.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("app.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host:     new("app.example.com"),
+						Path:     new("/foo"),
+						PathType: new(IRPathType_Prefix),
+					},
+				},
+			},
+			expectedOrder: []*IRRoute{
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host:     new("app.example.com"),
+						Path:     new("/foo"),
+						PathType: new(IRPathType_Prefix),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("app.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("*.api.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Host: new("*.example.com"),
+					},
+				},
+				{
+					HTTPMatchCriteria: &IRHTTPMatch{
+						Path:     new("/longer-path"),
+						PathType: new(IRPathType_Prefix),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
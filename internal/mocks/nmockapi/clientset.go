@@ -11,15 +11,20 @@ type Clientset struct {
 	ipPoliciesClient          *IPPolicyClient
 	ipPolicyRulesClient       *IPPolicyRuleClient
 	kubernetesOperatorsClient *KubernetesOperatorsClient
+	tcpAddressesClient        *TCPAddressesClient
+	tlsCertificatesClient     *TLSCertificatesClient
 }
 
 func NewClientset() *Clientset {
+	ipPoliciesClient := NewIPPolicyClient()
 	return &Clientset{
 		domainsClient:             NewDomainClient(),
 		endpointsClient:           NewEndpointsClient(),
-		ipPoliciesClient:          NewIPPolicyClient(),
-		ipPolicyRulesClient:       NewIPPolicyRuleClient(NewIPPolicyClient()),
+		ipPoliciesClient:          ipPoliciesClient,
+		ipPolicyRulesClient:       NewIPPolicyRuleClient(ipPoliciesClient),
 		kubernetesOperatorsClient: NewKubernetesOperatorsClient(),
+		tcpAddressesClient:        NewTCPAddressClient(),
+		tlsCertificatesClient:     NewTLSCertificatesClient(),
 	}
 }
 
@@ -44,5 +49,9 @@ func (m *Clientset) KubernetesOperators() ngrokapi.KubernetesOperatorsClient {
 }
 
 func (m *Clientset) TCPAddresses() ngrokapi.TCPAddressesClient {
-	return nil
+	return m.tcpAddressesClient
+}
+
+func (m *Clientset) TLSCertificates() ngrokapi.TLSCertificatesClient {
+	return m.tlsCertificatesClient
 }
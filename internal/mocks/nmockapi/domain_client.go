@@ -43,12 +43,18 @@ func (m *DomainClient) Create(_ context.Context, item *ngrok.ReservedDomainCreat
 	id := m.newID()
 
 	newDomain := &ngrok.ReservedDomain{
-		ID:         id,
-		CreatedAt:  m.createdAt(),
-		Domain:     item.Domain,
-		Region:     item.Region,
-		URI:        fmt.Sprintf("https://mock-api.ngrok.com/reserved_domains/%s", id),
-		ResolvesTo: item.ResolvesTo,
+		ID:          id,
+		CreatedAt:   m.createdAt(),
+		Domain:      item.Domain,
+		Region:      item.Region,
+		Description: item.Description,
+		Metadata:    item.Metadata,
+		URI:         fmt.Sprintf("https://mock-api.ngrok.com/reserved_domains/%s", id),
+		ResolvesTo:  item.ResolvesTo,
+	}
+
+	if item.CertificateID != nil {
+		newDomain.Certificate = m.certificateRef(*item.CertificateID)
 	}
 
 	if !isNgrokManagedDomain(newDomain) {
@@ -76,15 +82,15 @@ func (m *DomainClient) Update(ctx context.Context, item *ngrok.ReservedDomainUpd
 		existingItem.Metadata = *item.Metadata
 	}
 
+	// An uploaded certificate and a certificate management policy are mutually exclusive
 	if item.CertificateID != nil {
-		existingItem.Certificate = &ngrok.Ref{
-			ID:  *item.CertificateID,
-			URI: fmt.Sprintf("https://mock-api.ngrok.com/certificates/%s", *item.CertificateID),
-		}
+		existingItem.Certificate = m.certificateRef(*item.CertificateID)
+		existingItem.CertificateManagementPolicy = nil
 	}
 
 	if item.CertificateManagementPolicy != nil {
 		existingItem.CertificateManagementPolicy = item.CertificateManagementPolicy
+		existingItem.Certificate = nil
 	}
 
 	if item.ResolvesTo != nil {
@@ -95,6 +101,13 @@ func (m *DomainClient) Update(ctx context.Context, item *ngrok.ReservedDomainUpd
 	return existingItem, nil
 }
 
+func (m *DomainClient) certificateRef(id string) *ngrok.Ref {
+	return &ngrok.Ref{
+		ID:  id,
+		URI: fmt.Sprintf("https://mock-api.ngrok.com/certificates/%s", id),
+	}
+}
+
 var (
 	ngrokManagedDomainSuffixes = []string{
 		"ngrok.app",
@@ -2,7 +2,6 @@ package nmockapi
 
 import (
 	context "context"
-	"errors"
 	"fmt"
 	"math/rand"
 
@@ -22,6 +21,9 @@ func NewTCPAddressClient() *TCPAddressesClient {
 }
 
 func (m *TCPAddressesClient) Create(_ context.Context, item *ngrok.ReservedAddrCreate) (*ngrok.ReservedAddr, error) {
+	if m.createError != nil {
+		return nil, m.createError
+	}
 	id := m.newID()
 
 	newAddr := &ngrok.ReservedAddr{
@@ -29,6 +31,7 @@ func (m *TCPAddressesClient) Create(_ context.Context, item *ngrok.ReservedAddrC
 		CreatedAt:   m.createdAt(),
 		Region:      item.Region,
 		Description: item.Description,
+		Metadata:    item.Metadata,
 		URI:         "https://mock-api.ngrok.com/reserved_addrs/" + id,
 		Addr:        "0.tcp.ngrok.io:1",
 	}
@@ -40,6 +43,22 @@ func (m *TCPAddressesClient) Create(_ context.Context, item *ngrok.ReservedAddrC
 	return newAddr, nil
 }
 
-func (m *TCPAddressesClient) Update(_ context.Context, _ *ngrok.ReservedAddrUpdate) (*ngrok.ReservedAddr, error) {
-	return nil, errors.New("not implemented")
+func (m *TCPAddressesClient) Update(ctx context.Context, item *ngrok.ReservedAddrUpdate) (*ngrok.ReservedAddr, error) {
+	if m.updateError != nil {
+		return nil, m.updateError
+	}
+	existingItem, err := m.Get(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Description != nil {
+		existingItem.Description = *item.Description
+	}
+	if item.Metadata != nil {
+		existingItem.Metadata = *item.Metadata
+	}
+
+	m.items[item.ID] = existingItem
+	return existingItem, nil
 }
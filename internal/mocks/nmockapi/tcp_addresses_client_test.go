@@ -88,6 +88,49 @@ var _ = Describe("TCPAddressesClient", func() {
 		})
 	})
 
+	Describe("Update", func() {
+		var (
+			id      string
+			updated *ngrok.ReservedAddr
+			err     error
+		)
+
+		JustBeforeEach(func() {
+			updated, err = client.Update(ctx, &ngrok.ReservedAddrUpdate{
+				ID:          id,
+				Description: new("updated"),
+				Metadata:    new(`{"owned-by":"test"}`),
+			})
+		})
+
+		Context("when the address exists", func() {
+			BeforeEach(func() {
+				addr, createErr := client.Create(ctx, &ngrok.ReservedAddrCreate{
+					Region:      "us",
+					Description: "to-update",
+				})
+				Expect(createErr).ToNot(HaveOccurred())
+				id = addr.ID
+			})
+
+			It("should update the description and metadata", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updated.Description).To(Equal("updated"))
+				Expect(updated.Metadata).To(Equal(`{"owned-by":"test"}`))
+			})
+		})
+
+		Context("when the address does not exist", func() {
+			BeforeEach(func() {
+				id = "non-existent-id"
+			})
+
+			It("should return a not found error", func() {
+				Expect(ngrok.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		var (
 			id     string
@@ -0,0 +1,66 @@
+package nmockapi
+
+import (
+	context "context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+)
+
+// TLSCertificatesClient is a mock implementation of the ngrok API client for managing
+// uploaded TLS certificates. Like the real API, it rejects certificates that cannot be parsed.
+type TLSCertificatesClient struct {
+	baseClient[*ngrok.TLSCertificate]
+}
+
+func NewTLSCertificatesClient() *TLSCertificatesClient {
+	return &TLSCertificatesClient{
+		baseClient: newBase[*ngrok.TLSCertificate](
+			"cert",
+		),
+	}
+}
+
+func (m *TLSCertificatesClient) Create(_ context.Context, item *ngrok.TLSCertificateCreate) (*ngrok.TLSCertificate, error) {
+	if m.createError != nil {
+		return nil, m.createError
+	}
+
+	block, _ := pem.Decode([]byte(item.CertificatePEM))
+	if block == nil {
+		return nil, m.invalidCertErr("no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, m.invalidCertErr(err.Error())
+	}
+
+	id := m.newID()
+	newCert := &ngrok.TLSCertificate{
+		ID:                id,
+		URI:               fmt.Sprintf("https://mock-api.ngrok.com/tls_certificates/%s", id),
+		CreatedAt:         m.createdAt(),
+		Description:       item.Description,
+		Metadata:          item.Metadata,
+		CertificatePEM:    item.CertificatePEM,
+		SubjectCommonName: cert.Subject.CommonName,
+		NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+		SerialNumber:      cert.SerialNumber.Text(16),
+	}
+	newCert.SubjectAlternativeNames.DNSNames = cert.DNSNames
+
+	m.items[id] = newCert
+	return newCert, nil
+}
+
+func (m *TLSCertificatesClient) invalidCertErr(msg string) error {
+	return &ngrok.Error{
+		StatusCode: http.StatusBadRequest,
+		Msg:        fmt.Sprintf("The certificate PEM is invalid: %s", msg),
+	}
+}
@@ -0,0 +1,85 @@
+package nmockapi
+
+import (
+	context "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/ngrok/ngrok-api-go/v7"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLSCertificatesClient", func() {
+	var (
+		client *TLSCertificatesClient
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		client = NewTLSCertificatesClient()
+		ctx = GinkgoT().Context()
+	})
+
+	Describe("Create", func() {
+		var (
+			certPEM string
+			cert    *ngrok.TLSCertificate
+			err     error
+		)
+
+		JustBeforeEach(func() {
+			cert, err = client.Create(ctx, &ngrok.TLSCertificateCreate{
+				Description:    "test certificate",
+				CertificatePEM: certPEM,
+			})
+		})
+
+		When("the certificate is valid", func() {
+			BeforeEach(func() {
+				key, keyErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Expect(keyErr).ToNot(HaveOccurred())
+				tmpl := &x509.Certificate{
+					SerialNumber: big.NewInt(1),
+					Subject:      pkix.Name{CommonName: "example.com"},
+					DNSNames:     []string{"example.com"},
+					NotBefore:    time.Now().Add(-time.Hour),
+					NotAfter:     time.Now().Add(time.Hour),
+				}
+				der, certErr := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+				Expect(certErr).ToNot(HaveOccurred())
+				certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+			})
+
+			It("should create a new certificate", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cert.ID).To(MatchRegexp("^cert_"))
+				Expect(cert.Description).To(Equal("test certificate"))
+				Expect(cert.SubjectCommonName).To(Equal("example.com"))
+				Expect(cert.SubjectAlternativeNames.DNSNames).To(ConsistOf("example.com"))
+				Expect(cert.NotAfter).ToNot(BeEmpty())
+
+				fetched, getErr := client.Get(ctx, cert.ID)
+				Expect(getErr).ToNot(HaveOccurred())
+				Expect(fetched).To(Equal(cert))
+			})
+		})
+
+		When("the certificate is not valid PEM", func() {
+			BeforeEach(func() {
+				certPEM = "not a certificate"
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(cert).To(BeNil())
+			})
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 	"github.com/ngrok/ngrok-api-go/v7/kubernetes_operators"
 	"github.com/ngrok/ngrok-api-go/v7/reserved_addrs"
 	"github.com/ngrok/ngrok-api-go/v7/reserved_domains"
+	"github.com/ngrok/ngrok-api-go/v7/tls_certificates"
 )
 
 type Clientset interface {
@@ -19,6 +20,7 @@ type Clientset interface {
 	IPPolicyRules() IPPolicyRulesClient
 	KubernetesOperators() KubernetesOperatorsClient
 	TCPAddresses() TCPAddressesClient
+	TLSCertificates() TLSCertificatesClient
 }
 
 type DefaultClientset struct {
@@ -28,6 +30,7 @@ type DefaultClientset struct {
 	ipPolicyRulesClient       *ip_policy_rules.Client
 	kubernetesOperatorsClient *kubernetes_operators.Client
 	tcpAddrsClient            *reserved_addrs.Client
+	tlsCertificatesClient     *tls_certificates.Client
 }
 
 // NewClientSet creates a new ClientSet from an ngrok client config.
@@ -39,6 +42,7 @@ func NewClientSet(config *ngrok.ClientConfig) *DefaultClientset {
 		ipPolicyRulesClient:       ip_policy_rules.NewClient(config),
 		kubernetesOperatorsClient: kubernetes_operators.NewClient(config),
 		tcpAddrsClient:            reserved_addrs.NewClient(config),
+		tlsCertificatesClient:     tls_certificates.NewClient(config),
 	}
 }
 
@@ -91,6 +95,7 @@ type IPPoliciesClient interface {
 	Reader[*ngrok.IPPolicy]
 	Updater[*ngrok.IPPolicyUpdate, *ngrok.IPPolicy]
 	Deletor
+	Lister[*ngrok.IPPolicy]
 }
 
 func (c *DefaultClientset) IPPolicies() IPPoliciesClient {
@@ -123,10 +128,22 @@ func (c *DefaultClientset) KubernetesOperators() KubernetesOperatorsClient {
 
 type TCPAddressesClient interface {
 	Creator[*ngrok.ReservedAddrCreate, *ngrok.ReservedAddr]
+	Reader[*ngrok.ReservedAddr]
 	Updater[*ngrok.ReservedAddrUpdate, *ngrok.ReservedAddr]
+	Deletor
 	Lister[*ngrok.ReservedAddr]
 }
 
 func (c *DefaultClientset) TCPAddresses() TCPAddressesClient {
 	return c.tcpAddrsClient
 }
+
+type TLSCertificatesClient interface {
+	Creator[*ngrok.TLSCertificateCreate, *ngrok.TLSCertificate]
+	Reader[*ngrok.TLSCertificate]
+	Deletor
+}
+
+func (c *DefaultClientset) TLSCertificates() TLSCertificatesClient {
+	return c.tlsCertificatesClient
+}
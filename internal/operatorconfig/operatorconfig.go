@@ -0,0 +1,186 @@
+// Package operatorconfig keeps the settings of the operator's managers that can be changed on the KubernetesOperator
+// without restarting them. The managers' flags only provide the defaults of the settings that aren't set on it.
+package operatorconfig
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	controllerruntime "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Config is the configuration in effect for the managers
+type Config = ngrokv1alpha1.KubernetesOperatorEffectiveConfig
+
+// OperatorIDMetadataKey is the ngrok metadata key set to the ngrok API ID of the KubernetesOperator on the resources
+// created for Ingresses and Gateways, which identifies the installation that created them
+const OperatorIDMetadataKey = "k8s-operator-id"
+
+// Defaults are the values of the settings that aren't set on the KubernetesOperator, from the managers' flags
+type Defaults struct {
+	DefaultDomainReclaimPolicy ingressv1alpha1.DomainReclaimPolicy
+	NgrokMetadata              map[string]string
+}
+
+// Resolve returns the configuration in effect for the KubernetesOperator, applying the defaults to the settings it
+// doesn't set. ko is nil when the KubernetesOperator doesn't exist, in which case only the defaults apply.
+func Resolve(ko *ngrokv1alpha1.KubernetesOperator, defaults Defaults) Config {
+	config := Config{
+		DefaultDomainReclaimPolicy: defaults.DefaultDomainReclaimPolicy,
+		NgrokMetadata:              maps.Clone(defaults.NgrokMetadata),
+		DrainPolicy:                ngrokv1alpha1.DrainPolicyRetain,
+	}
+	if ko == nil {
+		return config
+	}
+
+	if c := ko.Spec.Config; c != nil {
+		if c.DefaultDomainReclaimPolicy != "" {
+			config.DefaultDomainReclaimPolicy = c.DefaultDomainReclaimPolicy
+		}
+		if len(c.NgrokMetadata) > 0 {
+			config.NgrokMetadata = maps.Clone(c.NgrokMetadata)
+		}
+	}
+	// The api-manager writes the flags for these to spec.drain and spec.binding, which spec.config overrides
+	config.EndpointSelectors = slices.Clone(ko.GetEndpointSelectors())
+	config.DrainPolicy = ko.GetDrainPolicy()
+	return config
+}
+
+// Store holds the configuration in effect and runs the registered handlers when it changes
+type Store struct {
+	defaults Defaults
+
+	mu         sync.RWMutex
+	config     Config
+	operatorID string
+	handlers   []func(ctx context.Context, config Config)
+}
+
+// NewStore returns a Store with the defaults in effect until the KubernetesOperator is read
+func NewStore(defaults Defaults) *Store {
+	return &Store{
+		defaults: defaults,
+		config:   Resolve(nil, defaults),
+	}
+}
+
+// Get returns the configuration in effect. The caller must not modify it.
+func (s *Store) Get() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// OperatorID returns the ngrok API ID of the KubernetesOperator, which is empty until it has been registered with
+// the ngrok API
+func (s *Store) OperatorID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.operatorID
+}
+
+// Resolve returns the configuration in effect for the KubernetesOperator with the defaults of the Store
+func (s *Store) Resolve(ko *ngrokv1alpha1.KubernetesOperator) Config {
+	return Resolve(ko, s.defaults)
+}
+
+// OnChange registers a handler that is called with the new configuration each time it or the operator ID changes. It
+// must be called before the manager starts.
+func (s *Store) OnChange(handler func(ctx context.Context, config Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// set updates the configuration in effect and the operator ID, and returns whether either changed
+func (s *Store) set(ctx context.Context, config Config, operatorID string) bool {
+	s.mu.Lock()
+	if reflect.DeepEqual(s.config, config) && s.operatorID == operatorID {
+		s.mu.Unlock()
+		return false
+	}
+	s.config = config
+	s.operatorID = operatorID
+	handlers := slices.Clone(s.handlers)
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(ctx, config)
+	}
+	return true
+}
+
+// Watcher keeps a Store up to date with the KubernetesOperator of this installation. It runs on every replica, since
+// each of them reads the configuration.
+type Watcher struct {
+	client.Client
+	Log   logr.Logger
+	Store *Store
+
+	// Namespace and Name of the KubernetesOperator, i.e. the release namespace and name
+	Namespace string
+	Name      string
+}
+
+// SetupWithManager sets up the watcher with the Manager
+func (w *Watcher) SetupWithManager(mgr ctrl.Manager) error {
+	ownKO := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == w.Namespace && obj.GetName() == w.Name
+	})
+	// The ID is recorded in the status once the KubernetesOperator is registered with the ngrok API
+	idChanged := predicate.TypedFuncs[client.Object]{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldKO, okOld := e.ObjectOld.(*ngrokv1alpha1.KubernetesOperator)
+			newKO, okNew := e.ObjectNew.(*ngrokv1alpha1.KubernetesOperator)
+			return okOld && okNew && oldKO.Status.ID != newKO.Status.ID
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("operator-config").
+		For(&ngrokv1alpha1.KubernetesOperator{}, builder.WithPredicates(ownKO, predicate.Or(predicate.GenerationChangedPredicate{}, idChanged))).
+		WithOptions(controllerruntime.Options{NeedLeaderElection: new(false)}).
+		Complete(w)
+}
+
+// Reconcile loads the configuration and ID of the KubernetesOperator into the Store, or the defaults when it doesn't
+// exist
+func (w *Watcher) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ko := &ngrokv1alpha1.KubernetesOperator{}
+	if err := w.Get(ctx, req.NamespacedName, ko); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		ko = nil
+	}
+
+	config := w.Store.Resolve(ko)
+	operatorID := ""
+	if ko != nil {
+		operatorID = ko.Status.ID
+	}
+	if w.Store.set(ctx, config, operatorID) {
+		w.Log.Info("operator configuration changed",
+			"operatorID", operatorID,
+			"defaultDomainReclaimPolicy", config.DefaultDomainReclaimPolicy,
+			"ngrokMetadata", config.NgrokMetadata,
+			"endpointSelectors", config.EndpointSelectors,
+			"drainPolicy", config.DrainPolicy,
+		)
+	}
+	return ctrl.Result{}, nil
+}
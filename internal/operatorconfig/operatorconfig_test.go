@@ -0,0 +1,111 @@
+package operatorconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+)
+
+var defaults = Defaults{
+	DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete,
+	NgrokMetadata:              map[string]string{"cluster": "prod"},
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, Config{
+		DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete,
+		NgrokMetadata:              map[string]string{"cluster": "prod"},
+		DrainPolicy:                ngrokv1alpha1.DrainPolicyRetain,
+	}, Resolve(nil, defaults))
+
+	ko := &ngrokv1alpha1.KubernetesOperator{
+		Spec: ngrokv1alpha1.KubernetesOperatorSpec{
+			Binding: &ngrokv1alpha1.KubernetesOperatorBinding{EndpointSelectors: []string{"true"}},
+			Drain:   &ngrokv1alpha1.DrainConfig{Policy: ngrokv1alpha1.DrainPolicyDelete},
+			Config:  &ngrokv1alpha1.KubernetesOperatorConfig{},
+		},
+	}
+	assert.Equal(t, Config{
+		DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete,
+		NgrokMetadata:              map[string]string{"cluster": "prod"},
+		EndpointSelectors:          []string{"true"},
+		DrainPolicy:                ngrokv1alpha1.DrainPolicyDelete,
+	}, Resolve(ko, defaults))
+
+	ko.Spec.Config = &ngrokv1alpha1.KubernetesOperatorConfig{
+		DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyRetain,
+		NgrokMetadata:              map[string]string{"team": "platform"},
+	}
+	config := Resolve(ko, defaults)
+	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, config.DefaultDomainReclaimPolicy)
+	assert.Equal(t, map[string]string{"team": "platform"}, config.NgrokMetadata)
+	assert.Equal(t, []string{"true"}, config.EndpointSelectors)
+	assert.Equal(t, ngrokv1alpha1.DrainPolicyDelete, config.DrainPolicy)
+
+	// spec.config overrides spec.drain and spec.binding, which the api-manager writes from its flags
+	ko.Spec.Config.DrainPolicy = ngrokv1alpha1.DrainPolicyRetain
+	ko.Spec.Config.EndpointSelectors = []string{"false"}
+	config = Resolve(ko, defaults)
+	assert.Equal(t, []string{"false"}, config.EndpointSelectors)
+	assert.Equal(t, ngrokv1alpha1.DrainPolicyRetain, config.DrainPolicy)
+
+	// The effective config doesn't share the spec's maps
+	config.NgrokMetadata["team"] = "changed"
+	assert.Equal(t, "platform", ko.Spec.Config.NgrokMetadata["team"])
+}
+
+func TestWatcher(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+
+	ko := &ngrokv1alpha1.KubernetesOperator{Name: "ngrok-operator", Namespace: "ngrok-operator"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ko).WithStatusSubresource(ko).Build()
+
+	store := NewStore(defaults)
+	var changes []Config
+	store.OnChange(func(_ context.Context, config Config) {
+		changes = append(changes, config)
+	})
+	w := &Watcher{Client: c, Log: logr.Discard(), Store: store, Namespace: ko.Namespace, Name: ko.Name}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name}}
+
+	// A KubernetesOperator without spec.config keeps the defaults
+	_, err := w.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	ko.Spec.Config = &ngrokv1alpha1.KubernetesOperatorConfig{DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyRetain}
+	require.NoError(t, c.Update(t.Context(), ko))
+	_, err = w.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, store.Get().DefaultDomainReclaimPolicy)
+	assert.Equal(t, changes[0], store.Get())
+
+	// The ID is loaded once the KubernetesOperator is registered with the ngrok API
+	assert.Empty(t, store.OperatorID())
+	ko.Status.ID = "k8sop_123"
+	require.NoError(t, c.Status().Update(t.Context(), ko))
+	_, err = w.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "k8sop_123", store.OperatorID())
+
+	// Falls back to the defaults when the KubernetesOperator is deleted
+	require.NoError(t, c.Delete(t.Context(), ko))
+	_, err = w.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyDelete, store.Get().DefaultDomainReclaimPolicy)
+	assert.Empty(t, store.OperatorID())
+}
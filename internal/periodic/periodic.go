@@ -0,0 +1,38 @@
+// Package periodic runs tasks at a fixed interval alongside the controllers of a manager.
+package periodic
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// LeaderTask is a manager runnable that runs a task every Interval on the leader only, like the controllers whose
+// resources the task reads or updates, so that replicas don't race each other
+type LeaderTask struct {
+	Interval time.Duration
+	Run      func(ctx context.Context)
+}
+
+var _ manager.LeaderElectionRunnable = &LeaderTask{}
+
+// Start runs the task every Interval until ctx is done
+func (t *LeaderTask) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.Run(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection makes the task run on the leader only
+func (t *LeaderTask) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,29 @@
+package periodic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderTask(t *testing.T) {
+	var runs atomic.Int32
+	task := &LeaderTask{
+		Interval: time.Millisecond,
+		Run: func(context.Context) {
+			runs.Add(1)
+		},
+	}
+	assert.True(t, task.NeedLeaderElection())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() { done <- task.Start(ctx) }()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// DNSResolver is an interface for resolving DNS records. It is satisfied by *net.Resolver, and by using an interface
+// the resolver can easily be swapped out for testing.
+type DNSResolver interface {
+	// LookupCNAME returns the canonical name for the given host after following any CNAME records. When the host
+	// has no CNAME record, the host itself is returned.
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	// LookupHost returns the addresses the given host resolves to.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// NewDefaultDNSResolver returns a DNSResolver that uses the DNS configuration of the pod it runs in.
+func NewDefaultDNSResolver() DNSResolver {
+	return net.DefaultResolver
+}
+
+// IsDNSNotFound returns true if the error is a DNS lookup error for a name or record that does not exist.
+func IsDNSNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// StaticDNSResolver is a DNS resolver that resolves records from static maps.
+type StaticDNSResolver struct {
+	// map of host to CNAME target
+	cnames map[string]string
+	// map of host to addresses
+	hosts map[string][]string
+}
+
+// NewStaticDNSResolver creates a new StaticDNSResolver.
+func NewStaticDNSResolver() *StaticDNSResolver {
+	return &StaticDNSResolver{
+		cnames: make(map[string]string),
+		hosts:  make(map[string][]string),
+	}
+}
+
+// AddCNAME adds a CNAME record to the static map.
+func (r *StaticDNSResolver) AddCNAME(host, target string) {
+	r.cnames[fqdn(host)] = fqdn(target)
+}
+
+// AddHost adds address records to the static map.
+func (r *StaticDNSResolver) AddHost(host string, addrs ...string) {
+	r.hosts[fqdn(host)] = addrs
+}
+
+// LookupCNAME follows CNAME records in the static map and returns the canonical name with a trailing dot, like
+// *net.Resolver does.
+func (r *StaticDNSResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	name := fqdn(host)
+	// Bound the chain so CNAME loops fail rather than hang
+	for range 8 {
+		target, ok := r.cnames[name]
+		if !ok {
+			break
+		}
+		name = target
+	}
+
+	if _, ok := r.hosts[name]; !ok && name == fqdn(host) {
+		return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return name, nil
+}
+
+// LookupHost follows CNAME records in the static map and returns the addresses of the canonical name.
+func (r *StaticDNSResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	name, err := r.LookupCNAME(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, ok := r.hosts[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func fqdn(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, ".")) + "."
+}
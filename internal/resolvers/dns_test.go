@@ -0,0 +1,46 @@
+package resolvers
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultDNSResolverImplementsDNSResolver(t *testing.T) {
+	assert.Implements(t, (*DNSResolver)(nil), NewDefaultDNSResolver())
+}
+
+func TestStaticDNSResolver(t *testing.T) {
+	r := NewStaticDNSResolver()
+	r.AddCNAME("app.example.com", "abc.ngrok-cname.com")
+	r.AddCNAME("abc.ngrok-cname.com", "edge.ngrok.io")
+	r.AddHost("edge.ngrok.io", "1.2.3.4")
+	r.AddHost("apex.example.com", "5.6.7.8")
+
+	// CNAME chains are followed to the canonical name
+	canonical, err := r.LookupCNAME(t.Context(), "App.Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "edge.ngrok.io.", canonical)
+
+	addrs, err := r.LookupHost(t.Context(), "app.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4"}, addrs)
+
+	// Hosts without a CNAME are their own canonical name
+	canonical, err = r.LookupCNAME(t.Context(), "apex.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "apex.example.com.", canonical)
+
+	_, err = r.LookupCNAME(t.Context(), "missing.example.com")
+	assert.True(t, IsDNSNotFound(err))
+	_, err = r.LookupHost(t.Context(), "missing.example.com")
+	assert.True(t, IsDNSNotFound(err))
+}
+
+func TestIsDNSNotFound(t *testing.T) {
+	assert.True(t, IsDNSNotFound(&net.DNSError{IsNotFound: true}))
+	assert.False(t, IsDNSNotFound(&net.DNSError{IsTimeout: true}))
+	assert.False(t, IsDNSNotFound(assert.AnError))
+}
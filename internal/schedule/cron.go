@@ -0,0 +1,147 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed standard five field cron expression: minute, hour, day of month, month and day of week
+type Cron struct {
+	minute, hour, dom, month, dow []bool
+	// domRestricted and dowRestricted record whether the day fields don't start with "*", because a day matches
+	// either of them when both are set
+	domRestricted, dowRestricted bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a standard five field cron expression. Fields are lists of values, ranges (1-5), steps (*/15 or
+// 1-30/5) and "*". Sunday is 0 or 7.
+func ParseCron(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields, has %d", expr, len(cronFields), len(parts))
+	}
+
+	values := make([][]bool, len(cronFields))
+	for i, f := range cronFields {
+		v, err := parseCronField(parts[i], f)
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		values[i] = v
+	}
+
+	// Sunday can be written as 7
+	dow := values[4]
+	dow[0] = dow[0] || dow[7]
+
+	return &Cron{
+		minute:        values[0],
+		hour:          values[1],
+		dom:           values[2],
+		month:         values[3],
+		dow:           dow[:7],
+		domRestricted: !strings.HasPrefix(parts[2], "*"),
+		dowRestricted: !strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, f cronField) ([]bool, error) {
+	values := make([]bool, f.max+1)
+	for item := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepPart)
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+			step = s
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loPart, f); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(hiPart, f); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				// 5/15 means every 15 starting at 5
+				hi = f.max
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("invalid range %q in %s field", rangePart, f.name)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be between %d and %d", s, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that matches the expression, in t's location. It returns the zero time when
+// there is none in the next five years, e.g. for February 30.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[t.Weekday()]
+	if c.domRestricted && c.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, time.March, 4, 10, 30, 15, 0, time.UTC) // a Wednesday
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, time.March, 4, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, time.March, 4, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * *", base, time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", base, time.Date(2026, time.March, 4, 13, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, time.March, 6, 12, 0, 0, 0, time.UTC), time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", base, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 0 15 * 5", base, time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", base, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Next(tt.from))
+		})
+	}
+}
+
+func TestCronNext_Location(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	c, err := ParseCron("0 9 * * *")
+	require.NoError(t, err)
+	next := c.Next(time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2026, time.July, 1, 13, 0, 0, 0, time.UTC), next.UTC())
+}
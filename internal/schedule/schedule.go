@@ -0,0 +1,165 @@
+// Package schedule decides when an endpoint with a schedule is online. The endpoint controllers remove the ngrok
+// endpoint while it is offline, report the state in the Expired condition, and reconcile again when it changes.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/controller/conditions"
+)
+
+const (
+	// ConditionExpired is True while an endpoint with a schedule is offline
+	ConditionExpired = "Expired"
+
+	ReasonScheduleActive  = "ScheduleActive"
+	ReasonScheduleWaiting = "ScheduleWaiting"
+	ReasonScheduleEnded   = "ScheduleEnded"
+)
+
+// State is whether an endpoint is online at a point in time
+type State struct {
+	Active bool
+	// Next is when the state changes: when an online endpoint goes offline, or when an offline endpoint comes
+	// online. It is zero when the state doesn't change again.
+	Next time.Time
+}
+
+// Validate returns an error if the schedule can't be evaluated
+func Validate(s *ngrokv1alpha1.EndpointSchedule) error {
+	_, err := Evaluate(s, time.Time{}, time.Time{})
+	return err
+}
+
+// Evaluate returns the state at now of an endpoint with the schedule s, whose resource was created at created. An
+// endpoint without a schedule is always online.
+func Evaluate(s *ngrokv1alpha1.EndpointSchedule, created, now time.Time) (State, error) {
+	if s == nil {
+		return State{Active: true}, nil
+	}
+
+	start := created
+	if s.NotBefore != nil {
+		start = s.NotBefore.Time
+	}
+	var end time.Time
+	if s.NotAfter != nil {
+		end = s.NotAfter.Time
+	}
+	var ttl time.Duration
+	if s.TTL != nil {
+		ttl = s.TTL.Duration
+		if ttl <= 0 {
+			return State{}, fmt.Errorf("ttl must be positive, got %s", ttl)
+		}
+	}
+
+	if s.Cron == "" {
+		if s.TimeZone != "" {
+			return State{}, errors.New("timeZone requires cron")
+		}
+		if ttl > 0 && (end.IsZero() || start.Add(ttl).Before(end)) {
+			end = start.Add(ttl)
+		}
+		return window(start, end, now), nil
+	}
+
+	if ttl == 0 {
+		return State{}, errors.New("ttl is required when cron is set")
+	}
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return State{}, err
+	}
+	loc := time.UTC
+	if s.TimeZone != "" {
+		if loc, err = time.LoadLocation(s.TimeZone); err != nil {
+			return State{}, fmt.Errorf("invalid timeZone: %w", err)
+		}
+	}
+
+	if !end.IsZero() && !now.Before(end) {
+		return State{}, nil
+	}
+	// The activation that covers now is the first one after now-ttl, ignoring any before start
+	from := now.Add(-ttl)
+	if beforeStart := start.Add(-time.Nanosecond); beforeStart.After(from) {
+		from = beforeStart
+	}
+	activation := cron.Next(from.In(loc))
+	if activation.IsZero() || (!end.IsZero() && !activation.Before(end)) {
+		return State{}, nil
+	}
+	if activation.After(now) {
+		return State{Next: activation}, nil
+	}
+
+	until := activation.Add(ttl)
+	if !end.IsZero() && end.Before(until) {
+		until = end
+	}
+	return State{Active: true, Next: until}, nil
+}
+
+// window returns the state of an endpoint that is online from start until end, or forever when end is zero
+func window(start, end, now time.Time) State {
+	switch {
+	case !end.IsZero() && !start.Before(end):
+		return State{}
+	case now.Before(start):
+		return State{Next: start}
+	case end.IsZero():
+		return State{Active: true}
+	case now.Before(end):
+		return State{Active: true, Next: end}
+	default:
+		return State{}
+	}
+}
+
+// RequeueAfter returns how long to wait before reconciling the endpoint again to apply the next state change, or 0
+// if there is none
+func (s State) RequeueAfter(now time.Time) time.Duration {
+	if s.Next.IsZero() {
+		return 0
+	}
+	// Reconcile just after the change, so it has happened when the schedule is evaluated again
+	return s.Next.Sub(now) + time.Second
+}
+
+// RequeueAfter returns how long to wait before reconciling an endpoint with the schedule s again, or 0 if it
+// doesn't have one or its state doesn't change again
+func RequeueAfter(s *ngrokv1alpha1.EndpointSchedule, created time.Time) time.Duration {
+	now := time.Now()
+	state, err := Evaluate(s, created, now)
+	if err != nil {
+		return 0
+	}
+	return state.RequeueAfter(now)
+}
+
+// SetCondition records the state in the Expired condition of an endpoint. Endpoints without a schedule don't
+// have the condition.
+func SetCondition(conds *[]metav1.Condition, generation int64, s *ngrokv1alpha1.EndpointSchedule, state State) {
+	if s == nil {
+		meta.RemoveStatusCondition(conds, ConditionExpired)
+		return
+	}
+
+	switch {
+	case state.Active && state.Next.IsZero():
+		conditions.Set(conds, generation, ConditionExpired, false, ReasonScheduleActive, "Online")
+	case state.Active:
+		conditions.Set(conds, generation, ConditionExpired, false, ReasonScheduleActive, fmt.Sprintf("Online until %s", state.Next.UTC().Format(time.RFC3339)))
+	case state.Next.IsZero():
+		conditions.Set(conds, generation, ConditionExpired, true, ReasonScheduleEnded, "The schedule has ended, the endpoint was removed from ngrok")
+	default:
+		conditions.Set(conds, generation, ConditionExpired, true, ReasonScheduleWaiting, fmt.Sprintf("Offline until %s", state.Next.UTC().Format(time.RFC3339)))
+	}
+}
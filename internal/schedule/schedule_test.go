@@ -0,0 +1,157 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, time.March, 4, hour, minute, 0, 0, time.UTC)
+}
+
+func TestEvaluate(t *testing.T) {
+	created := at(8, 0)
+
+	tests := []struct {
+		name     string
+		schedule *ngrokv1alpha1.EndpointSchedule
+		now      time.Time
+		want     State
+	}{
+		{
+			name: "no schedule",
+			now:  at(9, 0),
+			want: State{Active: true},
+		},
+		{
+			name:     "ttl from creation",
+			schedule: &ngrokv1alpha1.EndpointSchedule{TTL: &metav1.Duration{Duration: 2 * time.Hour}},
+			now:      at(9, 0),
+			want:     State{Active: true, Next: at(10, 0)},
+		},
+		{
+			name:     "ttl elapsed",
+			schedule: &ngrokv1alpha1.EndpointSchedule{TTL: &metav1.Duration{Duration: 2 * time.Hour}},
+			now:      at(10, 0),
+			want:     State{},
+		},
+		{
+			name:     "before notBefore",
+			schedule: &ngrokv1alpha1.EndpointSchedule{NotBefore: new(metav1.NewTime(at(12, 0)))},
+			now:      at(9, 0),
+			want:     State{Next: at(12, 0)},
+		},
+		{
+			name: "notAfter before end of ttl",
+			schedule: &ngrokv1alpha1.EndpointSchedule{
+				NotBefore: new(metav1.NewTime(at(9, 0))),
+				NotAfter:  new(metav1.NewTime(at(10, 0))),
+				TTL:       &metav1.Duration{Duration: 2 * time.Hour},
+			},
+			now:  at(9, 30),
+			want: State{Active: true, Next: at(10, 0)},
+		},
+		{
+			name:     "cron inside a window",
+			schedule: &ngrokv1alpha1.EndpointSchedule{Cron: "0 */4 * * *", TTL: &metav1.Duration{Duration: time.Hour}},
+			now:      at(8, 30),
+			want:     State{Active: true, Next: at(9, 0)},
+		},
+		{
+			name:     "cron between windows",
+			schedule: &ngrokv1alpha1.EndpointSchedule{Cron: "0 */4 * * *", TTL: &metav1.Duration{Duration: time.Hour}},
+			now:      at(10, 0),
+			want:     State{Next: at(12, 0)},
+		},
+		{
+			name: "cron window started before notBefore",
+			schedule: &ngrokv1alpha1.EndpointSchedule{
+				Cron:      "0 */4 * * *",
+				TTL:       &metav1.Duration{Duration: time.Hour},
+				NotBefore: new(metav1.NewTime(at(8, 30))),
+			},
+			now:  at(8, 45),
+			want: State{Next: at(12, 0)},
+		},
+		{
+			name: "cron after notAfter",
+			schedule: &ngrokv1alpha1.EndpointSchedule{
+				Cron:     "0 */4 * * *",
+				TTL:      &metav1.Duration{Duration: time.Hour},
+				NotAfter: new(metav1.NewTime(at(11, 0))),
+			},
+			now:  at(10, 0),
+			want: State{},
+		},
+		{
+			name: "cron in a time zone",
+			schedule: &ngrokv1alpha1.EndpointSchedule{
+				Cron:     "0 9 * * *",
+				TTL:      &metav1.Duration{Duration: 8 * time.Hour},
+				TimeZone: "America/New_York",
+			},
+			now:  at(15, 0),
+			want: State{Active: true, Next: at(22, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.schedule, created, tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Active, got.Active)
+			assert.True(t, tt.want.Next.Equal(got.Next), "want next %s, got %s", tt.want.Next, got.Next)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate(&ngrokv1alpha1.EndpointSchedule{Cron: "0 9 * * 1-5", TTL: &metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"}))
+
+	for name, s := range map[string]*ngrokv1alpha1.EndpointSchedule{
+		"negative ttl":           {TTL: &metav1.Duration{Duration: -time.Hour}},
+		"cron without ttl":       {Cron: "0 9 * * *"},
+		"invalid cron":           {Cron: "0 9 * *", TTL: &metav1.Duration{Duration: time.Hour}},
+		"time zone without cron": {TimeZone: "UTC"},
+		"invalid time zone":      {Cron: "0 9 * * *", TTL: &metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+	} {
+		assert.Error(t, Validate(s), name)
+	}
+}
+
+func TestStateRequeueAfter(t *testing.T) {
+	assert.Zero(t, State{Active: true}.RequeueAfter(at(9, 0)))
+	assert.Equal(t, time.Hour+time.Second, State{Next: at(10, 0)}.RequeueAfter(at(9, 0)))
+}
+
+func TestSetCondition(t *testing.T) {
+	s := &ngrokv1alpha1.EndpointSchedule{TTL: &metav1.Duration{Duration: time.Hour}}
+	var conds []metav1.Condition
+
+	SetCondition(&conds, 1, s, State{Active: true, Next: at(10, 0)})
+	cond := meta.FindStatusCondition(conds, ConditionExpired)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReasonScheduleActive, cond.Reason)
+	assert.Equal(t, "Online until 2026-03-04T10:00:00Z", cond.Message)
+
+	SetCondition(&conds, 1, s, State{Next: at(12, 0)})
+	cond = meta.FindStatusCondition(conds, ConditionExpired)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReasonScheduleWaiting, cond.Reason)
+
+	SetCondition(&conds, 1, s, State{})
+	cond = meta.FindStatusCondition(conds, ConditionExpired)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReasonScheduleEnded, cond.Reason)
+
+	SetCondition(&conds, 1, nil, State{Active: true})
+	assert.Empty(t, conds)
+}
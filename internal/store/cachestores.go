@@ -51,6 +51,8 @@ type CacheStores struct {
 
 	// Ngrok Stores
 	DomainV1             cache.Store
+	IPPolicyV1           cache.Store
+	TCPAddressV1         cache.Store
 	NgrokTrafficPolicyV1 cache.Store
 	AgentEndpointV1      cache.Store
 	CloudEndpointV1      cache.Store
@@ -78,6 +80,8 @@ func NewCacheStores(logger logr.Logger) CacheStores {
 		ReferenceGrant: cache.NewStore(keyFunc),
 		// Ngrok Stores
 		DomainV1:             cache.NewStore(keyFunc),
+		IPPolicyV1:           cache.NewStore(keyFunc),
+		TCPAddressV1:         cache.NewStore(keyFunc),
 		NgrokTrafficPolicyV1: cache.NewStore(keyFunc),
 		AgentEndpointV1:      cache.NewStore(keyFunc),
 		CloudEndpointV1:      cache.NewStore(keyFunc),
@@ -146,6 +150,10 @@ func (c CacheStores) Get(obj runtime.Object) (item any, exists bool, err error)
 	// ----------------------------------------------------------------------------
 	case *ingressv1alpha1.Domain:
 		return c.DomainV1.Get(obj)
+	case *ingressv1alpha1.IPPolicy:
+		return c.IPPolicyV1.Get(obj)
+	case *ingressv1alpha1.TCPAddress:
+		return c.TCPAddressV1.Get(obj)
 	case *ngrokv1alpha1.NgrokTrafficPolicy:
 		return c.NgrokTrafficPolicyV1.Get(obj)
 	case *ngrokv1alpha1.AgentEndpoint:
@@ -201,6 +209,10 @@ func (c CacheStores) Add(obj runtime.Object) error {
 	// ----------------------------------------------------------------------------
 	case *ingressv1alpha1.Domain:
 		return c.DomainV1.Add(obj)
+	case *ingressv1alpha1.IPPolicy:
+		return c.IPPolicyV1.Add(obj)
+	case *ingressv1alpha1.TCPAddress:
+		return c.TCPAddressV1.Add(obj)
 	case *ngrokv1alpha1.NgrokTrafficPolicy:
 		return c.NgrokTrafficPolicyV1.Add(obj)
 	case *ngrokv1alpha1.AgentEndpoint:
@@ -257,6 +269,10 @@ func (c CacheStores) Delete(obj runtime.Object) error {
 	// ----------------------------------------------------------------------------
 	case *ingressv1alpha1.Domain:
 		return c.DomainV1.Delete(obj)
+	case *ingressv1alpha1.IPPolicy:
+		return c.IPPolicyV1.Delete(obj)
+	case *ingressv1alpha1.TCPAddress:
+		return c.TCPAddressV1.Delete(obj)
 	case *ngrokv1alpha1.NgrokTrafficPolicy:
 		return c.NgrokTrafficPolicyV1.Delete(obj)
 	case *ngrokv1alpha1.AgentEndpoint:
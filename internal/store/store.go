@@ -53,6 +53,8 @@ type Storer interface {
 	GetConfigMapV1(name, namespace string) (*corev1.ConfigMap, error)
 	GetNgrokIngressV1(name, namespace string) (*netv1.Ingress, error)
 	GetNgrokTrafficPolicyV1(name, namespace string) (*ngrokv1alpha1.NgrokTrafficPolicy, error)
+	GetIPPolicyV1(name, namespace string) (*ingressv1alpha1.IPPolicy, error)
+	GetTCPAddressV1(name, namespace string) (*ingressv1alpha1.TCPAddress, error)
 	GetGateway(name string, namespace string) (*gatewayv1.Gateway, error)
 	GetGatewayClass(name string) (*gatewayv1.GatewayClass, error)
 	GetHTTPRoute(name string, namespace string) (*gatewayv1.HTTPRoute, error)
@@ -174,6 +176,16 @@ func (s Store) GetNgrokTrafficPolicyV1(name, namespace string) (*ngrokv1alpha1.N
 	return genericGetByKey[ngrokv1alpha1.NgrokTrafficPolicy](s.stores.NgrokTrafficPolicyV1, getKey(name, namespace))
 }
 
+// GetIPPolicyV1 returns the named IPPolicy
+func (s Store) GetIPPolicyV1(name, namespace string) (*ingressv1alpha1.IPPolicy, error) {
+	return genericGetByKey[ingressv1alpha1.IPPolicy](s.stores.IPPolicyV1, getKey(name, namespace))
+}
+
+// GetTCPAddressV1 returns the named TCPAddress
+func (s Store) GetTCPAddressV1(name, namespace string) (*ingressv1alpha1.TCPAddress, error) {
+	return genericGetByKey[ingressv1alpha1.TCPAddress](s.stores.TCPAddressV1, getKey(name, namespace))
+}
+
 func (s Store) GetGateway(name string, namespace string) (*gatewayv1.Gateway, error) {
 	return genericGetByKey[gatewayv1.Gateway](s.stores.Gateway, getKey(name, namespace))
 }
@@ -0,0 +1,739 @@
+package trafficpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ActionConfig is implemented by the typed configuration of every action type
+// in ActionTypes. It lets callers build and inspect actions without falling back
+// to map[string]any.
+type ActionConfig interface {
+	// ActionType returns the type of action the configuration belongs to.
+	ActionType() ActionType
+	// Validate returns an error if the configuration would be rejected by ngrok.
+	Validate() error
+}
+
+// NewAction wraps a typed configuration in an Action of the matching type.
+func NewAction(config ActionConfig) Action {
+	return Action{
+		Type:   config.ActionType(),
+		Config: config,
+	}
+}
+
+// AddHeadersConfig is the configuration for the add-headers action.
+type AddHeadersConfig struct {
+	// Headers to add, keyed by header name. Values may contain CEL interpolations.
+	Headers map[string]string `json:"headers"`
+}
+
+func (AddHeadersConfig) ActionType() ActionType { return ActionType_AddHeaders }
+
+func (c AddHeadersConfig) Validate() error {
+	if len(c.Headers) == 0 {
+		return errors.New("headers must not be empty")
+	}
+	for name := range c.Headers {
+		if name == "" {
+			return errors.New("header names must not be empty")
+		}
+	}
+	return nil
+}
+
+// NewAddHeadersAction creates a new action that adds headers to the request(OnHTTPRequest phase) or
+// response(OnHTTPResponse phase).
+func NewAddHeadersAction(headers map[string]string) Action {
+	return NewAction(AddHeadersConfig{Headers: headers})
+}
+
+// RemoveHeadersConfig is the configuration for the remove-headers action.
+type RemoveHeadersConfig struct {
+	// Names of the headers to remove.
+	Headers []string `json:"headers"`
+}
+
+func (RemoveHeadersConfig) ActionType() ActionType { return ActionType_RemoveHeaders }
+
+func (c RemoveHeadersConfig) Validate() error {
+	if len(c.Headers) == 0 {
+		return errors.New("headers must not be empty")
+	}
+	if slices.Contains(c.Headers, "") {
+		return errors.New("header names must not be empty")
+	}
+	return nil
+}
+
+// NewRemoveHeadersAction creates a new action that removes headers from the request(OnHTTPRequest phase) or
+// response(OnHTTPResponse phase).
+func NewRemoveHeadersAction(headers []string) Action {
+	return NewAction(RemoveHeadersConfig{Headers: headers})
+}
+
+// BasicAuthConfig is the configuration for the basic-auth action.
+type BasicAuthConfig struct {
+	// Credentials in the form "username:password".
+	Credentials []string `json:"credentials"`
+	// The realm presented to the client in the WWW-Authenticate header.
+	Realm *string `json:"realm,omitempty"`
+	// Allow CORS preflight requests to bypass authentication checks.
+	AllowCORSPreflight *bool `json:"allow_cors_preflight,omitempty"`
+}
+
+func (BasicAuthConfig) ActionType() ActionType { return ActionType_BasicAuth }
+
+func (c BasicAuthConfig) Validate() error {
+	if len(c.Credentials) == 0 {
+		return errors.New("credentials must not be empty")
+	}
+	for i, cred := range c.Credentials {
+		user, pass, ok := strings.Cut(cred, ":")
+		if !ok || user == "" || pass == "" {
+			return fmt.Errorf("credentials[%d] must be in the form username:password", i)
+		}
+	}
+	return nil
+}
+
+// NewBasicAuthAction creates a new action that requires clients to authenticate with one of
+// the configured username and password pairs.
+func NewBasicAuthAction(config BasicAuthConfig) Action {
+	return NewAction(config)
+}
+
+// CircuitBreakerConfig is the configuration for the circuit-breaker action.
+type CircuitBreakerConfig struct {
+	// The ratio of errors to requests, between 0 and 1, at which the breaker trips.
+	ErrorThreshold float64 `json:"error_threshold"`
+	// The minimum number of requests in the window before the breaker can trip.
+	VolumeThreshold *uint32 `json:"volume_threshold,omitempty"`
+	// The rolling window over which the error ratio is computed.
+	WindowDuration *time.Duration `json:"window_duration,omitempty"`
+	// How long the breaker stays tripped before letting requests through again.
+	TrippedDuration *time.Duration `json:"tripped_duration,omitempty"`
+}
+
+func (CircuitBreakerConfig) ActionType() ActionType { return ActionType_CircuitBreaker }
+
+func (CircuitBreakerConfig) durationFields() []string {
+	return []string{"window_duration", "tripped_duration"}
+}
+
+func (c CircuitBreakerConfig) Validate() error {
+	if c.ErrorThreshold <= 0 || c.ErrorThreshold > 1 {
+		return fmt.Errorf("error_threshold must be greater than 0 and at most 1, got %v", c.ErrorThreshold)
+	}
+	if c.WindowDuration != nil && *c.WindowDuration <= 0 {
+		return errors.New("window_duration must be positive")
+	}
+	if c.TrippedDuration != nil && *c.TrippedDuration <= 0 {
+		return errors.New("tripped_duration must be positive")
+	}
+	return nil
+}
+
+// NewCicuitBreakerAction creates a new action that rejects requests when the error rate and request volume within a rolling
+// window exceeds defined thresholds. Can only be used in the OnHTTPRequest phase.
+func NewCircuitBreakerAction(errorThreshold float64, volumeThreshold *uint32, windowDuration *time.Duration, trippedDuration *time.Duration) Action {
+	return NewAction(CircuitBreakerConfig{
+		ErrorThreshold:  errorThreshold,
+		VolumeThreshold: volumeThreshold,
+		WindowDuration:  windowDuration,
+		TrippedDuration: trippedDuration,
+	})
+}
+
+// compressionAlgorithms are the algorithms supported by the compress-response action.
+var compressionAlgorithms = []string{"br", "compress", "deflate", "gzip"}
+
+// CompressResponseConfig is the configuration for the compress-response action.
+type CompressResponseConfig struct {
+	// Algorithms in order of preference. When empty, ngrok picks based on the Accept-Encoding header.
+	Algorithms []string `json:"algorithms,omitempty"`
+}
+
+func (CompressResponseConfig) ActionType() ActionType { return ActionType_CompressResponse }
+
+func (c CompressResponseConfig) Validate() error {
+	for _, alg := range c.Algorithms {
+		if !slices.Contains(compressionAlgorithms, alg) {
+			return fmt.Errorf("unsupported compression algorithm %q, must be one of %s", alg, strings.Join(compressionAlgorithms, ", "))
+		}
+	}
+	return nil
+}
+
+// NewCompressResponseAction creates a new action that compresses the response. Can only be used
+// in the OnHTTPResponse phase.
+func NewCompressResponseAction(algorithms []string) Action {
+	return NewAction(CompressResponseConfig{Algorithms: algorithms})
+}
+
+// CustomResponseConfig is the configuration for the custom-response action.
+type CustomResponseConfig struct {
+	StatusCode int               `json:"status_code"`
+	Content    string            `json:"content,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+func (CustomResponseConfig) ActionType() ActionType { return ActionType_CustomResponse }
+
+func (c CustomResponseConfig) Validate() error {
+	return validateStatusCode(c.StatusCode)
+}
+
+// NewCustomResponseAction creates a new action that enables you to return a hard-coded response back to the client that made a request to your endpoint.
+func NewCustomResponseAction(statusCode int, content string, headers map[string]string) Action {
+	return NewAction(CustomResponseConfig{
+		StatusCode: statusCode,
+		Content:    content,
+		Headers:    headers,
+	})
+}
+
+// DenyConfig is the configuration for the deny action.
+type DenyConfig struct {
+	// The status code returned to HTTP clients. Defaults to 403 when unset.
+	StatusCode *int `json:"status_code,omitempty"`
+}
+
+func (DenyConfig) ActionType() ActionType { return ActionType_Deny }
+
+func (c DenyConfig) Validate() error {
+	if c.StatusCode == nil {
+		return nil
+	}
+	return validateStatusCode(*c.StatusCode)
+}
+
+// NewDenyAction creates a new action that rejects the request or connection.
+func NewDenyAction(config DenyConfig) Action {
+	return NewAction(config)
+}
+
+// ForwardInternalConfig is the configuration for the forward-internal action.
+type ForwardInternalConfig struct {
+	// The URL of the internal endpoint to forward to.
+	URL string `json:"url"`
+	// The binding of the internal endpoint, if it is not the default.
+	Binding *string `json:"binding,omitempty"`
+	// What to do when the upstream cannot be reached, either "halt" or "continue".
+	OnError *string `json:"on_error,omitempty"`
+}
+
+func (ForwardInternalConfig) ActionType() ActionType { return ActionType_ForwardInternal }
+
+func (c ForwardInternalConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("url must not be empty")
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return fmt.Errorf("url is invalid: %w", err)
+	}
+	if c.OnError != nil && *c.OnError != "halt" && *c.OnError != "continue" {
+		return fmt.Errorf("on_error must be one of halt, continue, got %q", *c.OnError)
+	}
+	return nil
+}
+
+// NewForwardInternalAction creates a new action that forwards the traffic to an internal endpoint.
+func NewForwardInternalAction(url string) Action {
+	return NewAction(ForwardInternalConfig{URL: url})
+}
+
+// JWTValidationConfig is the configuration for the jwt-validation action.
+type JWTValidationConfig struct {
+	Issuer   JWTAllowList  `json:"issuer"`
+	Audience JWTAllowList  `json:"audience"`
+	HTTP     JWTHTTPConfig `json:"http"`
+	JWS      JWTJWSConfig  `json:"jws"`
+}
+
+// JWTAllowList restricts a JWT claim to a set of values.
+type JWTAllowList struct {
+	AllowList []JWTAllowListEntry `json:"allow_list"`
+}
+
+// JWTAllowListEntry is a single allowed claim value.
+type JWTAllowListEntry struct {
+	Value string `json:"value"`
+}
+
+// JWTHTTPConfig describes where tokens are read from on HTTP requests.
+type JWTHTTPConfig struct {
+	Tokens []JWTToken `json:"tokens"`
+}
+
+// JWTToken describes a single location a token may be read from.
+type JWTToken struct {
+	// The token type, "jwt" or "jwe".
+	Type string `json:"type"`
+	// Where to read the token from, "header" or "body".
+	Method string `json:"method"`
+	// The header or body field name that carries the token.
+	Name string `json:"name"`
+	// A prefix to strip from the value, e.g. "Bearer ".
+	Prefix *string `json:"prefix,omitempty"`
+}
+
+// JWTJWSConfig configures signature verification.
+type JWTJWSConfig struct {
+	AllowedAlgorithms []string   `json:"allowed_algorithms"`
+	Keys              JWTJWSKeys `json:"keys"`
+}
+
+// JWTJWSKeys lists the sources of the keys used to verify signatures.
+type JWTJWSKeys struct {
+	Sources JWTJWSKeySources `json:"sources"`
+}
+
+// JWTJWSKeySources lists JWKS URLs to fetch verification keys from.
+type JWTJWSKeySources struct {
+	// The ngrok API spells this field additional_jkws.
+	AdditionalJWKS []string `json:"additional_jkws"`
+}
+
+func (JWTValidationConfig) ActionType() ActionType { return ActionType_JWTValidation }
+
+func (c JWTValidationConfig) Validate() error {
+	if err := c.Issuer.validate("issuer"); err != nil {
+		return err
+	}
+	if err := c.Audience.validate("audience"); err != nil {
+		return err
+	}
+	if len(c.HTTP.Tokens) == 0 {
+		return errors.New("http.tokens must not be empty")
+	}
+	for i, tok := range c.HTTP.Tokens {
+		if tok.Type != "jwt" && tok.Type != "jwe" {
+			return fmt.Errorf("http.tokens[%d].type must be one of jwt, jwe, got %q", i, tok.Type)
+		}
+		if tok.Method != "header" && tok.Method != "body" {
+			return fmt.Errorf("http.tokens[%d].method must be one of header, body, got %q", i, tok.Method)
+		}
+		if tok.Name == "" {
+			return fmt.Errorf("http.tokens[%d].name must not be empty", i)
+		}
+	}
+	if len(c.JWS.AllowedAlgorithms) == 0 {
+		return errors.New("jws.allowed_algorithms must not be empty")
+	}
+	for i, src := range c.JWS.Keys.Sources.AdditionalJWKS {
+		if err := validateAbsoluteURL(src); err != nil {
+			return fmt.Errorf("jws.keys.sources.additional_jkws[%d] %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (l JWTAllowList) validate(field string) error {
+	if len(l.AllowList) == 0 {
+		return fmt.Errorf("%s.allow_list must not be empty", field)
+	}
+	for i, entry := range l.AllowList {
+		if entry.Value == "" {
+			return fmt.Errorf("%s.allow_list[%d].value must not be empty", field, i)
+		}
+	}
+	return nil
+}
+
+// NewJWTValidationAction creates a new action that validates JWTs presented on HTTP requests.
+func NewJWTValidationAction(config JWTValidationConfig) Action {
+	return NewAction(config)
+}
+
+// LogConfig is the configuration for the log action.
+type LogConfig struct {
+	// Arbitrary metadata attached to the log event.
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+func (LogConfig) ActionType() ActionType { return ActionType_Log }
+
+func (LogConfig) Validate() error { return nil }
+
+// NewLogAction creates a new action that sends an event with the given metadata to the ngrok event stream.
+func NewLogAction(metadata map[string]any) Action {
+	return NewAction(LogConfig{Metadata: metadata})
+}
+
+// SetVarsConfig is the configuration for the set-vars action.
+type SetVarsConfig struct {
+	// Variables to set, in order. Each entry holds exactly one name and value.
+	Vars []map[string]any `json:"vars"`
+}
+
+func (SetVarsConfig) ActionType() ActionType { return ActionType_SetVars }
+
+func (c SetVarsConfig) Validate() error {
+	if len(c.Vars) == 0 {
+		return errors.New("vars must not be empty")
+	}
+	for i, v := range c.Vars {
+		if len(v) != 1 {
+			return fmt.Errorf("vars[%d] must set exactly one variable, got %d", i, len(v))
+		}
+		for name := range v {
+			if name == "" {
+				return fmt.Errorf("vars[%d] variable name must not be empty", i)
+			}
+		}
+	}
+	return nil
+}
+
+// NewSetVarsAction creates a new action that sets the given variables, in order, for use by later rules.
+func NewSetVarsAction(vars ...map[string]any) Action {
+	return NewAction(SetVarsConfig{Vars: vars})
+}
+
+// OAuthConfig is the configuration for protecting an endpoint with OAuth.
+type OAuthConfig struct {
+	// The name of the OAuth identity provider to be used for authentication.
+	Provider string `json:"provider,omitempty"`
+	// Allow CORS preflight requests to bypass authentication checks. Enable if the endpoint needs to be accessible via CORS.
+	AllowCORSPreflight *bool `json:"allow_cors_preflight,omitempty"`
+	// Sets the allowed domain for the auth cookie.
+	AuthCookieDomain *string `json:"auth_cookie_domain,omitempty"`
+	// Unique authentication identifier for this provider. This value will be used for the cookie, redirect, authentication and logout purposes.
+	AuthID *string `json:"auth_id,omitempty"`
+	// A map of additional URL parameters to apply to the authorization endpoint URL.
+	AuthzURLParams map[string]string `json:"authz_url_params,omitempty"`
+	// Your OAuth app's client ID. Set to nil if you want to use ngrok’s managed application.
+	ClientID *string `json:"client_id,omitempty"`
+	// Your OAuth app's client secret. Set to nil if you want to use a managed application.
+	ClientSecret *string `json:"client_secret,omitempty"`
+	// Defines the period of inactivity after which a user's session is automatically ended, requiring re-authentication.
+	IdleSessionTimeout *time.Duration `json:"idle_session_timeout,omitempty"`
+	// Defines the maximum lifetime of a session regardless of activity.
+	MaxSessionDuration *time.Duration `json:"max_session_duration,omitempty"`
+	// A list of additional scopes to request when users authenticate with the identity provider.
+	Scopes []string `json:"scopes,omitempty"`
+	// How often should ngrok refresh data about the authenticated user from the identity provider.
+	UserinfoRefreshInterval *time.Duration `json:"userinfo_refresh_interval,omitempty"`
+}
+
+func (OAuthConfig) ActionType() ActionType { return ActionType_OAuth }
+
+func (OAuthConfig) durationFields() []string {
+	return []string{"idle_session_timeout", "max_session_duration", "userinfo_refresh_interval"}
+}
+
+func (c OAuthConfig) Validate() error {
+	if c.Provider == "" {
+		return errors.New("provider must not be empty")
+	}
+	if (c.ClientID == nil) != (c.ClientSecret == nil) {
+		return errors.New("client_id and client_secret must be set together")
+	}
+	return nil
+}
+
+// NewOAuthAction creates a new OAuth action that restricts access to only authorized users by enforcing OAuth
+// through an identity provider of your choice.
+func NewOAuthAction(config OAuthConfig) Action {
+	return NewAction(config)
+}
+
+// OIDCConfig is the configuration for protecting an endpoint with OIDC.
+type OIDCConfig struct {
+	// The base URL of the Open ID provider that serves an OpenID Provider Configuration Document at /.well-known/openid-configuration.
+	IssuerURL string `json:"issuer_url,omitempty"`
+	// Allow CORS preflight requests to bypass authentication checks. Enable if the endpoint needs to be accessible via CORS.
+	AllowCORSPreflight *bool `json:"allow_cors_preflight,omitempty"`
+	// Sets the allowed domain for the auth cookie.
+	AuthCookieDomain *string `json:"auth_cookie_domain,omitempty"`
+	// Unique authentication identifier for this provider. This value will be used for the cookie, redirect, authentication and logout purposes.
+	AuthID *string `json:"auth_id,omitempty"`
+	// A map of additional URL parameters to apply to the authorization endpoint URL.
+	AuthzURLParams map[string]string `json:"authz_url_params,omitempty"`
+	// Your OAuth app's client ID. Set to nil if you want to use ngrok’s managed application.
+	ClientID *string `json:"client_id,omitempty"`
+	// Your OAuth app's client secret. Set to nil if you want to use a managed application.
+	ClientSecret *string `json:"client_secret,omitempty"`
+	// Defines the period of inactivity after which a user's session is automatically ended, requiring re-authentication.
+	IdleSessionTimeout *time.Duration `json:"idle_session_timeout,omitempty"`
+	// Defines the maximum lifetime of a session regardless of activity.
+	MaxSessionDuration *time.Duration `json:"max_session_duration,omitempty"`
+	// A list of additional scopes to request when users authenticate with the identity provider.
+	Scopes []string `json:"scopes,omitempty"`
+	// How often should ngrok refresh data about the authenticated user from the identity provider.
+	UserinfoRefreshInterval *time.Duration `json:"userinfo_refresh_interval,omitempty"`
+}
+
+func (OIDCConfig) ActionType() ActionType { return ActionType_OIDC }
+
+func (OIDCConfig) durationFields() []string {
+	return []string{"idle_session_timeout", "max_session_duration", "userinfo_refresh_interval"}
+}
+
+func (c OIDCConfig) Validate() error {
+	if err := validateAbsoluteURL(c.IssuerURL); err != nil {
+		return fmt.Errorf("issuer_url %w", err)
+	}
+	if c.ClientID == nil || *c.ClientID == "" {
+		return errors.New("client_id must not be empty")
+	}
+	if c.ClientSecret == nil || *c.ClientSecret == "" {
+		return errors.New("client_secret must not be empty")
+	}
+	return nil
+}
+
+// NewOIDCAction creates a new OIDC action that restricts access to only authorized users by enforcing OIDC
+// through an identity provider of your choice.
+func NewOIDCAction(config OIDCConfig) Action {
+	return NewAction(config)
+}
+
+// RateLimitAlgorithmSlidingWindow is the only algorithm currently supported by the rate-limit action.
+const RateLimitAlgorithmSlidingWindow = "sliding_window"
+
+// RateLimitConfig is the configuration for the rate-limit action.
+type RateLimitConfig struct {
+	// An optional name used to identify the limiter in events.
+	Name string `json:"name,omitempty"`
+	// The rate limiting algorithm. Only "sliding_window" is supported.
+	Algorithm string `json:"algorithm"`
+	// The number of requests allowed per Rate.
+	Capacity int `json:"capacity"`
+	// The window the capacity applies to, as a duration string such as "60s".
+	Rate string `json:"rate"`
+	// CEL expressions whose values are combined to key the buckets, e.g. "conn.client_ip".
+	BucketKey []string `json:"bucket_key"`
+}
+
+func (RateLimitConfig) ActionType() ActionType { return ActionType_RateLimit }
+
+func (c RateLimitConfig) Validate() error {
+	if c.Algorithm != RateLimitAlgorithmSlidingWindow {
+		return fmt.Errorf("algorithm must be %s, got %q", RateLimitAlgorithmSlidingWindow, c.Algorithm)
+	}
+	if c.Capacity <= 0 {
+		return fmt.Errorf("capacity must be positive, got %d", c.Capacity)
+	}
+	d, err := time.ParseDuration(c.Rate)
+	if err != nil {
+		return fmt.Errorf("rate must be a duration: %w", err)
+	}
+	if d <= 0 {
+		return errors.New("rate must be positive")
+	}
+	if len(c.BucketKey) == 0 {
+		return errors.New("bucket_key must not be empty")
+	}
+	return nil
+}
+
+// NewRateLimitAction creates a new action that limits the rate of requests per bucket key.
+func NewRateLimitAction(config RateLimitConfig) Action {
+	return NewAction(config)
+}
+
+// RedirectConfig is the configuration for the redirect action.
+type RedirectConfig struct {
+	// A regular expression matched against the request URL. Defaults to matching the whole URL.
+	From *string `json:"from,omitempty"`
+	// The location to redirect to. May reference capture groups from From.
+	To string `json:"to"`
+	// The 3xx status code to respond with. Defaults to 302.
+	StatusCode *int `json:"status_code,omitempty"`
+	// Additional headers to add to the redirect response.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (RedirectConfig) ActionType() ActionType { return ActionType_Redirect }
+
+func (c RedirectConfig) Validate() error {
+	if c.To == "" {
+		return errors.New("to must not be empty")
+	}
+	if c.From != nil {
+		if _, err := regexp.Compile(*c.From); err != nil {
+			return fmt.Errorf("from is not a valid regular expression: %w", err)
+		}
+	}
+	if c.StatusCode != nil && (*c.StatusCode < 300 || *c.StatusCode > 399) {
+		return fmt.Errorf("status_code must be a 3xx code, got %d", *c.StatusCode)
+	}
+	return nil
+}
+
+// NewRedirectAction creates a new action that redirects the client to another URL.
+func NewRedirectAction(config RedirectConfig) Action {
+	return NewAction(config)
+}
+
+// RestrictIPsConfig is the configuration for the restrict-ips action.
+type RestrictIPsConfig struct {
+	// When false, violations are only logged. Defaults to true.
+	Enforce *bool `json:"enforce,omitempty"`
+	// CIDRs that are allowed to connect.
+	Allow []string `json:"allow,omitempty"`
+	// CIDRs that are denied.
+	Deny []string `json:"deny,omitempty"`
+	// IDs of IP policies to apply.
+	IPPolicies []string `json:"ip_policies,omitempty"`
+}
+
+func (RestrictIPsConfig) ActionType() ActionType { return ActionType_RestrictIPs }
+
+func (c RestrictIPsConfig) Validate() error {
+	if len(c.Allow) == 0 && len(c.Deny) == 0 && len(c.IPPolicies) == 0 {
+		return errors.New("at least one of allow, deny or ip_policies must be set")
+	}
+	if err := validateCIDRs("allow", c.Allow); err != nil {
+		return err
+	}
+	if err := validateCIDRs("deny", c.Deny); err != nil {
+		return err
+	}
+	if slices.Contains(c.IPPolicies, "") {
+		return errors.New("ip_policies must not contain empty IDs")
+	}
+	return nil
+}
+
+// NewRestrictIPsAction creates a new action that restricts access by client IP.
+// Supported on OnHTTPRequest, OnTCPConnect, and OnHTTPResponse phases.
+func NewRestrictIPsAction(config RestrictIPsConfig) Action {
+	return NewAction(config)
+}
+
+// NewRestrictIPsActionFromIPPolicies creates a new action that restricts access to a set of IP policies.
+// Supported on OnHTTPRequest, OnTCPConnect, and OnHTTPResponse phases.
+func NewRestricIPsActionFromIPPolicies(policies []string) Action {
+	return NewAction(RestrictIPsConfig{IPPolicies: policies})
+}
+
+// TLSTerminationConfig is the configuration for terminating TLS on an endpoint.
+type TLSTerminationConfig struct {
+	MinVersion                      *string  `json:"min_version,omitempty"`
+	MaxVersion                      *string  `json:"max_version,omitempty"`
+	ServerPrivateKey                *string  `json:"server_private_key,omitempty"`
+	ServerCertificate               *string  `json:"server_certificate,omitempty"`
+	MutualTLSCertificateAuthorities []string `json:"mutual_tls_certificate_authorities,omitempty"`
+	MutualTLSVerificationStrategy   *string  `json:"mutual_tls_verification_strategy,omitempty"`
+}
+
+// tlsVersions are the versions accepted by min_version and max_version.
+var tlsVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+func (TLSTerminationConfig) ActionType() ActionType { return ActionType_TerminateTLS }
+
+func (c TLSTerminationConfig) Validate() error {
+	if c.MinVersion != nil && !slices.Contains(tlsVersions, *c.MinVersion) {
+		return fmt.Errorf("min_version must be one of %s, got %q", strings.Join(tlsVersions, ", "), *c.MinVersion)
+	}
+	if c.MaxVersion != nil && !slices.Contains(tlsVersions, *c.MaxVersion) {
+		return fmt.Errorf("max_version must be one of %s, got %q", strings.Join(tlsVersions, ", "), *c.MaxVersion)
+	}
+	if c.MinVersion != nil && c.MaxVersion != nil && *c.MinVersion > *c.MaxVersion {
+		return errors.New("min_version must not be greater than max_version")
+	}
+	if (c.ServerPrivateKey == nil) != (c.ServerCertificate == nil) {
+		return errors.New("server_private_key and server_certificate must be set together")
+	}
+	return nil
+}
+
+// NewTerminateTLSAction creates a new action that configures how TLS is terminated on the endpoint.
+func NewTerminateTLSAction(config TLSTerminationConfig) Action {
+	return NewAction(config)
+}
+
+// URLRewriteConfig is the configuration for the url-rewrite action.
+type URLRewriteConfig struct {
+	// A regular expression matched against the request URL.
+	From string `json:"from"`
+	// The replacement URL. May reference capture groups from From.
+	To string `json:"to"`
+}
+
+func (URLRewriteConfig) ActionType() ActionType { return ActionType_URLRewrite }
+
+func (c URLRewriteConfig) Validate() error {
+	if c.From == "" {
+		return errors.New("from must not be empty")
+	}
+	if _, err := regexp.Compile(c.From); err != nil {
+		return fmt.Errorf("from is not a valid regular expression: %w", err)
+	}
+	if c.To == "" {
+		return errors.New("to must not be empty")
+	}
+	return nil
+}
+
+// NewURLRewriteAction creates a new action that rewrites the request URL before it is forwarded upstream.
+func NewURLRewriteAction(config URLRewriteConfig) Action {
+	return NewAction(config)
+}
+
+// VerifyWebhookConfig is the configuration for the verify-webhook action.
+type VerifyWebhookConfig struct {
+	Provider string `json:"provider"`
+	Secret   string `json:"secret"`
+}
+
+func (VerifyWebhookConfig) ActionType() ActionType { return ActionType_VerifyWebhook }
+
+func (c VerifyWebhookConfig) Validate() error {
+	if c.Provider == "" {
+		return errors.New("provider must not be empty")
+	}
+	return nil
+}
+
+// NewWebhookVerificationAction creates a new action that verifies a webhook request.
+func NewWebhookVerificationAction(provider, secret string) Action {
+	return NewAction(VerifyWebhookConfig{
+		Provider: provider,
+		Secret:   secret,
+	})
+}
+
+func validateStatusCode(code int) error {
+	if code < 100 || code > 599 {
+		return fmt.Errorf("status_code must be between 100 and 599, got %d", code)
+	}
+	return nil
+}
+
+// validateCIDRs accepts CIDRs as well as bare addresses, which ngrok treats as /32 or /128.
+func validateCIDRs(field string, cidrs []string) error {
+	for i, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(cidr); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(cidr); err != nil {
+			return fmt.Errorf("%s[%d] is not a valid CIDR or IP address: %q", field, i, cidr)
+		}
+	}
+	return nil
+}
+
+func validateAbsoluteURL(raw string) error {
+	if raw == "" {
+		return errors.New("must not be empty")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("is invalid: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("must be an absolute URL, got %q", raw)
+	}
+	return nil
+}
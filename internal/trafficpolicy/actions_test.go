@@ -0,0 +1,139 @@
+package trafficpolicy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func TestActionConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  ActionConfig
+		wantErr string
+	}{
+		{name: "add-headers", config: AddHeadersConfig{Headers: map[string]string{"x-a": "b"}}},
+		{name: "add-headers empty", config: AddHeadersConfig{}, wantErr: "headers must not be empty"},
+		{name: "remove-headers empty name", config: RemoveHeadersConfig{Headers: []string{""}}, wantErr: "header names must not be empty"},
+		{name: "basic-auth", config: BasicAuthConfig{Credentials: []string{"user:pass"}}},
+		{name: "basic-auth malformed", config: BasicAuthConfig{Credentials: []string{"user:pass", "nopass"}}, wantErr: "credentials[1] must be in the form username:password"},
+		{name: "circuit-breaker threshold", config: CircuitBreakerConfig{ErrorThreshold: 1.5}, wantErr: "error_threshold"},
+		{name: "compress-response", config: CompressResponseConfig{Algorithms: []string{"gzip", "br"}}},
+		{name: "compress-response unknown", config: CompressResponseConfig{Algorithms: []string{"zstd"}}, wantErr: `unsupported compression algorithm "zstd"`},
+		{name: "custom-response status", config: CustomResponseConfig{StatusCode: 42}, wantErr: "status_code must be between 100 and 599"},
+		{name: "deny default", config: DenyConfig{}},
+		{name: "deny status", config: DenyConfig{StatusCode: ptr.To(700)}, wantErr: "status_code"},
+		{name: "forward-internal", config: ForwardInternalConfig{URL: "https://svc.internal"}},
+		{name: "forward-internal on_error", config: ForwardInternalConfig{URL: "https://svc.internal", OnError: ptr.To("retry")}, wantErr: "on_error"},
+		{
+			name: "jwt-validation",
+			config: JWTValidationConfig{
+				Issuer:   JWTAllowList{AllowList: []JWTAllowListEntry{{Value: "https://issuer.example.com"}}},
+				Audience: JWTAllowList{AllowList: []JWTAllowListEntry{{Value: "api"}}},
+				HTTP:     JWTHTTPConfig{Tokens: []JWTToken{{Type: "jwt", Method: "header", Name: "Authorization", Prefix: ptr.To("Bearer ")}}},
+				JWS: JWTJWSConfig{
+					AllowedAlgorithms: []string{"RS256"},
+					Keys:              JWTJWSKeys{Sources: JWTJWSKeySources{AdditionalJWKS: []string{"https://issuer.example.com/.well-known/jwks.json"}}},
+				},
+			},
+		},
+		{name: "jwt-validation missing issuer", config: JWTValidationConfig{}, wantErr: "issuer.allow_list must not be empty"},
+		{name: "log", config: LogConfig{}},
+		{name: "set-vars", config: SetVarsConfig{Vars: []map[string]any{{"a": 1}, {"b": "two"}}}},
+		{name: "set-vars multiple keys", config: SetVarsConfig{Vars: []map[string]any{{"a": 1, "b": 2}}}, wantErr: "vars[0] must set exactly one variable"},
+		{name: "oauth", config: OAuthConfig{Provider: "google"}},
+		{name: "oauth partial client", config: OAuthConfig{Provider: "google", ClientID: ptr.To("id")}, wantErr: "client_id and client_secret must be set together"},
+		{name: "oidc", config: OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: ptr.To("id"), ClientSecret: ptr.To("secret")}},
+		{name: "oidc relative issuer", config: OIDCConfig{IssuerURL: "accounts.example.com"}, wantErr: "issuer_url must be an absolute URL"},
+		{name: "rate-limit", config: RateLimitConfig{Algorithm: RateLimitAlgorithmSlidingWindow, Capacity: 10, Rate: "60s", BucketKey: []string{"conn.client_ip"}}},
+		{name: "rate-limit bad rate", config: RateLimitConfig{Algorithm: RateLimitAlgorithmSlidingWindow, Capacity: 10, Rate: "often", BucketKey: []string{"conn.client_ip"}}, wantErr: "rate must be a duration"},
+		{name: "rate-limit algorithm", config: RateLimitConfig{Algorithm: "token_bucket"}, wantErr: "algorithm must be sliding_window"},
+		{name: "redirect", config: RedirectConfig{To: "https://example.com", StatusCode: ptr.To(301)}},
+		{name: "redirect non 3xx", config: RedirectConfig{To: "https://example.com", StatusCode: ptr.To(200)}, wantErr: "status_code must be a 3xx code"},
+		{name: "redirect bad regex", config: RedirectConfig{From: ptr.To("("), To: "/"}, wantErr: "from is not a valid regular expression"},
+		{name: "restrict-ips", config: RestrictIPsConfig{Allow: []string{"10.0.0.0/8", "192.168.1.1"}}},
+		{name: "restrict-ips empty", config: RestrictIPsConfig{}, wantErr: "at least one of allow, deny or ip_policies"},
+		{name: "restrict-ips bad cidr", config: RestrictIPsConfig{Deny: []string{"10.0.0.0/33"}}, wantErr: "deny[0] is not a valid CIDR"},
+		{name: "terminate-tls", config: TLSTerminationConfig{MinVersion: ptr.To("1.2"), MaxVersion: ptr.To("1.3")}},
+		{name: "terminate-tls inverted", config: TLSTerminationConfig{MinVersion: ptr.To("1.3"), MaxVersion: ptr.To("1.2")}, wantErr: "min_version must not be greater than max_version"},
+		{name: "url-rewrite", config: URLRewriteConfig{From: "^/old(.*)$", To: "/new$1"}},
+		{name: "url-rewrite missing to", config: URLRewriteConfig{From: ".*"}, wantErr: "to must not be empty"},
+		{name: "verify-webhook", config: VerifyWebhookConfig{Provider: "github", Secret: "s"}},
+		{name: "verify-webhook no provider", config: VerifyWebhookConfig{}, wantErr: "provider must not be empty"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNewActionUsesConfigType(t *testing.T) {
+	for _, cfg := range []ActionConfig{
+		AddHeadersConfig{}, RemoveHeadersConfig{}, BasicAuthConfig{}, CircuitBreakerConfig{},
+		CompressResponseConfig{}, CustomResponseConfig{}, DenyConfig{}, ForwardInternalConfig{},
+		JWTValidationConfig{}, LogConfig{}, SetVarsConfig{}, OAuthConfig{}, OIDCConfig{},
+		RateLimitConfig{}, RedirectConfig{}, RestrictIPsConfig{}, TLSTerminationConfig{},
+		URLRewriteConfig{}, VerifyWebhookConfig{},
+	} {
+		action := NewAction(cfg)
+		assert.Equal(t, cfg.ActionType(), action.Type)
+		assert.Contains(t, ActionTypes(), action.Type)
+	}
+}
+
+func TestActionJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		action   Action
+		expected string
+	}{
+		{
+			name:     "redirect omits unset fields",
+			action:   NewRedirectAction(RedirectConfig{To: "https://example.com$1"}),
+			expected: `{"type":"redirect","config":{"to":"https://example.com$1"}}`,
+		},
+		{
+			name:     "url-rewrite",
+			action:   NewURLRewriteAction(URLRewriteConfig{From: "^/a", To: "/b"}),
+			expected: `{"type":"url-rewrite","config":{"from":"^/a","to":"/b"}}`,
+		},
+		{
+			name:     "set-vars keeps order",
+			action:   NewSetVarsAction(map[string]any{"a": 1}, map[string]any{"b": true}),
+			expected: `{"type":"set-vars","config":{"vars":[{"a":1},{"b":true}]}}`,
+		},
+		{
+			name: "rate-limit",
+			action: NewRateLimitAction(RateLimitConfig{
+				Algorithm: RateLimitAlgorithmSlidingWindow,
+				Capacity:  30,
+				Rate:      "60s",
+				BucketKey: []string{"conn.client_ip"},
+			}),
+			expected: `{"type":"rate-limit","config":{"algorithm":"sliding_window","capacity":30,"rate":"60s","bucket_key":["conn.client_ip"]}}`,
+		},
+		{
+			name:     "circuit-breaker durations",
+			action:   NewCircuitBreakerAction(0.5, nil, ptr.To(10*time.Second), nil),
+			expected: `{"type":"circuit-breaker","config":{"error_threshold":0.5,"window_duration":10000000000}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.action)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package trafficpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrUnknownActionType is returned when an action has no typed configuration in this package.
+	ErrUnknownActionType = errors.New("unknown traffic policy action type")
+	// ErrUnknownActionFields is returned when an action's configuration has fields its typed
+	// configuration does not know about. Decoding such a config would silently drop them.
+	ErrUnknownActionFields = errors.New("traffic policy action config has unknown fields")
+)
+
+// durationConfig is implemented by configs with time.Duration fields. Policy documents write
+// durations as strings, such as "5m", which encoding/json can't decode into a time.Duration.
+type durationConfig interface {
+	// durationFields returns the JSON names of the config's time.Duration fields.
+	durationFields() []string
+}
+
+// normalizeDurations rewrites the duration strings in the given fields of a JSON config as
+// integer nanoseconds, the encoding of time.Duration. Other values are left for the decoder.
+func normalizeDurations(data []byte, fields []string) ([]byte, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		// Let the decoder report the malformed config
+		return data, nil
+	}
+
+	for _, field := range fields {
+		var s string
+		if err := json.Unmarshal(raw[field], &s); err != nil {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a duration: %w", field, err)
+		}
+		raw[field] = json.RawMessage(strconv.FormatInt(int64(d), 10))
+	}
+	return json.Marshal(raw)
+}
+
+var actionDecoders = map[ActionType]func([]byte) (ActionConfig, error){
+	ActionType_AddHeaders:       decodeActionConfig[AddHeadersConfig],
+	ActionType_BasicAuth:        decodeActionConfig[BasicAuthConfig],
+	ActionType_CircuitBreaker:   decodeActionConfig[CircuitBreakerConfig],
+	ActionType_CompressResponse: decodeActionConfig[CompressResponseConfig],
+	ActionType_CustomResponse:   decodeActionConfig[CustomResponseConfig],
+	ActionType_Deny:             decodeActionConfig[DenyConfig],
+	ActionType_ForwardInternal:  decodeActionConfig[ForwardInternalConfig],
+	ActionType_JWTValidation:    decodeActionConfig[JWTValidationConfig],
+	ActionType_Log:              decodeActionConfig[LogConfig],
+	ActionType_SetVars:          decodeActionConfig[SetVarsConfig],
+	ActionType_OAuth:            decodeActionConfig[OAuthConfig],
+	ActionType_OIDC:             decodeActionConfig[OIDCConfig],
+	ActionType_RateLimit:        decodeActionConfig[RateLimitConfig],
+	ActionType_Redirect:         decodeActionConfig[RedirectConfig],
+	ActionType_RemoveHeaders:    decodeActionConfig[RemoveHeadersConfig],
+	ActionType_RestrictIPs:      decodeActionConfig[RestrictIPsConfig],
+	ActionType_TerminateTLS:     decodeActionConfig[TLSTerminationConfig],
+	ActionType_URLRewrite:       decodeActionConfig[URLRewriteConfig],
+	ActionType_VerifyWebhook:    decodeActionConfig[VerifyWebhookConfig],
+}
+
+func decodeActionConfig[T ActionConfig](data []byte) (ActionConfig, error) {
+	var cfg T
+	if dc, ok := any(cfg).(durationConfig); ok {
+		var err error
+		if data, err = normalizeDurations(data, dc.durationFields()); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		// Distinguish fields we don't model from configs that are malformed.
+		if lenientErr := json.Unmarshal(data, new(T)); lenientErr == nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownActionFields, err)
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// DecodeAction returns the typed, validated configuration of an action. The action's config
+// may already be typed, as built by the constructors in this package, or be the generic value
+// produced by unmarshalling policy JSON.
+func DecodeAction(action Action) (ActionConfig, error) {
+	decode, ok := actionDecoders[action.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownActionType, action.Type)
+	}
+
+	cfg, ok := action.Config.(ActionConfig)
+	if !ok {
+		data, err := json.Marshal(action.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s config: %w", action.Type, err)
+		}
+		if cfg, err = decode(data); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %w", action.Type, err)
+		}
+	}
+
+	if cfg.ActionType() != action.Type {
+		return nil, fmt.Errorf("action type %s does not match config for %s", action.Type, cfg.ActionType())
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s config: %w", action.Type, err)
+	}
+	return cfg, nil
+}
+
+// DecodeTrafficPolicy parses a traffic policy like NewTrafficPolicyFromJSON, then replaces the
+// config of every action with its typed configuration. Actions of unknown types, or whose
+// config has fields we don't model, keep their raw config so nothing is lost when the policy is
+// re-encoded. Any other decoding or validation failure is returned as an error.
+func DecodeTrafficPolicy(data []byte) (*TrafficPolicy, error) {
+	tp, err := NewTrafficPolicyFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tp.eachAction(func(path string, action *Action) error {
+		cfg, err := DecodeAction(*action)
+		switch {
+		case errors.Is(err, ErrUnknownActionType), errors.Is(err, ErrUnknownActionFields):
+			return nil
+		case err != nil:
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		action.Config = cfg
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tp, nil
+}
+
+// Validate checks the configuration of every action with a known type and returns all of the
+// errors found. Actions of unknown types, or with fields we don't model, are left for the ngrok
+// API to validate.
+func (tp *TrafficPolicy) Validate() error {
+	var errs []error
+	_ = tp.eachAction(func(path string, action *Action) error {
+		_, err := DecodeAction(*action)
+		if err != nil && !errors.Is(err, ErrUnknownActionType) && !errors.Is(err, ErrUnknownActionFields) {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// eachAction calls fn with the location and a pointer to every action in the policy, stopping
+// at the first error.
+func (tp *TrafficPolicy) eachAction(fn func(path string, action *Action) error) error {
+	phases := []struct {
+		name  string
+		rules []Rule
+	}{
+		{"on_http_request", tp.OnHTTPRequest},
+		{"on_http_response", tp.OnHTTPResponse},
+		{"on_tcp_connect", tp.OnTCPConnect},
+	}
+	for _, phase := range phases {
+		for i := range phase.rules {
+			for j := range phase.rules[i].Actions {
+				path := fmt.Sprintf("%s[%d].actions[%d]", phase.name, i, j)
+				if err := fn(path, &phase.rules[i].Actions[j]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
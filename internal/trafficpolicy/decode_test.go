@@ -0,0 +1,156 @@
+package trafficpolicy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestDecodeAction(t *testing.T) {
+	t.Run("typed config", func(t *testing.T) {
+		cfg, err := DecodeAction(NewForwardInternalAction("https://svc.internal"))
+		require.NoError(t, err)
+		assert.Equal(t, ForwardInternalConfig{URL: "https://svc.internal"}, cfg)
+	})
+
+	t.Run("generic config", func(t *testing.T) {
+		cfg, err := DecodeAction(Action{
+			Type: ActionType_Redirect,
+			Config: map[string]any{
+				"to":          "https://example.com",
+				"status_code": float64(301),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, RedirectConfig{To: "https://example.com", StatusCode: ptr.To(301)}, cfg)
+	})
+
+	t.Run("duration strings", func(t *testing.T) {
+		cfg, err := DecodeAction(Action{
+			Type:   ActionType_CircuitBreaker,
+			Config: map[string]any{"error_threshold": 0.25, "tripped_duration": "2m"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ptr.To(2*time.Minute), cfg.(CircuitBreakerConfig).TrippedDuration)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := DecodeAction(Action{Type: "some-new-action"})
+		assert.ErrorIs(t, err, ErrUnknownActionType)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		_, err := DecodeAction(Action{
+			Type:   ActionType_Deny,
+			Config: map[string]any{"status_code": float64(404), "brand_new": true},
+		})
+		assert.ErrorIs(t, err, ErrUnknownActionFields)
+	})
+
+	t.Run("malformed config", func(t *testing.T) {
+		_, err := DecodeAction(Action{
+			Type:   ActionType_RateLimit,
+			Config: map[string]any{"capacity": "lots"},
+		})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnknownActionFields)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := DecodeAction(Action{
+			Type:   ActionType_URLRewrite,
+			Config: map[string]any{"from": "(", "to": "/"},
+		})
+		assert.ErrorContains(t, err, "invalid url-rewrite config: from is not a valid regular expression")
+	})
+
+	t.Run("mismatched type", func(t *testing.T) {
+		_, err := DecodeAction(Action{Type: ActionType_Deny, Config: LogConfig{}})
+		assert.ErrorContains(t, err, "does not match")
+	})
+}
+
+func TestDecodeTrafficPolicy(t *testing.T) {
+	policy := `{
+		"on_http_request": [{
+			"name": "limit",
+			"actions": [
+				{"type": "rate-limit", "config": {"algorithm": "sliding_window", "capacity": 10, "rate": "1m", "bucket_key": ["conn.client_ip"]}},
+				{"type": "some-new-action", "config": {"anything": 1}},
+				{"type": "deny", "config": {"status_code": 429, "brand_new": true}}
+			]
+		}],
+		"on_tcp_connect": [{
+			"actions": [{"type": "restrict-ips", "config": {"allow": ["10.0.0.0/8"]}}]
+		}]
+	}`
+
+	tp, err := DecodeTrafficPolicy([]byte(policy))
+	require.NoError(t, err)
+
+	actions := tp.OnHTTPRequest[0].Actions
+	assert.Equal(t, RateLimitConfig{
+		Algorithm: RateLimitAlgorithmSlidingWindow,
+		Capacity:  10,
+		Rate:      "1m",
+		BucketKey: []string{"conn.client_ip"},
+	}, actions[0].Config)
+	assert.Equal(t, map[string]any{"anything": float64(1)}, actions[1].Config)
+	assert.Equal(t, map[string]any{"status_code": float64(429), "brand_new": true}, actions[2].Config)
+	assert.Equal(t, RestrictIPsConfig{Allow: []string{"10.0.0.0/8"}}, tp.OnTCPConnect[0].Actions[0].Config)
+
+	// Decoded policies round trip without losing configuration.
+	data, err := json.Marshal(tp)
+	require.NoError(t, err)
+	assert.JSONEq(t, policy, string(data))
+
+	_, err = DecodeTrafficPolicy([]byte(`{"on_http_response": [{"actions": [{"type": "compress-response", "config": {"algorithms": ["zstd"]}}]}]}`))
+	assert.ErrorContains(t, err, "on_http_response[0].actions[0]: invalid compress-response config")
+}
+
+func TestDecodeTrafficPolicyDurations(t *testing.T) {
+	tp, err := DecodeTrafficPolicy([]byte(loadTestData("policy-durations.json")))
+	require.NoError(t, err)
+
+	actions := tp.OnHTTPRequest[0].Actions
+	assert.Equal(t, CircuitBreakerConfig{
+		ErrorThreshold:  0.25,
+		WindowDuration:  ptr.To(30 * time.Second),
+		TrippedDuration: ptr.To(2 * time.Minute),
+	}, actions[0].Config)
+	assert.Equal(t, OAuthConfig{
+		Provider:                "google",
+		IdleSessionTimeout:      ptr.To(time.Hour),
+		MaxSessionDuration:      ptr.To(24 * time.Hour),
+		UserinfoRefreshInterval: ptr.To(5 * time.Minute),
+	}, actions[1].Config)
+
+	_, err = DecodeAction(Action{
+		Type:   ActionType_CircuitBreaker,
+		Config: map[string]any{"error_threshold": 0.25, "window_duration": "soon"},
+	})
+	assert.ErrorContains(t, err, "window_duration must be a duration")
+}
+
+func TestTrafficPolicyValidate(t *testing.T) {
+	tp := NewTrafficPolicy()
+	tp.AddRuleOnHTTPRequest(Rule{Actions: []Action{
+		NewAddHeadersAction(map[string]string{"x-a": "b"}),
+		NewBasicAuthAction(BasicAuthConfig{}),
+		{Type: "some-new-action"},
+	}})
+	tp.AddRuleOnHTTPResponse(Rule{Actions: []Action{
+		NewCustomResponseAction(0, "", nil),
+	}})
+
+	err := tp.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "on_http_request[0].actions[1]: invalid basic-auth config: credentials must not be empty")
+	assert.ErrorContains(t, err, "on_http_response[0].actions[0]: invalid custom-response config")
+
+	assert.NoError(t, NewTrafficPolicy().Validate())
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"time"
 )
 
 // ActionType is a type of action that can be taken. Ref: https://ngrok.com/docs/traffic-policy/actions/
@@ -229,218 +228,3 @@ type Action struct {
 	Type   ActionType `json:"type"`
 	Config any        `json:"config"`
 }
-
-// NewAddHeadersAction creates a new action that adds headers to the request(OnHTTPRequest phase) or
-// response(OnHTTPResponse phase).
-func NewAddHeadersAction(headers map[string]string) Action {
-	config := struct {
-		Headers map[string]string `json:"headers"`
-	}{
-		Headers: headers,
-	}
-
-	return Action{
-		Type:   ActionType_AddHeaders,
-		Config: config,
-	}
-}
-
-// NewRemoveHeadersAction creates a new action that removes headers from the request(OnHTTPRequest phase) or
-// response(OnHTTPResponse phase).
-func NewRemoveHeadersAction(headers []string) Action {
-	config := struct {
-		Headers []string `json:"headers"`
-	}{
-		Headers: headers,
-	}
-
-	return Action{
-		Type:   ActionType_RemoveHeaders,
-		Config: config,
-	}
-}
-
-// NewCompressResponseAction creates a new action that compresses the response. Can only be used
-// in the OnHTTPResponse phase.
-func NewCompressResponseAction(algorithms []string) Action {
-	config := struct {
-		Algorithms []string `json:"algorithms,omitempty"`
-	}{
-		Algorithms: algorithms,
-	}
-
-	return Action{
-		Type:   ActionType_CompressResponse,
-		Config: config,
-	}
-}
-
-// NewCicuitBreakerAction creates a new action that rejects requests when the error rate and request volume within a rolling
-// window exceeds defined thresholds. Can only be used in the OnHTTPRequest phase.
-func NewCircuitBreakerAction(errorThreshold float64, volumeThreshold *uint32, windowDuration *time.Duration, trippedDuration *time.Duration) Action {
-	config := struct {
-		ErrorThreshold  float64        `json:"error_threshold"`
-		VolumeThreshold *uint32        `json:"volume_threshold,omitempty"`
-		WindowDuration  *time.Duration `json:"window_duration,omitempty"`
-		TrippedDuration *time.Duration `json:"tripped_duration,omitempty"`
-	}{
-		ErrorThreshold:  errorThreshold,
-		VolumeThreshold: volumeThreshold,
-		WindowDuration:  windowDuration,
-		TrippedDuration: trippedDuration,
-	}
-	return Action{
-		Type:   ActionType_CircuitBreaker,
-		Config: config,
-	}
-}
-
-// NewCustomResponseAction creates a new action that enables you to return a hard-coded response back to the client that made a request to your endpoint.
-func NewCustomResponseAction(statusCode int, content string, headers map[string]string) Action {
-	config := struct {
-		StatusCode int               `json:"status_code"`
-		Content    string            `json:"content,omitempty"`
-		Headers    map[string]string `json:"headers,omitempty"`
-	}{
-		StatusCode: statusCode,
-		Content:    content,
-		Headers:    headers,
-	}
-	return Action{
-		Type:   ActionType_CustomResponse,
-		Config: config,
-	}
-}
-
-// OAuthConfig is the configuration for protecting an endpoint with OAuth.
-type OAuthConfig struct {
-	// The name of the OAuth identity provider to be used for authentication.
-	Provider string `json:"provider,omitempty"`
-	// Allow CORS preflight requests to bypass authentication checks. Enable if the endpoint needs to be accessible via CORS.
-	AllowCORSPreflight *bool `json:"allow_cors_preflight,omitempty"`
-	// Sets the allowed domain for the auth cookie.
-	AuthCookieDomain *string `json:"auth_cookie_domain,omitempty"`
-	// Unique authentication identifier for this provider. This value will be used for the cookie, redirect, authentication and logout purposes.
-	AuthID *string `json:"auth_id,omitempty"`
-	// A map of additional URL parameters to apply to the authorization endpoint URL.
-	AuthzURLParams map[string]string `json:"authz_url_params,omitempty"`
-	// Your OAuth app's client ID. Set to nil if you want to use ngrok’s managed application.
-	ClientID *string `json:"client_id,omitempty"`
-	// Your OAuth app's client secret. Set to nil if you want to use a managed application.
-	ClientSecret *string `json:"client_secret,omitempty"`
-	// Defines the period of inactivity after which a user's session is automatically ended, requiring re-authentication.
-	IdleSessionTimeout *time.Duration `json:"idle_session_timeout,omitempty"`
-	// Defines the maximum lifetime of a session regardless of activity.
-	MaxSessionDuration *time.Duration `json:"max_session_duration,omitempty"`
-	// A list of additional scopes to request when users authenticate with the identity provider.
-	Scopes []string `json:"scopes,omitempty"`
-	// How often should ngrok refresh data about the authenticated user from the identity provider.
-	UserinfoRefreshInterval *time.Duration `json:"userinfo_refresh_interval,omitempty"`
-}
-
-// NewOAuthAction creates a new OAuth action that restricts access to only authorized users by enforcing OAuth
-// through an identity provider of your choice.
-func NewOAuthAction(config OAuthConfig) Action {
-	return Action{
-		Type:   ActionType_OAuth,
-		Config: config,
-	}
-}
-
-// OIDCConfig is the configuration for protecting an endpoint with OIDC.
-type OIDCConfig struct {
-	// The base URL of the Open ID provider that serves an OpenID Provider Configuration Document at /.well-known/openid-configuration.
-	IssuerURL string `json:"issuer_url,omitempty"`
-	// Allow CORS preflight requests to bypass authentication checks. Enable if the endpoint needs to be accessible via CORS.
-	AllowCORSPreflight *bool `json:"allow_cors_preflight,omitempty"`
-	// Sets the allowed domain for the auth cookie.
-	AuthCookieDomain *string `json:"auth_cookie_domain,omitempty"`
-	// Unique authentication identifier for this provider. This value will be used for the cookie, redirect, authentication and logout purposes.
-	AuthID *string `json:"auth_id,omitempty"`
-	// A map of additional URL parameters to apply to the authorization endpoint URL.
-	AuthzURLParams map[string]string `json:"authz_url_params,omitempty"`
-	// Your OAuth app's client ID. Set to nil if you want to use ngrok’s managed application.
-	ClientID *string `json:"client_id,omitempty"`
-	// Your OAuth app's client secret. Set to nil if you want to use a managed application.
-	ClientSecret *string `json:"client_secret,omitempty"`
-	// Defines the period of inactivity after which a user's session is automatically ended, requiring re-authentication.
-	IdleSessionTimeout *time.Duration `json:"idle_session_timeout,omitempty"`
-	// Defines the maximum lifetime of a session regardless of activity.
-	MaxSessionDuration *time.Duration `json:"max_session_duration,omitempty"`
-	// A list of additional scopes to request when users authenticate with the identity provider.
-	Scopes []string `json:"scopes,omitempty"`
-	// How often should ngrok refresh data about the authenticated user from the identity provider.
-	UserinfoRefreshInterval *time.Duration `json:"userinfo_refresh_interval,omitempty"`
-}
-
-// NewOIDCAction creates a new OIDC action that restricts access to only authorized users by enforcing OIDC
-// through an identity provider of your choice.
-func NewOIDCAction(config OIDCConfig) Action {
-	return Action{
-		Type:   ActionType_OIDC,
-		Config: config,
-	}
-}
-
-// NewRestrictIPsActionFromIPPolicies creates a new action that restricts access to a set of IP policies.
-// Supported on OnHTTPRequest, OnTCPConnect, and OnHTTPResponse phases.
-func NewRestricIPsActionFromIPPolicies(policies []string) Action {
-	config := struct {
-		IPPolicies []string `json:"ip_policies"`
-	}{
-		IPPolicies: policies,
-	}
-
-	return Action{
-		Type:   ActionType_RestrictIPs,
-		Config: config,
-	}
-}
-
-// TLSTerminationConfig is the configuration for terminating TLS on an endpoint.
-type TLSTerminationConfig struct {
-	MinVersion                      *string  `json:"min_version,omitempty"`
-	MaxVersion                      *string  `json:"max_version,omitempty"`
-	ServerPrivateKey                *string  `json:"server_private_key,omitempty"`
-	ServerCertificate               *string  `json:"server_certificate,omitempty"`
-	MutualTLSCertificateAuthorities []string `json:"mutual_tls_certificate_authorities,omitempty"`
-	MutualTLSVerificationStrategy   *string  `json:"mutual_tls_verification_strategy,omitempty"`
-}
-
-// NewTerminateTLSAction creates a new action that configures how TLS is terminated on the endpoint.
-func NewTerminateTLSAction(config TLSTerminationConfig) Action {
-	return Action{
-		Type:   ActionType_TerminateTLS,
-		Config: config,
-	}
-}
-
-// NewWebhookVerificationAction creates a new action that verifies a webhook request.
-func NewWebhookVerificationAction(provider, secret string) Action {
-	config := struct {
-		Provider string `json:"provider"`
-		Secret   string `json:"secret"`
-	}{
-		Provider: provider,
-		Secret:   secret,
-	}
-
-	return Action{
-		Type:   ActionType_VerifyWebhook,
-		Config: config,
-	}
-}
-
-// NewForwardInternalAction creates a new action that forwards the traffic to an internal endpoint.
-func NewForwardInternalAction(url string) Action {
-	config := struct {
-		URL string `json:"url"`
-	}{
-		URL: url,
-	}
-
-	return Action{
-		Type:   ActionType_ForwardInternal,
-		Config: config,
-	}
-}
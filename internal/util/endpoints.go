@@ -16,6 +16,29 @@ func IsInternalDomain(host string) bool {
 	return strings.HasSuffix(h, ".internal")
 }
 
+// ngrokManagedDomainSuffixes are the domains under which ngrok manages the DNS records and certificates
+var ngrokManagedDomainSuffixes = []string{
+	"ngrok.app",
+	"ngrok.dev",
+	"ngrok.io",
+	"ngrok.pizza",
+	"ngrok-free.app",
+	"ngrok-free.dev",
+	"ngrok-free.pizza",
+}
+
+// IsNgrokManagedDomain returns true if the given hostname is a subdomain of one of ngrok's own domains,
+// such as "app.ngrok.app". ngrok manages the DNS records and certificates of these domains.
+func IsNgrokManagedDomain(host string) bool {
+	h := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+	for _, suffix := range ngrokManagedDomainSuffixes {
+		if strings.HasSuffix(h, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseAndSanitizeEndpointURL parses/sanitizes an input string for an endpoint url and provides a *url.URL following the restrictions for endpoints.
 // when isIngressURL is true, the input string does not require a port (excluding tcp addresses)
 func ParseAndSanitizeEndpointURL(input string, isIngressURL bool) (*url.URL, error) {
@@ -35,6 +35,31 @@ func TestIsInternalDomain(t *testing.T) {
 	}
 }
 
+func TestIsNgrokManagedDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected bool
+	}{
+		{"ngrok.app subdomain", "app.ngrok.app", true},
+		{"ngrok-free.dev subdomain", "foo.ngrok-free.dev", true},
+		{"wildcard", "*.foo.ngrok.io", true},
+		{"uppercase with trailing dot", "APP.NGROK.APP.", true},
+		{"custom domain", "app.example.com", false},
+		{"lookalike domain", "app.myngrok.app", false},
+		{"apex", "ngrok.app", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsNgrokManagedDomain(tt.host)
+			if result != tt.expected {
+				t.Errorf("IsNgrokManagedDomain(%q) = %v, want %v", tt.host, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseAndSanitizeEndpointURL(t *testing.T) {
 	successCases := []struct {
 		name         string
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,6 +20,7 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/version"
 	"golang.ngrok.com/ngrok/v2"
 	"golang.ngrok.com/ngrok/v2/rpc"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -45,6 +47,30 @@ type AgentTLSTermination struct {
 	ClientAuth tls.ClientAuthType
 }
 
+// ForwarderEventType is the kind of change reported by a ForwarderEvent
+type ForwarderEventType string
+
+const (
+	// ForwarderClosed is sent when the forwarder of an agent endpoint stops without the endpoint being deleted
+	// or replaced, e.g. because the ngrok service closed the endpoint
+	ForwarderClosed ForwarderEventType = "Closed"
+	// ForwarderErrored is sent for every agent endpoint when the agent loses its session. The agent reconnects on
+	// its own.
+	ForwarderErrored ForwarderEventType = "Errored"
+	// ForwarderReconnected is sent for every agent endpoint when the agent reconnects after losing its session
+	ForwarderReconnected ForwarderEventType = "Reconnected"
+)
+
+// ForwarderEvent reports a change in the lifecycle of an agent endpoint's forwarder that was not caused by
+// CreateAgentEndpoint or DeleteAgentEndpoint
+type ForwarderEvent struct {
+	// Name is the name the agent endpoint was created with
+	Name string
+	Type ForwarderEventType
+	// Err is why the forwarder closed or errored, if known
+	Err error
+}
+
 type Driver interface {
 	// CreateAgentEndpoint creates or updates an agent endpoint by name using the provided desired configuration state.
 	CreateAgentEndpoint(ctx context.Context, name string, spec ngrokv1alpha1.AgentEndpointSpec, trafficPolicy string, clientCerts []tls.Certificate, agentTLS *AgentTLSTermination) (*EndpointResult, error)
@@ -52,6 +78,9 @@ type Driver interface {
 	// DeleteAgentEndpoint deletes an agent endpoint by name.
 	DeleteAgentEndpoint(ctx context.Context, name string) error
 
+	// Events returns the channel on which lifecycle events of the created agent endpoints are sent.
+	Events() <-chan ForwarderEvent
+
 	healthcheck.HealthChecker
 }
 
@@ -116,6 +145,9 @@ type driver struct {
 	healthcheck.HealthChecker
 	done      chan bool
 	closeOnce sync.Once
+
+	events       *forwarderEventQueue
+	disconnected atomic.Bool
 }
 
 // NewDriver creates a new Driver instance with the provided options.
@@ -138,6 +170,7 @@ func NewDriver(driverOpts ...DriverOption) (Driver, error) {
 		forwarders:    newEndpointForwarderMap(),
 		HealthChecker: healthcheck.NewChannelHealthChecker(readyChan, aliveChan),
 	}
+	d.events = newForwarderEventQueue(d.done)
 
 	// Initialize the agent as not ready until it connects
 	readyChan <- errors.New("attempting to connect")
@@ -202,6 +235,10 @@ func NewDriver(driverOpts ...DriverOption) (Driver, error) {
 				default:
 					logger.V(5).Info("ngrok agent connected, but ready channel is full")
 				}
+
+				if d.disconnected.Swap(false) {
+					d.publishAll(ForwarderReconnected, nil)
+				}
 			case *ngrok.EventAgentDisconnected:
 				logger.Error(v.Error, "ngrok agent disconnected")
 				err := v.Error
@@ -211,6 +248,9 @@ func NewDriver(driverOpts ...DriverOption) (Driver, error) {
 				default:
 					logger.V(5).Info("ngrok agent disconnected, but ready channel is full")
 				}
+
+				d.disconnected.Store(true)
+				d.publishAll(ForwarderErrored, err)
 			}
 		}),
 	}
@@ -261,15 +301,22 @@ func (d *driver) CreateAgentEndpoint(ctx context.Context, name string, spec ngro
 		"upstream.protocol", spec.Upstream.Protocol,
 	)
 
+	// Endpoints are pooled by default so that every agent-manager replica can start them
+	poolingEnabled := ptr.Deref(spec.PoolingEnabled, true)
+
 	epf, ok := d.forwarders.Get(name)
 	if ok {
 		// Check if the endpoint matches the spec. If it does, do nothing.
 		// If it doesn't, stop the old endpoint and start a new one.
 		// For now, we just stop the old endpoint and always start a new one.
-		if !epf.PoolingEnabled() {
-			// If pooling is not enabled, we have to stop the old endpoint before starting a new one.
+		// Removing it first keeps its watcher from reporting it as closed.
+		d.forwarders.Remove(name, epf)
+		if !epf.PoolingEnabled() || !poolingEnabled {
+			// If either endpoint doesn't allow pooling, they can't share the URL, so we have to stop the old
+			// endpoint before starting a new one.
 			log.Info("Stopping existing agent endpoint", "id", epf.ID())
 			if err := epf.CloseWithContext(ctx); err != nil {
+				d.forwarders.Add(name, epf)
 				return &EndpointResult{Ready: false}, err
 			}
 		} else {
@@ -282,8 +329,7 @@ func (d *driver) CreateAgentEndpoint(ctx context.Context, name string, spec ngro
 		ngrok.WithURL(spec.URL),
 		ngrok.WithBindings(spec.Bindings...),
 		ngrok.WithMetadata(commonv1alpha1.MetadataAPIString(spec.Metadata)),
-		// TODO(stacks): This may end up being configurable on a per-endpoint basis in the future
-		ngrok.WithPoolingEnabled(true),
+		ngrok.WithPoolingEnabled(poolingEnabled),
 		ngrok.WithDescription(spec.Description),
 	}
 
@@ -323,6 +369,7 @@ func (d *driver) CreateAgentEndpoint(ctx context.Context, name string, spec ngro
 	).Info("Created agent endpoint")
 
 	d.forwarders.Add(name, epf)
+	go d.watch(name, epf)
 
 	result := &EndpointResult{
 		URL:           epf.URL().String(),
@@ -342,16 +389,43 @@ func (d *driver) DeleteAgentEndpoint(ctx context.Context, name string) error {
 		return nil
 	}
 
+	// Remove the forwarder before closing it so that its watcher doesn't report it as closed
+	d.forwarders.Remove(name, epf)
 	if err := epf.CloseWithContext(ctx); err != nil {
 		log.Error(err, "Error closing agent endpoint")
+		d.forwarders.Add(name, epf)
 		return err
 	}
 
-	d.forwarders.Delete(name)
 	log.Info("AgentEndpoint deleted successfully")
 	return nil
 }
 
+func (d *driver) Events() <-chan ForwarderEvent {
+	return d.events.Events()
+}
+
+// watch waits for the forwarder of the agent endpoint name to stop, and reports it as closed unless it was
+// deleted or replaced in the meantime
+func (d *driver) watch(name string, epf ngrok.EndpointForwarder) {
+	<-epf.Done()
+	if d.forwarders.Remove(name, epf) {
+		d.publish(ForwarderEvent{Name: name, Type: ForwarderClosed, Err: errors.New("the endpoint stopped forwarding connections")})
+	}
+}
+
+// publishAll sends an event of type eventType for every agent endpoint
+func (d *driver) publishAll(eventType ForwarderEventType, err error) {
+	for _, name := range d.forwarders.Names() {
+		d.publish(ForwarderEvent{Name: name, Type: eventType, Err: err})
+	}
+}
+
+// publish queues event for the Events channel without blocking the caller, which may be the agent's event handler
+func (d *driver) publish(event ForwarderEvent) {
+	d.events.Publish(event)
+}
+
 func buildUpstream(upstreamSpec ngrokv1alpha1.EndpointUpstream, clientCerts []tls.Certificate) *ngrok.Upstream {
 	upstreamTLSConfig := buildUpstreamTLSConfig(clientCerts)
 	upstreamOpts := []ngrok.UpstreamOption{
@@ -3,8 +3,23 @@ package agent
 import (
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.ngrok.com/ngrok/v2"
 )
 
+// fakeForwarder is an endpoint forwarder that stops when done is closed
+type fakeForwarder struct {
+	ngrok.EndpointForwarder
+	done chan struct{}
+}
+
+func (f *fakeForwarder) Done() <-chan struct{} {
+	return f.done
+}
+
 func TestDriverCloseOnceNoPanic(t *testing.T) {
 	d := &driver{
 		done: make(chan bool),
@@ -28,3 +43,62 @@ func TestDriverCloseOnceNoPanic(t *testing.T) {
 		t.Fatal("expected d.done to be closed")
 	}
 }
+
+func TestDriverWatchReportsClosedForwarder(t *testing.T) {
+	d := &driver{
+		done:       make(chan bool),
+		forwarders: newEndpointForwarderMap(),
+	}
+	d.events = newForwarderEventQueue(d.done)
+	defer close(d.done)
+
+	closed := &fakeForwarder{done: make(chan struct{})}
+	d.forwarders.Add("default/closed", closed)
+	go d.watch("default/closed", closed)
+
+	// A forwarder removed before it stops was deleted or replaced, which isn't reported
+	deleted := &fakeForwarder{done: make(chan struct{})}
+	d.forwarders.Add("default/deleted", deleted)
+	go d.watch("default/deleted", deleted)
+	require.True(t, d.forwarders.Remove("default/deleted", deleted))
+	close(deleted.done)
+
+	close(closed.done)
+	select {
+	case event := <-d.Events():
+		assert.Equal(t, "default/closed", event.Name)
+		assert.Equal(t, ForwarderClosed, event.Type)
+		assert.Error(t, event.Err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a ForwarderClosed event")
+	}
+	_, ok := d.forwarders.Get("default/closed")
+	assert.False(t, ok)
+
+	select {
+	case event := <-d.Events():
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDriverPublishAll(t *testing.T) {
+	d := &driver{
+		done:       make(chan bool),
+		forwarders: newEndpointForwarderMap(),
+	}
+	d.events = newForwarderEventQueue(d.done)
+	defer close(d.done)
+	d.forwarders.Add("default/a", nil)
+	d.forwarders.Add("default/b", nil)
+
+	d.publishAll(ForwarderReconnected, nil)
+
+	var names []string
+	for range 2 {
+		event := <-d.Events()
+		assert.Equal(t, ForwarderReconnected, event.Type)
+		names = append(names, event.Name)
+	}
+	assert.ElementsMatch(t, []string{"default/a", "default/b"}, names)
+}
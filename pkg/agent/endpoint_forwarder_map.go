@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"maps"
+	"slices"
 	"sync"
 
 	"golang.ngrok.com/ngrok/v2"
@@ -35,3 +37,21 @@ func (a *endpointForwarderMap) Delete(name string) {
 	defer a.mu.Unlock()
 	delete(a.m, name)
 }
+
+// Remove deletes name only if it still maps to ep, and reports whether it did
+func (a *endpointForwarderMap) Remove(name string, ep ngrok.EndpointForwarder) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if current, ok := a.m[name]; !ok || current != ep {
+		return false
+	}
+	delete(a.m, name)
+	return true
+}
+
+// Names returns the names of all endpoint forwarders
+func (a *endpointForwarderMap) Names() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Collect(maps.Keys(a.m))
+}
@@ -23,3 +23,16 @@ func TestEndpointForwarderMap(t *testing.T) {
 	assert.False(t, ok)
 	assert.Nil(t, ep)
 }
+
+func TestEndpointForwarderMapRemove(t *testing.T) {
+	m := newEndpointForwarderMap()
+	old, current := &fakeForwarder{}, &fakeForwarder{}
+
+	m.Add("test", current)
+	assert.False(t, m.Remove("test", old))
+	assert.False(t, m.Remove("other", current))
+	assert.Equal(t, []string{"test"}, m.Names())
+
+	assert.True(t, m.Remove("test", current))
+	assert.Empty(t, m.Names())
+}
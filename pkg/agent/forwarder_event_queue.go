@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"sync"
+)
+
+// forwarderEventQueue sends the published forwarder events on a channel from a single goroutine, so that publishing
+// never blocks. Only the latest pending event of each agent endpoint is kept, which bounds the queue by the number of
+// endpoints.
+type forwarderEventQueue struct {
+	out  chan ForwarderEvent
+	wake chan struct{}
+
+	mu      sync.Mutex
+	order   []string
+	pending map[string]ForwarderEvent
+}
+
+// newForwarderEventQueue returns a queue whose dispatcher runs until done is closed. Events that haven't been
+// received by then are dropped.
+func newForwarderEventQueue(done <-chan bool) *forwarderEventQueue {
+	q := &forwarderEventQueue{
+		out:     make(chan ForwarderEvent),
+		wake:    make(chan struct{}, 1),
+		pending: make(map[string]ForwarderEvent),
+	}
+	go q.dispatch(done)
+	return q
+}
+
+// Events returns the channel the events are sent on
+func (q *forwarderEventQueue) Events() <-chan ForwarderEvent {
+	return q.out
+}
+
+// Publish queues event, replacing the pending event of the same agent endpoint if there is one
+func (q *forwarderEventQueue) Publish(event ForwarderEvent) {
+	q.mu.Lock()
+	if _, ok := q.pending[event.Name]; !ok {
+		q.order = append(q.order, event.Name)
+	}
+	q.pending[event.Name] = event
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next removes and returns the oldest pending event
+func (q *forwarderEventQueue) next() (ForwarderEvent, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.order) == 0 {
+		return ForwarderEvent{}, false
+	}
+	name := q.order[0]
+	q.order = q.order[1:]
+	event := q.pending[name]
+	delete(q.pending, name)
+	return event, true
+}
+
+func (q *forwarderEventQueue) dispatch(done <-chan bool) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-q.wake:
+		}
+
+		for event, ok := q.next(); ok; event, ok = q.next() {
+			select {
+			case q.out <- event:
+			case <-done:
+				return
+			}
+		}
+	}
+}
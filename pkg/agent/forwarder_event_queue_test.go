@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwarderEventQueueKeepsLatestEvent(t *testing.T) {
+	q := &forwarderEventQueue{
+		wake:    make(chan struct{}, 1),
+		pending: make(map[string]ForwarderEvent),
+	}
+
+	// Publishing doesn't block without a dispatcher, and replaces the pending event of the same endpoint
+	for range 1000 {
+		q.Publish(ForwarderEvent{Name: "default/a", Type: ForwarderErrored})
+	}
+	q.Publish(ForwarderEvent{Name: "default/b", Type: ForwarderClosed})
+	q.Publish(ForwarderEvent{Name: "default/a", Type: ForwarderReconnected})
+
+	event, ok := q.next()
+	assert.True(t, ok)
+	assert.Equal(t, ForwarderEvent{Name: "default/a", Type: ForwarderReconnected}, event)
+	event, ok = q.next()
+	assert.True(t, ok)
+	assert.Equal(t, ForwarderEvent{Name: "default/b", Type: ForwarderClosed}, event)
+	_, ok = q.next()
+	assert.False(t, ok)
+}
+
+func TestForwarderEventQueueDispatch(t *testing.T) {
+	done := make(chan bool)
+	defer close(done)
+	q := newForwarderEventQueue(done)
+
+	q.Publish(ForwarderEvent{Name: "default/a", Type: ForwarderClosed})
+	select {
+	case event := <-q.Events():
+		assert.Equal(t, ForwarderEvent{Name: "default/a", Type: ForwarderClosed}, event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a ForwarderClosed event")
+	}
+
+	select {
+	case event := <-q.Events():
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -13,6 +13,7 @@ type MockAgentDriver struct {
 	createResults map[string]*EndpointResult // keyed by endpoint name
 	createErrors  map[string]error           // keyed by endpoint name
 	deleteErrors  map[string]error           // keyed by endpoint name
+	events        chan ForwarderEvent
 
 	// Default fallback values
 	DefaultResult *EndpointResult
@@ -44,6 +45,7 @@ func NewMockAgentDriver() *MockAgentDriver {
 		createResults: make(map[string]*EndpointResult),
 		createErrors:  make(map[string]error),
 		deleteErrors:  make(map[string]error),
+		events:        make(chan ForwarderEvent, 16),
 	}
 }
 
@@ -105,6 +107,16 @@ func (m *MockAgentDriver) DeleteAgentEndpoint(_ context.Context, name string) er
 	return m.DeleteError
 }
 
+// Events implements Driver interface
+func (m *MockAgentDriver) Events() <-chan ForwarderEvent {
+	return m.events
+}
+
+// SendEvent sends a forwarder lifecycle event as if it came from the agent
+func (m *MockAgentDriver) SendEvent(event ForwarderEvent) {
+	m.events <- event
+}
+
 // Ready implements healthcheck.HealthChecker interface
 func (m *MockAgentDriver) Ready(_ context.Context, _ *http.Request) error {
 	return nil
@@ -8,6 +8,8 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/util"
 	"golang.org/x/sync/errgroup"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -27,8 +29,6 @@ func ingressToDomains(in *netv1.Ingress, newDomainMetadata string, existingDomai
 			continue
 		}
 		if _, found := existingDomains[domainName]; found {
-			// TODO update ingress status
-			// also add error to error page
 			continue
 		}
 
@@ -64,8 +64,6 @@ func gatewayToDomains(in *gatewayv1.Gateway, newDomainMetadata string, existingD
 			continue
 		}
 		if _, found := existingDomains[domainName]; found {
-			// TODO update gateway status
-			// also add error to error page
 			continue
 		}
 
@@ -86,6 +84,51 @@ func gatewayToDomains(in *gatewayv1.Gateway, newDomainMetadata string, existingD
 	return endpointDomains
 }
 
+// domainProblemConditions are the Domain conditions checked, in order, for the reason a domain can't serve traffic.
+// DNS and certificate problems come before the Ready condition that summarizes them since they are more specific.
+var domainProblemConditions = []string{"DNSConfigured", "CertificateReady", "DomainCreated", "Ready"}
+
+// domainStatusReasonPrefix prefixes Domain condition reasons reported in the port status of Ingresses, which must be
+// domain-prefixed for implementation-specific errors
+const domainStatusReasonPrefix = "ngrok.com/"
+
+// domainProblem returns the condition that best explains why the domain can't serve traffic yet, or nil when it can
+// or it hasn't been reconciled yet
+func domainProblem(domain ingressv1alpha1.Domain) *metav1.Condition {
+	for _, conditionType := range domainProblemConditions {
+		cond := meta.FindStatusCondition(domain.Status.Conditions, conditionType)
+		if cond != nil && cond.Status == metav1.ConditionFalse {
+			return cond
+		}
+	}
+	return nil
+}
+
+// findDomainForHost returns the domain serving the host, which is the host's own domain or the most specific wildcard
+// domain covering it
+func findDomainForHost(host string, domains map[string]ingressv1alpha1.Domain, wildcards []string) (ingressv1alpha1.Domain, bool) {
+	if d, ok := domains[host]; ok {
+		return d, true
+	}
+	// Hosts covered by a wildcard domain are served through the wildcard domain
+	wildcard := coveringWildcardHost(host, wildcards)
+	if wildcard == "" {
+		return ingressv1alpha1.Domain{}, false
+	}
+	return domains[wildcard], true
+}
+
+// wildcardDomainNames returns the names of the wildcard domains
+func wildcardDomainNames(domains map[string]ingressv1alpha1.Domain) []string {
+	wildcards := []string{}
+	for domainName := range domains {
+		if isWildcardHost(domainName) {
+			wildcards = append(wildcards, domainName)
+		}
+	}
+	return wildcards
+}
+
 // applyDomains takes a set of the desired domains and current domains, creates any missing desired domains, and updated existing domains if needed
 func (d *Driver) applyDomains(ctx context.Context, c client.Client, desiredDomains map[string]ingressv1alpha1.Domain) error {
 	var g errgroup.Group
@@ -99,9 +142,14 @@ func (d *Driver) applyDomains(ctx context.Context, c client.Client, desiredDomai
 
 			res, err := controllerutil.CreateOrPatch(ctx, c, domain, func() error {
 				domain.Spec.Domain = desiredDomain.Spec.Domain
-				// Only set the reclaim policy on create
-				if domain.CreationTimestamp.IsZero() && d.defaultDomainReclaimPolicy != nil {
-					domain.Spec.ReclaimPolicy = *d.defaultDomainReclaimPolicy
+				switch policy := d.domainReclaimPolicy(); {
+				// The domain is shared with the other clusters, so deleting it in one cluster must not release
+				// the reservation the others still serve
+				case d.multiCluster != nil:
+					domain.Spec.ReclaimPolicy = ingressv1alpha1.DomainReclaimPolicyRetain
+				// Otherwise only set the reclaim policy on create
+				case domain.CreationTimestamp.IsZero() && policy != nil:
+					domain.Spec.ReclaimPolicy = *policy
 				}
 				// Set controller labels inside the mutate so the call covers both
 				// create and patch: CreateOrPatch's Get overwrites anything set on
@@ -142,6 +190,7 @@ type domainSet struct {
 }
 
 func (d *Driver) calculateDomainSet() *domainSet {
+	ingressNgrokMetadata, gatewayNgrokMetadata := d.ngrokMetadata()
 	ret := &domainSet{
 		endpointIngressDomains: make(map[string]ingressv1alpha1.Domain),
 		endpointGatewayDomains: make(map[string]ingressv1alpha1.Domain),
@@ -151,7 +200,7 @@ func (d *Driver) calculateDomainSet() *domainSet {
 	// Calculate domains from ingress resources
 	ingresses := d.store.ListNgrokIngressesV1()
 	for _, ingress := range ingresses {
-		endpointDomains := ingressToDomains(ingress, d.ingressNgrokMetadata, nil)
+		endpointDomains := ingressToDomains(ingress, ingressNgrokMetadata, nil)
 		for key, val := range endpointDomains {
 			ret.totalDomains[key] = val
 			ret.endpointIngressDomains[key] = val
@@ -161,11 +210,37 @@ func (d *Driver) calculateDomainSet() *domainSet {
 	// Calculate domains from gateway resources
 	gateways := d.store.ListNgrokGateways()
 	for _, gateway := range gateways {
-		endpointDomains := gatewayToDomains(gateway, d.gatewayNgrokMetadata, ret.totalDomains)
+		endpointDomains := gatewayToDomains(gateway, gatewayNgrokMetadata, ret.totalDomains)
 		for key, val := range endpointDomains {
 			ret.totalDomains[key] = val
 			ret.endpointGatewayDomains[key] = val
 		}
 	}
+
+	ret.removeWildcardCoveredDomains()
 	return ret
 }
+
+// removeWildcardCoveredDomains removes domains for hostnames that are covered by a wildcard domain in the same
+// namespace, since requests for them are routed by the wildcard's endpoint. A hostname that still gets its own
+// endpoint has its domain reserved by the endpoint controllers instead.
+func (s *domainSet) removeWildcardCoveredDomains() {
+	wildcardsByNamespace := make(map[string][]string)
+	for domainName, domain := range s.totalDomains {
+		if isWildcardHost(domainName) {
+			wildcardsByNamespace[domain.Namespace] = append(wildcardsByNamespace[domain.Namespace], domainName)
+		}
+	}
+	if len(wildcardsByNamespace) == 0 {
+		return
+	}
+
+	for domainName, domain := range s.totalDomains {
+		if isWildcardHost(domainName) || coveringWildcardHost(domainName, wildcardsByNamespace[domain.Namespace]) == "" {
+			continue
+		}
+		delete(s.totalDomains, domainName)
+		delete(s.endpointIngressDomains, domainName)
+		delete(s.endpointGatewayDomains, domainName)
+	}
+}
@@ -1,15 +1,25 @@
 package managerdriver
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 	"github.com/ngrok/ngrok-operator/internal/testutils"
 )
 
@@ -190,3 +200,84 @@ func TestGatewayToDomains_SkipsNilHostname(t *testing.T) {
 	_, found := result["example.com"]
 	assert.True(t, found)
 }
+
+func TestDomainSet_RemoveWildcardCoveredDomains(t *testing.T) {
+	newDomain := func(domain, namespace string) ingressv1alpha1.Domain {
+		return ingressv1alpha1.Domain{
+			Name:      ingressv1alpha1.HyphenatedDomainNameFromURL(domain),
+			Namespace: namespace,
+			Spec:      ingressv1alpha1.DomainSpec{Domain: domain},
+		}
+	}
+
+	set := &domainSet{
+		endpointIngressDomains: map[string]ingressv1alpha1.Domain{
+			"*.example.com":   newDomain("*.example.com", "default"),
+			"app.example.com": newDomain("app.example.com", "default"),
+			"example.com":     newDomain("example.com", "default"),
+		},
+		endpointGatewayDomains: map[string]ingressv1alpha1.Domain{
+			"*.api.example.com": newDomain("*.api.example.com", "default"),
+			"web.example.com":   newDomain("web.example.com", "other"),
+		},
+	}
+	set.totalDomains = map[string]ingressv1alpha1.Domain{}
+	for key, val := range set.endpointIngressDomains {
+		set.totalDomains[key] = val
+	}
+	for key, val := range set.endpointGatewayDomains {
+		set.totalDomains[key] = val
+	}
+
+	set.removeWildcardCoveredDomains()
+
+	// Covered hostnames in the same namespace are removed. Narrower wildcards, the base domain of a wildcard and
+	// hostnames in other namespaces keep their own domains.
+	assert.ElementsMatch(t, []string{"*.example.com", "*.api.example.com", "example.com", "web.example.com"}, slices.Collect(maps.Keys(set.totalDomains)))
+	assert.ElementsMatch(t, []string{"*.example.com", "example.com"}, slices.Collect(maps.Keys(set.endpointIngressDomains)))
+	assert.ElementsMatch(t, []string{"*.api.example.com", "web.example.com"}, slices.Collect(maps.Keys(set.endpointGatewayDomains)))
+}
+
+func TestDriverUsesOperatorConfig(t *testing.T) {
+	static := NewDriver(logr.Discard(), runtime.NewScheme(), "ngrok", types.NamespacedName{Namespace: "ngrok-op", Name: "ngrok"},
+		WithGatewayEnabled(true),
+		WithDefaultDomainReclaimPolicy(ingressv1alpha1.DomainReclaimPolicyDelete),
+	).WithNgrokMetadata(map[string]string{"cluster": "prod"})
+	ingressMetadata, gatewayMetadata := static.ngrokMetadata()
+	assert.JSONEq(t, `{"owned-by":"ngrok-operator","cluster":"prod"}`, ingressMetadata)
+	assert.JSONEq(t, `{"owned-by":"kubernetes-gateway-api","cluster":"prod"}`, gatewayMetadata)
+	assert.Equal(t, ptr.To(ingressv1alpha1.DomainReclaimPolicyDelete), static.domainReclaimPolicy())
+
+	config := operatorconfig.NewStore(operatorconfig.Defaults{
+		DefaultDomainReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyRetain,
+		NgrokMetadata:              map[string]string{"team": "platform"},
+	})
+	live := NewDriver(logr.Discard(), runtime.NewScheme(), "ngrok", types.NamespacedName{Namespace: "ngrok-op", Name: "ngrok"},
+		WithDefaultDomainReclaimPolicy(ingressv1alpha1.DomainReclaimPolicyDelete),
+		WithOperatorConfig(config),
+	).WithNgrokMetadata(map[string]string{"cluster": "prod"})
+	ingressMetadata, gatewayMetadata = live.ngrokMetadata()
+	assert.JSONEq(t, `{"owned-by":"ngrok-operator","team":"platform"}`, ingressMetadata)
+	assert.Empty(t, gatewayMetadata, "gateway metadata is only set with the gateway feature")
+	assert.Equal(t, ptr.To(ingressv1alpha1.DomainReclaimPolicyRetain), live.domainReclaimPolicy())
+
+	// The ID of the KubernetesOperator is added once it is registered
+	scheme := runtime.NewScheme()
+	require.NoError(t, ngrokv1alpha1.AddToScheme(scheme))
+	ko := &ngrokv1alpha1.KubernetesOperator{
+		Name:      "ngrok",
+		Namespace: "ngrok-op",
+		Status:    ngrokv1alpha1.KubernetesOperatorStatus{ID: "k8sop_123"},
+	}
+	w := &operatorconfig.Watcher{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(ko).Build(),
+		Log:       logr.Discard(),
+		Store:     config,
+		Namespace: ko.Namespace,
+		Name:      ko.Name,
+	}
+	_, err := w.Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name}})
+	require.NoError(t, err)
+	ingressMetadata, _ = live.ngrokMetadata()
+	assert.JSONEq(t, `{"owned-by":"ngrok-operator","team":"platform","k8s-operator-id":"k8sop_123"}`, ingressMetadata)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net"
 	"reflect"
 	"sort"
 	"strings"
@@ -36,7 +37,9 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/controller/labels"
 	"github.com/ngrok/ngrok-operator/internal/drain"
 	"github.com/ngrok/ngrok-operator/internal/errors"
+	"github.com/ngrok/ngrok-operator/internal/operatorconfig"
 	"github.com/ngrok/ngrok-operator/internal/store"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	"github.com/ngrok/ngrok-operator/internal/util"
 )
 
@@ -66,10 +69,29 @@ type Driver struct {
 	disableGatewayReferenceGrants bool
 	gatewayControllerName         string
 
+	// ingressNginxCompatibility enables translating supported nginx.ingress.kubernetes.io annotations
+	ingressNginxCompatibility bool
+
+	// ingressSecretAnnotations enables the feature annotations that copy credentials from a Secret into the
+	// traffic policy of the generated endpoints
+	ingressSecretAnnotations bool
+
+	// multiCluster shares the endpoints for Ingress and Gateway hostnames with other clusters when set
+	multiCluster *MultiCluster
+
 	defaultDomainReclaimPolicy *ingressv1alpha1.DomainReclaimPolicy
 
+	// operatorConfig, when set, provides the default domain reclaim policy and custom ngrok metadata from the
+	// KubernetesOperator instead of the static ones
+	operatorConfig *operatorconfig.Store
+
 	recorder events.EventRecorder
 
+	// recordedWarnings are the translation warnings found by the last translation, which have
+	// already been recorded as events
+	warningsMu       sync.Mutex
+	recordedWarnings map[translationWarningKey]struct{}
+
 	// drainState is used to check if the operator is draining.
 	// If draining, Sync() returns early to prevent creating new resources.
 	drainState drain.State
@@ -98,6 +120,25 @@ func WithDisableGatewayReferenceGrants(disable bool) DriverOpt {
 	}
 }
 
+func WithIngressNginxCompatibility(enabled bool) DriverOpt {
+	return func(d *Driver) {
+		d.ingressNginxCompatibility = enabled
+	}
+}
+
+func WithIngressSecretAnnotations(enabled bool) DriverOpt {
+	return func(d *Driver) {
+		d.ingressSecretAnnotations = enabled
+	}
+}
+
+// WithMultiCluster enables multi-cluster mode, see MultiCluster
+func WithMultiCluster(mc *MultiCluster) DriverOpt {
+	return func(d *Driver) {
+		d.multiCluster = mc
+	}
+}
+
 func WithSyncAllowConcurrent(allowed bool) DriverOpt {
 	return func(d *Driver) {
 		d.syncAllowConcurrent = allowed
@@ -128,6 +169,14 @@ func WithDefaultDomainReclaimPolicy(policy ingressv1alpha1.DomainReclaimPolicy)
 	}
 }
 
+// WithOperatorConfig makes the driver read the default domain reclaim policy and the custom ngrok metadata from the
+// operator configuration on each sync, instead of those set by WithDefaultDomainReclaimPolicy and WithNgrokMetadata
+func WithOperatorConfig(config *operatorconfig.Store) DriverOpt {
+	return func(d *Driver) {
+		d.operatorConfig = config
+	}
+}
+
 func WithEventRecorder(recorder events.EventRecorder) DriverOpt {
 	return func(d *Driver) {
 		d.recorder = recorder
@@ -187,6 +236,43 @@ func (d *Driver) WithNgrokMetadata(customNgrokMetadata map[string]string) *Drive
 	return d
 }
 
+// ngrokMetadata returns the ngrok metadata of the resources created for Ingresses and Gateways
+func (d *Driver) ngrokMetadata() (ingress string, gateway string) {
+	if d.operatorConfig == nil {
+		return d.ingressNgrokMetadata, d.gatewayNgrokMetadata
+	}
+
+	customNgrokMetadata := maps.Clone(d.operatorConfig.Get().NgrokMetadata)
+	// The ID of the KubernetesOperator identifies the resources of this installation to the garbage collector
+	if id := d.operatorConfig.OperatorID(); id != "" {
+		if customNgrokMetadata == nil {
+			customNgrokMetadata = map[string]string{}
+		}
+		customNgrokMetadata[operatorconfig.OperatorIDMetadataKey] = id
+	}
+	ingress, err := d.setNgrokMetadataOwner("ngrok-operator", customNgrokMetadata)
+	if err != nil {
+		d.log.Error(err, "error marshalling custom ngrokmetadata", "customNgrokMetadata", customNgrokMetadata)
+		return d.ingressNgrokMetadata, d.gatewayNgrokMetadata
+	}
+	if d.gatewayEnabled {
+		gateway, err = d.setNgrokMetadataOwner("kubernetes-gateway-api", customNgrokMetadata)
+		if err != nil {
+			d.log.Error(err, "error marshalling custom ngrokmetadata", "customNgrokMetadata", customNgrokMetadata)
+			return d.ingressNgrokMetadata, d.gatewayNgrokMetadata
+		}
+	}
+	return ingress, gateway
+}
+
+// domainReclaimPolicy returns the reclaim policy of the Domains the driver creates, if any
+func (d *Driver) domainReclaimPolicy() *ingressv1alpha1.DomainReclaimPolicy {
+	if d.operatorConfig != nil {
+		return new(d.operatorConfig.Get().DefaultDomainReclaimPolicy)
+	}
+	return d.defaultDomainReclaimPolicy
+}
+
 // Useful for tests
 func (d *Driver) GetStore() store.Storer {
 	return d.store
@@ -272,6 +358,14 @@ func listObjectsForType(ctx context.Context, client client.Reader, v any, listOp
 		domains := &ingressv1alpha1.DomainList{}
 		err := client.List(ctx, domains, listOpts...)
 		return util.ToClientObjects(domains.Items), err
+	case *ingressv1alpha1.IPPolicy:
+		ipPolicies := &ingressv1alpha1.IPPolicyList{}
+		err := client.List(ctx, ipPolicies, listOpts...)
+		return util.ToClientObjects(ipPolicies.Items), err
+	case *ingressv1alpha1.TCPAddress:
+		tcpAddresses := &ingressv1alpha1.TCPAddressList{}
+		err := client.List(ctx, tcpAddresses, listOpts...)
+		return util.ToClientObjects(tcpAddresses.Items), err
 	case *ngrokv1alpha1.NgrokTrafficPolicy:
 		policies := &ngrokv1alpha1.NgrokTrafficPolicyList{}
 		err := client.List(ctx, policies, listOpts...)
@@ -303,6 +397,8 @@ func listObjectsForType(ctx context.Context, client client.Reader, v any, listOp
 // - Namespaces
 // - ConfigMaps
 // - Domains
+// - IPPolicies
+// - TCPAddresses
 // - Edges
 // - Tunnels
 // - ModuleSets
@@ -320,6 +416,7 @@ func (d *Driver) Seed(ctx context.Context, c client.Reader, listOpts ...client.L
 		&corev1.ConfigMap{},
 		// CRDs
 		&ingressv1alpha1.Domain{},
+		&ingressv1alpha1.IPPolicy{},
 		&ngrokv1alpha1.NgrokTrafficPolicy{},
 		&ngrokv1alpha1.AgentEndpoint{},
 		&ngrokv1alpha1.CloudEndpoint{},
@@ -331,6 +428,7 @@ func (d *Driver) Seed(ctx context.Context, c client.Reader, listOpts ...client.L
 			&gatewayv1.GatewayClass{},
 			&gatewayv1.HTTPRoute{},
 			&gatewayv1beta1.ReferenceGrant{},
+			&ingressv1alpha1.TCPAddress{},
 		)
 
 		if d.gatewayTCPRouteEnabled {
@@ -416,6 +514,10 @@ func (d *Driver) UpdateReferenceGrant(referenceGrant *gatewayv1beta1.ReferenceGr
 	return d.store.GetReferenceGrant(referenceGrant.Name, referenceGrant.Namespace)
 }
 
+func (d *Driver) UpdateSecret(secret *corev1.Secret) error {
+	return d.store.Update(secret)
+}
+
 func (d *Driver) UpdateNamespace(namespace *corev1.Namespace) (*corev1.Namespace, error) {
 	if err := d.store.Update(namespace); err != nil {
 		return nil, err
@@ -493,6 +595,13 @@ func (d *Driver) DeleteReferenceGrant(n types.NamespacedName) error {
 	return d.cacheStores.Delete(referenceGrant)
 }
 
+func (d *Driver) DeleteNamedSecret(n types.NamespacedName) error {
+	secret := &corev1.Secret{}
+	secret.SetNamespace(n.Namespace)
+	secret.SetName(n.Name)
+	return d.cacheStores.Delete(secret)
+}
+
 func (d *Driver) DeleteNamespace(name string) error {
 	namespace := &corev1.Namespace{}
 	namespace.SetName(name)
@@ -626,16 +735,22 @@ func (d *Driver) Sync(ctx context.Context, c client.Client) error {
 	// TODO (Alice): move domains, edges, tunnels to translator
 	domains := d.calculateDomainSet()
 
+	ingressNgrokMetadata, gatewayNgrokMetadata := d.ngrokMetadata()
+
 	translator := NewTranslator(
 		d.log,
 		d.store,
 		d.controllerLabels.Labels(),
-		d.ingressNgrokMetadata,
-		d.gatewayNgrokMetadata,
+		ingressNgrokMetadata,
+		gatewayNgrokMetadata,
 		d.clusterDomain,
 		d.disableGatewayReferenceGrants,
+		d.ingressNginxCompatibility,
+		d.ingressSecretAnnotations,
+		d.multiCluster,
 	)
 	translationResult := translator.Translate()
+	d.recordTranslationWarnings(translationResult.Warnings)
 
 	// LEGACY-PREFIX-MIGRATION: BEGIN
 	// listAgentEndpointsForController / listCloudEndpointsForController
@@ -770,6 +885,46 @@ func (d *Driver) updateIngressStatuses(ctx context.Context, c client.Client) err
 	return g.Wait()
 }
 
+// translationWarningKey identifies a warning on an object across translations
+type translationWarningKey struct {
+	objType   string
+	uid       types.UID
+	namespace string
+	name      string
+	reason    string
+	message   string
+}
+
+// recordTranslationWarnings records the warnings found while translating resources as events on them.
+// Every sync translates all resources again, so only the warnings that the previous translation didn't
+// find are recorded. A warning that goes away and comes back is recorded again.
+func (d *Driver) recordTranslationWarnings(warnings []TranslationWarning) {
+	if d.recorder == nil {
+		return
+	}
+
+	d.warningsMu.Lock()
+	defer d.warningsMu.Unlock()
+
+	current := make(map[translationWarningKey]struct{}, len(warnings))
+	for _, w := range warnings {
+		key := translationWarningKey{
+			objType:   fmt.Sprintf("%T", w.Object),
+			uid:       w.Object.GetUID(),
+			namespace: w.Object.GetNamespace(),
+			name:      w.Object.GetName(),
+			reason:    w.Reason,
+			message:   w.Message,
+		}
+		current[key] = struct{}{}
+		if _, ok := d.recordedWarnings[key]; ok {
+			continue
+		}
+		d.recorder.Eventf(w.Object, nil, corev1.EventTypeWarning, w.Reason, "Translate", "%s", w.Message)
+	}
+	d.recordedWarnings = current
+}
+
 // recordDomainEventsForIngress records events to the ingress based on the Ready condition
 // of its associated domains. This helps users understand domain-related issues (e.g., using
 // an invalid domain on a free account) without needing to inspect Domain CRs directly.
@@ -811,6 +966,7 @@ func (d *Driver) updateGatewayStatuses(ctx context.Context, c client.Client) err
 		return err
 	}
 
+	wildcards := wildcardDomainNames(domains)
 	needsUpdate := []*gatewayv1.Gateway{}
 
 	for _, gateway := range d.store.ListNgrokGateways() {
@@ -847,6 +1003,20 @@ func (d *Driver) updateGatewayStatuses(ctx context.Context, c client.Client) err
 			}
 		}
 
+		// Reserved TCP addresses are reachable at their reserved host
+		for _, gatewayAddress := range gateway.Spec.Addresses {
+			if gatewayAddress.Type == nil || *gatewayAddress.Type != TCPAddressGatewayAddressType {
+				continue
+			}
+			tcpAddress, err := d.store.GetTCPAddressV1(gatewayAddress.Value, gateway.Namespace)
+			if err != nil || tcpAddress.Status.Address == "" {
+				continue
+			}
+			if host, _, err := net.SplitHostPort(tcpAddress.Status.Address); err == nil {
+				addresses[host] = struct{}{}
+			}
+		}
+
 		for addr := range addresses {
 			newStatus.Addresses = append(newStatus.Addresses, gatewayv1.GatewayStatusAddress{
 				Type:  ptr.To(gatewayv1.HostnameAddressType),
@@ -858,25 +1028,61 @@ func (d *Driver) updateGatewayStatuses(ctx context.Context, c client.Client) err
 			return newStatus.Addresses[i].Value < newStatus.Addresses[j].Value
 		})
 
+		listenerHostnames := map[gatewayv1.SectionName]string{}
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.Hostname != nil {
+				listenerHostnames[listener.Name] = string(*listener.Hostname)
+			}
+		}
+
+		programmedListeners, pendingListeners := 0, 0
 		for i := range newStatus.Listeners {
 			listener := &newStatus.Listeners[i]
 			if meta.IsStatusConditionFalse(listener.Conditions, string(gatewayv1.ListenerConditionAccepted)) {
 				continue
 			}
 
-			meta.SetStatusCondition(&listener.Conditions, metav1.Condition{
+			// Listeners whose domain can't serve traffic yet report why on their Programmed condition
+			programmed := metav1.Condition{
 				Type:               string(gatewayv1.ListenerConditionProgrammed),
 				Status:             metav1.ConditionTrue,
 				Reason:             string(gatewayv1.ListenerReasonProgrammed),
 				ObservedGeneration: gateway.Generation,
-			})
+			}
+			if hostname, ok := listenerHostnames[listener.Name]; ok {
+				if domain, ok := findDomainForHost(hostname, domains, wildcards); ok {
+					if problem := domainProblem(domain); problem != nil {
+						programmed.Status = metav1.ConditionFalse
+						programmed.Reason = problem.Reason
+						programmed.Message = fmt.Sprintf("Domain %q is not ready: %s", hostname, problem.Message)
+					}
+				}
+			}
+			meta.SetStatusCondition(&listener.Conditions, programmed)
 
+			if programmed.Status == metav1.ConditionTrue {
+				programmedListeners++
+			} else {
+				pendingListeners++
+			}
+		}
+
+		switch {
+		case programmedListeners > 0:
 			meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
 				Type:               string(gatewayv1.GatewayConditionProgrammed),
 				Status:             metav1.ConditionTrue,
 				Reason:             string(gatewayv1.GatewayReasonProgrammed),
 				ObservedGeneration: gateway.Generation,
 			})
+		case pendingListeners > 0:
+			meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+				Type:               string(gatewayv1.GatewayConditionProgrammed),
+				Status:             metav1.ConditionFalse,
+				Reason:             string(gatewayv1.GatewayReasonPending),
+				Message:            "Waiting for the domains of all listeners to be ready",
+				ObservedGeneration: gateway.Generation,
+			})
 		}
 
 		if reflect.DeepEqual(gateway.Status, newStatus) {
@@ -1061,14 +1267,6 @@ func (d *Driver) createEndpointPolicyForGateway(rule *gatewayv1.HTTPRouteRule, n
 	return policy, nil
 }
 
-type RemoveHeadersConfig struct {
-	Headers []string `json:"headers"`
-}
-
-type AddHeadersConfig struct {
-	Headers map[string]string `json:"headers"`
-}
-
 func (d *Driver) handleExtensionRef(extensionRef *gatewayv1.LocalObjectReference, namespace string, trafficPolicy util.TrafficPolicy) error {
 	switch extensionRef.Kind {
 	case "NgrokTrafficPolicy":
@@ -1117,25 +1315,7 @@ func (d *Driver) handleHTTPHeaderFilterRemove(headersToRemove []string, actions
 		return nil
 	}
 
-	removeHeaders, err := json.Marshal(RemoveHeadersConfig{Headers: headersToRemove})
-	if err != nil {
-		d.log.Error(err, "cannot convert headers to json", "headers", headersToRemove)
-		return err
-	}
-
-	action := util.EndpointAction{
-		Type:   "remove-headers",
-		Config: removeHeaders,
-	}
-
-	rawAction, err := json.Marshal(&action)
-	if err != nil {
-		return err
-	}
-
-	actions.EndpointActions = append(actions.EndpointActions, rawAction)
-
-	return nil
+	return d.appendAction(trafficpolicy.NewRemoveHeadersAction(headersToRemove), actions)
 }
 
 func (d *Driver) handleHTTPHeaderFilterAdd(headersToAdd []gatewayv1.HTTPHeader, actions *util.Actions, requestRedirectHeaders map[string]string) error {
@@ -1143,34 +1323,16 @@ func (d *Driver) handleHTTPHeaderFilterAdd(headersToAdd []gatewayv1.HTTPHeader,
 		return nil
 	}
 
-	config := AddHeadersConfig{Headers: make(map[string]string)}
+	headers := make(map[string]string, len(headersToAdd))
 	for _, header := range headersToAdd {
-		config.Headers[string(header.Name)] = header.Value
+		headers[string(header.Name)] = header.Value
 	}
 
 	if requestRedirectHeaders != nil {
-		maps.Copy(requestRedirectHeaders, config.Headers)
-	}
-
-	addHeaders, err := json.Marshal(config)
-	if err != nil {
-		d.log.Error(err, "cannot convert headers to json", "headers", headersToAdd)
-		return err
-	}
-
-	action := util.EndpointAction{
-		Type:   "add-headers",
-		Config: addHeaders,
-	}
-
-	rawAction, err := json.Marshal(&action)
-	if err != nil {
-		return nil
+		maps.Copy(requestRedirectHeaders, headers)
 	}
 
-	actions.EndpointActions = append(actions.EndpointActions, rawAction)
-
-	return nil
+	return d.appendAction(trafficpolicy.NewAddHeadersAction(headers), actions)
 }
 
 func (d *Driver) handleHTTPHeaderFilterSet(filter *gatewayv1.HTTPHeaderFilter, actions *util.Actions, requestRedirectHeaders map[string]string) error {
@@ -1189,72 +1351,35 @@ func (d *Driver) handleHTTPHeaderFilterSet(filter *gatewayv1.HTTPHeaderFilter, a
 	return d.handleHTTPHeaderFilterAdd(filter.Set, actions, requestRedirectHeaders)
 }
 
-type URLRedirectConfig struct {
-	To         *string `json:"to"`
-	From       *string `json:"from"`
-	StatusCode *int    `json:"status_code"`
-	// convert to response headers
-	Headers map[string]string `json:"headers"`
-}
-
 func (d *Driver) createUrlRedirectConfig(from string, to string, requestHeaders map[string]string, statusCode *int, actions *util.Actions) error {
-	urlRedirectAction := URLRedirectConfig{
-		To:         &to,
+	return d.appendAction(trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
 		From:       &from,
+		To:         to,
 		StatusCode: statusCode,
 		Headers:    requestHeaders,
-	}
-	config, err := json.Marshal(urlRedirectAction)
-
-	if err != nil {
-		d.log.Error(err, "cannot convert request redirect filter to json", "HTTPRequestRedirectFilter", urlRedirectAction)
-		return err
-	}
-
-	action := util.EndpointAction{
-		Type:   "redirect",
-		Config: config,
-	}
-
-	rawAction, err := json.Marshal(&action)
-	if err != nil {
-		return err
-	}
-
-	actions.EndpointActions = append(actions.EndpointActions, rawAction)
-
-	return nil
-}
-
-type URLRewriteConfig struct {
-	To   *string `json:"to"`
-	From *string `json:"from"`
+	}), actions)
 }
 
 func (d *Driver) createURLRewriteConfig(from string, to string, actions *util.Actions) error {
-	urlRewriteAction := URLRewriteConfig{
-		To:   &to,
-		From: &from,
-	}
-	config, err := json.Marshal(urlRewriteAction)
+	return d.appendAction(trafficpolicy.NewURLRewriteAction(trafficpolicy.URLRewriteConfig{
+		From: from,
+		To:   to,
+	}), actions)
+}
 
-	if err != nil {
-		d.log.Error(err, "cannot convert request rewrite filter to json", "HTTPRequestRewriteFilter", urlRewriteAction)
+// appendAction validates a typed traffic policy action and appends its JSON to actions.
+func (d *Driver) appendAction(action trafficpolicy.Action, actions *util.Actions) error {
+	if _, err := trafficpolicy.DecodeAction(action); err != nil {
+		d.log.Error(err, "invalid traffic policy action generated from route filter", "type", action.Type)
 		return err
 	}
 
-	action := util.EndpointAction{
-		Type:   "url-rewrite",
-		Config: config,
-	}
-
-	rawAction, err := json.Marshal(&action)
+	rawAction, err := json.Marshal(action)
 	if err != nil {
 		return err
 	}
 
 	actions.EndpointActions = append(actions.EndpointActions, rawAction)
-
 	return nil
 }
 
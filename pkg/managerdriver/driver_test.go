@@ -1514,6 +1514,76 @@ var _ = Describe("Driver", func() {
 			Expect(programmedCond).ToNot(BeNil(), "Programmed condition should be set on accepted listener")
 			Expect(programmedCond.Status).To(Equal(metav1.ConditionTrue))
 		})
+
+		It("Should report the reason the domain of a listener is not ready", func() {
+			ngrokGWClass := testutils.NewGatewayClass(true)
+
+			ngrokGateway := testutils.NewGatewayWithHostnames("ngrok-gw", "test-namespace", "ngrok.example.com")
+			ngrokGateway.Spec.GatewayClassName = gatewayv1.ObjectName(ngrokGWClass.Name)
+			ngrokGateway.Status.Listeners = []gatewayv1.ListenerStatus{
+				{
+					Name: "listener-0",
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(gatewayv1.ListenerConditionAccepted),
+							Status:             metav1.ConditionTrue,
+							Reason:             string(gatewayv1.ListenerReasonAccepted),
+							LastTransitionTime: metav1.Now(),
+						},
+					},
+				},
+			}
+
+			// The domain is reserved, but its DNS records aren't configured yet
+			domain := testutils.NewDomainV1("ngrok.example.com", "test-namespace")
+			domain.Status.CNAMETarget = new("cname.ngrok.example.com")
+			domain.Status.Conditions = []metav1.Condition{
+				{Type: "Ready", Status: metav1.ConditionTrue, Reason: "DomainActive", LastTransitionTime: metav1.Now()},
+				{Type: "DNSConfigured", Status: metav1.ConditionFalse, Reason: "DNSRecordMissing", Message: "No DNS record found", LastTransitionTime: metav1.Now()},
+			}
+
+			gwDriver := NewDriver(
+				GinkgoLogr,
+				scheme,
+				testutils.DefaultControllerName,
+				types.NamespacedName{Name: defaultManagerName},
+				WithGatewayEnabled(true),
+				WithGatewayControllerName("ngrok.com/gateway-controller"),
+				WithSyncAllowConcurrent(true),
+			)
+
+			obs := []runtime.Object{ngrokGWClass, ngrokGateway, domain}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(obs...).
+				WithStatusSubresource(&gatewayv1.Gateway{}).
+				Build()
+
+			err := gwDriver.Seed(GinkgoT().Context(), c)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = gwDriver.updateGatewayStatuses(GinkgoT().Context(), c)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedGW := &gatewayv1.Gateway{}
+			err = c.Get(GinkgoT().Context(), types.NamespacedName{
+				Namespace: "test-namespace",
+				Name:      "ngrok-gw",
+			}, updatedGW)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(updatedGW.Status.Listeners).To(HaveLen(1))
+			programmedCond := meta.FindStatusCondition(updatedGW.Status.Listeners[0].Conditions, string(gatewayv1.ListenerConditionProgrammed))
+			Expect(programmedCond).ToNot(BeNil())
+			Expect(programmedCond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(programmedCond.Reason).To(Equal("DNSRecordMissing"))
+			Expect(programmedCond.Message).To(ContainSubstring("No DNS record found"))
+
+			gatewayProgrammed := meta.FindStatusCondition(updatedGW.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+			Expect(gatewayProgrammed).ToNot(BeNil())
+			Expect(gatewayProgrammed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(gatewayProgrammed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+		})
 	})
 
 	Describe("calculateDomainSet", func() {
@@ -1859,3 +1929,46 @@ var _ = Describe("RecordDomainEventsForIngress", func() {
 		}).ToNot(Panic())
 	})
 })
+
+var _ = Describe("RecordTranslationWarnings", func() {
+	var driver *Driver
+	var fakeRecorder *events.FakeRecorder
+	var ingress *netv1.Ingress
+
+	BeforeEach(func() {
+		fakeRecorder = events.NewFakeRecorder(10)
+		driver = NewDriver(
+			GinkgoLogr,
+			runtime.NewScheme(),
+			testutils.DefaultControllerName,
+			types.NamespacedName{Name: defaultManagerName},
+			WithEventRecorder(fakeRecorder),
+		)
+		ingress = testutils.NewTestIngressV1WithHosts("test-ingress", "default", "example.com")
+	})
+
+	It("Should only record new or changed warnings", func() {
+		warning := TranslationWarning{Object: ingress, Reason: "IgnoredAnnotation", Message: "first"}
+
+		driver.recordTranslationWarnings([]TranslationWarning{warning})
+		Expect(fakeRecorder.Events).To(Receive(Equal("Warning IgnoredAnnotation first")))
+
+		// The next sync finds the same warning
+		driver.recordTranslationWarnings([]TranslationWarning{warning})
+		Expect(fakeRecorder.Events).ToNot(Receive())
+
+		warning.Message = "second"
+		driver.recordTranslationWarnings([]TranslationWarning{warning})
+		Expect(fakeRecorder.Events).To(Receive(Equal("Warning IgnoredAnnotation second")))
+	})
+
+	It("Should record a warning again after it was resolved", func() {
+		warning := TranslationWarning{Object: ingress, Reason: "IgnoredAnnotation", Message: "first"}
+
+		driver.recordTranslationWarnings([]TranslationWarning{warning})
+		Expect(fakeRecorder.Events).To(Receive())
+		driver.recordTranslationWarnings(nil)
+		driver.recordTranslationWarnings([]TranslationWarning{warning})
+		Expect(fakeRecorder.Events).To(Receive(Equal("Warning IgnoredAnnotation first")))
+	})
+})
@@ -20,16 +20,21 @@ func (d *Driver) SyncEndpoints(ctx context.Context, c client.Client) error {
 	}
 
 	d.log.Info("syncing cloud and agent endpoints state!!")
+	ingressNgrokMetadata, gatewayNgrokMetadata := d.ngrokMetadata()
 	translator := NewTranslator(
 		d.log,
 		d.store,
 		d.controllerLabels.Labels(),
-		d.ingressNgrokMetadata,
-		d.gatewayNgrokMetadata,
+		ingressNgrokMetadata,
+		gatewayNgrokMetadata,
 		d.clusterDomain,
 		d.disableGatewayReferenceGrants,
+		d.ingressNginxCompatibility,
+		d.ingressSecretAnnotations,
+		d.multiCluster,
 	)
 	translationResult := translator.Translate()
+	d.recordTranslationWarnings(translationResult.Warnings)
 
 	// LEGACY-PREFIX-MIGRATION: BEGIN (read-side cleanup): collapse to single-selector c.List calls
 	currentAgentEndpoints, err := d.listAgentEndpointsForController(ctx, c)
@@ -0,0 +1,220 @@
+package managerdriver
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+)
+
+// ClusterMetadataKey is the ngrok metadata key naming the cluster that created an endpoint in multi-cluster mode
+const ClusterMetadataKey = "cluster"
+
+// ClusterMember is one of the clusters serving the same hostnames in multi-cluster mode
+type ClusterMember struct {
+	Name string
+	// Priority orders failover between clusters: traffic goes to the clusters with the lowest priority first, and
+	// to the next priority when none of them can be reached
+	Priority int
+	// Weight is the share of traffic a cluster gets among the clusters with the same priority
+	Weight int
+}
+
+// MultiCluster lets several operator installations serve the same Ingress and Gateway hostnames. Each cluster
+// creates a pooled CloudEndpoint for a hostname with the same traffic policy, which forwards to the internal
+// AgentEndpoints of every member cluster and fails over between them. Internal endpoint URLs are derived from the
+// cluster name instead of the service UID, so every cluster knows the URLs of the others.
+type MultiCluster struct {
+	// ClusterName is the name of this cluster, one of the Members
+	ClusterName string
+	// Members are all of the clusters serving the hostnames, including this one
+	Members []ClusterMember
+}
+
+// ParseClusterMembers parses cluster members of the form name[:priority[:weight]]. The priority defaults to 0 and
+// the weight to 1.
+func ParseClusterMembers(specs []string) ([]ClusterMember, error) {
+	members := make([]ClusterMember, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid cluster member %q, must be name[:priority[:weight]]", spec)
+		}
+		m := ClusterMember{Name: parts[0], Weight: 1}
+		var err error
+		if len(parts) > 1 {
+			if m.Priority, err = strconv.Atoi(parts[1]); err != nil {
+				return nil, fmt.Errorf("invalid priority in cluster member %q: %w", spec, err)
+			}
+		}
+		if len(parts) > 2 {
+			if m.Weight, err = strconv.Atoi(parts[2]); err != nil {
+				return nil, fmt.Errorf("invalid weight in cluster member %q: %w", spec, err)
+			}
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// Validate returns an error if the configuration can't be used. A configuration without members only has this
+// cluster.
+func (mc *MultiCluster) Validate() error {
+	if errs := validation.IsDNS1123Label(mc.ClusterName); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %q: %s", mc.ClusterName, strings.Join(errs, ", "))
+	}
+	if len(mc.Members) == 0 {
+		mc.Members = []ClusterMember{{Name: mc.ClusterName, Weight: 1}}
+	}
+
+	seen := map[string]bool{}
+	for _, m := range mc.Members {
+		if errs := validation.IsDNS1123Label(m.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid cluster member name %q: %s", m.Name, strings.Join(errs, ", "))
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("duplicate cluster member %q", m.Name)
+		}
+		seen[m.Name] = true
+		if m.Priority < 0 {
+			return fmt.Errorf("cluster member %q has a negative priority", m.Name)
+		}
+		if m.Weight < 1 {
+			return fmt.Errorf("cluster member %q must have a weight of at least 1", m.Name)
+		}
+	}
+	if !seen[mc.ClusterName] {
+		return errors.New("the cluster members must include this cluster")
+	}
+	return nil
+}
+
+// applyToVirtualHost makes the endpoints of a virtual host shareable with the other member clusters. The public
+// endpoint is always a pooled CloudEndpoint, since collapsing it into the AgentEndpoint of a local service would
+// leave no endpoint to fail over from, and it is tagged with the name of this cluster.
+func (mc *MultiCluster) applyToVirtualHost(irVHost *ir.IRVirtualHost) {
+	irVHost.MappingStrategy = ir.IRMappingStrategy_EndpointsVerbose
+	irVHost.EndpointPoolingEnabled = new(true)
+
+	clusterMetadata, _ := json.Marshal(map[string]string{ClusterMetadataKey: mc.ClusterName})
+	irVHost.Metadata = ir.MergeMetadata(irVHost.Metadata, string(clusterMetadata))
+}
+
+// localService returns irService as it is used to build the internal endpoint of this cluster. In multi-cluster
+// mode the internal URL is derived from the cluster name instead of the service UID, which the other clusters can't
+// know.
+func (mc *MultiCluster) localService(irService ir.IRService) ir.IRService {
+	if mc == nil {
+		return irService
+	}
+	irService.UID = mc.ClusterName
+	return irService
+}
+
+// priorityGroups returns the members grouped by priority, lowest first. Members of a group are ordered by name.
+func (mc *MultiCluster) priorityGroups() [][]ClusterMember {
+	sorted := slices.Clone(mc.Members)
+	slices.SortFunc(sorted, func(a, b ClusterMember) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), strings.Compare(a.Name, b.Name))
+	})
+
+	groups := [][]ClusterMember{}
+	for i, m := range sorted {
+		if i == 0 || m.Priority != sorted[i-1].Priority {
+			groups = append(groups, []ClusterMember{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], m)
+	}
+	return groups
+}
+
+// buildClusterRouteRules builds the rules that forward to the internal endpoint of irService in every member
+// cluster. Clusters are tried in priority order, and within a priority a cluster is picked by weight before failing
+// over to the others. The last rule forwards without on_error, so a request is never answered by later rules when no
+// cluster could be reached.
+func (t *translator) buildClusterRouteRules(name string, protocol ir.IRProtocol, irService ir.IRService) ([]trafficpolicy.Rule, error) {
+	forward := func(ruleName string, member ClusterMember, continueOnError bool, expressions ...string) (trafficpolicy.Rule, error) {
+		url, err := buildInternalEndpointURL(protocol, member.Name, irService.Name, irService.Namespace, t.clusterDomain, irService.Port, irService.ClientCertRefs)
+		if err != nil {
+			return trafficpolicy.Rule{}, err
+		}
+		cfg := trafficpolicy.ForwardInternalConfig{URL: url}
+		if continueOnError {
+			cfg.OnError = new("continue")
+		}
+		return trafficpolicy.Rule{
+			Name:        fmt.Sprintf("%s-Cluster-%s%s", name, member.Name, ruleName),
+			Expressions: expressions,
+			Actions:     []trafficpolicy.Action{trafficpolicy.NewAction(cfg)},
+		}, nil
+	}
+
+	rules := []trafficpolicy.Rule{}
+	groups := t.multiCluster.priorityGroups()
+	for i, group := range groups {
+		if len(group) == 1 {
+			// The error of the last cluster is returned to the client
+			rule, err := forward("", group[0], i < len(groups)-1)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+			continue
+		}
+
+		totalWeight := 0
+		for _, m := range group {
+			totalWeight += m.Weight
+		}
+		rules = append(rules, trafficpolicy.Rule{
+			Name: fmt.Sprintf("%s-Gen-Cluster-Random-Number", name),
+			Actions: []trafficpolicy.Action{
+				trafficpolicy.NewSetVarsAction(map[string]any{
+					"cluster_random_num": fmt.Sprintf("${rand.int(0,%d)}", totalWeight-1),
+				}),
+			},
+		})
+
+		// The cluster picked by weight is tried first, then the others of the group
+		picked := make([]string, 0, len(group))
+		lowerBound := 0
+		for _, m := range group {
+			upperBound := lowerBound + m.Weight - 1
+			picked = append(picked, fmt.Sprintf("int(vars.cluster_random_num) >= %d && int(vars.cluster_random_num) <= %d", lowerBound, upperBound))
+			lowerBound = upperBound + 1
+		}
+		for j, m := range group {
+			rule, err := forward("", m, true, picked[j])
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+		for j, m := range group {
+			rule, err := forward("-Failover", m, true, fmt.Sprintf("!(%s)", picked[j]))
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	last := groups[len(groups)-1]
+	if len(last) == 1 {
+		return rules, nil
+	}
+	// Every cluster of the last group has been tried at this point, retry the last one to return its error
+	rule, err := forward("-Last", last[len(last)-1], false)
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, rule), nil
+}
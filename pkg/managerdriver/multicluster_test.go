@@ -0,0 +1,170 @@
+package managerdriver
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+)
+
+func TestParseClusterMembers(t *testing.T) {
+	members, err := ParseClusterMembers([]string{"us", "eu:1", "ap:1:3"})
+	require.NoError(t, err)
+	assert.Equal(t, []ClusterMember{
+		{Name: "us", Priority: 0, Weight: 1},
+		{Name: "eu", Priority: 1, Weight: 1},
+		{Name: "ap", Priority: 1, Weight: 3},
+	}, members)
+
+	for _, spec := range []string{"us:a", "us:0:b", "us:0:1:2"} {
+		_, err := ParseClusterMembers([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMultiClusterValidate(t *testing.T) {
+	mc := &MultiCluster{ClusterName: "us"}
+	require.NoError(t, mc.Validate())
+	assert.Equal(t, []ClusterMember{{Name: "us", Weight: 1}}, mc.Members)
+
+	for name, mc := range map[string]*MultiCluster{
+		"invalid cluster name": {ClusterName: "US_East"},
+		"invalid member name":  {ClusterName: "us", Members: []ClusterMember{{Name: "us", Weight: 1}, {Name: "EU", Weight: 1}}},
+		"duplicate member":     {ClusterName: "us", Members: []ClusterMember{{Name: "us", Weight: 1}, {Name: "us", Weight: 1}}},
+		"missing this cluster": {ClusterName: "us", Members: []ClusterMember{{Name: "eu", Weight: 1}}},
+		"zero weight":          {ClusterName: "us", Members: []ClusterMember{{Name: "us"}}},
+		"negative priority":    {ClusterName: "us", Members: []ClusterMember{{Name: "us", Priority: -1, Weight: 1}}},
+	} {
+		assert.Error(t, mc.Validate(), name)
+	}
+}
+
+func TestBuildClusterRouteRules(t *testing.T) {
+	svc := ir.IRService{UID: "service-uid", Namespace: "default", Name: "test-service", Port: 8080}
+	urlFor := func(cluster string) string {
+		url, err := buildInternalEndpointURL(ir.IRProtocol_HTTPS, cluster, svc.Name, svc.Namespace, "cluster.local", svc.Port, nil)
+		require.NoError(t, err)
+		return url
+	}
+	forward := func(name, cluster string, continueOnError bool, expressions ...string) trafficpolicy.Rule {
+		cfg := trafficpolicy.ForwardInternalConfig{URL: urlFor(cluster)}
+		if continueOnError {
+			cfg.OnError = new("continue")
+		}
+		return trafficpolicy.Rule{Name: name, Expressions: expressions, Actions: []trafficpolicy.Action{trafficpolicy.NewAction(cfg)}}
+	}
+
+	t.Run("single cluster per priority", func(t *testing.T) {
+		tr := &translator{clusterDomain: "cluster.local", multiCluster: &MultiCluster{
+			ClusterName: "us",
+			Members:     []ClusterMember{{Name: "us", Weight: 1}, {Name: "eu", Priority: 1, Weight: 1}},
+		}}
+		rules, err := tr.buildClusterRouteRules("Generated-Route", ir.IRProtocol_HTTPS, svc)
+		require.NoError(t, err)
+		assert.Equal(t, []trafficpolicy.Rule{
+			forward("Generated-Route-Cluster-us", "us", true),
+			forward("Generated-Route-Cluster-eu", "eu", false),
+		}, rules)
+	})
+
+	t.Run("weighted clusters", func(t *testing.T) {
+		tr := &translator{clusterDomain: "cluster.local", multiCluster: &MultiCluster{
+			ClusterName: "us",
+			Members:     []ClusterMember{{Name: "us", Weight: 3}, {Name: "eu", Weight: 1}},
+		}}
+		rules, err := tr.buildClusterRouteRules("Generated-Route", ir.IRProtocol_HTTPS, svc)
+		require.NoError(t, err)
+
+		eu := "int(vars.cluster_random_num) >= 0 && int(vars.cluster_random_num) <= 0"
+		us := "int(vars.cluster_random_num) >= 1 && int(vars.cluster_random_num) <= 3"
+		assert.Equal(t, []trafficpolicy.Rule{
+			{
+				Name: "Generated-Route-Gen-Cluster-Random-Number",
+				Actions: []trafficpolicy.Action{
+					trafficpolicy.NewSetVarsAction(map[string]any{"cluster_random_num": "${rand.int(0,3)}"}),
+				},
+			},
+			forward("Generated-Route-Cluster-eu", "eu", true, eu),
+			forward("Generated-Route-Cluster-us", "us", true, us),
+			forward("Generated-Route-Cluster-eu-Failover", "eu", true, "!("+eu+")"),
+			forward("Generated-Route-Cluster-us-Failover", "us", true, "!("+us+")"),
+			forward("Generated-Route-Cluster-us-Last", "us", false),
+		}, rules)
+	})
+}
+
+func TestMultiClusterDefaultDestination(t *testing.T) {
+	tr := &translator{clusterDomain: "cluster.local", multiCluster: &MultiCluster{
+		ClusterName: "us",
+		Members:     []ClusterMember{{Name: "us", Weight: 1}, {Name: "eu", Priority: 1, Weight: 1}},
+	}}
+	irVHost := &ir.IRVirtualHost{
+		Listener: ir.IRListener{Hostname: "cloud-host", Port: 443, Protocol: ir.IRProtocol_HTTPS},
+		Metadata: `{"team":"web"}`,
+		DefaultDestination: &ir.IRDestination{
+			Upstream: &ir.IRUpstream{
+				Service: ir.IRService{UID: "service-uid", Namespace: "default", Name: "test-service", Port: 8080, Scheme: ir.IRScheme_HTTP},
+			},
+		},
+		MappingStrategy: ir.IRMappingStrategy_EndpointsCollapsed,
+	}
+	tr.multiCluster.applyToVirtualHost(irVHost)
+	assert.Equal(t, ir.IRMappingStrategy_EndpointsVerbose, irVHost.MappingStrategy)
+	assert.Equal(t, new(true), irVHost.EndpointPoolingEnabled)
+	assert.JSONEq(t, `{"team":"web","cluster":"us"}`, irVHost.Metadata)
+
+	cache := map[ir.IRServiceKey]*ngrokv1alpha1.AgentEndpoint{}
+	policy, err := tr.buildDefaultDestinationPolicy(irVHost, cache)
+	require.NoError(t, err)
+	require.Len(t, policy.OnHTTPRequest, 2)
+	assert.Equal(t, "Generated-Route-Default-Backend-Cluster-us", policy.OnHTTPRequest[0].Name)
+	assert.Equal(t, "Generated-Route-Default-Backend-Cluster-eu", policy.OnHTTPRequest[1].Name)
+
+	// The internal endpoint of this cluster has the URL the other clusters forward to
+	agentEndpoint := cache[irVHost.DefaultDestination.Upstream.Service.Key()]
+	require.NotNil(t, agentEndpoint)
+	localCfg, ok := policy.OnHTTPRequest[0].Actions[0].Config.(trafficpolicy.ForwardInternalConfig)
+	require.True(t, ok)
+	assert.Equal(t, localCfg.URL, agentEndpoint.Spec.URL)
+}
+
+func TestMultiClusterDomainsAreRetained(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ingressv1alpha1.AddToScheme(scheme))
+
+	// A Domain created before multi-cluster mode was enabled
+	existing := &ingressv1alpha1.Domain{
+		Name:              "app-example-com",
+		Namespace:         "default",
+		CreationTimestamp: metav1.Now(),
+		Spec:              ingressv1alpha1.DomainSpec{Domain: "app.example.com", ReclaimPolicy: ingressv1alpha1.DomainReclaimPolicyDelete},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	d := NewDriver(logr.Discard(), scheme, "ngrok", types.NamespacedName{Namespace: "ngrok-op", Name: "ngrok"},
+		WithDefaultDomainReclaimPolicy(ingressv1alpha1.DomainReclaimPolicyDelete),
+		WithMultiCluster(&MultiCluster{ClusterName: "us"}),
+	)
+	desired := map[string]ingressv1alpha1.Domain{
+		"app.example.com": {Name: "app-example-com", Namespace: "default", Spec: ingressv1alpha1.DomainSpec{Domain: "app.example.com"}},
+		"api.example.com": {Name: "api-example-com", Namespace: "default", Spec: ingressv1alpha1.DomainSpec{Domain: "api.example.com"}},
+	}
+	require.NoError(t, d.applyDomains(t.Context(), c, desired))
+
+	domains := &ingressv1alpha1.DomainList{}
+	require.NoError(t, c.List(t.Context(), domains))
+	require.Len(t, domains.Items, 2)
+	for _, domain := range domains.Items {
+		assert.Equal(t, ingressv1alpha1.DomainReclaimPolicyRetain, domain.Spec.ReclaimPolicy, domain.Name)
+	}
+}
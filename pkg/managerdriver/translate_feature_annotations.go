@@ -0,0 +1,253 @@
+package managerdriver
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReasonTrafficPolicyAnnotationConflict is the event reason used when a feature annotation is ignored
+	// because the resource's traffic policy already configures the same action.
+	ReasonTrafficPolicyAnnotationConflict = "TrafficPolicyAnnotationConflict"
+	// ReasonInvalidFeatureAnnotations is the event reason used when the feature annotations on a resource
+	// cannot be compiled into a traffic policy.
+	ReasonInvalidFeatureAnnotations = "InvalidFeatureAnnotations"
+
+	// basicAuthSecretKey is the Secret key holding "username:password" lines for opaque basic auth Secrets.
+	basicAuthSecretKey = "auth"
+	// oidcClientIDKey and oidcClientSecretKey are the Secret keys holding OIDC client credentials.
+	oidcClientIDKey     = "client-id"
+	oidcClientSecretKey = "client-secret"
+)
+
+// featureAnnotationsToTrafficPolicy compiles the feature annotations on obj (rate limiting, basic auth, IP
+// policies, OIDC, CORS and headers) into traffic policy rules and combines them with explicit, the policy
+// loaded from the traffic-policy annotation. Annotation rules run first. A feature whose action is already
+// configured by explicit is skipped and a warning is recorded against obj, so the explicit policy wins.
+func (t *translator) featureAnnotationsToTrafficPolicy(obj client.Object, explicit *trafficpolicy.TrafficPolicy) (*trafficpolicy.TrafficPolicy, error) {
+	features, err := annotations.ExtractFeatureAnnotations(obj)
+	if err != nil {
+		return nil, err
+	}
+	if features.IsEmpty() {
+		return explicit, nil
+	}
+
+	conflicts := func(annotation string, actionTypes ...trafficpolicy.ActionType) bool {
+		return t.annotationConflicts(obj, explicit, annotation, actionTypes...)
+	}
+
+	tp := trafficpolicy.NewTrafficPolicy()
+
+	if len(features.IPPolicies) > 0 && !conflicts(annotations.IPPoliciesAnnotation, trafficpolicy.ActionType_RestrictIPs) {
+		ids, err := t.resolveIPPolicyIDs(obj.GetNamespace(), features.IPPolicies)
+		if err != nil {
+			return nil, err
+		}
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:    "Annotation-IP-Policies",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRestricIPsActionFromIPPolicies(ids)},
+		})
+	}
+
+	if rl := features.RateLimit; rl != nil && !conflicts(annotations.RateLimitAnnotation, trafficpolicy.ActionType_RateLimit) {
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name: "Annotation-Rate-Limit",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRateLimitAction(trafficpolicy.RateLimitConfig{
+				Algorithm: trafficpolicy.RateLimitAlgorithmSlidingWindow,
+				Capacity:  rl.Capacity,
+				Rate:      rl.Window.String(),
+				BucketKey: rl.BucketKeys,
+			})},
+		})
+	}
+
+	// Preflight requests must be answered before authentication, since browsers never send credentials with them.
+	if cors := features.CORS; cors != nil {
+		addCORSRules(tp, cors)
+	}
+
+	if features.BasicAuthSecret != "" && !conflicts(annotations.BasicAuthSecretAnnotation, trafficpolicy.ActionType_BasicAuth) {
+		action, err := t.basicAuthActionFromSecret(obj.GetNamespace(), features.BasicAuthSecret)
+		if err != nil {
+			return nil, err
+		}
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:    "Annotation-Basic-Auth",
+			Actions: []trafficpolicy.Action{action},
+		})
+	}
+
+	if features.OIDC != nil && !conflicts(annotations.OIDCIssuerURLAnnotation, trafficpolicy.ActionType_OIDC, trafficpolicy.ActionType_OAuth) {
+		action, err := t.oidcActionFromSecret(obj.GetNamespace(), features.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:    "Annotation-OIDC",
+			Actions: []trafficpolicy.Action{action},
+		})
+	}
+
+	if len(features.RequestHeaders) > 0 {
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:    "Annotation-Request-Headers",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewAddHeadersAction(features.RequestHeaders)},
+		})
+	}
+	if len(features.ResponseHeaders) > 0 {
+		tp.AddRuleOnHTTPResponse(trafficpolicy.Rule{
+			Name:    "Annotation-Response-Headers",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewAddHeadersAction(features.ResponseHeaders)},
+		})
+	}
+
+	if err := tp.Validate(); err != nil {
+		return nil, err
+	}
+
+	tp.Merge(explicit)
+	return tp, nil
+}
+
+// annotationConflicts returns true, and records a warning against obj, if tp already configures one of
+// actionTypes, meaning the feature configured by annotation should be ignored.
+func (t *translator) annotationConflicts(obj client.Object, tp *trafficpolicy.TrafficPolicy, annotation string, actionTypes ...trafficpolicy.ActionType) bool {
+	if tp == nil {
+		return false
+	}
+	for _, actionType := range actionTypes {
+		if tp.ContainsAction(actionType) {
+			t.warn(obj, ReasonTrafficPolicyAnnotationConflict,
+				fmt.Sprintf("ignoring %q annotation: the traffic policy for this resource already configures a %s action", annotation, actionType))
+			return true
+		}
+	}
+	return false
+}
+
+// resolveIPPolicyIDs maps IPPolicy names to the IDs of the ngrok IP policies they manage.
+func (t *translator) resolveIPPolicyIDs(namespace string, names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		ipPolicy, err := t.store.GetIPPolicyV1(name, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load IPPolicy %q referenced by %q: %w", name, annotations.IPPoliciesAnnotation, err)
+		}
+		if ipPolicy.Status.ID == "" {
+			return nil, fmt.Errorf("IPPolicy %q referenced by %q has not been created in ngrok yet", name, annotations.IPPoliciesAnnotation)
+		}
+		ids = append(ids, ipPolicy.Status.ID)
+	}
+	return ids, nil
+}
+
+// errSecretAnnotationsDisabled returns the error for an annotation that references a Secret while the Secret
+// feature annotations are disabled
+func errSecretAnnotationsDisabled(annotation string) error {
+	return fmt.Errorf("%q copies the referenced Secret's credentials into the generated endpoints and is disabled. Enable it with --ingress-secret-annotations, or configure the action in an NgrokTrafficPolicy", annotation)
+}
+
+// basicAuthActionFromSecret builds a basic-auth action from either a kubernetes.io/basic-auth Secret or
+// a Secret with "username:password" lines under the "auth" key.
+func (t *translator) basicAuthActionFromSecret(namespace, name string) (trafficpolicy.Action, error) {
+	if !t.ingressSecretAnnotations {
+		return trafficpolicy.Action{}, errSecretAnnotationsDisabled(annotations.BasicAuthSecretAnnotation)
+	}
+	secret, err := t.store.GetSecretV1(name, namespace)
+	if err != nil {
+		return trafficpolicy.Action{}, fmt.Errorf("unable to load Secret %q referenced by %q: %w", name, annotations.BasicAuthSecretAnnotation, err)
+	}
+
+	credentials := []string{}
+	if secret.Type == corev1.SecretTypeBasicAuth {
+		credentials = append(credentials, string(secret.Data[corev1.BasicAuthUsernameKey])+":"+string(secret.Data[corev1.BasicAuthPasswordKey]))
+	} else {
+		for line := range strings.SplitSeq(string(secret.Data[basicAuthSecretKey]), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				credentials = append(credentials, line)
+			}
+		}
+	}
+
+	cfg := trafficpolicy.BasicAuthConfig{Credentials: credentials}
+	if err := cfg.Validate(); err != nil {
+		return trafficpolicy.Action{}, fmt.Errorf("secret %q referenced by %q is invalid: %w", name, annotations.BasicAuthSecretAnnotation, err)
+	}
+	return trafficpolicy.NewBasicAuthAction(cfg), nil
+}
+
+// oidcActionFromSecret builds an openid-connect action using the client credentials in the referenced Secret.
+func (t *translator) oidcActionFromSecret(namespace string, oidc *annotations.OIDC) (trafficpolicy.Action, error) {
+	if !t.ingressSecretAnnotations {
+		return trafficpolicy.Action{}, errSecretAnnotationsDisabled(annotations.OIDCSecretAnnotation)
+	}
+	secret, err := t.store.GetSecretV1(oidc.SecretName, namespace)
+	if err != nil {
+		return trafficpolicy.Action{}, fmt.Errorf("unable to load Secret %q referenced by %q: %w", oidc.SecretName, annotations.OIDCSecretAnnotation, err)
+	}
+
+	cfg := trafficpolicy.OIDCConfig{
+		IssuerURL:    oidc.IssuerURL,
+		ClientID:     ptr.To(string(secret.Data[oidcClientIDKey])),
+		ClientSecret: ptr.To(string(secret.Data[oidcClientSecretKey])),
+		Scopes:       oidc.Scopes,
+	}
+	if err := cfg.Validate(); err != nil {
+		return trafficpolicy.Action{}, fmt.Errorf("OIDC annotations are invalid: %w", err)
+	}
+	return trafficpolicy.NewOIDCAction(cfg), nil
+}
+
+// addCORSRules answers CORS preflight requests and adds CORS headers to responses for allowed origins.
+func addCORSRules(tp *trafficpolicy.TrafficPolicy, cors *annotations.CORS) {
+	allowOrigin := "*"
+	originExpressions := []string{}
+	if cors.AllowOrigins[0] != "*" {
+		quoted := make([]string, 0, len(cors.AllowOrigins))
+		for _, origin := range cors.AllowOrigins {
+			quoted = append(quoted, strconv.Quote(origin))
+		}
+		allowOrigin = "${req.headers['origin'][0]}"
+		originExpressions = append(originExpressions,
+			fmt.Sprintf("'origin' in req.headers && req.headers['origin'][0] in [%s]", strings.Join(quoted, ", ")))
+	}
+
+	responseHeaders := map[string]string{"Access-Control-Allow-Origin": allowOrigin}
+	if allowOrigin != "*" {
+		responseHeaders["Vary"] = "Origin"
+	}
+	if cors.AllowCredentials {
+		responseHeaders["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	preflightHeaders := map[string]string{
+		"Access-Control-Allow-Methods": strings.Join(cors.AllowMethods, ", "),
+		"Access-Control-Allow-Headers": strings.Join(cors.AllowHeaders, ", "),
+	}
+	maps.Copy(preflightHeaders, responseHeaders)
+	if cors.MaxAge != nil {
+		preflightHeaders["Access-Control-Max-Age"] = strconv.Itoa(*cors.MaxAge)
+	}
+
+	tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+		Name: "Annotation-CORS-Preflight",
+		Expressions: append([]string{
+			"req.method == 'OPTIONS' && 'access-control-request-method' in req.headers",
+		}, originExpressions...),
+		Actions: []trafficpolicy.Action{trafficpolicy.NewCustomResponseAction(204, "", preflightHeaders)},
+	})
+	tp.AddRuleOnHTTPResponse(trafficpolicy.Rule{
+		Name:        "Annotation-CORS-Headers",
+		Expressions: originExpressions,
+		Actions:     []trafficpolicy.Action{trafficpolicy.NewAddHeadersAction(responseHeaders)},
+	})
+}
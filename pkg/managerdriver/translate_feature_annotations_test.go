@@ -0,0 +1,288 @@
+package managerdriver
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	ingressv1alpha1 "github.com/ngrok/ngrok-operator/api/ingress/v1alpha1"
+	"github.com/ngrok/ngrok-operator/internal/annotations"
+	"github.com/ngrok/ngrok-operator/internal/store"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func newFeatureAnnotationsTranslator(t *testing.T, objs ...runtime.Object) *translator {
+	t.Helper()
+	logger := logr.New(logr.Discard().GetSink())
+	s := store.New(store.NewCacheStores(logger), "ngrok.com/ingress-controller", logger)
+	for _, obj := range objs {
+		require.NoError(t, s.Add(obj))
+	}
+	return &translator{log: logger, store: s, ingressSecretAnnotations: true}
+}
+
+func featureAnnotationsIngress(annotations map[string]string) *netv1.Ingress {
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-ingress",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+	}
+}
+
+func ruleNames(rules []trafficpolicy.Rule) []any {
+	names := []any{}
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+func TestFeatureAnnotationsToTrafficPolicy(t *testing.T) {
+	basicAuthSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+			corev1.BasicAuthPasswordKey: []byte("pass"),
+		},
+	}
+	opaqueAuthSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "htpasswd", Namespace: "default"},
+		Data:       map[string][]byte{"auth": []byte("alice:one\n\nbob:two\n")},
+	}
+	oidcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oidc", Namespace: "default"},
+		Data: map[string][]byte{
+			"client-id":     []byte("id"),
+			"client-secret": []byte("secret"),
+		},
+	}
+	officePolicy := &ingressv1alpha1.IPPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "default"},
+		Status:     ingressv1alpha1.IPPolicyStatus{ID: "ipp_office"},
+	}
+	pendingPolicy := &ingressv1alpha1.IPPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+	}
+
+	t.Run("no feature annotations keeps the explicit policy", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t)
+		explicit := trafficpolicy.NewTrafficPolicy()
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(featureAnnotationsIngress(nil), explicit)
+		require.NoError(t, err)
+		assert.Same(t, explicit, tp)
+	})
+
+	t.Run("compiles every feature in order", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t, basicAuthSecret, officePolicy)
+		ing := featureAnnotationsIngress(map[string]string{
+			annotations.IPPoliciesAnnotation:       "office",
+			annotations.RateLimitAnnotation:        "100/1m",
+			annotations.CORSAllowOriginsAnnotation: "*",
+			annotations.BasicAuthSecretAnnotation:  "creds",
+			annotations.RequestHeadersAnnotation:   `{"X-Via": "ngrok"}`,
+			annotations.ResponseHeadersAnnotation:  `{"X-Served-By": "ngrok"}`,
+		})
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(ing, nil)
+		require.NoError(t, err)
+		require.NoError(t, tp.Validate())
+		assert.Empty(t, tr.warnings)
+
+		assert.Equal(t, []any{
+			"Annotation-IP-Policies",
+			"Annotation-Rate-Limit",
+			"Annotation-CORS-Preflight",
+			"Annotation-Basic-Auth",
+			"Annotation-Request-Headers",
+		}, ruleNames(tp.OnHTTPRequest))
+		assert.Equal(t, []any{
+			"Annotation-CORS-Headers",
+			"Annotation-Response-Headers",
+		}, ruleNames(tp.OnHTTPResponse))
+
+		assert.Equal(t, trafficpolicy.NewRestricIPsActionFromIPPolicies([]string{"ipp_office"}), tp.OnHTTPRequest[0].Actions[0])
+		assert.Equal(t, trafficpolicy.NewRateLimitAction(trafficpolicy.RateLimitConfig{
+			Algorithm: trafficpolicy.RateLimitAlgorithmSlidingWindow,
+			Capacity:  100,
+			Rate:      "1m0s",
+			BucketKey: []string{"conn.client_ip"},
+		}), tp.OnHTTPRequest[1].Actions[0])
+		assert.Equal(t, trafficpolicy.NewBasicAuthAction(trafficpolicy.BasicAuthConfig{
+			Credentials: []string{"user:pass"},
+		}), tp.OnHTTPRequest[3].Actions[0])
+	})
+
+	t.Run("opaque basic auth secret", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t, opaqueAuthSecret)
+		ing := featureAnnotationsIngress(map[string]string{annotations.BasicAuthSecretAnnotation: "htpasswd"})
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(ing, nil)
+		require.NoError(t, err)
+		assert.Equal(t, trafficpolicy.NewBasicAuthAction(trafficpolicy.BasicAuthConfig{
+			Credentials: []string{"alice:one", "bob:two"},
+		}), tp.OnHTTPRequest[0].Actions[0])
+	})
+
+	t.Run("oidc", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t, oidcSecret)
+		ing := featureAnnotationsIngress(map[string]string{
+			annotations.OIDCIssuerURLAnnotation: "https://accounts.example.com",
+			annotations.OIDCSecretAnnotation:    "oidc",
+			annotations.OIDCScopesAnnotation:    "openid,email",
+		})
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(ing, nil)
+		require.NoError(t, err)
+		assert.Equal(t, trafficpolicy.NewOIDCAction(trafficpolicy.OIDCConfig{
+			IssuerURL:    "https://accounts.example.com",
+			ClientID:     ptr.To("id"),
+			ClientSecret: ptr.To("secret"),
+			Scopes:       []string{"openid", "email"},
+		}), tp.OnHTTPRequest[0].Actions[0])
+	})
+
+	t.Run("cors with specific origins", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t)
+		ing := featureAnnotationsIngress(map[string]string{
+			annotations.CORSAllowOriginsAnnotation:     "https://a.example.com",
+			annotations.CORSAllowMethodsAnnotation:     "GET",
+			annotations.CORSAllowHeadersAnnotation:     "Content-Type",
+			annotations.CORSAllowCredentialsAnnotation: "true",
+			annotations.CORSMaxAgeAnnotation:           "600",
+		})
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(ing, nil)
+		require.NoError(t, err)
+
+		originExpression := `'origin' in req.headers && req.headers['origin'][0] in ["https://a.example.com"]`
+		assert.Equal(t, trafficpolicy.Rule{
+			Name: "Annotation-CORS-Preflight",
+			Expressions: []string{
+				"req.method == 'OPTIONS' && 'access-control-request-method' in req.headers",
+				originExpression,
+			},
+			Actions: []trafficpolicy.Action{trafficpolicy.NewCustomResponseAction(204, "", map[string]string{
+				"Access-Control-Allow-Origin":      "${req.headers['origin'][0]}",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET",
+				"Access-Control-Allow-Headers":     "Content-Type",
+				"Access-Control-Max-Age":           "600",
+				"Vary":                             "Origin",
+			})},
+		}, tp.OnHTTPRequest[0])
+		assert.Equal(t, trafficpolicy.Rule{
+			Name:        "Annotation-CORS-Headers",
+			Expressions: []string{originExpression},
+			Actions: []trafficpolicy.Action{trafficpolicy.NewAddHeadersAction(map[string]string{
+				"Access-Control-Allow-Origin":      "${req.headers['origin'][0]}",
+				"Access-Control-Allow-Credentials": "true",
+				"Vary":                             "Origin",
+			})},
+		}, tp.OnHTTPResponse[0])
+	})
+
+	t.Run("explicit policy wins conflicts and runs after annotation rules", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t)
+		ing := featureAnnotationsIngress(map[string]string{
+			annotations.RateLimitAnnotation:      "100/1m",
+			annotations.RequestHeadersAnnotation: `{"X-Via": "ngrok"}`,
+		})
+		explicit := trafficpolicy.NewTrafficPolicy()
+		explicit.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name: "explicit",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRateLimitAction(trafficpolicy.RateLimitConfig{
+				Algorithm: trafficpolicy.RateLimitAlgorithmSlidingWindow,
+				Capacity:  10,
+				Rate:      "1s",
+				BucketKey: []string{"conn.client_ip"},
+			})},
+		})
+
+		tp, err := tr.featureAnnotationsToTrafficPolicy(ing, explicit)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"Annotation-Request-Headers", "explicit"}, ruleNames(tp.OnHTTPRequest))
+
+		require.Len(t, tr.warnings, 1)
+		assert.Equal(t, ing, tr.warnings[0].Object)
+		assert.Equal(t, ReasonTrafficPolicyAnnotationConflict, tr.warnings[0].Reason)
+		assert.Contains(t, tr.warnings[0].Message, annotations.RateLimitAnnotation)
+	})
+
+	errorCases := []struct {
+		name        string
+		objs        []runtime.Object
+		annotations map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "missing basic auth secret",
+			annotations: map[string]string{annotations.BasicAuthSecretAnnotation: "creds"},
+			expectedErr: `unable to load Secret "creds"`,
+		},
+		{
+			name: "empty basic auth secret",
+			objs: []runtime.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"}}},
+			annotations: map[string]string{
+				annotations.BasicAuthSecretAnnotation: "creds",
+			},
+			expectedErr: "credentials must not be empty",
+		},
+		{
+			name:        "missing ip policy",
+			annotations: map[string]string{annotations.IPPoliciesAnnotation: "office"},
+			expectedErr: `unable to load IPPolicy "office"`,
+		},
+		{
+			name:        "ip policy not yet created",
+			objs:        []runtime.Object{pendingPolicy},
+			annotations: map[string]string{annotations.IPPoliciesAnnotation: "pending"},
+			expectedErr: "has not been created in ngrok yet",
+		},
+		{
+			name: "missing oidc secret",
+			annotations: map[string]string{
+				annotations.OIDCIssuerURLAnnotation: "https://accounts.example.com",
+				annotations.OIDCSecretAnnotation:    "oidc",
+			},
+			expectedErr: `unable to load Secret "oidc"`,
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{annotations.RateLimitAnnotation: "lots"},
+			expectedErr: annotations.RateLimitAnnotation,
+		},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := newFeatureAnnotationsTranslator(t, tc.objs...)
+			_, err := tr.featureAnnotationsToTrafficPolicy(featureAnnotationsIngress(tc.annotations), nil)
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+	t.Run("secret annotations disabled", func(t *testing.T) {
+		tr := newFeatureAnnotationsTranslator(t, basicAuthSecret, oidcSecret)
+		tr.ingressSecretAnnotations = false
+
+		_, err := tr.featureAnnotationsToTrafficPolicy(featureAnnotationsIngress(map[string]string{
+			annotations.BasicAuthSecretAnnotation: "creds",
+		}), nil)
+		assert.ErrorContains(t, err, "--ingress-secret-annotations")
+
+		_, err = tr.featureAnnotationsToTrafficPolicy(featureAnnotationsIngress(map[string]string{
+			annotations.OIDCIssuerURLAnnotation: "https://accounts.example.com",
+			annotations.OIDCSecretAnnotation:    "oidc",
+		}), nil)
+		assert.ErrorContains(t, err, "--ingress-secret-annotations")
+	})
+}
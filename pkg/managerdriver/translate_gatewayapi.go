@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	"github.com/ngrok/ngrok-operator/internal/annotations"
@@ -13,6 +14,7 @@ import (
 	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,6 +34,10 @@ const (
 	LegacyTLSOptionKeyPrefix = "k8s.ngrok.com/terminate-tls."
 
 	// LEGACY-PREFIX-MIGRATION: END
+
+	// TCPAddressGatewayAddressType is the Gateway spec.addresses type for referencing a TCPAddress by name in the
+	// Gateway's namespace. The reserved host is used as the hostname of the Gateway's TCP endpoints.
+	TCPAddressGatewayAddressType gatewayv1.AddressType = "ngrok.com/TCPAddress"
 )
 
 var (
@@ -130,8 +136,26 @@ func (t *translator) findMatchingVHostsForXRoute(
 		}
 
 		// Grab all the hostnames from the Gateway's addresses field. We don't currently allow IP addresses to be specified here, only hostnames
+		// and references to TCPAddresses, whose reserved ports are kept so that only TCP listeners on those ports use them
 		gatewayAddressHostnames := []string{}
+		tcpAddressPorts := map[string]int32{}
+		tcpAddressesResolved := true
 		for _, gatewayAddress := range gateway.Spec.Addresses {
+			if gatewayAddress.Type != nil && *gatewayAddress.Type == TCPAddressGatewayAddressType {
+				host, port, err := t.resolveGatewayTCPAddress(gateway.Namespace, gatewayAddress.Value)
+				if err != nil {
+					t.log.Error(err, "unable to resolve TCPAddress in Gateway spec.addresses, this Gateway will be skipped until it is ready",
+						"gateway", fmt.Sprintf("%s.%s", gateway.Name, gateway.Namespace),
+						"address value", gatewayAddress.Value,
+					)
+					tcpAddressesResolved = false
+					continue
+				}
+				tcpAddressPorts[host] = port
+				gatewayAddressHostnames = appendStringUnique(gatewayAddressHostnames, host)
+				continue
+			}
+
 			if gatewayAddress.Type == nil || *gatewayAddress.Type != gatewayv1.HostnameAddressType {
 				t.log.Error(errors.New("invalid Gateway. non-hostname address in spec.addresses"), "this Gateway will be skipped. only hostname type addresses are supported at the moment. The default type is IPAddress, so the type must explicitly be set to \"Hostname\"",
 					"gateway", fmt.Sprintf("%s.%s", gateway.Name, gateway.Namespace),
@@ -150,6 +174,9 @@ func (t *translator) findMatchingVHostsForXRoute(
 
 			gatewayAddressHostnames = appendStringUnique(gatewayAddressHostnames, gatewayAddress.Value)
 		}
+		if !tcpAddressesResolved {
+			continue
+		}
 
 		// We currently require this annotation to be present for an Ingress to be translated into CloudEndpoints/AgentEndpoints, otherwise the default behaviour is to
 		// translate it into HTTPSEdges (legacy). A future version will remove support for HTTPSEdges and translation into CloudEndpoints/AgentEndpoints will become the new
@@ -271,6 +298,18 @@ func (t *translator) findMatchingVHostsForXRoute(
 			}
 
 			for _, virtualHostHostname := range virtualHostHostnames {
+				// A reserved TCP address can only be used by a TCP listener on its reserved port
+				if reservedPort, isTCPAddress := tcpAddressPorts[virtualHostHostname]; isTCPAddress &&
+					(matchingListener.Protocol != gatewayv1.TCPProtocolType || int32(matchingListener.Port) != reservedPort) {
+					t.log.Error(errors.New("gateway listener does not match the reserved TCP address"), "skipping TCPAddress for gateway listener, TCPAddresses can only be used by TCP listeners on their reserved port",
+						"gateway", fmt.Sprintf("%s.%s", gateway.Name, gateway.Namespace),
+						"listener", string(matchingListener.Name),
+						"listener port", matchingListener.Port,
+						"tcp address", fmt.Sprintf("%s:%d", virtualHostHostname, reservedPort),
+					)
+					continue
+				}
+
 				// Check if this Gateway already has an irVHost for this specific hostname, otherwise make one
 				irListener := ir.IRListener{
 					Hostname: ir.IRHostname(virtualHostHostname),
@@ -344,6 +383,28 @@ func (t *translator) findMatchingVHostsForXRoute(
 	return vHostsMatchingRoute
 }
 
+// resolveGatewayTCPAddress returns the host and port reserved by the named TCPAddress. TCPAddresses that have not
+// reserved their address yet return an error, and the Gateway is translated again once they do.
+func (t *translator) resolveGatewayTCPAddress(namespace, name string) (string, int32, error) {
+	tcpAddress, err := t.store.GetTCPAddressV1(name, namespace)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to get TCPAddress %s.%s: %w", name, namespace, err)
+	}
+	if tcpAddress.Status.Address == "" || !meta.IsStatusConditionTrue(tcpAddress.Status.Conditions, "Ready") {
+		return "", 0, fmt.Errorf("TCPAddress %s.%s is not ready", name, namespace)
+	}
+
+	host, portStr, err := net.SplitHostPort(tcpAddress.Status.Address)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid address %q for TCPAddress %s.%s: %w", tcpAddress.Status.Address, name, namespace, err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in address %q for TCPAddress %s.%s: %w", tcpAddress.Status.Address, name, namespace, err)
+	}
+	return host, int32(port), nil
+}
+
 // #region HTTPRoute to IR
 
 // HTTPRouteToIR translates a single HTTPRoute into IR by finding which Gateways it matches and adding the rules from the HTTPRoute
@@ -368,6 +429,18 @@ func (t *translator) HTTPRouteToIR(
 		// Note: it would be more efficient to build the routes for the HTTPRoute once, then apply them to all matching virtualHosts, but
 		// each Gateway can specify upstream client certificates, so the routes we build are dependent on the current Gateway
 		routesToAdd := t.httpRouteRulesToIR(irVHost, httpRoute, upstreamCache)
+
+		// When the virtual host is for a wildcard hostname, restrict the routes to the HTTPRoute's own hostnames
+		if routeHosts := routeHostsForVirtualHost(string(irVHost.Listener.Hostname), hostnameStrings); routeHosts != nil {
+			hostRoutes := make([]*ir.IRRoute, 0, len(routesToAdd)*len(routeHosts))
+			for _, routeHost := range routeHosts {
+				for _, routeToAdd := range routesToAdd {
+					hostRoutes = append(hostRoutes, withHostMatch(routeToAdd, routeHost))
+				}
+			}
+			routesToAdd = hostRoutes
+		}
+
 		for _, routeToAdd := range routesToAdd {
 			for _, destination := range routeToAdd.Destinations {
 				// Inherit all the virtual host's owning resources
@@ -642,6 +715,10 @@ func (t *translator) matchGatewayListenersToXRoute(
 		// Note: since we are introducing support for Gateway.spec.addresses, I think it would make sense for a future change to make it so that the domains for the endpoints must be specified in the Gateway.spec.addresses field, and the listener.hostname becomes something that is used
 		// for host header and SNI matching on requests.
 
+		//
+		// Wildcard listener hostnames such as "*.example.com" are valid either way. They produce a single wildcard endpoint, and HTTPRoutes attached to them
+		// with narrower hostnames only match requests for those hostnames.
+
 		listenerHostname := "*"
 		if len(gatewayAddressHostnames) == 0 {
 			if listener.Hostname == nil {
@@ -894,18 +971,8 @@ func gwapiRequestHeaderFilterToTrafficPolicy(filter gatewayv1.HTTPRouteFilter) (
 	ret.OnHTTPRequest = []trafficpolicy.Rule{{
 		Name: "GatewayAPI-Request-Header-Filter",
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_RemoveHeaders,
-				Config: map[string]any{
-					"headers": headersToRemove,
-				},
-			},
-			{
-				Type: trafficpolicy.ActionType_AddHeaders,
-				Config: map[string]any{
-					"headers": headersToAdd,
-				},
-			},
+			trafficpolicy.NewRemoveHeadersAction(headersToRemove),
+			trafficpolicy.NewAddHeadersAction(headersToAdd),
 		},
 	}}
 
@@ -940,18 +1007,8 @@ func gwapiResponseHeaderFilterToTrafficPolicy(filter gatewayv1.HTTPRouteFilter)
 	ret.OnHTTPResponse = []trafficpolicy.Rule{{
 		Name: "GatewayAPI-Response-Header-Filter",
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_RemoveHeaders,
-				Config: map[string]any{
-					"headers": headersToRemove,
-				},
-			},
-			{
-				Type: trafficpolicy.ActionType_AddHeaders,
-				Config: map[string]any{
-					"headers": headersToAdd,
-				},
-			},
+			trafficpolicy.NewRemoveHeadersAction(headersToRemove),
+			trafficpolicy.NewAddHeadersAction(headersToAdd),
 		},
 	}}
 
@@ -1030,20 +1087,17 @@ func gwapiRedirectFilterToTrafficPolicy(filter gatewayv1.HTTPRouteFilter, matchC
 	ret.OnHTTPRequest = []trafficpolicy.Rule{{
 		Name: "GatewayAPI-Redirect-Filter",
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_Redirect,
-				Config: map[string]any{
-					"from": from,
-					"to": fmt.Sprintf("%s%s%s%s%s",
-						toScheme,
-						toHostname,
-						toPort,
-						toPrefix,
-						toRemainingPath,
-					),
-					"status_code": statusCode,
-				},
-			},
+			trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
+				From: &from,
+				To: fmt.Sprintf("%s%s%s%s%s",
+					toScheme,
+					toHostname,
+					toPort,
+					toPrefix,
+					toRemainingPath,
+				),
+				StatusCode: &statusCode,
+			}),
 		},
 	}}
 
@@ -1101,19 +1155,16 @@ func gwapiURLRewriteFilterToTrafficPolicy(filter gatewayv1.HTTPRouteFilter, matc
 	ret.OnHTTPRequest = []trafficpolicy.Rule{{
 		Name: "GatewayAPI-URL-Rewrite-Filter",
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_URLRewrite,
-				Config: map[string]any{
-					"from": from,
-					"to": fmt.Sprintf("%s%s%s%s%s",
-						toScheme,
-						toHostname,
-						toPort,
-						toPrefix,
-						toRemainingPath,
-					),
-				},
-			},
+			trafficpolicy.NewURLRewriteAction(trafficpolicy.URLRewriteConfig{
+				From: from,
+				To: fmt.Sprintf("%s%s%s%s%s",
+					toScheme,
+					toHostname,
+					toPort,
+					toPrefix,
+					toRemainingPath,
+				),
+			}),
 		},
 	}}
 
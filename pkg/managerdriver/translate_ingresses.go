@@ -1,10 +1,12 @@
 package managerdriver
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
 
+	ngrokv1alpha1 "github.com/ngrok/ngrok-operator/api/ngrok/v1alpha1"
 	"github.com/ngrok/ngrok-operator/internal/annotations"
 	"github.com/ngrok/ngrok-operator/internal/errors"
 	"github.com/ngrok/ngrok-operator/internal/ir"
@@ -15,6 +17,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ReasonInvalidEndpointSchedule is the event reason used when the endpoint schedule annotation on a resource is
+// invalid
+const ReasonInvalidEndpointSchedule = "InvalidEndpointSchedule"
+
 // #region Ingresses to IR
 
 // ingressesToIR fetches all stored ingresses and translates them into IR for further processing and translation
@@ -23,7 +29,17 @@ func (t *translator) ingressesToIR() []*ir.IRVirtualHost {
 	upstreamCache := make(map[ir.IRServiceKey]*ir.IRUpstream) // Each unique service/port combo corresponds to one IRUpstream
 
 	ingresses := t.store.ListNgrokIngressesV1()
+	nginxCanaries := []*netv1.Ingress{}
 	for _, ingress := range ingresses {
+		if t.ingressNginxCompatibility {
+			t.reportIgnoredNginxAnnotations(ingress)
+			// Canaries only add backends to the routes of their primary Ingress, so they are handled once all others are
+			if isNginxCanary(ingress) {
+				nginxCanaries = append(nginxCanaries, ingress)
+				continue
+			}
+		}
+
 		// We currently require this annotation to be present for an Ingress to be translated into CloudEndpoints/AgentEndpoints, otherwise the default behaviour is to
 		// translate it into HTTPSEdges (legacy). A future version will remove support for HTTPSEdges and translation into CloudEndpoints/AgentEndpoints will become the new
 		// default behaviour.
@@ -50,6 +66,24 @@ func (t *translator) ingressesToIR() []*ir.IRVirtualHost {
 			continue
 		}
 
+		annotationTrafficPolicy, err = t.featureAnnotationsToTrafficPolicy(ingress, annotationTrafficPolicy)
+		if err != nil {
+			t.log.Error(err, "error compiling feature annotations for ingress",
+				"ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace))
+			t.warn(ingress, ReasonInvalidFeatureAnnotations, err.Error())
+			continue
+		}
+
+		if t.ingressNginxCompatibility {
+			annotationTrafficPolicy, err = t.nginxAnnotationsToTrafficPolicy(ingress, annotationTrafficPolicy)
+			if err != nil {
+				t.log.Error(err, "error translating ingress-nginx annotations for ingress",
+					"ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace))
+				t.warn(ingress, ReasonInvalidNginxAnnotations, err.Error())
+				continue
+			}
+		}
+
 		var defaultDestination *ir.IRDestination
 		if ingress.Spec.DefaultBackend != nil {
 			defaultDestination, err = t.ingressBackendToIR(ingress, ingress.Spec.DefaultBackend, upstreamCache)
@@ -69,6 +103,15 @@ func (t *translator) ingressesToIR() []*ir.IRVirtualHost {
 			continue
 		}
 
+		endpointSchedule, err := annotations.ExtractEndpointSchedule(ingress)
+		if err != nil {
+			t.log.Error(err, "failed to check endpoint schedule annotation for ingress",
+				"ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace),
+			)
+			t.warn(ingress, ReasonInvalidEndpointSchedule, err.Error())
+			continue
+		}
+
 		resourceMetadata, err := annotations.ExtractMetadata(ingress)
 		if err != nil {
 			t.log.Error(err, fmt.Sprintf("failed to read %q annotation for ingress", annotations.MetadataAnnotation),
@@ -92,12 +135,17 @@ func (t *translator) ingressesToIR() []*ir.IRVirtualHost {
 			annotationTrafficPolicy,
 			tpObjRef,
 			bindings,
+			endpointSchedule,
 			mappingStrategy,
 			resourceMetadata,
 			resourceDescription,
 		)
 	}
 
+	for _, canary := range nginxCanaries {
+		t.nginxCanaryToIR(canary, hostCache, upstreamCache)
+	}
+
 	vHostSlice := []*ir.IRVirtualHost{}
 	for _, irVHost := range hostCache {
 		vHostSlice = append(vHostSlice, irVHost)
@@ -118,6 +166,7 @@ func (t *translator) ingressToIR(
 	annotationTrafficPolicy *trafficpolicy.TrafficPolicy,
 	annotationTrafficPolicyRef *ir.OwningResource,
 	bindings []string,
+	endpointSchedule *ngrokv1alpha1.EndpointSchedule,
 	mappingStrategy ir.IRMappingStrategy,
 	resourceMetadata string,
 	resourceDescription string,
@@ -149,6 +198,15 @@ func (t *translator) ingressToIR(
 				)
 				continue
 			}
+			// Feature annotations are compiled into the traffic policy, so it must match as well
+			if !trafficPoliciesEqual(irVHost.TrafficPolicy, annotationTrafficPolicy) {
+				t.log.Error(errors.New("different traffic policy feature annotations provided for the same hostname"),
+					"when using the same hostname across multiple ingresses, ensure that they use the same rate limit, auth, IP policy, CORS and header annotations",
+					"current ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace),
+					"hostname", ruleHostname,
+				)
+				continue
+			}
 			// They must have the same configuration for whether or not to pool endpoints
 			if !ptr.Equal(irVHost.EndpointPoolingEnabled, endpointPoolingEnabled) {
 				t.log.Error(errors.New("different endpoint pooling annotations provided for the same hostname"),
@@ -158,6 +216,15 @@ func (t *translator) ingressToIR(
 				)
 				continue
 			}
+			// They must have the same schedule, since they share the endpoint
+			if !reflect.DeepEqual(irVHost.Schedule, endpointSchedule) {
+				t.log.Error(errors.New("different endpoint schedule annotations provided for the same hostname"),
+					"when using the same hostname across multiple ingresses, ensure that they all have the same endpoint schedule",
+					"current ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace),
+					"hostname", ruleHostname,
+				)
+				continue
+			}
 
 			// They must share the same namespace
 			if irVHost.Namespace != ingress.Namespace {
@@ -227,6 +294,7 @@ func (t *translator) ingressToIR(
 				Metadata:               ir.MergeMetadata(t.defaultIngressMetadata, resourceMetadata),
 				Description:            resourceDescription,
 				Bindings:               bindings,
+				Schedule:               endpointSchedule,
 				MappingStrategy:        mappingStrategy,
 			}
 			hostCache[ir.IRHostname(ruleHostname)] = irVHost
@@ -261,13 +329,25 @@ func (t *translator) ingressPathsToIR(ingress *netv1.Ingress, ruleHostname strin
 		}
 
 		pathType := netv1PathTypeToIR(t.log, pathMatch.PathType)
-		irRoutes = append(irRoutes, &ir.IRRoute{
+		irRoute := &ir.IRRoute{
 			HTTPMatchCriteria: &ir.IRHTTPMatch{
 				Path:     &pathMatch.Path,
 				PathType: &pathType,
 			},
 			Destinations: []*ir.IRDestination{destination},
-		})
+		}
+		if t.ingressNginxCompatibility {
+			if err := t.nginxPathToIR(ingress, ruleHostname, pathMatch, irRoute); err != nil {
+				t.log.Error(err, "ingress rule could not be successfully processed. other ingress rules will continue to be evaluated",
+					"ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace),
+					"hostname", ruleHostname,
+					"path", pathMatch.Path,
+				)
+				t.warn(ingress, ReasonInvalidNginxAnnotations, err.Error())
+				continue
+			}
+		}
+		irRoutes = append(irRoutes, irRoute)
 	}
 	return irRoutes
 }
@@ -412,3 +492,25 @@ func trafficPolicyFromAnnotation(store store.Storer, obj client.Object) (tp *tra
 		Namespace: tpObj.Namespace,
 	}, nil
 }
+
+// trafficPoliciesEqual compares two traffic policies by their JSON content, since typed and
+// generic action configs that encode the same fields are equivalent.
+func trafficPoliciesEqual(a, b *trafficpolicy.TrafficPolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	var aContent, bContent any
+	for _, pair := range []struct {
+		tp  *trafficpolicy.TrafficPolicy
+		out *any
+	}{{a, &aContent}, {b, &bContent}} {
+		data, err := json.Marshal(pair.tp)
+		if err != nil {
+			return false
+		}
+		if err := json.Unmarshal(data, pair.out); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(aContent, bContent)
+}
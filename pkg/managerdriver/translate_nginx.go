@@ -0,0 +1,386 @@
+package managerdriver
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	netv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The ingress-nginx annotations understood when ingress-nginx compatibility is enabled. Any other
+// annotation with the nginx.ingress.kubernetes.io/ prefix is reported as ignored.
+const (
+	nginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
+	nginxRewriteTargetAnnotation         = nginxAnnotationPrefix + "rewrite-target"
+	nginxUseRegexAnnotation              = nginxAnnotationPrefix + "use-regex"
+	nginxSSLRedirectAnnotation           = nginxAnnotationPrefix + "ssl-redirect"
+	nginxForceSSLRedirectAnnotation      = nginxAnnotationPrefix + "force-ssl-redirect"
+	nginxAllowlistSourceRangeAnnotation  = nginxAnnotationPrefix + "allowlist-source-range"
+	nginxWhitelistSourceRangeAnnotation  = nginxAnnotationPrefix + "whitelist-source-range"
+	nginxDenylistSourceRangeAnnotation   = nginxAnnotationPrefix + "denylist-source-range"
+	nginxAppRootAnnotation               = nginxAnnotationPrefix + "app-root"
+	nginxCanaryAnnotation                = nginxAnnotationPrefix + "canary"
+	nginxCanaryWeightAnnotation          = nginxAnnotationPrefix + "canary-weight"
+	nginxCanaryWeightTotalAnnotation     = nginxAnnotationPrefix + "canary-weight-total"
+	nginxCanaryByHeaderAnnotation        = nginxAnnotationPrefix + "canary-by-header"
+	nginxCanaryByHeaderValueAnnotation   = nginxAnnotationPrefix + "canary-by-header-value"
+	nginxCanaryByHeaderPatternAnnotation = nginxAnnotationPrefix + "canary-by-header-pattern"
+
+	// ReasonIgnoredNginxAnnotations is the event reason used when an Ingress has ingress-nginx annotations
+	// that have no ngrok equivalent and are ignored.
+	ReasonIgnoredNginxAnnotations = "IgnoredNginxAnnotations"
+	// ReasonInvalidNginxAnnotations is the event reason used when supported ingress-nginx annotations on
+	// an Ingress cannot be translated.
+	ReasonInvalidNginxAnnotations = "InvalidNginxAnnotations"
+
+	// nginxDefaultCanaryWeightTotal is the total canary weights are relative to, unless overridden
+	nginxDefaultCanaryWeightTotal = 100
+)
+
+var supportedNginxAnnotations = []string{
+	nginxRewriteTargetAnnotation,
+	nginxUseRegexAnnotation,
+	nginxSSLRedirectAnnotation,
+	nginxForceSSLRedirectAnnotation,
+	nginxAllowlistSourceRangeAnnotation,
+	nginxWhitelistSourceRangeAnnotation,
+	nginxDenylistSourceRangeAnnotation,
+	nginxAppRootAnnotation,
+	nginxCanaryAnnotation,
+	nginxCanaryWeightAnnotation,
+	nginxCanaryWeightTotalAnnotation,
+	nginxCanaryByHeaderAnnotation,
+	nginxCanaryByHeaderValueAnnotation,
+	nginxCanaryByHeaderPatternAnnotation,
+}
+
+// urlOriginPattern matches the scheme, hostname and port of a URL as the first three capture groups
+const urlOriginPattern = `^([a-zA-Z][a-zA-Z0-9+\-.]*):\/\/([^\/:]+)(:\d+)?`
+
+// captureGroupReference matches $1 and ${1} style references to capture groups
+var captureGroupReference = regexp.MustCompile(`\$(\d+)|\$\{(\d+)\}`)
+
+// nginxRouteKey identifies the route generated for a path of a (non-canary) Ingress so canary Ingresses
+// can find the route they split traffic with
+type nginxRouteKey struct {
+	hostname string
+	path     string
+	pathType netv1.PathType
+}
+
+func nginxAnnotation(obj client.Object, annotation string) (string, bool) {
+	val, ok := obj.GetAnnotations()[annotation]
+	return strings.TrimSpace(val), ok
+}
+
+func nginxBoolAnnotation(obj client.Object, annotation string) (bool, error) {
+	val, ok := nginxAnnotation(obj, annotation)
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("annotation %q must be a boolean, got %q", annotation, val)
+	}
+	return b, nil
+}
+
+// nginxCIDRsAnnotation returns the CIDRs in the first of annotations that is set, along with its name
+func nginxCIDRsAnnotation(obj client.Object, annotations ...string) ([]string, string) {
+	for _, annotation := range annotations {
+		if val, ok := nginxAnnotation(obj, annotation); ok {
+			cidrs := []string{}
+			for cidr := range strings.SplitSeq(val, ",") {
+				if cidr = strings.TrimSpace(cidr); cidr != "" {
+					cidrs = append(cidrs, cidr)
+				}
+			}
+			return cidrs, annotation
+		}
+	}
+	return nil, ""
+}
+
+// isNginxCanary returns true if the Ingress is an ingress-nginx canary for another Ingress
+func isNginxCanary(ingress *netv1.Ingress) bool {
+	canary, err := nginxBoolAnnotation(ingress, nginxCanaryAnnotation)
+	return err == nil && canary
+}
+
+// reportIgnoredNginxAnnotations records a warning listing the ingress-nginx annotations on the Ingress that
+// are not supported, so users can see what did not carry over when moving to ngrok
+func (t *translator) reportIgnoredNginxAnnotations(ingress *netv1.Ingress) {
+	ignored := []string{}
+	for annotation := range ingress.GetAnnotations() {
+		if strings.HasPrefix(annotation, nginxAnnotationPrefix) && !slices.Contains(supportedNginxAnnotations, annotation) {
+			ignored = append(ignored, annotation)
+		}
+	}
+	if len(ignored) == 0 {
+		return
+	}
+	slices.Sort(ignored)
+	t.warn(ingress, ReasonIgnoredNginxAnnotations,
+		fmt.Sprintf("ignoring unsupported ingress-nginx annotations: %s", strings.Join(ignored, ", ")))
+}
+
+// #region Ingress-wide annotations
+
+// nginxAnnotationsToTrafficPolicy compiles the ingress-nginx annotations that apply to every request for the
+// Ingress (source ranges, SSL redirects and app-root) into traffic policy rules that run before the rules in
+// existing. A feature whose action is already configured by existing is skipped and a warning is recorded.
+func (t *translator) nginxAnnotationsToTrafficPolicy(ingress *netv1.Ingress, existing *trafficpolicy.TrafficPolicy) (*trafficpolicy.TrafficPolicy, error) {
+	tp := trafficpolicy.NewTrafficPolicy()
+
+	allow, allowAnnotation := nginxCIDRsAnnotation(ingress, nginxAllowlistSourceRangeAnnotation, nginxWhitelistSourceRangeAnnotation)
+	deny, denyAnnotation := nginxCIDRsAnnotation(ingress, nginxDenylistSourceRangeAnnotation)
+	if len(allow)+len(deny) > 0 && !t.annotationConflicts(ingress, existing, cmp.Or(allowAnnotation, denyAnnotation), trafficpolicy.ActionType_RestrictIPs) {
+		cfg := trafficpolicy.RestrictIPsConfig{Allow: allow, Deny: deny}
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid source range annotations: %w", err)
+		}
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:    "Ingress-Nginx-Source-Range",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRestrictIPsAction(cfg)},
+		})
+	}
+
+	sslRedirect, err := nginxBoolAnnotation(ingress, nginxSSLRedirectAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	forceSSLRedirect, err := nginxBoolAnnotation(ingress, nginxForceSSLRedirectAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if sslRedirect || forceSSLRedirect {
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name: "Ingress-Nginx-SSL-Redirect",
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
+				From:       new(`^http://(.*)$`),
+				To:         "https://$1",
+				StatusCode: new(308),
+			})},
+		})
+	}
+
+	if appRoot, ok := nginxAnnotation(ingress, nginxAppRootAnnotation); ok {
+		if !strings.HasPrefix(appRoot, "/") {
+			return nil, fmt.Errorf("annotation %q must be an absolute path, got %q", nginxAppRootAnnotation, appRoot)
+		}
+		tp.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+			Name:        "Ingress-Nginx-App-Root",
+			Expressions: []string{"req.url.path == '/'"},
+			Actions: []trafficpolicy.Action{trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
+				From:       new(urlOriginPattern + `.*$`),
+				To:         "$1://$2$3" + appRoot,
+				StatusCode: new(302),
+			})},
+		})
+	}
+
+	if tp.IsEmpty() {
+		return existing, nil
+	}
+	if err := tp.Validate(); err != nil {
+		return nil, err
+	}
+	tp.Merge(existing)
+	return tp, nil
+}
+
+// #region Path annotations
+
+// nginxPathToIR applies the ingress-nginx annotations that change how the paths of an Ingress are matched and
+// rewritten to the route generated for one of its paths. Like ingress-nginx, paths are treated as regular
+// expressions when use-regex is set or a rewrite-target is configured.
+func (t *translator) nginxPathToIR(ingress *netv1.Ingress, ruleHostname string, ingressPath netv1.HTTPIngressPath, route *ir.IRRoute) error {
+	if t.nginxRoutes == nil {
+		t.nginxRoutes = make(map[nginxRouteKey]*ir.IRRoute)
+	}
+	t.nginxRoutes[nginxPathKey(ruleHostname, ingressPath)] = route
+
+	rewriteTarget, rewrite := nginxAnnotation(ingress, nginxRewriteTargetAnnotation)
+	useRegex, err := nginxBoolAnnotation(ingress, nginxUseRegexAnnotation)
+	if err != nil {
+		return err
+	}
+	if !useRegex && !rewrite {
+		return nil
+	}
+
+	pathRegex := strings.TrimPrefix(ingressPath.Path, "^")
+	if ingressPath.PathType != nil && *ingressPath.PathType == netv1.PathTypeExact && !strings.HasSuffix(pathRegex, "$") {
+		pathRegex += "$"
+	}
+	compiled, err := regexp.Compile(pathRegex)
+	if err != nil {
+		return fmt.Errorf("path %q is not a valid regular expression: %w", ingressPath.Path, err)
+	}
+	// The regular expression is matched in a CEL raw string literal, which can't contain single quotes
+	if strings.Contains(pathRegex, "'") {
+		return fmt.Errorf("path %q must not contain a single quote when used as a regular expression", ingressPath.Path)
+	}
+
+	path := "^" + pathRegex
+	pathType := ir.IRPathType_Regex
+	route.HTTPMatchCriteria.Path = &path
+	route.HTTPMatchCriteria.PathType = &pathType
+
+	if !rewrite {
+		return nil
+	}
+
+	// ingress-nginx replaces the whole path with the rewrite target, which may reference the capture groups of
+	// the path. The groups are shifted past the URL origin, and the query string is kept.
+	target := captureGroupReference.ReplaceAllStringFunc(rewriteTarget, func(ref string) string {
+		n, _ := strconv.Atoi(strings.Trim(ref, "${}"))
+		return fmt.Sprintf("${%d}", n+3)
+	})
+	rewriteTP := trafficpolicy.NewTrafficPolicy()
+	rewriteTP.AddRuleOnHTTPRequest(trafficpolicy.Rule{
+		Name: "Ingress-Nginx-Rewrite-Target",
+		Actions: []trafficpolicy.Action{trafficpolicy.NewURLRewriteAction(trafficpolicy.URLRewriteConfig{
+			From: urlOriginPattern + pathRegex + `[^?]*(\?.*)?$`,
+			To:   fmt.Sprintf("$1://$2$3%s${%d}", target, compiled.NumSubexp()+4),
+		})},
+	})
+	if err := rewriteTP.Validate(); err != nil {
+		return err
+	}
+	route.TrafficPolicies = append(route.TrafficPolicies, rewriteTP)
+	return nil
+}
+
+func nginxPathKey(hostname string, ingressPath netv1.HTTPIngressPath) nginxRouteKey {
+	pathType := netv1.PathTypeImplementationSpecific
+	if ingressPath.PathType != nil {
+		pathType = *ingressPath.PathType
+	}
+	return nginxRouteKey{hostname: hostname, path: ingressPath.Path, pathType: pathType}
+}
+
+// #region Canary Ingresses
+
+// nginxCanaryToIR adds the backends of an ingress-nginx canary Ingress to the routes generated for the same
+// hosts and paths by the primary Ingress. Requests are sent to the canary backends when they match the
+// canary-by-header annotations, otherwise traffic is split according to the canary-weight annotations.
+func (t *translator) nginxCanaryToIR(ingress *netv1.Ingress, hostCache map[ir.IRHostname]*ir.IRVirtualHost, upstreamCache map[ir.IRServiceKey]*ir.IRUpstream) {
+	weight, total, err := nginxCanaryWeight(ingress)
+	if err != nil {
+		t.warn(ingress, ReasonInvalidNginxAnnotations, fmt.Sprintf("ignoring canary Ingress: %s", err))
+		return
+	}
+	headerMatch := nginxCanaryHeaderMatch(ingress)
+	if weight == 0 && headerMatch == nil {
+		t.warn(ingress, ReasonInvalidNginxAnnotations,
+			fmt.Sprintf("ignoring canary Ingress: one of %q or %q must be set", nginxCanaryWeightAnnotation, nginxCanaryByHeaderAnnotation))
+		return
+	}
+
+	owningResource := ir.OwningResource{
+		Kind:      "Ingress",
+		Name:      ingress.Name,
+		Namespace: ingress.Namespace,
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		irVHost, exists := hostCache[ir.IRHostname(rule.Host)]
+		if !exists || irVHost.Namespace != ingress.Namespace {
+			t.warn(ingress, ReasonInvalidNginxAnnotations,
+				fmt.Sprintf("ignoring canary rule for host %q: no primary Ingress in namespace %q uses this host", rule.Host, ingress.Namespace))
+			continue
+		}
+
+		for _, ingressPath := range rule.HTTP.Paths {
+			primary, exists := t.nginxRoutes[nginxPathKey(rule.Host, ingressPath)]
+			if !exists {
+				t.warn(ingress, ReasonInvalidNginxAnnotations,
+					fmt.Sprintf("ignoring canary path %q for host %q: no primary Ingress has the same path", ingressPath.Path, rule.Host))
+				continue
+			}
+
+			destination, err := t.ingressBackendToIR(ingress, &ingressPath.Backend, upstreamCache)
+			if err != nil {
+				t.log.Error(err, "canary ingress path could not be successfully processed",
+					"ingress", fmt.Sprintf("%s.%s", ingress.Name, ingress.Namespace),
+					"hostname", rule.Host,
+					"path", ingressPath.Path,
+				)
+				continue
+			}
+
+			if headerMatch != nil {
+				matchCriteria := *primary.HTTPMatchCriteria
+				matchCriteria.Headers = append(slices.Clone(matchCriteria.Headers), *headerMatch)
+				irVHost.Routes = append(irVHost.Routes, &ir.IRRoute{
+					HTTPMatchCriteria: &matchCriteria,
+					TrafficPolicies:   primary.TrafficPolicies,
+					Destinations:      []*ir.IRDestination{destination},
+				})
+			}
+
+			if weight > 0 {
+				if len(primary.Destinations) != 1 || primary.Destinations[0].Weight != nil {
+					t.warn(ingress, ReasonInvalidNginxAnnotations,
+						fmt.Sprintf("ignoring canary weight for path %q on host %q: traffic for the path is already split", ingressPath.Path, rule.Host))
+				} else if weight >= total {
+					primary.Destinations = []*ir.IRDestination{destination}
+				} else {
+					primaryWeight := total - weight
+					primary.Destinations[0].Weight = &primaryWeight
+					destination.Weight = &weight
+					primary.Destinations = append(primary.Destinations, destination)
+				}
+			}
+		}
+		irVHost.AddOwningResource(owningResource)
+	}
+}
+
+// nginxCanaryWeight returns the canary weight and the total it is relative to
+func nginxCanaryWeight(ingress *netv1.Ingress) (weight int, total int, err error) {
+	total = nginxDefaultCanaryWeightTotal
+	if val, ok := nginxAnnotation(ingress, nginxCanaryWeightTotalAnnotation); ok {
+		if total, err = strconv.Atoi(val); err != nil || total <= 0 {
+			return 0, 0, fmt.Errorf("annotation %q must be a positive integer, got %q", nginxCanaryWeightTotalAnnotation, val)
+		}
+	}
+	if val, ok := nginxAnnotation(ingress, nginxCanaryWeightAnnotation); ok {
+		if weight, err = strconv.Atoi(val); err != nil || weight < 0 {
+			return 0, 0, fmt.Errorf("annotation %q must be a non-negative integer, got %q", nginxCanaryWeightAnnotation, val)
+		}
+	}
+	return weight, total, nil
+}
+
+// nginxCanaryHeaderMatch returns the header requests must have to be sent to the canary, if any. Without a
+// value or pattern, ingress-nginx sends requests with the header set to "always" to the canary.
+func nginxCanaryHeaderMatch(ingress *netv1.Ingress) *ir.IRHeaderMatch {
+	header, ok := nginxAnnotation(ingress, nginxCanaryByHeaderAnnotation)
+	if !ok || header == "" {
+		return nil
+	}
+	match := &ir.IRHeaderMatch{
+		Name:      strings.ToLower(header),
+		Value:     "always",
+		ValueType: ir.IRStringValueType_Exact,
+	}
+	if value, ok := nginxAnnotation(ingress, nginxCanaryByHeaderValueAnnotation); ok && value != "" {
+		match.Value = value
+	} else if pattern, ok := nginxAnnotation(ingress, nginxCanaryByHeaderPatternAnnotation); ok && pattern != "" {
+		match.Value = pattern
+		match.ValueType = ir.IRStringValueType_Regex
+	}
+	return match
+}
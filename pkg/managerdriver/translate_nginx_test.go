@@ -0,0 +1,260 @@
+package managerdriver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/store"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func newNginxTranslator(t *testing.T, objs ...runtime.Object) *translator {
+	t.Helper()
+	logger := logr.New(logr.Discard().GetSink())
+	s := store.New(store.NewCacheStores(logger), "ngrok.com/ingress-controller", logger)
+	objs = append(objs,
+		&netv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ngrok"},
+			Spec:       netv1.IngressClassSpec{Controller: "ngrok.com/ingress-controller"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "app-uid"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-canary", Namespace: "default", UID: "canary-uid"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+		},
+	)
+	for _, obj := range objs {
+		require.NoError(t, s.Add(obj))
+	}
+	return &translator{log: logger, store: s, ingressNginxCompatibility: true}
+}
+
+func nginxIngress(name, service, path string, annotations map[string]string) *netv1.Ingress {
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: netv1.IngressSpec{
+			IngressClassName: ptr.To("ngrok"),
+			Rules: []netv1.IngressRule{{
+				Host: "app.example.com",
+				IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{
+					Paths: []netv1.HTTPIngressPath{{
+						Path:     path,
+						PathType: ptr.To(netv1.PathTypeImplementationSpecific),
+						Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+							Name: service,
+							Port: netv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestNginxAnnotationsToIR(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/", map[string]string{
+			"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8",
+			"nginx.ingress.kubernetes.io/configuration-snippet":  "more_set_headers \"X: y\";",
+		}))
+		tr.ingressNginxCompatibility = false
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		assert.Nil(t, vhosts[0].TrafficPolicy)
+		assert.Empty(t, tr.warnings)
+	})
+
+	t.Run("ingress-wide annotations", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/", map[string]string{
+			"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8, 192.168.0.0/16",
+			"nginx.ingress.kubernetes.io/force-ssl-redirect":     "true",
+			"nginx.ingress.kubernetes.io/app-root":               "/dashboard",
+		}))
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		require.NotNil(t, vhosts[0].TrafficPolicy)
+
+		// The virtual host holds a copy of the policy, so compare the encoded rules
+		expected, err := json.Marshal([]trafficpolicy.Rule{
+			{
+				Name: "Ingress-Nginx-Source-Range",
+				Actions: []trafficpolicy.Action{trafficpolicy.NewRestrictIPsAction(trafficpolicy.RestrictIPsConfig{
+					Allow: []string{"10.0.0.0/8", "192.168.0.0/16"},
+				})},
+			},
+			{
+				Name: "Ingress-Nginx-SSL-Redirect",
+				Actions: []trafficpolicy.Action{trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
+					From:       ptr.To(`^http://(.*)$`),
+					To:         "https://$1",
+					StatusCode: ptr.To(308),
+				})},
+			},
+			{
+				Name:        "Ingress-Nginx-App-Root",
+				Expressions: []string{"req.url.path == '/'"},
+				Actions: []trafficpolicy.Action{trafficpolicy.NewRedirectAction(trafficpolicy.RedirectConfig{
+					From:       ptr.To(urlOriginPattern + `.*$`),
+					To:         "$1://$2$3/dashboard",
+					StatusCode: ptr.To(302),
+				})},
+			},
+		})
+		require.NoError(t, err)
+		actual, err := json.Marshal(vhosts[0].TrafficPolicy.OnHTTPRequest)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual))
+		assert.Empty(t, tr.warnings)
+	})
+
+	t.Run("invalid source range skips the ingress", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/", map[string]string{
+			"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/33",
+		}))
+
+		assert.Empty(t, tr.ingressesToIR())
+		require.Len(t, tr.warnings, 1)
+		assert.Equal(t, ReasonInvalidNginxAnnotations, tr.warnings[0].Reason)
+	})
+
+	t.Run("ignored annotations are reported", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/", map[string]string{
+			"nginx.ingress.kubernetes.io/ssl-redirect":          "false",
+			"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X: y\";",
+			"nginx.ingress.kubernetes.io/auth-url":              "https://auth.example.com",
+		}))
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		assert.Nil(t, vhosts[0].TrafficPolicy)
+		require.Len(t, tr.warnings, 1)
+		assert.Equal(t, ReasonIgnoredNginxAnnotations, tr.warnings[0].Reason)
+		assert.Equal(t, "ignoring unsupported ingress-nginx annotations: nginx.ingress.kubernetes.io/auth-url, nginx.ingress.kubernetes.io/configuration-snippet", tr.warnings[0].Message)
+	})
+
+	t.Run("rewrite target", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/api(/|$)(.*)", map[string]string{
+			"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+		}))
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		require.Len(t, vhosts[0].Routes, 1)
+		route := vhosts[0].Routes[0]
+		assert.Equal(t, "^/api(/|$)(.*)", *route.HTTPMatchCriteria.Path)
+		assert.Equal(t, ir.IRPathType_Regex, *route.HTTPMatchCriteria.PathType)
+
+		require.Len(t, route.TrafficPolicies, 1)
+		rewrite := route.TrafficPolicies[0].OnHTTPRequest[0].Actions[0]
+		assert.Equal(t, trafficpolicy.NewURLRewriteAction(trafficpolicy.URLRewriteConfig{
+			From: urlOriginPattern + `/api(/|$)(.*)[^?]*(\?.*)?$`,
+			To:   "$1://$2$3/${5}${6}",
+		}), rewrite)
+
+		assert.Equal(t, []string{"vars.original_path.matches(r'^/api(/|$)(.*)')"}, irMatchCriteriaToTPExpressions(route.HTTPMatchCriteria, true))
+	})
+
+	t.Run("use regex with an invalid path", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/(unclosed", map[string]string{
+			"nginx.ingress.kubernetes.io/use-regex": "true",
+		}))
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		assert.Empty(t, vhosts[0].Routes)
+		require.Len(t, tr.warnings, 1)
+		assert.Equal(t, ReasonInvalidNginxAnnotations, tr.warnings[0].Reason)
+	})
+
+	t.Run("use regex with a single quote in the path", func(t *testing.T) {
+		tr := newNginxTranslator(t, nginxIngress("app", "app", "/api' || true || '/(.*)", map[string]string{
+			"nginx.ingress.kubernetes.io/use-regex": "true",
+		}))
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		assert.Empty(t, vhosts[0].Routes)
+		require.Len(t, tr.warnings, 1)
+		assert.Equal(t, ReasonInvalidNginxAnnotations, tr.warnings[0].Reason)
+		assert.Contains(t, tr.warnings[0].Message, "single quote")
+	})
+
+	t.Run("canary by weight", func(t *testing.T) {
+		tr := newNginxTranslator(t,
+			nginxIngress("app", "app", "/", nil),
+			nginxIngress("app-canary", "app-canary", "/", map[string]string{
+				"nginx.ingress.kubernetes.io/canary":        "true",
+				"nginx.ingress.kubernetes.io/canary-weight": "20",
+			}),
+		)
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		require.Len(t, vhosts[0].Routes, 1)
+		destinations := vhosts[0].Routes[0].Destinations
+		require.Len(t, destinations, 2)
+		assert.Equal(t, "app", destinations[0].Upstream.Service.Name)
+		assert.Equal(t, ptr.To(80), destinations[0].Weight)
+		assert.Equal(t, "app-canary", destinations[1].Upstream.Service.Name)
+		assert.Equal(t, ptr.To(20), destinations[1].Weight)
+		assert.Len(t, vhosts[0].OwningResources, 2)
+		assert.Empty(t, tr.warnings)
+	})
+
+	t.Run("canary by header", func(t *testing.T) {
+		tr := newNginxTranslator(t,
+			nginxIngress("app", "app", "/", nil),
+			nginxIngress("app-canary", "app-canary", "/", map[string]string{
+				"nginx.ingress.kubernetes.io/canary":                 "true",
+				"nginx.ingress.kubernetes.io/canary-by-header":       "X-Canary",
+				"nginx.ingress.kubernetes.io/canary-by-header-value": "yes",
+			}),
+		)
+
+		vhosts := tr.ingressesToIR()
+		require.Len(t, vhosts, 1)
+		vhosts[0].SortRoutes()
+		require.Len(t, vhosts[0].Routes, 2)
+
+		canary := vhosts[0].Routes[0]
+		assert.Equal(t, []ir.IRHeaderMatch{{Name: "x-canary", Value: "yes", ValueType: ir.IRStringValueType_Exact}}, canary.HTTPMatchCriteria.Headers)
+		assert.Equal(t, "app-canary", canary.Destinations[0].Upstream.Service.Name)
+		assert.Nil(t, canary.Destinations[0].Weight)
+
+		primary := vhosts[0].Routes[1]
+		assert.Empty(t, primary.HTTPMatchCriteria.Headers)
+		require.Len(t, primary.Destinations, 1)
+		assert.Equal(t, "app", primary.Destinations[0].Upstream.Service.Name)
+	})
+
+	t.Run("canary without primary", func(t *testing.T) {
+		tr := newNginxTranslator(t,
+			nginxIngress("app-canary", "app-canary", "/", map[string]string{
+				"nginx.ingress.kubernetes.io/canary":        "true",
+				"nginx.ingress.kubernetes.io/canary-weight": "20",
+			}),
+		)
+
+		assert.Empty(t, tr.ingressesToIR())
+		require.Len(t, tr.warnings, 1)
+		assert.Contains(t, tr.warnings[0].Message, "no primary Ingress")
+	})
+}
@@ -0,0 +1,151 @@
+package managerdriver
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/ngrok/ngrok-operator/internal/trafficpolicy"
+)
+
+// mergeWildcardVirtualHosts folds HTTP/HTTPS virtual hosts for exact hostnames into the virtual host of the most
+// specific wildcard hostname that matches them, so that a single wildcard endpoint routes on the request host instead
+// of creating one endpoint per hostname. The routes of a folded virtual host are restricted to its hostname and sorted
+// ahead of the wildcard's own routes, so requests for an exact hostname are only ever handled by its own routes.
+//
+// Only virtual hosts that would otherwise produce identical endpoints (same namespace, listener port/protocol, name
+// prefix, traffic policy, TLS termination, pooling, bindings, schedule, metadata and mapping strategy) are folded. Any
+// other exact hostname keeps its own endpoint, which ngrok prefers over the wildcard endpoint for that hostname.
+func (t *translator) mergeWildcardVirtualHosts(irVHosts []*ir.IRVirtualHost) []*ir.IRVirtualHost {
+	wildcards := []string{}
+	for _, irVHost := range irVHosts {
+		if isWildcardHost(string(irVHost.Listener.Hostname)) {
+			wildcards = appendStringUnique(wildcards, string(irVHost.Listener.Hostname))
+		}
+	}
+	if len(wildcards) == 0 {
+		return irVHosts
+	}
+
+	merged := make([]*ir.IRVirtualHost, 0, len(irVHosts))
+	for _, irVHost := range irVHosts {
+		if target := findWildcardVirtualHost(irVHost, irVHosts, wildcards); target != nil {
+			t.log.V(1).Info("merging virtual host into wildcard virtual host",
+				"hostname", string(irVHost.Listener.Hostname),
+				"wildcard", string(target.Listener.Hostname),
+				"generated from resources", irVHost.OwningResources,
+			)
+			foldIntoWildcardVirtualHost(target, irVHost)
+			continue
+		}
+		merged = append(merged, irVHost)
+	}
+	return merged
+}
+
+// findWildcardVirtualHost returns the wildcard virtual host that the given virtual host can be folded into, if any.
+// Wildcard virtual hosts are never folded themselves, so a narrower wildcard keeps its own endpoint.
+func findWildcardVirtualHost(irVHost *ir.IRVirtualHost, irVHosts []*ir.IRVirtualHost, wildcards []string) *ir.IRVirtualHost {
+	hostname := string(irVHost.Listener.Hostname)
+	if isWildcardHost(hostname) {
+		return nil
+	}
+	switch irVHost.Listener.Protocol {
+	case ir.IRProtocol_HTTP, ir.IRProtocol_HTTPS:
+	default:
+		return nil
+	}
+
+	// Try the most specific matching wildcard first, then fall back to less specific ones
+	candidates := slices.Clone(wildcards)
+	for {
+		wildcard := coveringWildcardHost(hostname, candidates)
+		if wildcard == "" {
+			return nil
+		}
+		for _, candidate := range irVHosts {
+			if string(candidate.Listener.Hostname) == wildcard && canFoldIntoWildcard(irVHost, candidate) {
+				return candidate
+			}
+		}
+		candidates = slices.DeleteFunc(candidates, func(c string) bool { return c == wildcard })
+	}
+}
+
+// canFoldIntoWildcard returns true if the endpoint generated for irVHost would be configured identically to the
+// endpoint generated for the wildcard virtual host apart from its routes
+func canFoldIntoWildcard(irVHost, wildcard *ir.IRVirtualHost) bool {
+	return irVHost.Namespace == wildcard.Namespace &&
+		irVHost.Listener.Port == wildcard.Listener.Port &&
+		irVHost.Listener.Protocol == wildcard.Listener.Protocol &&
+		reflect.DeepEqual(irVHost.NamePrefix, wildcard.NamePrefix) &&
+		reflect.DeepEqual(irVHost.TrafficPolicy, wildcard.TrafficPolicy) &&
+		reflect.DeepEqual(irVHost.TLSTermination, wildcard.TLSTermination) &&
+		reflect.DeepEqual(irVHost.EndpointPoolingEnabled, wildcard.EndpointPoolingEnabled) &&
+		slices.Equal(irVHost.Bindings, wildcard.Bindings) &&
+		reflect.DeepEqual(irVHost.Schedule, wildcard.Schedule) &&
+		irVHost.Metadata == wildcard.Metadata &&
+		irVHost.Description == wildcard.Description &&
+		irVHost.MappingStrategy == wildcard.MappingStrategy
+}
+
+// foldIntoWildcardVirtualHost moves the routes of irVHost onto the wildcard virtual host, restricted to the hostname of
+// irVHost. Requests for the hostname that don't match any of its routes go to its default destination, or receive a
+// 404 response when it has none, rather than falling through to the wildcard's routes.
+func foldIntoWildcardVirtualHost(wildcard, irVHost *ir.IRVirtualHost) {
+	hostname := string(irVHost.Listener.Hostname)
+	for _, irRoute := range irVHost.Routes {
+		wildcard.Routes = append(wildcard.Routes, withHostMatch(irRoute, hostname))
+	}
+
+	fallback := &ir.IRRoute{
+		HTTPMatchCriteria: &ir.IRHTTPMatch{Host: &hostname},
+	}
+	if irVHost.DefaultDestination != nil {
+		fallback.Destinations = []*ir.IRDestination{irVHost.DefaultDestination}
+	} else {
+		notFoundPolicy := trafficpolicy.NewTrafficPolicy()
+		notFoundPolicy.AddRuleOnHTTPRequest(buildDefault404TPRule())
+		fallback.TrafficPolicies = []*trafficpolicy.TrafficPolicy{notFoundPolicy}
+	}
+	wildcard.Routes = append(wildcard.Routes, fallback)
+
+	for _, owningResource := range irVHost.OwningResources {
+		wildcard.AddOwningResource(owningResource)
+	}
+}
+
+// withHostMatch returns a copy of the route that only matches requests for the given hostname
+func withHostMatch(irRoute *ir.IRRoute, hostname string) *ir.IRRoute {
+	matchCriteria := &ir.IRHTTPMatch{}
+	if irRoute.HTTPMatchCriteria != nil {
+		copied := *irRoute.HTTPMatchCriteria
+		matchCriteria = &copied
+	}
+	matchCriteria.Host = &hostname
+
+	routeCopy := *irRoute
+	routeCopy.HTTPMatchCriteria = matchCriteria
+	return &routeCopy
+}
+
+// routeHostsForVirtualHost returns the hostnames that an xRoute's rules should be restricted to when added to a
+// wildcard virtual host, such as an HTTPRoute for app.example.com attached to a listener for *.example.com. It returns
+// nil when the rules should match every host of the virtual host.
+func routeHostsForVirtualHost(vHostHostname string, routeHostnames []string) []string {
+	if !isWildcardHost(vHostHostname) || len(routeHostnames) == 0 {
+		return nil
+	}
+
+	hosts := []string{}
+	for _, routeHostname := range routeHostnames {
+		if routeHostname == "*" || routeHostname == vHostHostname || wildcardCoversHost(routeHostname, vHostHostname) {
+			// The route matches every host of the virtual host
+			return nil
+		}
+		if wildcardCoversHost(vHostHostname, routeHostname) {
+			hosts = appendStringUnique(hosts, routeHostname)
+		}
+	}
+	return hosts
+}
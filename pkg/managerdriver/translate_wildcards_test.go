@@ -0,0 +1,124 @@
+package managerdriver
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/ngrok/ngrok-operator/internal/ir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWildcardTestVHost(hostname string, paths ...string) *ir.IRVirtualHost {
+	irVHost := &ir.IRVirtualHost{
+		Namespace: "default",
+		Listener: ir.IRListener{
+			Hostname: ir.IRHostname(hostname),
+			Port:     443,
+			Protocol: ir.IRProtocol_HTTPS,
+		},
+	}
+	for _, path := range paths {
+		irVHost.Routes = append(irVHost.Routes, &ir.IRRoute{
+			HTTPMatchCriteria: &ir.IRHTTPMatch{
+				Path:     new(path),
+				PathType: new(ir.IRPathType_Prefix),
+			},
+			Destinations: []*ir.IRDestination{{}},
+		})
+	}
+	return irVHost
+}
+
+func TestMergeWildcardVirtualHosts(t *testing.T) {
+	tr := &translator{log: logr.Discard()}
+
+	wildcard := newWildcardTestVHost("*.example.com", "/")
+	narrowWildcard := newWildcardTestVHost("*.api.example.com", "/")
+	app := newWildcardTestVHost("app.example.com", "/app")
+	v1 := newWildcardTestVHost("v1.api.example.com", "/v1")
+	base := newWildcardTestVHost("example.com", "/")
+	otherNamespace := newWildcardTestVHost("ns.example.com", "/")
+	otherNamespace.Namespace = "other"
+	withTLS := newWildcardTestVHost("tls.example.com", "/")
+	withTLS.TLSTermination = &ir.IRTLSTermination{ServerCertificate: new("cert")}
+
+	merged := tr.mergeWildcardVirtualHosts([]*ir.IRVirtualHost{wildcard, narrowWildcard, app, v1, base, otherNamespace, withTLS})
+	assert.Equal(t, []*ir.IRVirtualHost{wildcard, narrowWildcard, base, otherNamespace, withTLS}, merged)
+
+	// app.example.com is folded into *.example.com with a route for its path and a host fallback
+	require.Len(t, wildcard.Routes, 3)
+	assert.Equal(t, "app.example.com", *wildcard.Routes[1].HTTPMatchCriteria.Host)
+	assert.Equal(t, "/app", *wildcard.Routes[1].HTTPMatchCriteria.Path)
+	assert.Equal(t, "app.example.com", *wildcard.Routes[2].HTTPMatchCriteria.Host)
+	assert.Nil(t, wildcard.Routes[2].HTTPMatchCriteria.Path)
+	assert.Len(t, wildcard.Routes[2].TrafficPolicies, 1)
+
+	// v1.api.example.com goes to the most specific wildcard
+	require.Len(t, narrowWildcard.Routes, 3)
+	assert.Equal(t, "v1.api.example.com", *narrowWildcard.Routes[1].HTTPMatchCriteria.Host)
+
+	// The original routes are left untouched
+	assert.Nil(t, app.Routes[0].HTTPMatchCriteria.Host)
+}
+
+func TestMergeWildcardVirtualHosts_DefaultDestination(t *testing.T) {
+	tr := &translator{log: logr.Discard()}
+
+	wildcard := newWildcardTestVHost("*.example.com", "/")
+	app := newWildcardTestVHost("app.example.com")
+	app.DefaultDestination = &ir.IRDestination{}
+
+	merged := tr.mergeWildcardVirtualHosts([]*ir.IRVirtualHost{app, wildcard})
+	assert.Equal(t, []*ir.IRVirtualHost{wildcard}, merged)
+
+	require.Len(t, wildcard.Routes, 2)
+	assert.Equal(t, "app.example.com", *wildcard.Routes[1].HTTPMatchCriteria.Host)
+	assert.Equal(t, []*ir.IRDestination{app.DefaultDestination}, wildcard.Routes[1].Destinations)
+	assert.Empty(t, wildcard.Routes[1].TrafficPolicies)
+}
+
+func TestRouteHostsForVirtualHost(t *testing.T) {
+	testCases := []struct {
+		name           string
+		vHostHostname  string
+		routeHostnames []string
+		expected       []string
+	}{
+		{
+			name:           "exact virtual host",
+			vHostHostname:  "app.example.com",
+			routeHostnames: []string{"app.example.com"},
+			expected:       nil,
+		},
+		{
+			name:          "no route hostnames",
+			vHostHostname: "*.example.com",
+			expected:      nil,
+		},
+		{
+			name:           "route hostname matches the wildcard",
+			vHostHostname:  "*.example.com",
+			routeHostnames: []string{"app.example.com", "*.example.com"},
+			expected:       nil,
+		},
+		{
+			name:           "route hostname wider than the wildcard",
+			vHostHostname:  "*.api.example.com",
+			routeHostnames: []string{"*.example.com"},
+			expected:       nil,
+		},
+		{
+			name:           "narrower route hostnames",
+			vHostHostname:  "*.example.com",
+			routeHostnames: []string{"app.example.com", "*.api.example.com", "app.other.com"},
+			expected:       []string{"app.example.com", "*.api.example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, routeHostsForVirtualHost(tc.vHostHostname, tc.routeHostnames))
+		})
+	}
+}
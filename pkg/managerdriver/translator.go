@@ -3,6 +3,7 @@ package managerdriver
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	commonv1alpha1 "github.com/ngrok/ngrok-operator/api/common/v1alpha1"
@@ -37,12 +38,39 @@ type translator struct {
 	// We give users the ability to opt-out of requiring ReferenceGrants for cross namespace
 	// references when using Gateway API
 	disableGatewayReferenceGrants bool
+
+	// When enabled, supported nginx.ingress.kubernetes.io annotations on Ingresses are translated
+	// as well, so that Ingresses written for ingress-nginx can move to ngrok with a class change
+	ingressNginxCompatibility bool
+	// routes generated for the paths of non-canary Ingresses, used to add the backends of ingress-nginx canaries
+	nginxRoutes map[nginxRouteKey]*ir.IRRoute
+
+	// When enabled, the feature annotations that reference a Secret (basic auth and OIDC) are translated. Their
+	// credentials are copied into the traffic policy of the generated endpoints, so anyone who can read those can
+	// read the credentials.
+	ingressSecretAnnotations bool
+
+	// When set, the endpoints of every hostname are shared with the other member clusters
+	multiCluster *MultiCluster
+
+	// warnings collected during translation that should be surfaced to users as events
+	warnings []TranslationWarning
 }
 
 // TranslationResult is the final set of translation output resources
 type TranslationResult struct {
 	AgentEndpoints map[types.NamespacedName]*ngrokv1alpha1.AgentEndpoint
 	CloudEndpoints map[types.NamespacedName]*ngrokv1alpha1.CloudEndpoint
+	// Warnings about the translated resources that should be recorded as events on them
+	Warnings []TranslationWarning
+}
+
+// TranslationWarning is a problem with a user resource found during translation that did not stop
+// the translation, such as configuration that was ignored.
+type TranslationWarning struct {
+	Object  client.Object
+	Reason  string
+	Message string
 }
 
 // NewTranslator creates a new default Translator
@@ -54,6 +82,9 @@ func NewTranslator(
 	defaultGatewayMetadata string,
 	clusterDomain string,
 	disableGatewayReferenceGrants bool,
+	ingressNginxCompatibility bool,
+	ingressSecretAnnotations bool,
+	multiCluster *MultiCluster,
 ) Translator {
 	return &translator{
 		log:                           log,
@@ -63,6 +94,9 @@ func NewTranslator(
 		defaultGatewayMetadata:        defaultGatewayMetadata,
 		clusterDomain:                 clusterDomain,
 		disableGatewayReferenceGrants: disableGatewayReferenceGrants,
+		ingressNginxCompatibility:     ingressNginxCompatibility,
+		ingressSecretAnnotations:      ingressSecretAnnotations,
+		multiCluster:                  multiCluster,
 	}
 }
 
@@ -72,13 +106,13 @@ func (t *translator) Translate() *TranslationResult {
 	gatewayVirtualHosts := t.gatewayAPIToIR()
 
 	virtualHosts := make([]*ir.IRVirtualHost, 0, len(ingressVirtualHosts)+len(gatewayVirtualHosts))
-	for _, irVHost := range ingressVirtualHosts {
-		irVHost.SortRoutes()
-		virtualHosts = append(virtualHosts, irVHost)
-	}
-	for _, irVHost := range gatewayVirtualHosts {
+	virtualHosts = append(virtualHosts, ingressVirtualHosts...)
+	virtualHosts = append(virtualHosts, gatewayVirtualHosts...)
+
+	// Hostnames covered by a wildcard hostname are routed by the wildcard's endpoint where possible
+	virtualHosts = t.mergeWildcardVirtualHosts(virtualHosts)
+	for _, irVHost := range virtualHosts {
 		irVHost.SortRoutes()
-		virtualHosts = append(virtualHosts, irVHost)
 	}
 
 	cloudEndpoints, agentEndpoints := t.IRToEndpoints(virtualHosts)
@@ -86,9 +120,16 @@ func (t *translator) Translate() *TranslationResult {
 	return &TranslationResult{
 		AgentEndpoints: agentEndpoints,
 		CloudEndpoints: cloudEndpoints,
+		Warnings:       t.warnings,
 	}
 }
 
+// warn records a warning about obj to be returned with the translation result
+func (t *translator) warn(obj client.Object, reason, message string) {
+	t.log.Info(message, "kind", obj.GetObjectKind().GroupVersionKind().Kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+	t.warnings = append(t.warnings, TranslationWarning{Object: obj, Reason: reason, Message: message})
+}
+
 // Takes a set of IRVirtualHosts and iterates over them to figure out which can and cannot be collapsed according to their mapping strategies
 func validateMappingStrategies(irVHosts []*ir.IRVirtualHost) {
 
@@ -172,6 +213,12 @@ func (t *translator) IRToEndpoints(irVHosts []*ir.IRVirtualHost) (cloudEndpoints
 	agentEndpointCache := make(map[ir.IRServiceKey]*ngrokv1alpha1.AgentEndpoint)
 	cloudEndpoints = make(map[types.NamespacedName]*ngrokv1alpha1.CloudEndpoint)
 
+	if t.multiCluster != nil {
+		for _, irVHost := range irVHosts {
+			t.multiCluster.applyToVirtualHost(irVHost)
+		}
+	}
+
 	validateMappingStrategies(irVHosts)
 	for _, irVHost := range irVHosts {
 		if irVHost.TrafficPolicy == nil && len(irVHost.Routes) == 0 {
@@ -376,18 +423,13 @@ func (t *translator) buildRoutingPolicy(irVHost *ir.IRVirtualHost, agentEndpoint
 	if captureOriginalParams {
 		routingTrafficPolicy.AddRuleOnHTTPRequest(trafficpolicy.Rule{
 			Name: "Capture-Original-Request-Data",
-			Actions: []trafficpolicy.Action{{
-				Type: trafficpolicy.ActionType_SetVars,
-				Config: map[string]any{
-					"vars": []map[string]any{{
-						"original_path": "${req.url.path}",
-					}, {
-						"original_headers": "${req.headers.encodeJson()}",
-					}, {
-						"original_query_params": "${req.url.query_params.encodeJson()}",
-					}},
-				},
-			}},
+			Actions: []trafficpolicy.Action{
+				trafficpolicy.NewSetVarsAction(
+					map[string]any{"original_path": "${req.url.path}"},
+					map[string]any{"original_headers": "${req.headers.encodeJson()}"},
+					map[string]any{"original_query_params": "${req.url.query_params.encodeJson()}"},
+				),
+			},
 		})
 	}
 
@@ -441,19 +483,14 @@ func (t *translator) buildRoutingPolicy(irVHost *ir.IRVirtualHost, agentEndpoint
 			}
 
 			// Make sure the weighted routes set-vars action that stores a random number doesn't erase our captured request data
-			setvarWeightCfg := map[string]any{
-				"vars": []map[string]any{{
-					"weighted_route_random_num": fmt.Sprintf("${rand.int(0,%d)}", routeTotalWeight-1),
-				}},
-			}
-
 			randomNumRule := trafficpolicy.Rule{
 				Name:        "Gen-Random-Number",
 				Expressions: matchExpressions,
-				Actions: []trafficpolicy.Action{{
-					Type:   trafficpolicy.ActionType_SetVars,
-					Config: setvarWeightCfg,
-				}},
+				Actions: []trafficpolicy.Action{
+					trafficpolicy.NewSetVarsAction(map[string]any{
+						"weighted_route_random_num": fmt.Sprintf("${rand.int(0,%d)}", routeTotalWeight-1),
+					}),
+				},
 			}
 
 			switch irVHost.Listener.Protocol {
@@ -509,7 +546,7 @@ func (t *translator) buildRoutingPolicy(irVHost *ir.IRVirtualHost, agentEndpoint
 					var err error
 					agentEndpoint, err = buildAgentEndpoint(
 						irVHost,
-						irService,
+						t.multiCluster.localService(irService),
 						t.clusterDomain,
 						irVHost.Metadata,
 						irVHost.Description)
@@ -528,26 +565,43 @@ func (t *translator) buildRoutingPolicy(irVHost *ir.IRVirtualHost, agentEndpoint
 				// If we're collapsing this upstream into a public AgentEndpoint then we need to set a variable for whether or not the request matched the local service instead of using
 				// forward-internal to forward to another endpoint
 
-				var tpRouteRule trafficpolicy.Rule
+				var tpRouteRules []trafficpolicy.Rule
 				if irVHost.CollapseIntoServiceKey != nil && irService.Key() == *irVHost.CollapseIntoServiceKey {
 					// Inject a rule into the traffic policy that will set a variable if we matched the local service
-					tpRouteRule = buildRouteLocallyVarRule("Generated-Local-Service-Route", true)
+					tpRouteRules = []trafficpolicy.Rule{buildRouteLocallyVarRule("Generated-Local-Service-Route", true)}
+				} else if t.multiCluster != nil {
+					// Route to the internal endpoint of the upstream in each of the member clusters
+					var err error
+					tpRouteRules, err = t.buildClusterRouteRules("Generated-Route", irVHost.Listener.Protocol, irService)
+					if err != nil {
+						t.log.Error(err, "failed to build multi-cluster routes",
+							"hostname", irVHost.Listener.Hostname,
+							"port", irVHost.Listener.Port,
+							"protocol", irVHost.Listener.Protocol,
+							"generated from resources", irDestination.Upstream.OwningResources,
+						)
+						continue
+					}
 				} else {
 					// Inject a rule into the traffic policy that will route to the desired upstream on path match for the route
-					tpRouteRule = buildEndpointServiceRouteRule("Generated-Route", agentEndpoint.Spec.URL)
-				}
-				tpRouteRule.Expressions = appendStringUnique(tpRouteRule.Expressions, matchExpressions...)
-				if weightedRouteExpression != nil {
-					tpRouteRule.Expressions = appendStringUnique(tpRouteRule.Expressions, *weightedRouteExpression)
+					tpRouteRules = []trafficpolicy.Rule{buildEndpointServiceRouteRule("Generated-Route", agentEndpoint.Spec.URL)}
 				}
 
-				switch irVHost.Listener.Protocol {
-				case ir.IRProtocol_HTTP, ir.IRProtocol_HTTPS:
-					routingTrafficPolicy.AddRuleOnHTTPRequest(tpRouteRule)
-				case ir.IRProtocol_TCP, ir.IRProtocol_TLS:
-					// This is only necessary for tcp:// and tls:// endpoints if we're balancing between multiple upstreams since othherwise they don't have match criteria
-					if len(irVHost.Routes) == 1 && len(irVHost.Routes[0].Destinations) > 1 {
-						routingTrafficPolicy.AddRuleOnTCPConnect(tpRouteRule)
+				for _, tpRouteRule := range tpRouteRules {
+					tpRouteRule.Expressions = appendStringUnique(tpRouteRule.Expressions, matchExpressions...)
+					if weightedRouteExpression != nil {
+						tpRouteRule.Expressions = appendStringUnique(tpRouteRule.Expressions, *weightedRouteExpression)
+					}
+
+					switch irVHost.Listener.Protocol {
+					case ir.IRProtocol_HTTP, ir.IRProtocol_HTTPS:
+						routingTrafficPolicy.AddRuleOnHTTPRequest(tpRouteRule)
+					case ir.IRProtocol_TCP, ir.IRProtocol_TLS:
+						// This is only necessary for tcp:// and tls:// endpoints if we're balancing between multiple upstreams since othherwise they don't have match criteria.
+						// In multi-cluster mode, there is always more than one upstream to fail over between.
+						if len(irVHost.Routes) == 1 && len(irVHost.Routes[0].Destinations) > 1 || t.multiCluster != nil {
+							routingTrafficPolicy.AddRuleOnTCPConnect(tpRouteRule)
+						}
 					}
 				}
 			}
@@ -566,6 +620,15 @@ func irMatchCriteriaToTPExpressions(matchCriteria *ir.IRHTTPMatch, getRequestDat
 		return expressions
 	}
 
+	// 0. Host matching. The host isn't modified by any of the actions we generate, so it is always read from the request
+	if matchCriteria.Host != nil {
+		if isWildcardHost(*matchCriteria.Host) {
+			expressions = appendStringUnique(expressions, fmt.Sprintf("req.host.endsWith('%s')", strings.TrimPrefix(*matchCriteria.Host, "*")))
+		} else {
+			expressions = appendStringUnique(expressions, fmt.Sprintf("req.host == '%s'", *matchCriteria.Host))
+		}
+	}
+
 	// 1. Path matching
 	if matchCriteria.Path != nil {
 		pathType := ir.IRPathType_Prefix // Defult to prefix match
@@ -579,6 +642,12 @@ func irMatchCriteriaToTPExpressions(matchCriteria *ir.IRHTTPMatch, getRequestDat
 			} else {
 				expressions = appendStringUnique(expressions, fmt.Sprintf("req.url.path == '%s'", *matchCriteria.Path))
 			}
+		case ir.IRPathType_Regex:
+			if getRequestDataFromVar {
+				expressions = appendStringUnique(expressions, fmt.Sprintf("vars.original_path.matches(r'%s')", *matchCriteria.Path))
+			} else {
+				expressions = appendStringUnique(expressions, fmt.Sprintf("req.url.path.matches(r'%s')", *matchCriteria.Path))
+			}
 		case ir.IRPathType_Prefix:
 			fallthrough
 		default:
@@ -671,12 +740,7 @@ func buildEndpointServiceRouteRule(name string, url string) trafficpolicy.Rule {
 	return trafficpolicy.Rule{
 		Name: name,
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_ForwardInternal,
-				Config: map[string]any{
-					"url": url,
-				},
-			},
+			trafficpolicy.NewForwardInternalAction(url),
 		},
 	}
 }
@@ -689,14 +753,9 @@ func buildRouteLocallyVarRule(name string, value bool) trafficpolicy.Rule {
 	return trafficpolicy.Rule{
 		Name: name,
 		Actions: []trafficpolicy.Action{
-			{
-				Type: trafficpolicy.ActionType_SetVars,
-				Config: map[string]any{
-					"vars": []map[string]any{{
-						"request_matched_local_svc": value,
-					}},
-				},
-			},
+			trafficpolicy.NewSetVarsAction(map[string]any{
+				"request_matched_local_svc": value,
+			}),
 		},
 	}
 }
@@ -729,7 +788,7 @@ func (t *translator) buildDefaultDestinationPolicy(irVHost *ir.IRVirtualHost, ag
 			var err error
 			agentEndpoint, err = buildAgentEndpoint(
 				irVHost,
-				irService,
+				t.multiCluster.localService(irService),
 				t.clusterDomain,
 				irVHost.Metadata,
 				irVHost.Description,
@@ -740,16 +799,24 @@ func (t *translator) buildDefaultDestinationPolicy(irVHost *ir.IRVirtualHost, ag
 			agentEndpointCache[irService.Key()] = agentEndpoint
 		}
 		// If we're collapsing this into an AgentEndpoint, we only want to run this when a request did not match the local service
-		var routeRule trafficpolicy.Rule
+		var routeRules []trafficpolicy.Rule
 		if irVHost.CollapseIntoServiceKey != nil && irService.Key() == *irVHost.CollapseIntoServiceKey {
-			routeRule = buildRouteLocallyVarRule("Generated-Route-Default-Backend", true)
+			routeRules = []trafficpolicy.Rule{buildRouteLocallyVarRule("Generated-Route-Default-Backend", true)}
+		} else if t.multiCluster != nil {
+			var err error
+			routeRules, err = t.buildClusterRouteRules("Generated-Route-Default-Backend", irVHost.Listener.Protocol, irService)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build multi-cluster routes. upstream generated from resources: %v, err: %w", upstream.OwningResources, err)
+			}
 		} else {
-			routeRule = buildEndpointServiceRouteRule("Generated-Route-Default-Backend", agentEndpoint.Spec.URL)
+			routeRules = []trafficpolicy.Rule{buildEndpointServiceRouteRule("Generated-Route-Default-Backend", agentEndpoint.Spec.URL)}
 		}
-		if irVHost.CollapseIntoServiceKey != nil {
-			routeRule.Expressions = appendStringUnique(routeRule.Expressions, "vars.request_matched_local_svc == false")
+		for _, routeRule := range routeRules {
+			if irVHost.CollapseIntoServiceKey != nil {
+				routeRule.Expressions = appendStringUnique(routeRule.Expressions, "vars.request_matched_local_svc == false")
+			}
+			defaultDestTrafficPolicy.AddRuleOnHTTPRequest(routeRule)
 		}
-		defaultDestTrafficPolicy.AddRuleOnHTTPRequest(routeRule)
 	}
 	return defaultDestTrafficPolicy, nil
 }
@@ -818,6 +885,7 @@ func buildCloudEndpoint(irVHost *ir.IRVirtualHost) (*ngrokv1alpha1.CloudEndpoint
 			Metadata:    commonv1alpha1.MetadataFromLegacyString(irVHost.Metadata),
 			Description: irVHost.Description,
 			Bindings:    irVHost.Bindings,
+			Schedule:    irVHost.Schedule,
 		},
 	}, nil
 }
@@ -831,6 +899,8 @@ func buildAgentEndpoint(
 	description string,
 ) (*ngrokv1alpha1.AgentEndpoint, error) {
 	bindings := []string{}
+	var schedule *ngrokv1alpha1.EndpointSchedule
+	var poolingEnabled *bool
 	var url string
 	if irVHost.CollapseIntoServiceKey != nil && irService.Key() == *irVHost.CollapseIntoServiceKey {
 		publicURL, err := buildPublicURL(irVHost)
@@ -839,6 +909,8 @@ func buildAgentEndpoint(
 		}
 		url = publicURL
 		bindings = irVHost.Bindings
+		schedule = irVHost.Schedule
+		poolingEnabled = irVHost.EndpointPoolingEnabled
 	} else {
 		internalURL, err := buildInternalEndpointURL(irVHost.Listener.Protocol, irService.UID, irService.Name, irService.Namespace, clusterDomain, irService.Port, irService.ClientCertRefs)
 		if err != nil {
@@ -869,6 +941,8 @@ func buildAgentEndpoint(
 				URL:      agentEndpointUpstreamURL(irService.Name, irService.Namespace, clusterDomain, irService.Port, irService.Scheme),
 				Protocol: irService.Protocol,
 			},
+			PoolingEnabled: poolingEnabled,
+			Schedule:       schedule,
 		},
 	}
 
@@ -890,17 +964,10 @@ func buildDefault404TPRule() trafficpolicy.Rule {
 	return trafficpolicy.Rule{
 		Name: "Fallback-404",
 		Actions: []trafficpolicy.Action{
-			{
-				// Basic text for now, but we can add styling/branding later
-				Type: trafficpolicy.ActionType_CustomResponse,
-				Config: map[string]any{
-					"status_code": 404,
-					"content":     "No route was found for this ngrok Endpoint",
-					"headers": map[string]string{
-						"content-type": "text/plain",
-					},
-				},
-			},
+			// Basic text for now, but we can add styling/branding later
+			trafficpolicy.NewCustomResponseAction(404, "No route was found for this ngrok Endpoint", map[string]string{
+				"content-type": "text/plain",
+			}),
 		},
 	}
 }
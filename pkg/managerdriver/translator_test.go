@@ -215,6 +215,19 @@ func TestBuildCloudEndpoint(t *testing.T) {
 			},
 			expectedName: "prefix-cloud-host",
 		},
+		{
+			testName: "Schedule",
+			irVHost: &ir.IRVirtualHost{
+				Namespace: "default",
+				Schedule:  &ngrokv1alpha1.EndpointSchedule{Cron: "0 9 * * 1-5"},
+				Listener: ir.IRListener{
+					Hostname: "cloud-host",
+					Port:     443,
+					Protocol: ir.IRProtocol_HTTPS,
+				},
+			},
+			expectedName: "cloud-host",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -226,6 +239,7 @@ func TestBuildCloudEndpoint(t *testing.T) {
 			assert.Equal(t, tc.irVHost.LabelsToAdd, result.Labels, "unexpected labels for test case")
 			assert.Equal(t, tc.irVHost.AnnotationsToAdd, result.Annotations, "unexpected annotations for test case")
 			assert.Equal(t, tc.irVHost.Metadata, common.MetadataAPIString(result.Spec.Metadata), "unexpected metadata for test case")
+			assert.Equal(t, tc.irVHost.Schedule, result.Spec.Schedule, "unexpected schedule for test case")
 		})
 	}
 }
@@ -324,12 +338,7 @@ func TestBuildDefaultDestinationPolicy(t *testing.T) {
 					{
 						Name: "Generated-Route-Default-Backend",
 						Actions: []trafficpolicy.Action{
-							{
-								Type: "forward-internal",
-								Config: map[string]any{
-									"url": "https://62d2f-test-service-default-cluster-local-8080.internal",
-								},
-							},
+							trafficpolicy.NewForwardInternalAction("https://62d2f-test-service-default-cluster-local-8080.internal"),
 						},
 					},
 				},
@@ -451,6 +460,7 @@ type TranslatorRawTestCase struct {
 		Configmaps      []map[string]any `yaml:"configMaps"`
 		Namespaces      []map[string]any `yaml:"namespaces"`
 		ReferenceGrants []map[string]any `yaml:"referenceGrants"`
+		TCPAddresses    []map[string]any `yaml:"tcpAddresses"`
 	} `yaml:"input"`
 
 	Expected struct {
@@ -475,6 +485,7 @@ type TranslatorTestCase struct {
 		Services        []*corev1.Service
 		Namespaces      []*corev1.Namespace
 		ReferenceGrants []*gatewayv1beta1.ReferenceGrant
+		TCPAddresses    []*ingressv1alpha1.TCPAddress
 	}
 
 	Expected struct {
@@ -548,6 +559,9 @@ func TestTranslate(t *testing.T) {
 				driver.gatewayNgrokMetadata,
 				"svc.cluster.local",
 				false, // Require reference grants (default)
+				false, // ingress-nginx compatibility disabled (default)
+				false, // Secret feature annotations disabled (default)
+				nil,   // multi-cluster disabled (default)
 			)
 
 			// Finally, run translate and check the contents
@@ -636,6 +650,7 @@ func TestTranslate(t *testing.T) {
 				assert.Equal(t, expectedAE.Spec.Description, actualAE.Spec.Description)
 				assert.Equal(t, common.MetadataAPIString(expectedAE.Spec.Metadata), common.MetadataAPIString(actualAE.Spec.Metadata))
 				assert.Equal(t, expectedAE.Spec.Bindings, actualAE.Spec.Bindings)
+				assert.Equal(t, expectedAE.Spec.PoolingEnabled, actualAE.Spec.PoolingEnabled)
 				assert.Equal(t, expectedAE.Spec.Upstream.Protocol, actualAE.Spec.Upstream.Protocol)
 				assert.Equal(t, expectedAE.Spec.Upstream.URL, actualAE.Spec.Upstream.URL)
 				assert.Equal(t, expectedAE.Spec.Upstream.ProxyProtocolVersion, actualAE.Spec.Upstream.ProxyProtocolVersion)
@@ -676,7 +691,10 @@ func TestTranslate(t *testing.T) {
 				driver.ingressNgrokMetadata,
 				driver.gatewayNgrokMetadata,
 				"svc.cluster.local",
-				true, // Disable reference grants
+				true,  // Disable reference grants
+				false, // ingress-nginx compatibility disabled (default)
+				false, // Secret feature annotations disabled (default)
+				nil,   // multi-cluster disabled (default)
 			)
 
 			// Finally, run translate and check the contents
@@ -765,6 +783,7 @@ func TestTranslate(t *testing.T) {
 				assert.Equal(t, expectedAE.Spec.Description, actualAE.Spec.Description)
 				assert.Equal(t, common.MetadataAPIString(expectedAE.Spec.Metadata), common.MetadataAPIString(actualAE.Spec.Metadata))
 				assert.Equal(t, expectedAE.Spec.Bindings, actualAE.Spec.Bindings)
+				assert.Equal(t, expectedAE.Spec.PoolingEnabled, actualAE.Spec.PoolingEnabled)
 				assert.Equal(t, expectedAE.Spec.Upstream.Protocol, actualAE.Spec.Upstream.Protocol)
 				assert.Equal(t, expectedAE.Spec.Upstream.URL, actualAE.Spec.Upstream.URL)
 				assert.Equal(t, expectedAE.Spec.Upstream.ProxyProtocolVersion, actualAE.Spec.Upstream.ProxyProtocolVersion)
@@ -816,6 +835,9 @@ func loadTranslatorInputObjs(t *testing.T, tc TranslatorTestCase) []runtime.Obje
 	for _, obj := range tc.Input.Namespaces {
 		inputObjects = append(inputObjects, obj)
 	}
+	for _, obj := range tc.Input.TCPAddresses {
+		inputObjects = append(inputObjects, obj)
+	}
 	return inputObjects
 }
 
@@ -924,6 +946,13 @@ func loadTranslatorTestCase(t *testing.T, file string, sch *runtime.Scheme) Tran
 		require.True(t, ok, "expected an NgrokTrafficPolicy, got %T", obj)
 		tc.Input.TrafficPolicies = append(tc.Input.TrafficPolicies, pol)
 	}
+	for _, rawObj := range rawTC.Input.TCPAddresses {
+		obj, err := decodeViaScheme(sch, rawObj)
+		require.NoError(t, err)
+		tcpAddress, ok := obj.(*ingressv1alpha1.TCPAddress)
+		require.True(t, ok, "expected a TCPAddress, got %T", obj)
+		tc.Input.TCPAddresses = append(tc.Input.TCPAddresses, tcpAddress)
+	}
 
 	// Decode expected objects
 	for _, rawObj := range rawTC.Expected.CloudEndpoints {
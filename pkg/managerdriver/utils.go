@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -231,6 +232,28 @@ func getPortAppProtocol(log logr.Logger, service *corev1.Service, port *corev1.S
 	return nil
 }
 
+// ServicePortUpstream returns the scheme and application protocol to connect to a service port with, the same way
+// as for an Ingress backend. The scheme comes from the ngrok.com/app-protocols annotation and defaults to http. The
+// application protocol comes from the port's appProtocol.
+func ServicePortUpstream(log logr.Logger, service *corev1.Service, port *corev1.ServicePort) (ir.IRScheme, *common.ApplicationProtocol) {
+	portProto, err := getProtoForServicePort(log, service, port.Name, ir.IRProtocol_HTTP)
+	if err != nil {
+		// When this function errors we still get a valid default, so no need to return
+		log.Error(err, "error getting protocol for service port")
+	}
+
+	scheme, err := protocolStringToIRScheme(portProto)
+	if err != nil {
+		log.Error(err, "error getting scheme from port protocol for service port",
+			"service", fmt.Sprintf("%s.%s", service.Name, service.Namespace),
+			"port name", port.Name,
+			"port number", port.Port,
+		)
+	}
+
+	return scheme, getPortAppProtocol(log, service, port)
+}
+
 func findServicesPort(log logr.Logger, service *corev1.Service, backendSvcPort netv1.ServiceBackendPort) (*corev1.ServicePort, error) {
 	for _, port := range service.Spec.Ports {
 		if (backendSvcPort.Number > 0 && port.Port == backendSvcPort.Number) || port.Name == backendSvcPort.Name {
@@ -241,6 +264,8 @@ func findServicesPort(log logr.Logger, service *corev1.Service, backendSvcPort n
 	return nil, fmt.Errorf("could not find matching port for service %s, backend port %v, name %s", service.Name, backendSvcPort.Number, backendSvcPort.Name)
 }
 
+// calculateIngressLoadBalancerIPStatus returns the load balancer status of an Ingress from the domains of its hosts.
+// Hosts whose domain can't serve traffic yet report the reason in the port status error.
 func calculateIngressLoadBalancerIPStatus(ing *netv1.Ingress, domains map[string]ingressv1alpha1.Domain) []netv1.IngressLoadBalancerIngress {
 	ingressHosts := map[string]bool{}
 	for _, rule := range ing.Spec.Rules {
@@ -249,8 +274,10 @@ func calculateIngressLoadBalancerIPStatus(ing *netv1.Ingress, domains map[string
 
 	status := []netv1.IngressLoadBalancerIngress{}
 
+	wildcards := wildcardDomainNames(domains)
+
 	for host := range ingressHosts {
-		d, ok := domains[host]
+		d, ok := findDomainForHost(host, domains, wildcards)
 		if !ok {
 			continue
 		}
@@ -262,21 +289,38 @@ func calculateIngressLoadBalancerIPStatus(ing *netv1.Ingress, domains map[string
 		case d.Status.CNAMETarget != nil:
 			hostname = *d.Status.CNAMETarget
 		// ngrok managed domain
-		default:
+		case util.IsNgrokManagedDomain(d.Status.Domain):
 			// Trim the wildcard prefix if it exists for ngrok managed domains
 			hostname = strings.TrimPrefix(d.Status.Domain, "*.")
 		}
 
-		if hostname != "" {
-			status = append(status, netv1.IngressLoadBalancerIngress{
-				Hostname: hostname,
-			})
+		// A custom domain that hasn't been reserved yet has no address. Publishing the host itself would make
+		// external-dns create a CNAME pointing at itself, so the entry is left out and the DomainNotReady event
+		// explains why.
+		if hostname == "" {
+			continue
+		}
+
+		lbIngress := netv1.IngressLoadBalancerIngress{
+			Hostname: hostname,
 		}
+		if problem := domainProblem(d); problem != nil {
+			lbIngress.Ports = []netv1.IngressPortStatus{{
+				Port:     443,
+				Protocol: corev1.ProtocolTCP,
+				Error:    new(domainStatusReasonPrefix + problem.Reason),
+			}}
+		}
+		status = append(status, lbIngress)
 	}
 
 	sort.Slice(status, func(i, j int) bool {
 		return status[i].Hostname < status[j].Hostname
 	})
+	// Several hosts can share the CNAME target of the same wildcard domain
+	status = slices.CompactFunc(status, func(a, b netv1.IngressLoadBalancerIngress) bool {
+		return a.Hostname == b.Hostname
+	})
 
 	return status
 }
@@ -356,6 +400,38 @@ func doHostGlobsMatch(hostname1 string, hostname2 string) (bool, error) {
 	}
 }
 
+// isWildcardHost returns true if the hostname is a wildcard hostname such as *.example.com
+func isWildcardHost(hostname string) bool {
+	return strings.HasPrefix(hostname, "*.")
+}
+
+// wildcardCoversHost returns true if the wildcard hostname matches the given hostname. The "*" of the wildcard
+// matches one or more labels, so *.example.com matches app.example.com and a.b.example.com as well as narrower
+// wildcards such as *.b.example.com, but not example.com itself.
+func wildcardCoversHost(wildcard string, hostname string) bool {
+	if !isWildcardHost(wildcard) || wildcard == hostname {
+		return false
+	}
+	suffix := strings.TrimPrefix(wildcard, "*")
+	return len(hostname) > len(suffix) && strings.HasSuffix(hostname, suffix)
+}
+
+// coveringWildcardHost returns the most specific of the wildcard hostnames that matches the given hostname, or an
+// empty string if none of them do. Ties between equally specific wildcards are broken lexicographically so that the
+// result doesn't depend on the order of the wildcards.
+func coveringWildcardHost(hostname string, wildcards []string) string {
+	best := ""
+	for _, wildcard := range wildcards {
+		if !wildcardCoversHost(wildcard, hostname) {
+			continue
+		}
+		if best == "" || len(wildcard) > len(best) || (len(wildcard) == len(best) && wildcard < best) {
+			best = wildcard
+		}
+	}
+	return best
+}
+
 func protocolStringToIRScheme(irProtocol ir.IRProtocol) (ir.IRScheme, error) {
 	switch irProtocol {
 	case "HTTP":
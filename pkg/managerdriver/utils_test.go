@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestSanitizeStringForURL(t *testing.T) {
@@ -427,6 +428,38 @@ func TestDoHostGlobsMatch(t *testing.T) {
 	}
 }
 
+func TestWildcardCoversHost(t *testing.T) {
+	testCases := []struct {
+		wildcard string
+		hostname string
+		expected bool
+	}{
+		{wildcard: "*.example.com", hostname: "app.example.com", expected: true},
+		{wildcard: "*.example.com", hostname: "a.b.example.com", expected: true},
+		{wildcard: "*.example.com", hostname: "*.b.example.com", expected: true},
+		{wildcard: "*.example.com", hostname: "example.com", expected: false},
+		{wildcard: "*.example.com", hostname: "*.example.com", expected: false},
+		{wildcard: "*.example.com", hostname: "app.notexample.com", expected: false},
+		{wildcard: "app.example.com", hostname: "app.example.com", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.wildcard+"/"+tc.hostname, func(t *testing.T) {
+			assert.Equal(t, tc.expected, wildcardCoversHost(tc.wildcard, tc.hostname))
+		})
+	}
+}
+
+func TestCoveringWildcardHost(t *testing.T) {
+	wildcards := []string{"*.example.com", "*.api.example.com", "*.other.com"}
+
+	assert.Equal(t, "*.api.example.com", coveringWildcardHost("v1.api.example.com", wildcards))
+	assert.Equal(t, "*.example.com", coveringWildcardHost("app.example.com", wildcards))
+	assert.Equal(t, "*.example.com", coveringWildcardHost("*.api.example.com", wildcards))
+	assert.Equal(t, "", coveringWildcardHost("*.example.com", wildcards))
+	assert.Equal(t, "", coveringWildcardHost("example.org", wildcards))
+}
+
 func TestGetProtoForServicePort(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -517,6 +550,19 @@ func TestGetPortAppProtocol(t *testing.T) {
 	}
 }
 
+func TestServicePortUpstream(t *testing.T) {
+	svc := &corev1.Service{Name: "svc", Namespace: "ns"}
+	port := &corev1.ServicePort{Name: "p", Port: 8443, AppProtocol: new("kubernetes.io/h2c")}
+
+	scheme, proto := ServicePortUpstream(logr.Discard(), svc, port)
+	assert.Equal(t, ir.IRScheme_HTTP, scheme)
+	assert.Equal(t, new(common.ApplicationProtocol_HTTP2), proto)
+
+	svc.Annotations = map[string]string{AppProtocolsAnnotation: `{"p":"HTTPS"}`}
+	scheme, _ = ServicePortUpstream(logr.Discard(), svc, port)
+	assert.Equal(t, ir.IRScheme_HTTPS, scheme)
+}
+
 func TestCalculateIngressLoadBalancerIPStatus_DeterministicOrder(t *testing.T) {
 	ing := &netv1.Ingress{
 		Spec: netv1.IngressSpec{
@@ -558,3 +604,84 @@ func TestCalculateIngressLoadBalancerIPStatus_DeterministicOrder(t *testing.T) {
 		assert.Equal(t, expected, result, "iteration %d: status should be sorted by hostname", i)
 	}
 }
+
+func TestCalculateIngressLoadBalancerIPStatus_WildcardDomain(t *testing.T) {
+	ing := &netv1.Ingress{
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{
+				{Host: "*.example.com"},
+				{Host: "app.example.com"},
+				{Host: "api.example.com"},
+			},
+		},
+	}
+
+	domains := map[string]ingressv1alpha1.Domain{
+		"*.example.com": {
+			Status: ingressv1alpha1.DomainStatus{
+				CNAMETarget: new("wildcard.cname.ngrok.io"),
+			},
+		},
+		"api.example.com": {
+			Status: ingressv1alpha1.DomainStatus{
+				CNAMETarget: new("api.cname.ngrok.io"),
+			},
+		},
+	}
+
+	expected := []netv1.IngressLoadBalancerIngress{
+		{Hostname: "api.cname.ngrok.io"},
+		{Hostname: "wildcard.cname.ngrok.io"},
+	}
+	assert.Equal(t, expected, calculateIngressLoadBalancerIPStatus(ing, domains))
+}
+
+func TestCalculateIngressLoadBalancerIPStatus_DomainNotReady(t *testing.T) {
+	ing := &netv1.Ingress{
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{
+				{Host: "app.example.com"},
+				{Host: "api.example.com"},
+				{Host: "ready.example.com"},
+			},
+		},
+	}
+
+	domains := map[string]ingressv1alpha1.Domain{
+		"app.example.com": {
+			Status: ingressv1alpha1.DomainStatus{
+				CNAMETarget: new("app.cname.ngrok.io"),
+				Conditions: []metav1.Condition{
+					{Type: "Ready", Status: metav1.ConditionFalse, Reason: "WaitingForCertificate"},
+					{Type: "DNSConfigured", Status: metav1.ConditionFalse, Reason: "DNSRecordMissing"},
+				},
+			},
+		},
+		// Domains that failed to be reserved have no address yet and are left out
+		"api.example.com": {
+			Status: ingressv1alpha1.DomainStatus{
+				Conditions: []metav1.Condition{
+					{Type: "Ready", Status: metav1.ConditionFalse, Reason: "DomainCreationFailed"},
+					{Type: "DomainCreated", Status: metav1.ConditionFalse, Reason: "DomainCreationFailed"},
+				},
+			},
+		},
+		"ready.example.com": {
+			Status: ingressv1alpha1.DomainStatus{
+				CNAMETarget: new("ready.cname.ngrok.io"),
+				Conditions: []metav1.Condition{
+					{Type: "Ready", Status: metav1.ConditionTrue, Reason: "DomainActive"},
+				},
+			},
+		},
+	}
+
+	expected := []netv1.IngressLoadBalancerIngress{
+		{
+			Hostname: "app.cname.ngrok.io",
+			Ports:    []netv1.IngressPortStatus{{Port: 443, Protocol: corev1.ProtocolTCP, Error: new("ngrok.com/DNSRecordMissing")}},
+		},
+		{Hostname: "ready.cname.ngrok.io"},
+	}
+	assert.Equal(t, expected, calculateIngressLoadBalancerIPStatus(ing, domains))
+}